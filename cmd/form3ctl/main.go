@@ -0,0 +1,256 @@
+// Command form3ctl is a helper tool for operating Form3 client configurations.
+//
+// The client is configured with the FORM3_* env vars or the -form3-* flags of the doctor, conformance and
+// accounts apply commands.
+//
+// Usage:
+//
+//	form3ctl doctor [-sandbox-organisation-id ID] [-timeout DURATION] [-form3-base-url URL]...
+//	form3ctl conformance [-timeout DURATION] [-form3-base-url URL]...
+//	form3ctl accounts render -f FILE [-env ENVIRONMENT] [-var NAME=VALUE]...
+//	form3ctl accounts apply -f FILE [-env ENVIRONMENT] [-var NAME=VALUE]... [-dry-run] [-form3-base-url URL]...
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/accountdef"
+	"form3interview/pkg/config"
+	"form3interview/pkg/conformance"
+	"form3interview/pkg/doctor"
+)
+
+const usage = `Usage: form3ctl <command> [flags]
+
+Commands:
+  doctor             verify connectivity and permissions of the configured Form3 API
+  conformance        verify that the configured Form3 API behaves as the SDK expects (creates and deletes accounts)
+  accounts render    print the accounts of a YAML definition file with the overlays and variables applied
+  accounts apply     create the missing accounts of a YAML definition file and recreate the changed ones
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "doctor":
+		os.Exit(runDoctor(os.Args[2:]))
+	case "conformance":
+		os.Exit(runConformance(os.Args[2:]))
+	case "accounts":
+		if len(os.Args) < 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		switch os.Args[2] {
+		case "render":
+			os.Exit(runAccountsRender(os.Args[3:]))
+		case "apply":
+			os.Exit(runAccountsApply(os.Args[3:]))
+		}
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	sandboxOrgID := fs.String("sandbox-organisation-id", "", "organisation used to create and delete a test account (the check is skipped if empty)")
+	timeout := fs.Duration("timeout", time.Minute, "timeout of all the checks")
+	clientFlags := config.RegisterFlags(fs)
+	_ = fs.Parse(args)
+
+	options, err := clientFlags.Options()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid client config: %s\n", err)
+		return 2
+	}
+
+	sandboxOrganisationID := uuid.Nil
+	if *sandboxOrgID != "" {
+		id, err := uuid.Parse(*sandboxOrgID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid sandbox organisation id: %s\n", err)
+			return 2
+		}
+		sandboxOrganisationID = id
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report := doctor.Run(ctx, sandboxOrganisationID, options...)
+	if err := report.Print(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if !report.Healthy() {
+		return 1
+	}
+	return 0
+}
+
+func runConformance(args []string) int {
+	fs := flag.NewFlagSet("conformance", flag.ExitOnError)
+	timeout := fs.Duration("timeout", time.Minute, "timeout of all the checks")
+	clientFlags := config.RegisterFlags(fs)
+	_ = fs.Parse(args)
+
+	options, err := clientFlags.Options()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid client config: %s\n", err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report := conformance.Run(ctx, options...)
+	if err := report.Print(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if !report.Compliant() {
+		return 1
+	}
+	return 0
+}
+
+func runAccountsRender(args []string) int {
+	fs := flag.NewFlagSet("accounts render", flag.ExitOnError)
+	defFlags := registerDefinitionFlags(fs)
+	_ = fs.Parse(args)
+
+	defs, status := defFlags.load()
+	if status != 0 {
+		return status
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(defs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func runAccountsApply(args []string) int {
+	fs := flag.NewFlagSet("accounts apply", flag.ExitOnError)
+	defFlags := registerDefinitionFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "only print the planned changes")
+	clientFlags := config.RegisterFlags(fs)
+	_ = fs.Parse(args)
+
+	options, err := clientFlags.Options()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid client config: %s\n", err)
+		return 2
+	}
+
+	defs, status := defFlags.load()
+	if status != 0 {
+		return status
+	}
+
+	accounts, err := account.NewClient(options...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid client config: %s\n", err)
+		return 2
+	}
+	defer accounts.Close()
+
+	plan, err := accountdef.Diff(accounts, defs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	pending := plan.Pending()
+	for _, change := range pending {
+		fmt.Printf("%s %s (%s)", change.Action, change.Definition.Name, change.Definition.ID)
+		if len(change.Diff) > 0 {
+			fmt.Printf(": %s", strings.Join(change.Diff, ", "))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d of %d accounts to change\n", len(pending), len(plan.Changes))
+	if *dryRun {
+		return 0
+	}
+
+	batch := accountdef.Apply(accounts, plan)
+	fmt.Printf("%d accounts changed\n", len(batch.Succeeded))
+	if err := batch.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// definitionFlags are the flags selecting the account definitions of the accounts commands.
+type definitionFlags struct {
+	file string
+	env  string
+	vars variables
+}
+
+func registerDefinitionFlags(fs *flag.FlagSet) *definitionFlags {
+	f := &definitionFlags{vars: variables{}}
+	fs.StringVar(&f.file, "f", "", "account definition file")
+	fs.StringVar(&f.env, "env", "", "environment of the overlay file to apply (i.e. prod applies accounts.prod.yaml for accounts.yaml)")
+	fs.Var(f.vars, "var", "template variable as NAME=VALUE (can be repeated)")
+	return f
+}
+
+// load returns the definitions or the exit status of the command if they can't be loaded.
+func (f *definitionFlags) load() ([]accountdef.Definition, int) {
+	if f.file == "" {
+		fmt.Fprintln(os.Stderr, "missing definition file (-f)")
+		return nil, 2
+	}
+
+	defs, err := accountdef.Load(f.file, accountdef.Options{Environment: f.env, Variables: f.vars})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, 1
+	}
+	return defs, 0
+}
+
+// variables collects the NAME=VALUE flags.
+type variables map[string]string
+
+func (v variables) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v variables) Set(pair string) error {
+	name, value, ok := strings.Cut(pair, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("%q is not in NAME=VALUE format", pair)
+	}
+	v[name] = value
+	return nil
+}
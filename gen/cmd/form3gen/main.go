@@ -0,0 +1,56 @@
+// Command form3gen generates a Form3 resource client from an OpenAPI definition.
+//
+// Usage:
+//
+//	form3gen -spec FILE -definition NAME -package NAME -url PATH -type TYPE [-name NAME] [-delete] [-out FILE]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"form3interview/gen"
+)
+
+func main() {
+	specPath := flag.String("spec", "", "OpenAPI (or Swagger 2.0) definition file in JSON or YAML")
+	res := gen.Resource{}
+	flag.StringVar(&res.Definition, "definition", "", "name of the resource data definition (i.e. AccountData)")
+	flag.StringVar(&res.Package, "package", "", "name of the generated Go package")
+	flag.StringVar(&res.Url, "url", "", "path of the resource collection (i.e. /organisation/accounts)")
+	flag.StringVar(&res.Type, "type", "", "JSON:API type of the resource (i.e. accounts)")
+	flag.StringVar(&res.Name, "name", "", "resource name used in docs and errors (default is the package name)")
+	flag.BoolVar(&res.Delete, "delete", false, "generate Delete and DeleteVersion for versioned resources")
+	out := flag.String("out", "", "output file (default is stdout)")
+	flag.Parse()
+
+	if *specPath == "" || res.Definition == "" || res.Package == "" || res.Url == "" || res.Type == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := gen.LoadSpec(*specPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	res.Source = filepath.Base(*specPath)
+	src, err := gen.Generate(spec, res)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*out, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
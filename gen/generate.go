@@ -0,0 +1,492 @@
+// Package gen generates Form3 resource clients from the published OpenAPI definitions.
+//
+// The generated package contains the resource models and a client built on the generic resource client
+// (see form3interview/internal/resource), so it has the same API and errors as the handwritten clients:
+// NewClient, Create, Fetch, List and optionally Delete and DeleteVersion.
+// Use the form3gen command to generate a package:
+//
+//	form3gen -spec limits.yaml -definition LimitData -package limit -url /limits -type limits -out pkg/limit/zz_generated.go
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var (
+	// ErrDefinitionNotFound definition is not in the spec
+	ErrDefinitionNotFound = errors.New("definition not found")
+	// ErrUnsupportedRef reference is not a local definition
+	ErrUnsupportedRef = errors.New("unsupported reference")
+	// ErrMissingEnvelopeField resource definition has no id, organisation_id, type, attributes (or version for deletable resources) field
+	ErrMissingEnvelopeField = errors.New("missing envelope field")
+)
+
+// envelopeFields are the JSON:API fields required by the generated client.
+var envelopeFields = []string{"id", "organisation_id", "type", "attributes"}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]string{"id": "ID"}
+
+// Resource describes the generated resource client.
+type Resource struct {
+	// Source is the name of the spec shown in the header of the generated file.
+	Source string
+	// Package is the name of the generated Go package.
+	Package string
+	// Definition is the name of the resource data definition (i.e. AccountData).
+	Definition string
+	// Url is the path of the resource collection (i.e. /organisation/accounts).
+	Url string
+	// Type is the JSON:API type of the resource (i.e. accounts).
+	Type string
+	// Name is the resource name used in the docs and errors (i.e. account). The lower cased package name is used if it's empty.
+	Name string
+	// Delete generates Delete and DeleteVersion methods for versioned resources.
+	Delete bool
+}
+
+type (
+	model struct {
+		Resource
+		Client      string
+		Data        string
+		Attributes  string
+		ErrNotFound string
+		ErrVersion  string
+		ErrExists   string
+		Structs     []structModel
+		Enums       []enumModel
+	}
+	structModel struct {
+		Name   string
+		Doc    string
+		Fields []fieldModel
+	}
+	fieldModel struct {
+		Name    string
+		Type    string
+		JSONTag string
+	}
+	enumModel struct {
+		Name   string
+		Values []enumValue
+	}
+	enumValue struct {
+		Name  string
+		Value string
+	}
+)
+
+// Generate returns the formatted Go source of the resource client.
+func Generate(spec *Spec, res Resource) ([]byte, error) {
+	if res.Name == "" {
+		res.Name = strings.ToLower(res.Package)
+	}
+
+	m := model{
+		Resource:    res,
+		Client:      lowerFirst(goName(res.Package)) + "Client",
+		Data:        goName(res.Definition),
+		ErrNotFound: "Err" + goName(res.Name) + "NotFound",
+		ErrVersion:  "ErrInvalid" + goName(res.Name) + "Version",
+		ErrExists:   "Err" + goName(res.Name) + "AlreadyExists",
+	}
+
+	data, err := spec.schema(res.Definition)
+	if err != nil {
+		return nil, err
+	}
+	required := append([]string{}, envelopeFields...)
+	if res.Delete {
+		required = append(required, "version")
+	}
+	for _, field := range required {
+		if _, ok := data.Properties[field]; !ok {
+			return nil, fmt.Errorf("%w: %s.%s", ErrMissingEnvelopeField, res.Definition, field)
+		}
+	}
+
+	b := builder{spec: spec, seen: map[string]bool{}}
+	if err := b.addStruct(m.Data, data); err != nil {
+		return nil, err
+	}
+	attributes, err := b.goType(m.Data, "attributes", data.Properties["attributes"])
+	if err != nil {
+		return nil, err
+	}
+	m.Attributes = strings.TrimPrefix(attributes, "*")
+	m.Structs, m.Enums = b.structs, b.enums
+
+	var buf bytes.Buffer
+	if err := clientTemplate.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// builder collects the Go types of the definitions reachable from the resource definition.
+type builder struct {
+	spec    *Spec
+	seen    map[string]bool
+	structs []structModel
+	enums   []enumModel
+}
+
+func (b *builder) addStruct(name string, schema Schema) error {
+	if b.seen[name] {
+		return nil
+	}
+	b.seen[name] = true
+
+	// the struct is reserved before the fields, so the nested types are generated after their parent
+	i := len(b.structs)
+	b.structs = append(b.structs, structModel{})
+
+	s := structModel{Name: name, Doc: strings.Join(strings.Fields(schema.Description), " ")}
+	for _, prop := range sortedKeys(schema.Properties) {
+		typ, err := b.goType(name, prop, schema.Properties[prop])
+		if err != nil {
+			return err
+		}
+		s.Fields = append(s.Fields, fieldModel{
+			Name:    goName(prop),
+			Type:    typ,
+			JSONTag: fmt.Sprintf("`json:\"%s,omitempty\"`", prop),
+		})
+	}
+	b.structs[i] = s
+	return nil
+}
+
+// goType returns the Go type of a property. The optional booleans and integers are pointers,
+// so their zero values can be sent, like in the handwritten models.
+func (b *builder) goType(parent, prop string, schema Schema) (string, error) {
+	if schema.Ref != "" {
+		name, err := refName(schema.Ref)
+		if err != nil {
+			return "", err
+		}
+		ref, err := b.spec.schema(name)
+		if err != nil {
+			return "", err
+		}
+		if ref.Type == "object" || len(ref.Properties) > 0 {
+			if err := b.addStruct(goName(name), ref); err != nil {
+				return "", err
+			}
+			return "*" + goName(name), nil
+		}
+		return b.goType(parent, name, ref)
+	}
+
+	switch schema.Type {
+	case "string":
+		if len(schema.Enum) > 0 {
+			return b.addEnum(parent+goName(prop), schema.Enum), nil
+		}
+		return "string", nil
+	case "integer":
+		return "*int64", nil
+	case "number":
+		return "*float64", nil
+	case "boolean":
+		return "*bool", nil
+	case "array":
+		if schema.Items == nil {
+			return "[]interface{}", nil
+		}
+		item, err := b.goType(parent, prop, *schema.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + strings.TrimPrefix(item, "*"), nil
+	case "object", "":
+		if len(schema.Properties) == 0 {
+			return "map[string]interface{}", nil
+		}
+		name := parent + goName(prop)
+		if err := b.addStruct(name, schema); err != nil {
+			return "", err
+		}
+		return "*" + name, nil
+	}
+	return "interface{}", nil
+}
+
+func (b *builder) addEnum(name string, values []string) string {
+	if b.seen[name] {
+		return name
+	}
+	b.seen[name] = true
+
+	e := enumModel{Name: name}
+	for _, value := range values {
+		e.Values = append(e.Values, enumValue{Name: name + goName(value), Value: value})
+	}
+	b.enums = append(b.enums, e)
+	return name
+}
+
+// goName converts a snake_case, kebab-case or space separated name to an exported Go name.
+func goName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '.'
+	})
+
+	var sb strings.Builder
+	for _, part := range parts {
+		if initialism, ok := initialisms[strings.ToLower(part)]; ok {
+			sb.WriteString(initialism)
+			continue
+		}
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func sortedKeys(m map[string]Schema) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var clientTemplate = template.Must(template.New("client").Parse(`// Code generated by form3gen{{if .Source}} from {{.Source}}{{end}}. DO NOT EDIT.
+
+// Package {{.Package}} provides Form3 client to manage {{.Type}}.
+package {{.Package}}
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+{{- if .Delete}}
+	"form3interview/pkg/conflict"
+{{- end}}
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	collectionUrl = "{{.Url}}"
+	resourceType  = "{{.Type}}"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// {{.ErrNotFound}} {{.Name}} not found
+	{{.ErrNotFound}} = errors.New("{{.Name}} not found")
+	// {{.ErrExists}} {{.Name}} with the same ID already exists
+	{{.ErrExists}} = fmt.Errorf("{{.Name}} %w", resource.ErrResourceAlreadyExists)
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+{{- if .Delete}}
+	// {{.ErrVersion}} {{.Name}} version not found
+	{{.ErrVersion}} = errors.New("invalid {{.Name}} version")
+{{- end}}
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type {{.Client}} struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 {{.Type}} created by NewClient.
+type Client = {{.Client}}
+
+// NewClient creates a client for managing Form3 {{.Type}}.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*{{.Client}}, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &{{.Client}}{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (c {{.Client}}) Close() error {
+	return resource.Close(c.client)
+}
+
+// Create a {{.Name}} with attributes.
+//
+// The request can be enriched by RequestEnricher
+func (c {{.Client}}) Create(attributes {{.Attributes}}, en ...re.RequestEnricher) (*{{.Data}}, error) {
+	newID, err := c.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	data := {{.Data}}{
+		ID:             newID.String(),
+		OrganisationID: c.config.OrganisationID.String(),
+		Type:           resourceType,
+		Attributes:     &attributes,
+	}
+
+	created, err := c.resource().Create(newID, data, en...)
+	if err != nil {
+		return nil, err
+	}
+	c.config.Log().Debugf("{{.Name}} %s created", data.ID)
+	return created, nil
+}
+
+// Fetch a {{.Name}} by it's ID.
+//
+// The request can be enriched by RequestEnricher
+func (c {{.Client}}) Fetch(id uuid.UUID, en ...re.RequestEnricher) (*{{.Data}}, error) {
+	return c.resource().Fetch(id, en...)
+}
+
+// List {{.Type}} page by page. Page numbers start from 0.
+//
+// The request can be enriched by RequestEnricher
+func (c {{.Client}}) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]{{.Data}}, error) {
+	return c.resource().List(pageNumber, pageSize, en...)
+}
+{{- if .Delete}}
+
+// Delete is a convenience function to delete a {{.Name}} by it's ID having the latest version.
+//
+// Under the hood it fetches the latest {{.Name}} and delete that with the specific version returned.
+// The request can be enriched by RequestEnricher
+func (c {{.Client}}) Delete(id uuid.UUID, en ...re.RequestEnricher) error {
+	if c.config.ReadOnly {
+		return ErrReadOnly
+	}
+
+	version, _, err := c.latestVersion(id, en...)
+	if err != nil {
+		return err
+	}
+	return c.DeleteVersion(id, version, en...)
+}
+
+// DeleteVersion deletes a {{.Name}} by it's ID having a specific version.
+//
+// A version conflict is resolved with the conflict policy of the config (see config.WithConflictPolicy).
+// The request can be enriched by RequestEnricher
+func (c {{.Client}}) DeleteVersion(id uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	err := c.config.ConflictPolicy.Resolve(conflict.Write{
+		ID:          id,
+		Version:     version,
+		ErrConflict: {{.ErrVersion}},
+		Do: func(version uint) error {
+			return c.resource().DeleteVersion(id, version, en...)
+		},
+		Latest: func() (uint, any, error) {
+			return c.latestVersion(id, en...)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	c.config.Log().Debugf("{{.Name}} %s deleted", id)
+	return nil
+}
+
+// latestVersion fetches the {{.Name}} and returns its current version.
+func (c {{.Client}}) latestVersion(id uuid.UUID, en ...re.RequestEnricher) (uint, any, error) {
+	data, err := c.Fetch(id, en...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	version := uint(0)
+	if data.Version != nil {
+		version = uint(*data.Version)
+	}
+	return version, data, nil
+}
+{{- end}}
+
+func (c {{.Client}}) resource() resource.Client[{{.Data}}] {
+	return resource.Client[{{.Data}}]{
+		HTTP:        c.client,
+		Config:      c.config,
+		Url:         collectionUrl,
+		ErrNotFound: {{.ErrNotFound}},
+		ErrAlreadyExists: {{.ErrExists}},
+{{- if .Delete}}
+		ErrInvalidVersion: {{.ErrVersion}},
+{{- end}}
+	}
+}
+{{range .Structs}}
+{{if .Doc}}// {{.Name}} {{.Doc}}
+{{end}}type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{.JSONTag}}
+{{- end}}
+}
+{{end}}
+{{- range .Enums}}
+type {{.Name}} string
+
+// Values of {{.Name}}.
+const (
+{{- $type := .Name}}
+{{- range .Values}}
+	{{.Name}} {{$type}} = "{{.Value}}"
+{{- end}}
+)
+{{end}}`))
@@ -0,0 +1,93 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type genTestSuite struct {
+	suite.Suite
+	spec *Spec
+}
+
+func TestGenTestSuite(t *testing.T) {
+	suite.Run(t, new(genTestSuite))
+}
+
+func (s *genTestSuite) SetupTest() {
+	spec, err := LoadSpec(filepath.Join("testdata", "limits.yaml"))
+	s.Require().NoError(err)
+	s.spec = spec
+}
+
+func (s *genTestSuite) TestGenerate() {
+	src, err := Generate(s.spec, Resource{
+		Source:     "limits.yaml",
+		Package:    "limit",
+		Definition: "LimitData",
+		Url:        "/limits",
+		Type:       "limits",
+		Delete:     true,
+	})
+	s.Require().NoError(err)
+
+	// regenerate with: go run ./gen/cmd/form3gen -spec gen/testdata/limits.yaml -definition LimitData -package limit -url /limits -type limits -delete -out gen/testdata/limit.go.golden
+	golden, err := os.ReadFile(filepath.Join("testdata", "limit.go.golden"))
+	s.Require().NoError(err)
+	s.Equal(string(golden), string(src))
+}
+
+func (s *genTestSuite) TestGenerateSupportsOpenAPI3Components() {
+	spec, err := ParseSpec([]byte(`{
+		"openapi": "3.0.0",
+		"components": {"schemas": {
+			"Thing": {"type": "object", "properties": {
+				"id": {"type": "string"}, "organisation_id": {"type": "string"}, "type": {"type": "string"},
+				"attributes": {"$ref": "#/components/schemas/ThingAttributes"}
+			}},
+			"ThingAttributes": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}}
+	}`))
+	s.Require().NoError(err)
+
+	src, err := Generate(spec, Resource{Package: "thing", Definition: "Thing", Url: "/things", Type: "things"})
+
+	s.Require().NoError(err)
+	s.Contains(string(src), "type ThingAttributes struct")
+	s.Contains(string(src), "ErrThingNotFound")
+	s.NotContains(string(src), "DeleteVersion")
+}
+
+func (s *genTestSuite) TestGenerateReturnsError() {
+	for _, test := range []struct {
+		name          string
+		resource      Resource
+		expectedError error
+	}{
+		{
+			name:          "definition not found",
+			resource:      Resource{Package: "limit", Definition: "Missing"},
+			expectedError: ErrDefinitionNotFound,
+		},
+		{
+			name:          "missing envelope field",
+			resource:      Resource{Package: "limit", Definition: "LimitAttributes"},
+			expectedError: ErrMissingEnvelopeField,
+		},
+	} {
+		s.Run(test.name, func() {
+			_, err := Generate(s.spec, test.resource)
+
+			s.ErrorIs(err, test.expectedError)
+		})
+	}
+}
+
+func (s *genTestSuite) TestGoName() {
+	s.Equal("OrganisationID", goName("organisation_id"))
+	s.Equal("PerScheme", goName("per_scheme"))
+	s.Equal("FPS", goName("FPS"))
+}
@@ -0,0 +1,68 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the part of an OpenAPI (or Swagger 2.0) document used by the generator.
+// Both JSON and YAML documents are supported.
+type Spec struct {
+	Definitions map[string]Schema `yaml:"definitions"`
+	Components  struct {
+		Schemas map[string]Schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// Schema is a JSON schema of a definition or property.
+type Schema struct {
+	Ref         string            `yaml:"$ref"`
+	Type        string            `yaml:"type"`
+	Format      string            `yaml:"format"`
+	Description string            `yaml:"description"`
+	Enum        []string          `yaml:"enum"`
+	Items       *Schema           `yaml:"items"`
+	Properties  map[string]Schema `yaml:"properties"`
+}
+
+// LoadSpec reads an OpenAPI document from a file.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSpec(data)
+}
+
+// ParseSpec parses an OpenAPI document.
+func ParseSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// schema returns a definition by it's name.
+func (s Spec) schema(name string) (Schema, error) {
+	if schema, ok := s.Definitions[name]; ok {
+		return schema, nil
+	}
+	if schema, ok := s.Components.Schemas[name]; ok {
+		return schema, nil
+	}
+	return Schema{}, fmt.Errorf("%w: %s", ErrDefinitionNotFound, name)
+}
+
+// refName returns the definition name of a local reference (i.e. #/definitions/Account gives Account).
+func refName(ref string) (string, error) {
+	for _, prefix := range []string{"#/definitions/", "#/components/schemas/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return strings.TrimPrefix(ref, prefix), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedRef, ref)
+}
@@ -1,27 +1,257 @@
 package config
 
 import (
+	"crypto"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+
+	"form3interview/pkg/auditlog"
+	"form3interview/pkg/auth"
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/clock"
+	"form3interview/pkg/conflict"
+	"form3interview/pkg/debuglog"
+	"form3interview/pkg/events"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/metrics"
+	"form3interview/pkg/ratelimit"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/retry"
+	"form3interview/pkg/signing"
 )
 
+var apiVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// parsers are the env var parsers of the config field types not supported by the env package.
+var parsers = map[reflect.Type]env.ParserFunc{
+	reflect.TypeOf(map[string]string{}): parseMap,
+}
+
 type ClientConfig struct {
-	OrganisationID  *uuid.UUID     `env:"ORGANISATION_ID"`
-	BaseUrl         *string        `env:"BASE_URL"`
+	OrganisationID *uuid.UUID `env:"ORGANISATION_ID"`
+	BaseUrl        *string    `env:"BASE_URL"`
+	// Version is the API version path (i.e. v1) appended to the base url. The base url is used as is if it's nil.
+	Version         *string        `env:"API_VERSION"`
 	Timeout         *time.Duration `env:"TIMEOUT" envDefault:"5s"`
 	MaxConns        int            `env:"MAX_CONNS" envDefault:"100"`
 	IdleConnTimeout *time.Duration `env:"IDLE_CONN_TIMEOUT" envDefault:"90s"`
+	PollInterval    *time.Duration `env:"POLL_INTERVAL" envDefault:"1s"`
+	PollTimeout     *time.Duration `env:"POLL_TIMEOUT" envDefault:"30s"`
+	StrictMode      bool           `env:"STRICT_MODE" envDefault:"false"`
+	ReadOnly        bool           `env:"READ_ONLY" envDefault:"false"`
+	SingleFlight    bool           `env:"SINGLE_FLIGHT" envDefault:"false"`
+	// ReturnExistingOnCreate makes Create return the existing resource instead of an error when the ID is taken.
+	ReturnExistingOnCreate bool `env:"RETURN_EXISTING_ON_CREATE" envDefault:"false"`
+	// BulkheadSize limits the concurrent requests of each resource client, they are not limited if it's 0.
+	// The requests wait at most BulkheadTimeout for a free slot, or as long as their context allows if it's nil.
+	BulkheadSize    int            `env:"BULKHEAD_SIZE" envDefault:"0"`
+	BulkheadTimeout *time.Duration `env:"BULKHEAD_TIMEOUT"`
+	// StaleReadMaxAge enables serving the cached responses of the Fetch calls not older than it when the API is failing.
+	// The stale reads are disabled if it's nil, the age is not limited if it's not positive.
+	StaleReadMaxAge *time.Duration `env:"STALE_READ_MAX_AGE"`
+	// AfterHookBodyLimit is the number of the response body bytes passed to the AfterHook of the RequestEnricher.
+	AfterHookBodyLimit int `env:"AFTER_HOOK_BODY_LIMIT" envDefault:"65536"`
+	// ErrorBodyLimit is the number of the body bytes of the unsuccessful responses captured in the APIErrors.
+	// The rest of the body is not read, the body is not limited if it's not positive.
+	ErrorBodyLimit int `env:"ERROR_BODY_LIMIT" envDefault:"65536"`
+	// SlowRequestThreshold enables logging the requests taking longer than it, they are not logged if it's nil.
+	SlowRequestThreshold *time.Duration `env:"SLOW_REQUEST_THRESHOLD"`
+	UserAgent            *string        `env:"USER_AGENT"`
+	// The transport timeouts keep the default transport's values when they are nil.
+	DialTimeout           *time.Duration `env:"DIAL_TIMEOUT"`
+	TLSHandshakeTimeout   *time.Duration `env:"TLS_HANDSHAKE_TIMEOUT"`
+	ResponseHeaderTimeout *time.Duration `env:"RESPONSE_HEADER_TIMEOUT"`
+	ExpectContinueTimeout *time.Duration `env:"EXPECT_CONTINUE_TIMEOUT"`
+	DisableKeepAlives     bool           `env:"DISABLE_KEEP_ALIVES" envDefault:"false"`
+	// KeepAlive is the TCP keep-alive period, keep-alives are disabled if it's negative.
+	KeepAlive         *time.Duration `env:"KEEP_ALIVE"`
+	ForceAttemptHTTP2 *bool          `env:"FORCE_ATTEMPT_HTTP2"`
+	// Headers are added to every request (i.e. X-Client-Version:1.2.0,X-Tenant:acme).
+	Headers map[string]string `env:"HEADERS"`
+	// ProxyUrl is the proxy used for all requests, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY env vars are used if it's nil.
+	ProxyUrl *url.URL `env:"PROXY_URL"`
+	// TLSConfig is the base TLS config of the transport, the CA cert, client cert and min version settings are applied on a clone of it.
+	TLSConfig      *tls.Config
+	CACertFile     *string `env:"CA_CERT_FILE"`
+	ClientCertFile *string `env:"CLIENT_CERT_FILE"`
+	ClientKeyFile  *string `env:"CLIENT_KEY_FILE"`
+	MinTLSVersion  uint16
+	// Transport is the base transport of the clients. The connection settings are applied on a clone of it
+	// if it's an *http.Transport, otherwise it's used as is. The default transport is used if it's nil.
+	Transport http.RoundTripper
+	// TransportWrappers are layered over the base transport in order.
+	TransportWrappers []func(http.RoundTripper) http.RoundTripper
+	// Middleware wraps every attempt of the requests of the clients in order (see re.Wrap).
+	Middleware []re.Middleware
+	// StatusHandlers map the unsuccessful responses with their status code to errors before the default mapping
+	// of the clients. The default mapping is used if the handler returns nil.
+	StatusHandlers map[int]func(*http.Response) error
+	// OnDeprecation is called with the deprecations announced by the responses (see result.DeprecationOf).
+	// The deprecation of each operation is logged once at warn level if it's nil.
+	OnDeprecation func(operation string, deprecation result.Deprecation)
+	// SharedTransport is the transport shared by the clients created by the form3 facade. It is used as is.
+	// A new transport is created by each client if it's nil.
+	SharedTransport http.RoundTripper
+	// APIKeyFile is the file of the API key sent as a bearer token. It's re-read when it changes.
+	APIKeyFile *string `env:"API_KEY_FILE"`
+	// PrivateKeyFile is the PEM file of the private key of the request signatures. It's re-read when it changes.
+	PrivateKeyFile *string `env:"PRIVATE_KEY_FILE"`
+	// RetryPolicy is the retry policy of the requests, retry.DefaultPolicy is used if it's nil.
+	RetryPolicy *retry.Policy
+	// ConflictPolicy resolves the version conflicts of the versioned writes, they fail by default.
+	ConflictPolicy conflict.Policy
+	// RateLimiter limits the rate of the requests, they are not limited if it's nil. The clients created by
+	// the form3 facade share it.
+	RateLimiter *ratelimit.Limiter
+	// CircuitBreaker short-circuits the requests while the API is failing, it's disabled if it's nil. The clients
+	// created by the form3 facade share it.
+	CircuitBreaker *circuitbreaker.Breaker
+	// AuditSink records the mutating calls, they are not recorded if it's nil.
+	AuditSink auditlog.Sink
+	// Events receives the lifecycle events of the clients, they are not published if it's nil. The clients
+	// created by the form3 facade share it.
+	Events *events.Bus
+	// Metrics creates the instruments the clients report their metrics into, they are not collected if it's nil.
+	Metrics metrics.Metrics
+	// Signer signs the requests (i.e. with a KMS or an HSM), it takes precedence over the SigningKeyID.
+	Signer signing.Signer
+	// VerifyResponses enables verifying the Digest and Signature headers of the responses when they are present.
+	VerifyResponses bool `env:"VERIFY_RESPONSES" envDefault:"false"`
+	// ResponseVerificationKey is the public key of the response signatures, only the digests are verified if it's nil.
+	ResponseVerificationKey crypto.PublicKey
+	// ClientID is the OAuth client ID of the client credentials grant. The bearer token is requested
+	// from the TokenUrl if it's set.
+	ClientID *string `env:"CLIENT_ID"`
+	// ClientSecret is the OAuth client secret of the client credentials grant.
+	ClientSecret *string `env:"CLIENT_SECRET"`
+	// TokenUrl is the URL of the OAuth token endpoint.
+	TokenUrl *string `env:"TOKEN_URL"`
+	// CredentialsProvider provides the bearer token and the signing key of the requests.
+	CredentialsProvider auth.CredentialsProvider
+	// SigningKeyID is the ID of the key the requests are signed with. The requests are not signed if it's nil.
+	SigningKeyID *string `env:"SIGNING_KEY_ID"`
+	// SigningKey is the private key of the request signatures, it's loaded from the PrivateKeyFile if it's nil.
+	SigningKey crypto.Signer
+	// Logger receives the log entries of the clients, the global zerolog logger is used if it's nil.
+	Logger logger.Logger
+	// LogLevel is the minimum level of the logged entries.
+	LogLevel logger.Level `env:"LOG_LEVEL" envDefault:"debug"`
+	// DebugLog switches the request/response debug logging on and off at runtime, it's disabled if it's nil.
+	DebugLog *debuglog.Toggle
+	// UUIDGenerator generates the IDs of the created resources, random (v4) UUIDs are generated if it's nil.
+	UUIDGenerator func() (uuid.UUID, error)
+	// Clock is the time source of the clients (retry delays, polling, health checks, rate limiter, bulkhead and the
+	// logged durations), the system clock is used if it's nil.
+	Clock clock.Clock
+	// HttpClient is used as is by the clients when it's set, so the timeout and connection settings don't apply to it.
+	HttpClient *http.Client
 }
 
 func NewConfig() ClientConfig {
 	cfg := ClientConfig{}
-	if err := env.Parse(&cfg, env.Options{
+	if err := env.ParseWithFuncs(&cfg, parsers, env.Options{
 		Prefix: "FORM3_",
 	}); err != nil {
 		log.Warn().Err(err).Msg("failed to init config with env vars")
 	}
 	return cfg
 }
+
+// parseMap parses comma separated key:value pairs (i.e. X-Tenant:acme,X-Client-Version:1.2.0).
+func parseMap(value string) (interface{}, error) {
+	m := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid key:value pair %q", pair)
+		}
+		m[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return m, nil
+}
+
+// Log returns the printer of the configured logger filtered by the log level.
+func (c ClientConfig) Log() logger.Printer {
+	l := c.Logger
+	if l == nil {
+		l = logger.Zerolog()
+	}
+	return logger.Printer{Logger: logger.WithMinLevel(l, c.LogLevel)}
+}
+
+// Retry returns the configured retry policy or the default one.
+func (c ClientConfig) Retry() retry.Policy {
+	if c.RetryPolicy != nil {
+		return *c.RetryPolicy
+	}
+	return retry.DefaultPolicy()
+}
+
+// NewID generates the ID of a new resource with the configured generator or a random (v4) UUID.
+func (c ClientConfig) NewID() (uuid.UUID, error) {
+	if c.UUIDGenerator != nil {
+		return c.UUIDGenerator()
+	}
+	return uuid.NewRandom()
+}
+
+// ClockOrSystem returns the configured clock or the system clock.
+func (c ClientConfig) ClockOrSystem() clock.Clock {
+	return clock.OrSystem(c.Clock)
+}
+
+// APIVersion returns the configured API version or the API version segment (i.e. v1) of the base url
+// or an empty string if it has none.
+func (c ClientConfig) APIVersion() string {
+	if c.Version != nil {
+		return strings.Trim(*c.Version, "/")
+	}
+	if c.BaseUrl == nil {
+		return ""
+	}
+
+	u, err := url.Parse(*c.BaseUrl)
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if apiVersionPattern.MatchString(segments[i]) {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// Url returns the url of an API path (i.e. /organisation/accounts). If the API version is configured it replaces
+// the version segment at the end of the base url (if any), so http://localhost:8080/v1 with version v2 targets
+// http://localhost:8080/v2.
+func (c ClientConfig) Url(path string) string {
+	base := strings.TrimRight(*c.BaseUrl, "/")
+	if c.Version != nil {
+		if i := strings.LastIndex(base, "/"); i >= 0 && apiVersionPattern.MatchString(base[i+1:]) {
+			base = base[:i]
+		}
+		base += "/" + strings.Trim(*c.Version, "/")
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return base + path
+}
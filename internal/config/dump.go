@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/ratelimit"
+	"form3interview/pkg/retry"
+)
+
+const (
+	redacted   = "REDACTED"
+	notSet     = "<not set>"
+	defaultVal = "<default>"
+)
+
+// sensitiveHeaderParts are the parts of the header names which may carry secrets.
+var sensitiveHeaderParts = []string{"authorization", "cookie", "key", "secret", "signature", "token"}
+
+// String returns the effective config in the key: value format of the config files with the secrets redacted,
+// so it can be logged while debugging connection issues.
+func (c ClientConfig) String() string {
+	var b strings.Builder
+	line := func(key string, value interface{}) {
+		fmt.Fprintf(&b, "%s: %v\n", key, value)
+	}
+
+	line("organisation_id", orNotSet(c.OrganisationID))
+	line("base_url", orNotSet(c.BaseUrl))
+	line("api_version", orDefault(c.Version))
+	line("timeout", orNotSet(c.Timeout))
+	line("max_conns", c.MaxConns)
+	line("idle_conn_timeout", orNotSet(c.IdleConnTimeout))
+	line("dial_timeout", orDefault(c.DialTimeout))
+	line("tls_handshake_timeout", orDefault(c.TLSHandshakeTimeout))
+	line("response_header_timeout", orDefault(c.ResponseHeaderTimeout))
+	line("expect_continue_timeout", orDefault(c.ExpectContinueTimeout))
+	line("disable_keep_alives", c.DisableKeepAlives)
+	line("keep_alive", orDefault(c.KeepAlive))
+	line("force_attempt_http2", orDefault(c.ForceAttemptHTTP2))
+	line("poll_interval", orNotSet(c.PollInterval))
+	line("poll_timeout", orNotSet(c.PollTimeout))
+	line("strict_mode", c.StrictMode)
+	line("read_only", c.ReadOnly)
+	line("single_flight", c.SingleFlight)
+	line("return_existing_on_create", c.ReturnExistingOnCreate)
+	line("bulkhead_size", c.BulkheadSize)
+	line("bulkhead_timeout", orNotSet(c.BulkheadTimeout))
+	line("stale_read_max_age", orNotSet(c.StaleReadMaxAge))
+	line("slow_request_threshold", orNotSet(c.SlowRequestThreshold))
+	line("after_hook_body_limit", c.AfterHookBodyLimit)
+	line("error_body_limit", c.ErrorBodyLimit)
+	line("user_agent", orDefault(c.UserAgent))
+	line("headers", redactHeaders(c.Headers))
+	if c.ProxyUrl != nil {
+		line("proxy_url", c.ProxyUrl.Redacted())
+	} else {
+		line("proxy_url", "<from env>")
+	}
+	line("api_key_file", orNotSet(c.APIKeyFile))
+	line("private_key_file", orNotSet(c.PrivateKeyFile))
+	line("client_credentials", clientCredentials(c))
+	line("verify_responses", c.VerifyResponses)
+	line("response_verification_key", customOrDefault(c.ResponseVerificationKey != nil, c.ResponseVerificationKey))
+	line("credentials_provider", customOrDefault(c.CredentialsProvider != nil, c.CredentialsProvider))
+	line("signing_key_id", orNotSet(c.SigningKeyID))
+	line("signing_key", customOrDefault(c.SigningKey != nil, c.SigningKey))
+	line("signer", customOrDefault(c.Signer != nil, c.Signer))
+	line("retry", retryPolicy(c.Retry()))
+	line("conflict_policy", c.ConflictPolicy)
+	line("rate_limit", rateLimit(c.RateLimiter))
+	line("circuit_breaker", circuitBreaker(c.CircuitBreaker))
+	line("audit_sink", customOrDefault(c.AuditSink != nil, c.AuditSink))
+	line("event_bus", customOrDefault(c.Events != nil, c.Events))
+	line("metrics", customOrDefault(c.Metrics != nil, c.Metrics))
+	line("ca_cert_file", orNotSet(c.CACertFile))
+	line("client_cert_file", orNotSet(c.ClientCertFile))
+	line("client_key_file", orNotSet(c.ClientKeyFile))
+	if c.MinTLSVersion != 0 {
+		line("min_tls_version", TLSVersionName(c.MinTLSVersion))
+	} else {
+		line("min_tls_version", defaultVal)
+	}
+	line("tls_config", customOrDefault(c.TLSConfig != nil, c.TLSConfig))
+	line("transport", customOrDefault(c.Transport != nil, c.Transport))
+	line("transport_wrappers", len(c.TransportWrappers))
+	line("middleware", len(c.Middleware))
+	line("status_handlers", len(c.StatusHandlers))
+	line("on_deprecation", customOrDefault(c.OnDeprecation != nil, c.OnDeprecation))
+	line("logger", customOrDefault(c.Logger != nil, c.Logger))
+	line("log_level", c.LogLevel)
+	line("debug_log", c.DebugLog)
+	line("uuid_generator", customOrDefault(c.UUIDGenerator != nil, c.UUIDGenerator))
+	line("clock", customOrDefault(c.Clock != nil, c.Clock))
+	line("http_client", customOrDefault(c.HttpClient != nil, c.HttpClient))
+	return b.String()
+}
+
+func orNotSet[T any](v *T) interface{} {
+	if v == nil {
+		return notSet
+	}
+	return *v
+}
+
+func orDefault[T any](v *T) interface{} {
+	if v == nil {
+		return defaultVal
+	}
+	return *v
+}
+
+func retryPolicy(p retry.Policy) string {
+	if !p.Enabled() {
+		return "disabled"
+	}
+	return fmt.Sprintf("max_attempts=%d base_delay=%s max_delay=%s jitter=%g status_codes=%v max_retry_after=%s mutations=%t",
+		p.MaxAttempts, p.BaseDelay, p.MaxDelay, p.Jitter, p.RetryableStatusCodes, p.MaxRetryAfter, p.RetryMutations)
+}
+
+func rateLimit(l *ratelimit.Limiter) string {
+	if l == nil {
+		return "disabled"
+	}
+	return fmt.Sprintf("rps=%g burst=%d", l.Rps(), l.Burst())
+}
+
+func circuitBreaker(b *circuitbreaker.Breaker) string {
+	if b == nil {
+		return "disabled"
+	}
+	s := b.Settings()
+	return fmt.Sprintf("failure_threshold=%d open_duration=%s half_open_probes=%d",
+		s.FailureThreshold, s.OpenDuration, s.HalfOpenProbes)
+}
+
+// clientCredentials returns the client ID and the token url, the client secret is never dumped.
+func clientCredentials(c ClientConfig) string {
+	if c.ClientID == nil {
+		return notSet
+	}
+	return fmt.Sprintf("%s at %s", *c.ClientID, orNotSet(c.TokenUrl))
+}
+
+func customOrDefault(set bool, v interface{}) string {
+	if !set {
+		return defaultVal
+	}
+	return fmt.Sprintf("custom (%T)", v)
+}
+
+func redactHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		value := headers[name]
+		if isSensitiveHeader(name) {
+			value = redacted
+		}
+		pairs = append(pairs, name+":"+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range sensitiveHeaderParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// TLSVersionName returns the name of the TLS version, i.e. TLS 1.3. Unknown versions are returned as hex numbers.
+func TLSVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04X", version)
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type tlsVersionTestSuite struct {
+	suite.Suite
+}
+
+func TestTLSVersionTestSuite(t *testing.T) {
+	suite.Run(t, new(tlsVersionTestSuite))
+}
+
+func (s *tlsVersionTestSuite) TestTLSVersionName() {
+	s.Equal("TLS 1.0", TLSVersionName(tls.VersionTLS10))
+	s.Equal("TLS 1.1", TLSVersionName(tls.VersionTLS11))
+	s.Equal("TLS 1.2", TLSVersionName(tls.VersionTLS12))
+	s.Equal("TLS 1.3", TLSVersionName(tls.VersionTLS13))
+	s.Equal("0x0305", TLSVersionName(0x0305))
+}
@@ -3,73 +3,566 @@ package requestenricher
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"path"
+	"runtime/pprof"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/google/uuid"
+
+	"form3interview/pkg/auditlog"
+	"form3interview/pkg/bulkhead"
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/clock"
+	"form3interview/pkg/events"
+	"form3interview/pkg/metrics"
 	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/retry"
+)
+
+// onErrorKey is the context key of the OnError hook of the RequestEnricher.
+type onErrorKey struct{}
+
+var (
+	// ErrClosed the request was sent with a closed client
+	ErrClosed = errors.New("client is closed")
+	// ErrTimeout the request timed out
+	ErrTimeout = errors.New("timeout")
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = errors.New("connection error")
 )
 
+// transportError classifies a network error as ErrTimeout or ErrConnection and wraps its cause.
+type transportError struct {
+	kind  error
+	cause error
+}
+
+func (e *transportError) Error() string {
+	return fmt.Sprintf("%s: %s", e.kind, e.cause)
+}
+
+// Is reports whether the target is the kind of the error.
+func (e *transportError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Unwrap returns the cause of the error.
+func (e *transportError) Unwrap() error {
+	return e.cause
+}
+
+// classify wraps the timeouts into ErrTimeout and the network errors into ErrConnection, other errors are
+// returned as is.
+func classify(err error) error {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &transportError{kind: ErrTimeout, cause: err}
+	case errors.Is(err, context.Canceled):
+		return err
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return &transportError{kind: ErrConnection, cause: err}
+	}
+	return err
+}
+
+// lifecycle tracks the in-flight requests of a client and its copies, so Close can wait for them.
+type lifecycle struct {
+	mu       sync.RWMutex
+	closed   bool
+	inFlight sync.WaitGroup
+	onClose  []func()
+}
+
+// begin registers an in-flight request, it fails with ErrClosed if the client is closed.
+func (l *lifecycle) begin() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return ErrClosed
+	}
+	l.inFlight.Add(1)
+	return nil
+}
+
+func (l *lifecycle) end() {
+	if l != nil {
+		l.inFlight.Done()
+	}
+}
+
 type EnrichedHttpClient struct {
-	client http.Client
+	client       http.Client
+	header       http.Header
+	prepare      []func(*http.Request) error
+	newKey       func() (string, error)
+	audit        auditlog.Sink
+	retry        retry.Policy
+	onRetry      func()
+	clock        clock.Clock
+	stale        *StaleCache
+	onStale      func()
+	metrics      *metrics.Recorder
+	events       *events.Bus
+	bodyLimit    int
+	middleware   []re.Middleware
+	slow         time.Duration
+	onSlow       func(req *http.Request, duration time.Duration, timing result.Timing)
+	onDeprecated func(req *http.Request, deprecation result.Deprecation)
+	lifecycle    *lifecycle
 }
 
 func EnrichClient(client http.Client) EnrichedHttpClient {
-	return EnrichedHttpClient{client: client}
+	return EnrichedHttpClient{client: client, clock: clock.System, lifecycle: &lifecycle{}}
+}
+
+// WithDefaultHeader returns a copy of the client which adds the header to every request.
+// Headers already set on the request are not overridden.
+func (c EnrichedHttpClient) WithDefaultHeader(header http.Header) EnrichedHttpClient {
+	c.header = header
+	return c
+}
+
+// WithPrepare returns a copy of the client which calls fn with every attempt of the requests before it's sent (i.e. to
+// authorize or sign it), so a retried request gets a fresh authorization and signature. The request header can be
+// modified, it's not shared with the caller or the other attempts. The request fails if fn returns an error.
+func (c EnrichedHttpClient) WithPrepare(fn func(*http.Request) error) EnrichedHttpClient {
+	c.prepare = append(append([]func(*http.Request) error{}, c.prepare...), fn)
+	return c
+}
+
+// WithIdempotencyKeys returns a copy of the client which sends a key generated by newKey in the Idempotency-Key
+// header of the mutating requests, unless the key is set by the RequestEnricher or the request.
+func (c EnrichedHttpClient) WithIdempotencyKeys(newKey func() (string, error)) EnrichedHttpClient {
+	c.newKey = newKey
+	return c
+}
+
+// WithAuditSink returns a copy of the client which records the mutating requests into the sink.
+func (c EnrichedHttpClient) WithAuditSink(sink auditlog.Sink) EnrichedHttpClient {
+	c.audit = sink
+	return c
+}
+
+// WithRetry returns a copy of the client which retries the failed requests with the policy.
+// onRetry (if it's not nil) is called before every retry.
+func (c EnrichedHttpClient) WithRetry(policy retry.Policy, onRetry func()) EnrichedHttpClient {
+	c.retry = policy
+	c.onRetry = onRetry
+	return c
+}
+
+// WithClock returns a copy of the client which waits for the delays of the retries and measures the durations of
+// the slow requests and the audit entries on the clock.
+func (c EnrichedHttpClient) WithClock(clk clock.Clock) EnrichedHttpClient {
+	c.clock = clk
+	return c
+}
+
+// WithStaleReads returns a copy of the client which caches the successful responses of the GET requests marked
+// by AllowStale and serves them when the API fails with a 5xx response or the circuit breaker is open. onStale
+// (if it's not nil) is called when a cached response is served.
+func (c EnrichedHttpClient) WithStaleReads(cache *StaleCache, onStale func()) EnrichedHttpClient {
+	c.stale = cache
+	c.onStale = onStale
+	return c
+}
+
+// WithMetrics returns a copy of the client which records its requests, retries and connections into the recorder.
+func (c EnrichedHttpClient) WithMetrics(recorder *metrics.Recorder) EnrichedHttpClient {
+	c.metrics = recorder
+	return c
+}
+
+// WithEvents returns a copy of the client which publishes the retries and the rate limited responses to the bus.
+func (c EnrichedHttpClient) WithEvents(bus *events.Bus) EnrichedHttpClient {
+	c.events = bus
+	return c
+}
+
+// WithMiddleware returns a copy of the client which sends every attempt of the requests through the middlewares,
+// after the request is prepared (i.e. authorized and signed) and inside the retries. The first middleware is the
+// outermost (see re.Wrap). The retrying middlewares must check retry.Retrying, so they don't nest the retries.
+func (c EnrichedHttpClient) WithMiddleware(middleware ...re.Middleware) EnrichedHttpClient {
+	c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], middleware...)
+	return c
+}
+
+// WithAfterHookBody returns a copy of the client which passes the first limit bytes of the response body to the
+// AfterHook of the RequestEnricher. The AfterHook gets an empty body if the limit is not positive.
+func (c EnrichedHttpClient) WithAfterHookBody(limit int) EnrichedHttpClient {
+	c.bodyLimit = limit
+	return c
+}
+
+// WithSlowRequests returns a copy of the client which calls onSlow with the requests taking longer than the
+// threshold (including the retries) and the time breakdown of their last attempt. The requests are traced for it.
+func (c EnrichedHttpClient) WithSlowRequests(threshold time.Duration, onSlow func(req *http.Request, duration time.Duration, timing result.Timing)) EnrichedHttpClient {
+	c.slow = threshold
+	c.onSlow = onSlow
+	return c
+}
+
+// WithDeprecations returns a copy of the client which calls onDeprecated with the requests whose responses
+// announce the deprecation of their endpoint (see result.DeprecationOf). The deprecations are published to the
+// bus of WithEvents too.
+func (c EnrichedHttpClient) WithDeprecations(onDeprecated func(req *http.Request, deprecation result.Deprecation)) EnrichedHttpClient {
+	c.onDeprecated = onDeprecated
+	return c
+}
+
+// OnClose returns a copy of the client which calls fn when the client is closed (i.e. to close the idle
+// connections of its transport). The copies share the close functions of the client they are created from.
+func (c EnrichedHttpClient) OnClose(fn func()) EnrichedHttpClient {
+	if c.lifecycle != nil {
+		c.lifecycle.mu.Lock()
+		c.lifecycle.onClose = append(c.lifecycle.onClose, fn)
+		c.lifecycle.mu.Unlock()
+	}
+	return c
+}
+
+// Close fails the further requests of the client and all its copies with ErrClosed, waits for the in-flight
+// requests and their hooks to finish and calls the OnClose functions. Closing a closed client does nothing.
+func (c EnrichedHttpClient) Close() error {
+	l := c.lifecycle
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	onClose := l.onClose
+	l.mu.Unlock()
+
+	l.inFlight.Wait()
+	for _, fn := range onClose {
+		fn()
+	}
+	return nil
 }
 
+// Do sends the request with the enrichers combined by re.Chain. The enricher runs through its middlewares (see
+// re.RequestEnricher.CallMiddleware): its call part wraps the whole call with the retries, and its attempt part
+// wraps every attempt outside the middlewares of the client.
 func (c EnrichedHttpClient) Do(req *http.Request, enricher ...re.RequestEnricher) (*http.Response, error) {
-	req = req.WithContext(c.getCtx(enricher...))
+	en := re.Chain(enricher...)
+	stale := staleAllowed(req.Context())
+	return en.CallMiddleware()(re.DoerFunc(func(req *http.Request) (resp *http.Response, err error) {
+		if err := c.lifecycle.begin(); err != nil {
+			return nil, err
+		}
+		defer c.lifecycle.end()
 
-	c.getBeforeHook(enricher...)()
-	resp, err := c.client.Do(req)
-	if err != nil {
+		// the middlewares and the metrics measure the durations on the clock of the client
+		ctx := clock.NewContext(req.Context(), c.clock)
+		if en.OnError != nil {
+			// the clients report the errors mapped from the responses with ReportError
+			ctx = context.WithValue(ctx, onErrorKey{}, en.OnError)
+		}
+		if stale {
+			ctx = context.WithValue(ctx, staleReadKey{}, true)
+		}
+		// the goroutine is labeled with the operation, so the profiles of the services can attribute the cost to it
+		labels := pprof.Labels("operation", metrics.Operation(req), "resource", metrics.Resource(req))
+		pprof.Do(ctx, labels, func(ctx context.Context) {
+			resp, err = c.do(req.WithContext(ctx), en)
+		})
 		return resp, err
+	})).Do(req)
+}
+
+func (c EnrichedHttpClient) do(req *http.Request, en re.RequestEnricher) (*http.Response, error) {
+	req = c.metrics.Trace(req)
+	done := c.metrics.Begin(req)
+	start := c.clock.Now()
+	if err := c.prepareRequest(req); err != nil {
+		c.record(req, nil, err, start)
+		done(nil, err)
+		return nil, err
 	}
 
-	enResp := cloneResponse(resp)
-	c.getAfterHook(enricher...)(enResp)
+	resp, err := c.send(req, en)
+	if c.stale != nil && req.Method == http.MethodGet && staleAllowed(req.Context()) {
+		resp, err = c.serveStale(req, resp, err, en)
+	}
+	err = classify(err)
+	if duration := c.clock.Now().Sub(start); c.onSlow != nil && duration > c.slow {
+		timing, _ := re.TimingOf(resp)
+		c.onSlow(req, duration, timing)
+	}
+	c.record(req, resp, err, start)
+	done(resp, err)
+	c.checkDeprecation(req, resp)
 	return resp, err
 }
 
-func (c EnrichedHttpClient) getCtx(en ...re.RequestEnricher) context.Context {
-	if len(en) == 0 || en[0].Ctx == nil {
-		return context.TODO()
+// checkDeprecation reports the deprecation announced by the response if there is any.
+func (c EnrichedHttpClient) checkDeprecation(req *http.Request, resp *http.Response) {
+	if c.onDeprecated == nil && c.events == nil {
+		return
+	}
+	deprecation, ok := result.DeprecationOf(resp)
+	if !ok {
+		return
+	}
+	c.events.Publish(events.Deprecated{Operation: metrics.Operation(req), Deprecation: deprecation})
+	if c.onDeprecated != nil {
+		c.onDeprecated(req, deprecation)
+	}
+}
+
+// serveStale caches the successful response or replaces the failed one with the cached response of the request.
+// The AfterHook is called with the cached response too, so the callers can tell it's stale.
+func (c EnrichedHttpClient) serveStale(req *http.Request, resp *http.Response, err error, en re.RequestEnricher) (*http.Response, error) {
+	if err == nil && resp.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		c.stale.store(req, resp, body)
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
 	}
 
-	return en[0].Ctx
+	failing := errors.Is(err, circuitbreaker.ErrCircuitOpen) || (err == nil && resp.StatusCode >= http.StatusInternalServerError)
+	if !failing {
+		return resp, err
+	}
+	cached, ok := c.stale.load(req)
+	if !ok {
+		return resp, err
+	}
+
+	if resp != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	if en.AfterHook != nil {
+		if err := re.RecoverHook("AfterHook", func() error { en.AfterHook(re.PeekResponse(cached, c.bodyLimit)); return nil }); err != nil {
+			cached.Body.Close()
+			return nil, err
+		}
+	}
+	if c.onStale != nil {
+		c.onStale()
+	}
+	return cached, nil
 }
 
-func (c EnrichedHttpClient) getBeforeHook(en ...re.RequestEnricher) func() {
-	if len(en) == 0 || en[0].BeforeHook == nil {
-		return func() {}
+// send sends the request and retries it with the retry policy. Every attempt is prepared by the prepare functions
+// and the attempt middleware of the enricher runs around it. The last attempt is returned when the retry wouldn't start before the deadline of the context.
+// The middlewares get the clock of the client and whether the requests are retried in the request context.
+func (c EnrichedHttpClient) send(req *http.Request, en re.RequestEnricher) (*http.Response, error) {
+	doer := re.Wrap(&c.client, append([]re.Middleware{en.AttemptMiddleware(c.bodyLimit)}, c.middleware...)...)
+	if c.retry.Enabled() {
+		req = req.WithContext(retry.WithRetrying(req.Context()))
 	}
+	c.retry.Budget.Deposit()
+	for attempt := 1; ; attempt++ {
+		prepared, err := c.prepareAttempt(req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := doer.Do(c.trace(prepared, en))
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			c.events.Publish(events.RateLimited{Operation: metrics.Operation(req), RetryAfter: retry.RetryAfter(resp)})
+		}
+		if !c.shouldRetry(req, resp, err, attempt) {
+			return resp, err
+		}
+		delay, ok := c.retry.Delay(attempt, resp)
+		if !ok || retry.PastDeadline(req.Context(), delay) {
+			return resp, err
+		}
+		retried := events.RequestRetried{Operation: metrics.Operation(req), Attempt: attempt, Err: err, Delay: delay}
+		if resp != nil {
+			retried.StatusCode = resp.StatusCode
+		}
+		withdrawn := c.retry.Budget.Withdraw()
 
-	return en[0].BeforeHook
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if !withdrawn {
+			exhausted := &retry.BudgetExhaustedError{Err: err}
+			if resp != nil {
+				exhausted.StatusCode = resp.StatusCode
+			}
+			return nil, exhausted
+		}
+		if err := clock.Sleep(req.Context(), c.clock, delay); err != nil {
+			return nil, err
+		}
+		if req, err = retry.Rewind(req); err != nil {
+			return nil, err
+		}
+		c.metrics.Retry(req)
+		c.events.Publish(retried)
+		if c.onRetry != nil {
+			c.onRetry()
+		}
+	}
 }
 
-func (c EnrichedHttpClient) getAfterHook(en ...re.RequestEnricher) func(*http.Response) {
-	if len(en) == 0 || en[0].AfterHook == nil {
-		return func(*http.Response) {}
+// shouldRetry returns true if the request failed with a retryable error and it can be sent again.
+func (c EnrichedHttpClient) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if errors.Is(err, circuitbreaker.ErrCircuitOpen) || errors.Is(err, bulkhead.ErrFull) || errors.Is(err, re.ErrHookPanicked) {
+		return false
 	}
+	return c.retry.Retryable(req, resp, err, attempt)
+}
 
-	return en[0].AfterHook
+// prepareRequest adds the default header and the idempotency key to the request (unless they are set by the
+// enricher or BeforeRequest). They are the same for all the attempts.
+func (c EnrichedHttpClient) prepareRequest(req *http.Request) error {
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	for name, values := range c.header {
+		if _, ok := req.Header[name]; !ok {
+			req.Header[name] = values
+		}
+	}
+	if c.newKey != nil && re.IsMutating(req.Method) && req.Header.Get(re.IdempotencyKeyHeader) == "" {
+		key, err := c.newKey()
+		if err != nil {
+			return err
+		}
+		req.Header.Set(re.IdempotencyKeyHeader, key)
+	}
+	return nil
+}
+
+// prepareAttempt returns a copy of the request prepared by the prepare functions for an attempt.
+func (c EnrichedHttpClient) prepareAttempt(req *http.Request) (*http.Request, error) {
+	if len(c.prepare) == 0 {
+		return req, nil
+	}
+	attempt := req.Clone(req.Context())
+	for _, prepare := range c.prepare {
+		if err := prepare(attempt); err != nil {
+			return nil, err
+		}
+	}
+	return attempt, nil
+}
+
+// record records the mutating request into the audit sink.
+func (c EnrichedHttpClient) record(req *http.Request, resp *http.Response, err error, start time.Time) {
+	if c.audit == nil || !re.IsMutating(req.Method) {
+		return
+	}
+
+	entry := auditlog.Entry{
+		Time:           start,
+		Method:         req.Method,
+		Path:           req.URL.Path,
+		ResourceID:     resourceID(req),
+		IdempotencyKey: req.Header.Get(re.IdempotencyKeyHeader),
+		Actor:          auditlog.Actor(req.Context()),
+		Err:            err,
+		Duration:       c.clock.Now().Sub(start),
+	}
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+	}
+	c.audit.Record(entry)
 }
 
-func cloneResponse(resp *http.Response) *http.Response {
-	return &http.Response{
-		Status:           resp.Status,
-		StatusCode:       resp.StatusCode,
-		Proto:            resp.Proto,
-		ProtoMajor:       resp.ProtoMajor,
-		ProtoMinor:       resp.ProtoMinor,
-		Header:           resp.Header,
-		Body:             io.NopCloser(bytes.NewReader([]byte{})),
-		ContentLength:    resp.ContentLength,
-		TransferEncoding: resp.TransferEncoding,
-		Close:            resp.Close,
-		Uncompressed:     resp.Uncompressed,
-		Trailer:          resp.Trailer,
-		Request:          resp.Request,
-		TLS:              resp.TLS,
+// resourceID returns the ID of the request body (i.e. of a created resource) or the last segment of the url path.
+func resourceID(req *http.Request) string {
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			defer body.Close()
+			var container struct {
+				Data struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			}
+			if json.NewDecoder(body).Decode(&container) == nil && container.Data.ID != "" {
+				return container.Data.ID
+			}
+		}
+	}
+	if id, err := uuid.Parse(path.Base(req.URL.Path)); err == nil {
+		return id.String()
+	}
+	return ""
+}
+
+// trace returns the request with a client trace collecting its timing if the slow requests are reported. The
+// requests are traced by the attempt middleware of the enricher if it asks for it.
+func (c EnrichedHttpClient) trace(req *http.Request, en re.RequestEnricher) *http.Request {
+	if c.onSlow == nil || en.Trace {
+		return req
+	}
+	return re.Trace(req)
+}
+
+// ReportError calls the OnError hook of the RequestEnricher of the request of the response with err, and returns err.
+// The clients call it with the errors mapped from the unsuccessful responses.
+func ReportError(resp *http.Response, err error) error {
+	if err == nil || resp == nil || resp.Request == nil {
+		return err
+	}
+	if onError, ok := resp.Request.Context().Value(onErrorKey{}).(func(*http.Request, error)); ok {
+		return re.CallOnError(onError, resp.Request, err)
+	}
+	return err
+}
+
+// RecordMeta returns the chain of the RequestEnrichers (see re.Chain) with hooks which record the details of the calls
+// into meta, measuring the durations on the clock. The hooks of the original enrichers are still called.
+func RecordMeta(meta *result.CallMeta, clk clock.Clock, en ...re.RequestEnricher) re.RequestEnricher {
+	enricher := re.Chain(en...)
+
+	var start time.Time
+	beforeHook, afterHook := enricher.BeforeHook, enricher.AfterHook
+	enricher.BeforeHook = func() {
+		if beforeHook != nil {
+			beforeHook()
+		}
+		meta.Requests++
+		start = clk.Now()
+	}
+	enricher.AfterHook = func(resp *http.Response) {
+		meta.Duration += clk.Now().Sub(start)
+		meta.StatusCode = resp.StatusCode
+		meta.Header = resp.Header
+		meta.RequestID = resp.Header.Get(result.RequestIDHeader)
+		meta.Stale = resp.Header.Get(result.WarningHeader) == result.StaleWarning
+		meta.Timing, _ = re.TimingOf(resp)
+		if resp.Request != nil {
+			meta.IdempotencyKey = resp.Request.Header.Get(re.IdempotencyKeyHeader)
+		}
+		if afterHook != nil {
+			afterHook(resp)
+		}
 	}
+	return enricher
 }
@@ -0,0 +1,1295 @@
+package requestenricher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"form3interview/pkg/auditlog"
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/clock"
+	"form3interview/pkg/clock/clocktest"
+	"form3interview/pkg/events"
+	"form3interview/pkg/metrics"
+	"form3interview/pkg/metrics/metricstest"
+	"form3interview/pkg/middleware"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/retry"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testUrl = "http://testhost/v1/organisation/accounts"
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type requestEnricherTestSuite struct {
+	suite.Suite
+	client EnrichedHttpClient
+}
+
+func TestRequestEnricherTestSuite(t *testing.T) {
+	suite.Run(t, new(requestEnricherTestSuite))
+}
+
+func (s *requestEnricherTestSuite) SetupTest() {
+	s.client = EnrichClient(newFakeClient())
+}
+
+func (s *requestEnricherTestSuite) TestDoRunsHooks() {
+	beforeHookCalled := false
+	var hookResp *http.Response
+	en := re.RequestEnricher{
+		BeforeHook: func() { beforeHookCalled = true },
+		AfterHook:  func(r *http.Response) { hookResp = r },
+	}
+
+	resp, err := s.client.Do(newRequest(s), en)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.True(beforeHookCalled)
+	s.Require().NotNil(hookResp)
+	s.NotSame(resp, hookResp)
+	s.Equal(http.StatusOK, hookResp.StatusCode)
+	hookBody, err := io.ReadAll(hookResp.Body)
+	s.Require().NoError(err)
+	s.Empty(hookBody)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Equal("{}", string(body))
+}
+
+func (s *requestEnricherTestSuite) TestDoPassesBodyToAfterHook() {
+	body := `{"data":{"id":"1"}}`
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := newFakeResponse(req)
+		resp.Body = io.NopCloser(strings.NewReader(body))
+		return resp, nil
+	})})
+	testCases := []struct {
+		name     string
+		limit    int
+		expected string
+	}{
+		{name: "whole body", limit: 1024, expected: body},
+		{name: "truncated body", limit: 9, expected: `{"data":{`},
+		{name: "disabled", limit: 0, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			var hookBody []byte
+			afterHook := re.RequestEnricher{AfterHook: func(resp *http.Response) {
+				var err error
+				hookBody, err = io.ReadAll(resp.Body)
+				s.Require().NoError(err)
+			}}
+
+			resp, err := client.WithAfterHookBody(tc.limit).Do(newRequest(s), afterHook)
+
+			s.Require().NoError(err)
+			defer resp.Body.Close()
+			s.Equal(tc.expected, string(hookBody))
+			actual, err := io.ReadAll(resp.Body)
+			s.Require().NoError(err)
+			s.Equal(body, string(actual))
+		})
+	}
+}
+
+func (s *requestEnricherTestSuite) TestDoReturnsBodyReadError_WhenBufferingForAfterHook() {
+	errRead := errors.New("unexpected EOF")
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := newFakeResponse(req)
+		resp.Body = io.NopCloser(io.MultiReader(strings.NewReader("{"), iotest.ErrReader(errRead)))
+		return resp, nil
+	})}).WithAfterHookBody(1024)
+
+	resp, err := client.Do(newRequest(s), re.RequestEnricher{AfterHook: func(*http.Response) {}})
+
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	actual, err := io.ReadAll(resp.Body)
+	s.ErrorIs(err, errRead)
+	s.Equal("{", string(actual))
+}
+
+func (s *requestEnricherTestSuite) TestDoSendsAttemptsThroughMiddleware() {
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		if attempts == 1 {
+			resp.StatusCode = http.StatusBadGateway
+		}
+		return resp, nil
+	})})
+	var calls []string
+	named := func(name string) re.Middleware {
+		return func(next re.Doer) re.Doer {
+			return re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+req.Header.Get("Authorization"))
+				return next.Do(req)
+			})
+		}
+	}
+	authorize := func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer key")
+		return nil
+	}
+
+	resp, err := client.WithPrepare(authorize).
+		WithRetry(retry.Policy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}}, nil).
+		WithMiddleware(named("logging")).
+		WithMiddleware(named("metrics")).
+		Do(newRequest(s))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal([]string{"logging Bearer key", "metrics Bearer key", "logging Bearer key", "metrics Bearer key"}, calls)
+}
+
+func (s *requestEnricherTestSuite) TestDoRejectsRetryMiddleware_WhenRetryIsEnabled() {
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		if attempts == 1 {
+			resp.StatusCode = http.StatusBadGateway
+		}
+		return resp, nil
+	})}).WithMiddleware(middleware.Retry(retry.Policy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}}))
+
+	_, err := client.WithRetry(retry.DefaultPolicy(), nil).Do(newRequest(s))
+	s.ErrorIs(err, middleware.ErrNestedRetry)
+	s.Zero(attempts)
+
+	resp, err := client.WithRetry(retry.Disabled(), nil).Do(newRequest(s))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(2, attempts)
+}
+
+func (s *requestEnricherTestSuite) TestDoPassesClockToMiddleware() {
+	fake := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	var actual clock.Clock
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return newFakeResponse(req), nil
+	})}).WithClock(fake).WithMiddleware(func(next re.Doer) re.Doer {
+		return re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			actual = clock.FromContext(req.Context())
+			return next.Do(req)
+		})
+	})
+
+	resp, err := client.Do(newRequest(s))
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Same(fake, actual)
+}
+
+func (s *requestEnricherTestSuite) TestDoChainsEnrichers() {
+	var calls []string
+	var idempotencyKey string
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "request")
+		idempotencyKey = req.Header.Get(re.IdempotencyKeyHeader)
+		return newFakeResponse(req), nil
+	})})
+	tracing := re.RequestEnricher{
+		BeforeHook: func() { calls = append(calls, "before tracing") },
+		AfterHook:  func(*http.Response) { calls = append(calls, "after tracing") },
+	}
+	timing := re.RequestEnricher{
+		BeforeHook: func() { calls = append(calls, "before timing") },
+		AfterHook: func(resp *http.Response) {
+			_, traced := re.TimingOf(resp)
+			s.True(traced)
+			calls = append(calls, "after timing")
+		},
+	}
+	req, err := http.NewRequest(http.MethodPost, testUrl, nil)
+	s.Require().NoError(err)
+
+	resp, err := client.Do(req, tracing, re.WithTrace(), re.WithIdempotencyKey("key-1"), timing)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal([]string{"before tracing", "before timing", "request", "after timing", "after tracing"}, calls)
+	s.Equal("key-1", idempotencyKey)
+}
+
+func (s *requestEnricherTestSuite) TestDoUsesEnricherContext() {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	var actualCtx context.Context
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		actualCtx = req.Context()
+		return newFakeResponse(req), nil
+	})})
+
+	resp, err := client.Do(newRequest(s), re.RequestEnricher{Ctx: ctx})
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal("value", actualCtx.Value(ctxKey{}))
+}
+
+func (s *requestEnricherTestSuite) TestDoWithTimeout() {
+	s.Run("deadline is set until the body is closed", func() {
+		var actualCtx context.Context
+		client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			actualCtx = req.Context()
+			return newFakeResponse(req), nil
+		})})
+
+		resp, err := client.Do(newRequest(s), re.WithTimeout(time.Minute))
+		s.Require().NoError(err)
+
+		_, ok := actualCtx.Deadline()
+		s.True(ok)
+		s.NoError(actualCtx.Err())
+		resp.Body.Close()
+		s.ErrorIs(actualCtx.Err(), context.Canceled)
+	})
+
+	s.Run("request exceeds the timeout", func() {
+		client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})})
+
+		_, err := client.Do(newRequest(s), re.RequestEnricher{Ctx: context.Background(), Timeout: time.Millisecond})
+
+		s.ErrorIs(err, context.DeadlineExceeded)
+		s.ErrorIs(err, ErrTimeout)
+	})
+}
+
+func (s *requestEnricherTestSuite) TestDoSetsProfilerLabels() {
+	labels := map[string]string{}
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		pprof.ForLabels(req.Context(), func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+		return newFakeResponse(req), nil
+	})})
+
+	resp, err := client.Do(newRequest(s))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(map[string]string{"operation": "GET /v1/organisation/accounts", "resource": "accounts"}, labels)
+}
+
+func (s *requestEnricherTestSuite) TestDoWithoutEnricher() {
+	resp, err := s.client.Do(newRequest(s))
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *requestEnricherTestSuite) TestDoAddsDefaultHeader() {
+	var actualHeader http.Header
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		actualHeader = req.Header
+		return newFakeResponse(req), nil
+	})}).WithDefaultHeader(http.Header{
+		"User-Agent":       []string{"form3-client"},
+		"X-Client-Version": []string{"1.2.0"},
+	})
+	req := newRequest(s)
+	req.Header.Set("User-Agent", "custom")
+
+	resp, err := client.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	s.Equal("custom", actualHeader.Get("User-Agent"))
+	s.Equal("1.2.0", actualHeader.Get("X-Client-Version"))
+	s.Empty(req.Header.Get("X-Client-Version"))
+}
+
+func (s *requestEnricherTestSuite) TestDoCallsPrepare() {
+	var actualHeader http.Header
+	transportCalled := false
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		transportCalled = true
+		actualHeader = req.Header
+		return newFakeResponse(req), nil
+	})})
+	authorize := func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer key")
+		return nil
+	}
+
+	s.Run("prepares the request", func() {
+		req := newRequest(s)
+		resp, err := client.WithPrepare(authorize).Do(req)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal("Bearer key", actualHeader.Get("Authorization"))
+		s.Empty(req.Header.Get("Authorization"))
+	})
+
+	s.Run("fails the request", func() {
+		transportCalled = false
+		expectedErr := errors.New("no credentials")
+		failing := func(*http.Request) error { return expectedErr }
+
+		_, err := client.WithPrepare(authorize).WithPrepare(failing).Do(newRequest(s), re.WithTimeout(time.Minute))
+
+		s.ErrorIs(err, expectedErr)
+		s.False(transportCalled)
+	})
+}
+
+func (s *requestEnricherTestSuite) TestDoPreparesEveryAttempt() {
+	var signatures []string
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		signatures = append(signatures, strings.Join(req.Header.Values("Signature"), ","))
+		resp := newFakeResponse(req)
+		if len(signatures) == 1 {
+			resp.StatusCode = http.StatusBadGateway
+		}
+		return resp, nil
+	})})
+	attempts := 0
+	sign := func(req *http.Request) error {
+		attempts++
+		req.Header.Add("Signature", fmt.Sprintf("attempt-%d", attempts))
+		return nil
+	}
+
+	resp, err := client.WithPrepare(sign).
+		WithRetry(retry.Policy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}}, nil).
+		Do(newRequest(s))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal([]string{"attempt-1", "attempt-2"}, signatures)
+}
+
+func (s *requestEnricherTestSuite) TestDoCallsBeforeRequest() {
+	var actualReq *http.Request
+	transportCalled := false
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		transportCalled = true
+		actualReq = req
+		return newFakeResponse(req), nil
+	})})
+	sign := func(req *http.Request) error {
+		req.Header.Set("Signature", req.Header.Get("X-Trace-Id")+" "+req.URL.RawQuery)
+		return nil
+	}
+
+	s.Run("modifies the request before it's signed", func() {
+		req := newRequest(s)
+		resp, err := client.WithPrepare(sign).Do(req, re.WithBeforeRequest(func(req *http.Request) error {
+			req.Header.Set("X-Trace-Id", "trace-1")
+			req.URL.RawQuery = "filter[bank_id]=400300"
+			return nil
+		}))
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+
+		s.Equal("trace-1 filter[bank_id]=400300", actualReq.Header.Get("Signature"))
+		s.Equal("filter[bank_id]=400300", actualReq.URL.RawQuery)
+		s.Empty(req.Header.Get("X-Trace-Id"))
+		s.Empty(req.URL.RawQuery)
+	})
+
+	s.Run("aborts the request", func() {
+		transportCalled = false
+		expectedErr := errors.New("not allowed")
+
+		_, err := client.Do(newRequest(s), re.WithBeforeRequest(func(*http.Request) error { return expectedErr }))
+
+		s.ErrorIs(err, expectedErr)
+		s.False(transportCalled)
+	})
+}
+
+func (s *requestEnricherTestSuite) TestDoCallsBeforeRequestOncePerCall() {
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		if attempts == 1 {
+			resp.StatusCode = http.StatusBadGateway
+		}
+		return resp, nil
+	})}).WithRetry(retry.Policy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}}, nil)
+	var calls []string
+	en := re.RequestEnricher{
+		BeforeRequest: func(*http.Request) error { calls = append(calls, "before request"); return nil },
+		BeforeHook:    func() { calls = append(calls, "before hook") },
+		AfterHook:     func(*http.Response) { calls = append(calls, "after hook") },
+	}
+
+	resp, err := client.Do(newRequest(s), en)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal([]string{"before request", "before hook", "after hook", "before hook", "after hook"}, calls)
+}
+
+func (s *requestEnricherTestSuite) TestDoCallsOnError() {
+	errReset := errors.New("connection reset by peer")
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodDelete {
+			return nil, errReset
+		}
+		return newFakeResponse(req), nil
+	})}).WithRetry(retry.Policy{MaxAttempts: 2}, nil)
+	var errs []error
+	onError := re.WithOnError(func(req *http.Request, err error) {
+		s.Equal(testUrl, req.URL.String())
+		errs = append(errs, err)
+	})
+
+	resp, err := client.Do(newRequest(s), onError)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Empty(errs)
+
+	req, err := http.NewRequest(http.MethodDelete, testUrl, nil)
+	s.Require().NoError(err)
+	_, err = client.Do(req, onError)
+	s.ErrorIs(err, errReset)
+	s.Require().Len(errs, 1)
+	s.ErrorIs(errs[0], errReset)
+}
+
+func (s *requestEnricherTestSuite) TestDoRecoversPanickingHooks() {
+	for _, tc := range []struct {
+		name     string
+		enricher re.RequestEnricher
+		hook     string
+		sent     bool
+	}{
+		{name: "before request", enricher: re.WithBeforeRequest(func(*http.Request) error { panic("boom") }), hook: "BeforeRequest"},
+		{name: "before hook", enricher: re.RequestEnricher{BeforeHook: func() { panic("boom") }}, hook: "BeforeHook"},
+		{name: "after hook", enricher: re.RequestEnricher{AfterHook: func(*http.Response) { panic("boom") }}, hook: "AfterHook", sent: true},
+	} {
+		s.Run(tc.name, func() {
+			var body *closeTrackingBody
+			client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				resp := newFakeResponse(req)
+				body = &closeTrackingBody{ReadCloser: resp.Body}
+				resp.Body = body
+				return resp, nil
+			})})
+			var reported error
+			onError := re.WithOnError(func(_ *http.Request, err error) { reported = err })
+
+			resp, err := client.Do(newRequest(s), tc.enricher, onError)
+
+			s.Nil(resp)
+			var panicErr *re.HookPanicError
+			s.Require().ErrorAs(err, &panicErr)
+			s.Equal(tc.hook, panicErr.Hook)
+			s.Equal(err, reported)
+			if tc.sent {
+				s.Require().NotNil(body)
+				s.True(body.closed)
+			} else {
+				s.Nil(body)
+			}
+		})
+	}
+}
+
+func (s *requestEnricherTestSuite) TestDoRecoversPanickingOnError() {
+	errReset := errors.New("connection reset by peer")
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errReset
+	})})
+
+	_, err := client.Do(newRequest(s), re.WithOnError(func(*http.Request, error) { panic("boom") }))
+
+	s.ErrorIs(err, re.ErrHookPanicked)
+	s.ErrorIs(err, errReset)
+}
+
+func (s *requestEnricherTestSuite) TestReportError() {
+	errNotFound := errors.New("not found")
+	var reported []error
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := newFakeResponse(req)
+		resp.StatusCode = http.StatusNotFound
+		return resp, nil
+	})})
+
+	resp, err := client.Do(newRequest(s), re.WithOnError(func(_ *http.Request, err error) { reported = append(reported, err) }))
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal(errNotFound, ReportError(resp, errNotFound))
+	s.Equal([]error{errNotFound}, reported)
+	s.NoError(ReportError(resp, nil))
+	s.Equal(errNotFound, ReportError(&http.Response{}, errNotFound))
+	s.Len(reported, 1)
+}
+
+func (s *requestEnricherTestSuite) TestDoSetsHeaderAndQueryOfEnricher() {
+	var actualReq *http.Request
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		actualReq = req
+		return newFakeResponse(req), nil
+	})}).WithDefaultHeader(http.Header{"X-Feature-Flag": []string{"default"}, "Accept": []string{"application/json"}})
+	sign := func(req *http.Request) error {
+		req.Header.Set("Signature", req.Header.Get("X-Feature-Flag")+" "+req.URL.RawQuery)
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodGet, testUrl+"?page[size]=10", nil)
+	s.Require().NoError(err)
+
+	resp, err := client.WithPrepare(sign).Do(req, re.WithHeader("X-Feature-Flag", "sepa"), re.WithQueryParam("filter[country]", "GB"))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal("sepa", actualReq.Header.Get("X-Feature-Flag"))
+	s.Equal("application/json", actualReq.Header.Get("Accept"))
+	s.Equal(url.Values{"page[size]": []string{"10"}, "filter[country]": []string{"GB"}}, actualReq.URL.Query())
+	s.Equal("sepa "+actualReq.URL.RawQuery, actualReq.Header.Get("Signature"))
+	s.Equal("page[size]=10", req.URL.RawQuery)
+}
+
+func (s *requestEnricherTestSuite) TestDoSetsIdempotencyKey() {
+	var actualKey string
+	keys := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		actualKey = req.Header.Get(re.IdempotencyKeyHeader)
+		return newFakeResponse(req), nil
+	})}).WithIdempotencyKeys(func() (string, error) {
+		keys++
+		return fmt.Sprintf("key-%d", keys), nil
+	})
+
+	testCases := []struct {
+		name        string
+		method      string
+		enricher    []re.RequestEnricher
+		expectedKey string
+	}{
+		{name: "generated for POST", method: http.MethodPost, expectedKey: "key-1"},
+		{name: "generated for DELETE", method: http.MethodDelete, expectedKey: "key-2"},
+		{name: "not set for GET", method: http.MethodGet, expectedKey: ""},
+		{name: "overridden by the enricher", method: http.MethodPatch, enricher: []re.RequestEnricher{re.WithIdempotencyKey("retry-key")}, expectedKey: "retry-key"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req, err := http.NewRequest(tc.method, testUrl, nil)
+			s.Require().NoError(err)
+
+			resp, err := client.Do(req, tc.enricher...)
+			s.Require().NoError(err)
+			resp.Body.Close()
+
+			s.Equal(tc.expectedKey, actualKey)
+			s.Empty(req.Header.Get(re.IdempotencyKeyHeader))
+		})
+	}
+}
+
+func (s *requestEnricherTestSuite) TestDoFailsWhenIdempotencyKeyCantBeGenerated() {
+	expectedErr := errors.New("no entropy")
+	client := s.client.WithIdempotencyKeys(func() (string, error) { return "", expectedErr })
+	req, err := http.NewRequest(http.MethodPost, testUrl, nil)
+	s.Require().NoError(err)
+
+	_, err = client.Do(req)
+
+	s.ErrorIs(err, expectedErr)
+}
+
+func (s *requestEnricherTestSuite) TestDoRecordsMutatingRequestsIntoAuditSink() {
+	var entries []auditlog.Entry
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodDelete {
+			return nil, errors.New("connection reset")
+		}
+		resp := newFakeResponse(req)
+		resp.StatusCode = http.StatusCreated
+		return resp, nil
+	})}).
+		WithIdempotencyKeys(func() (string, error) { return "key-1", nil }).
+		WithAuditSink(auditlog.SinkFunc(func(e auditlog.Entry) { entries = append(entries, e) }))
+	ctx := auditlog.WithActor(context.Background(), "alice")
+	accountID := "0ea2e2a4-6b5c-4d6f-8a3b-1e2f3a4b5c6d"
+
+	create, err := http.NewRequest(http.MethodPost, testUrl, strings.NewReader(`{"data":{"id":"`+accountID+`"}}`))
+	s.Require().NoError(err)
+	resp, err := client.Do(create, re.RequestEnricher{Ctx: ctx})
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	fetch, err := http.NewRequest(http.MethodGet, testUrl+"/"+accountID, nil)
+	s.Require().NoError(err)
+	resp, err = client.Do(fetch, re.RequestEnricher{Ctx: ctx})
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	del, err := http.NewRequest(http.MethodDelete, testUrl+"/"+accountID+"?version=0", nil)
+	s.Require().NoError(err)
+	_, err = client.Do(del)
+	s.Require().Error(err)
+
+	s.Require().Len(entries, 2)
+	s.Equal(http.MethodPost, entries[0].Method)
+	s.Equal("/v1/organisation/accounts", entries[0].Path)
+	s.Equal(accountID, entries[0].ResourceID)
+	s.Equal("key-1", entries[0].IdempotencyKey)
+	s.Equal("alice", entries[0].Actor)
+	s.Equal(http.StatusCreated, entries[0].StatusCode)
+	s.True(entries[0].Succeeded())
+	s.Equal(http.MethodDelete, entries[1].Method)
+	s.Equal(accountID, entries[1].ResourceID)
+	s.Empty(entries[1].Actor)
+	s.Error(entries[1].Err)
+	s.False(entries[1].Succeeded())
+}
+
+func (s *requestEnricherTestSuite) TestDoRetriesFailedRequests() {
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryableStatusCodes: []int{http.StatusBadGateway}}
+	testCases := []struct {
+		name             string
+		method           string
+		statuses         []int
+		retryMutations   bool
+		expectedAttempts int
+		expectedStatus   int
+	}{
+		{name: "succeeds after retries", method: http.MethodGet, statuses: []int{502, 502, 200}, expectedAttempts: 3, expectedStatus: 200},
+		{name: "gives up after max attempts", method: http.MethodGet, statuses: []int{502, 502, 502, 200}, expectedAttempts: 3, expectedStatus: 502},
+		{name: "not retryable status", method: http.MethodGet, statuses: []int{500, 200}, expectedAttempts: 1, expectedStatus: 500},
+		{name: "mutation is not retried", method: http.MethodPost, statuses: []int{502, 201}, expectedAttempts: 1, expectedStatus: 502},
+		{name: "mutation with idempotency key", method: http.MethodPost, statuses: []int{502, 201}, retryMutations: true, expectedAttempts: 2, expectedStatus: 201},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			var bodies []string
+			retries := 0
+			p := policy
+			p.RetryMutations = tc.retryMutations
+			client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				s.Require().NoError(err)
+				bodies = append(bodies, string(body))
+				resp := newFakeResponse(req)
+				resp.StatusCode = tc.statuses[len(bodies)-1]
+				return resp, nil
+			})}).
+				WithIdempotencyKeys(func() (string, error) { return "key-1", nil }).
+				WithRetry(p, func() { retries++ })
+			req, err := http.NewRequest(tc.method, testUrl, strings.NewReader("{}"))
+			s.Require().NoError(err)
+
+			var meta result.CallMeta
+			resp, err := client.Do(req, RecordMeta(&meta, clock.System))
+			s.Require().NoError(err)
+			resp.Body.Close()
+
+			s.Equal(tc.expectedStatus, resp.StatusCode)
+			s.Len(bodies, tc.expectedAttempts)
+			for _, body := range bodies {
+				s.Equal("{}", body)
+			}
+			s.Equal(tc.expectedAttempts-1, retries)
+			s.Equal(tc.expectedAttempts, meta.Requests)
+		})
+	}
+}
+
+func (s *requestEnricherTestSuite) TestDoRetriesNetworkErrors() {
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return newFakeResponse(req), nil
+	})}).WithRetry(retry.Policy{MaxAttempts: 2}, nil)
+
+	resp, err := client.Do(newRequest(s))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(2, attempts)
+}
+
+func (s *requestEnricherTestSuite) TestDoClassifiesTransportErrors() {
+	errOther := errors.New("other")
+	testCases := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "network timeout", err: &url.Error{Op: "Get", URL: testUrl, Err: &net.OpError{Op: "dial", Err: timeoutError{}}}, expected: ErrTimeout},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expected: ErrTimeout},
+		{name: "connection refused", err: &url.Error{Op: "Get", URL: testUrl, Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, expected: ErrConnection},
+		{name: "unknown host", err: &net.DNSError{Err: "no such host", Name: "testhost", IsNotFound: true}, expected: ErrConnection},
+		{name: "connection closed by server", err: &url.Error{Op: "Get", URL: testUrl, Err: io.EOF}, expected: ErrConnection},
+		{name: "canceled", err: context.Canceled, expected: context.Canceled},
+		{name: "circuit open", err: circuitbreaker.ErrCircuitOpen, expected: circuitbreaker.ErrCircuitOpen},
+		{name: "other", err: errOther, expected: errOther},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, tc.err
+			})})
+
+			_, err := client.Do(newRequest(s))
+
+			s.ErrorIs(err, tc.expected)
+			s.ErrorIs(err, tc.err)
+			for _, sentinel := range []error{ErrTimeout, ErrConnection} {
+				if sentinel != tc.expected {
+					s.NotErrorIs(err, sentinel)
+				}
+			}
+		})
+	}
+}
+
+func (s *requestEnricherTestSuite) TestDoWaitsRetryAfter_WhenRateLimited() {
+	policy := retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxRetryAfter: 5 * time.Second, RetryableStatusCodes: []int{http.StatusTooManyRequests}}
+	testCases := []struct {
+		name             string
+		retryAfter       string
+		expectedAttempts int
+		expectedStatus   int
+		minDuration      time.Duration
+	}{
+		{name: "waits retry after", retryAfter: "1", expectedAttempts: 2, expectedStatus: http.StatusOK, minDuration: time.Second},
+		{name: "gives up when retry after is over the limit", retryAfter: "60", expectedAttempts: 1, expectedStatus: http.StatusTooManyRequests},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			attempts := 0
+			client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				resp := newFakeResponse(req)
+				if attempts == 1 {
+					resp.StatusCode = http.StatusTooManyRequests
+					resp.Header.Set("Retry-After", tc.retryAfter)
+				}
+				return resp, nil
+			})}).WithRetry(policy, nil)
+
+			start := time.Now()
+			resp, err := client.Do(newRequest(s))
+
+			s.Require().NoError(err)
+			resp.Body.Close()
+			s.Equal(tc.expectedAttempts, attempts)
+			s.Equal(tc.expectedStatus, resp.StatusCode)
+			s.GreaterOrEqual(time.Since(start), tc.minDuration)
+		})
+	}
+}
+
+func (s *requestEnricherTestSuite) TestDoDoesNotRetry_WhenCircuitIsOpen() {
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, circuitbreaker.ErrCircuitOpen
+	})}).WithRetry(retry.Policy{MaxAttempts: 3}, nil)
+
+	_, err := client.Do(newRequest(s))
+
+	s.ErrorIs(err, circuitbreaker.ErrCircuitOpen)
+	s.Equal(1, attempts)
+}
+
+func (s *requestEnricherTestSuite) TestDoReturnsLastAttempt_WhenRetryWouldMissDeadline() {
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		resp.StatusCode = http.StatusServiceUnavailable
+		return resp, nil
+	})}).WithRetry(retry.Policy{MaxAttempts: 3, BaseDelay: time.Minute, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}, nil)
+
+	start := time.Now()
+	resp, err := client.Do(newRequest(s), re.WithTimeout(time.Second))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	s.Equal(1, attempts)
+	s.Less(time.Since(start), time.Second)
+}
+
+func (s *requestEnricherTestSuite) TestDoWaitsForRetryOnClock() {
+	clock := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		if attempts == 1 {
+			resp.StatusCode = http.StatusServiceUnavailable
+		}
+		return resp, nil
+	})}).
+		WithRetry(retry.Policy{MaxAttempts: 2, BaseDelay: time.Minute, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}, nil).
+		WithClock(clock)
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, err := client.Do(newRequest(s))
+		s.NoError(err)
+		done <- resp
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	resp := <-done
+	s.Require().NotNil(resp)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(2, attempts)
+}
+
+func (s *requestEnricherTestSuite) TestDoStopsRetrying_WhenContextIsDone() {
+	attempts := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		time.AfterFunc(10*time.Millisecond, cancel)
+		resp := newFakeResponse(req)
+		resp.StatusCode = http.StatusServiceUnavailable
+		return resp, nil
+	})}).WithRetry(retry.Policy{MaxAttempts: 3, BaseDelay: time.Minute, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}, nil)
+
+	_, err := client.Do(newRequest(s), re.RequestEnricher{Ctx: ctx})
+
+	s.ErrorIs(err, context.Canceled)
+	s.Equal(1, attempts)
+}
+
+func (s *requestEnricherTestSuite) TestDoReturnsBudgetExhaustedError() {
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		resp.StatusCode = http.StatusServiceUnavailable
+		return resp, nil
+	})}).WithRetry(retry.Policy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		Budget:               retry.NewBudget(0, 1),
+	}, nil)
+
+	_, err := client.Do(newRequest(s))
+
+	s.ErrorIs(err, retry.ErrBudgetExhausted)
+	var exhausted *retry.BudgetExhaustedError
+	s.Require().ErrorAs(err, &exhausted)
+	s.Equal(http.StatusServiceUnavailable, exhausted.StatusCode)
+	s.Equal(2, attempts)
+}
+
+func (s *requestEnricherTestSuite) TestDoRecordsMetrics() {
+	fake := &metricstest.Fake{}
+	recorder, err := metrics.NewRecorder(fake)
+	s.Require().NoError(err)
+	attempts := 0
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		if attempts == 1 {
+			resp.StatusCode = http.StatusBadGateway
+		}
+		return resp, nil
+	})}).WithRetry(retry.Policy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}}, nil).WithMetrics(recorder)
+
+	resp, err := client.Do(newRequest(s))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(float64(1), fake.Value(metrics.RequestsTotal, "GET /v1/organisation/accounts", "200"))
+	s.Equal(float64(1), fake.Value(metrics.RetriesTotal, "GET /v1/organisation/accounts"))
+}
+
+func (s *requestEnricherTestSuite) TestDoPublishesEvents() {
+	bus := events.NewBus()
+	var retried []events.RequestRetried
+	var rateLimited []events.RateLimited
+	events.Subscribe(bus, func(e events.RequestRetried) { retried = append(retried, e) })
+	events.Subscribe(bus, func(e events.RateLimited) { rateLimited = append(rateLimited, e) })
+	attempts := 0
+	errReset := errors.New("connection reset by peer")
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		resp := newFakeResponse(req)
+		switch attempts {
+		case 1:
+			return nil, errReset
+		case 2:
+			resp.StatusCode = http.StatusBadGateway
+		case 3:
+			resp.StatusCode = http.StatusTooManyRequests
+			resp.Header.Set("Retry-After", "60")
+		}
+		return resp, nil
+	})}).WithRetry(retry.Policy{
+		MaxAttempts:          4,
+		BaseDelay:            time.Millisecond,
+		MaxRetryAfter:        time.Second,
+		RetryableStatusCodes: []int{http.StatusBadGateway, http.StatusTooManyRequests},
+	}, nil).WithEvents(bus)
+
+	resp, err := client.Do(newRequest(s))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Require().Len(retried, 2)
+	s.Equal("GET /v1/organisation/accounts", retried[0].Operation)
+	s.Equal(1, retried[0].Attempt)
+	s.ErrorIs(retried[0].Err, errReset)
+	s.Zero(retried[0].StatusCode)
+	s.Equal(2, retried[1].Attempt)
+	s.Equal(http.StatusBadGateway, retried[1].StatusCode)
+	s.NoError(retried[1].Err)
+	s.Positive(retried[1].Delay)
+	s.Equal([]events.RateLimited{{Operation: "GET /v1/organisation/accounts", RetryAfter: time.Minute}}, rateLimited)
+}
+
+func (s *requestEnricherTestSuite) TestDoReportsSlowRequests() {
+	delay := 20 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+	}))
+	defer server.Close()
+	var durations []time.Duration
+	var timings []result.Timing
+	client := EnrichClient(*server.Client()).WithSlowRequests(10*time.Millisecond, func(req *http.Request, d time.Duration, timing result.Timing) {
+		durations = append(durations, d)
+		timings = append(timings, timing)
+	})
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	s.Require().NoError(err)
+
+	resp, err := client.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	delay = 0
+	resp, err = client.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Require().Len(durations, 1)
+	s.GreaterOrEqual(durations[0], 20*time.Millisecond)
+	s.GreaterOrEqual(timings[0].TTFB, 20*time.Millisecond)
+	s.Positive(timings[0].Connect)
+}
+
+func (s *requestEnricherTestSuite) TestDoMeasuresSlowRequestsOnTheClock() {
+	clock := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	var durations []time.Duration
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		clock.Advance(time.Minute)
+		return newFakeResponse(req), nil
+	})}).WithClock(clock).WithSlowRequests(time.Second, func(req *http.Request, d time.Duration, timing result.Timing) {
+		durations = append(durations, d)
+	})
+
+	resp, err := client.Do(newRequest(s))
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal([]time.Duration{time.Minute}, durations)
+}
+
+func (s *requestEnricherTestSuite) TestDoReportsDeprecations() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/limits" {
+			w.Header().Set(result.SunsetHeader, "Wed, 01 Jul 2026 00:00:00 GMT")
+			w.Header().Add(result.WarningHeader, `299 - "Deprecated API"`)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	bus := events.NewBus()
+	var published []events.Deprecated
+	events.Subscribe(bus, func(e events.Deprecated) { published = append(published, e) })
+	var deprecations []result.Deprecation
+	client := EnrichClient(*server.Client()).WithEvents(bus).WithDeprecations(func(req *http.Request, deprecation result.Deprecation) {
+		deprecations = append(deprecations, deprecation)
+	})
+
+	for _, path := range []string{"/v1/limits", "/v1/accounts"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		s.Require().NoError(err)
+		resp, err := client.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+
+	expected := result.Deprecation{Sunset: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Warnings: []string{"Deprecated API"}}
+	s.Equal([]result.Deprecation{expected}, deprecations)
+	s.Equal([]events.Deprecated{{Operation: "GET /v1/limits", Deprecation: expected}}, published)
+}
+
+func (s *requestEnricherTestSuite) TestCloseWaitsForInFlightRequests() {
+	started, release := make(chan struct{}), make(chan struct{})
+	var events []string
+	var mu sync.Mutex
+	event := func(e string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	}
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return newFakeResponse(req), nil
+	})}).OnClose(func() { event("closed") })
+
+	go func() {
+		resp, err := client.Do(newRequest(s), re.RequestEnricher{AfterHook: func(*http.Response) { event("after hook") }})
+		s.NoError(err)
+		resp.Body.Close()
+	}()
+	<-started
+	time.AfterFunc(10*time.Millisecond, func() { close(release) })
+
+	s.Require().NoError(client.Close())
+	s.Require().NoError(client.Close())
+
+	s.Equal([]string{"after hook", "closed"}, events)
+	_, err := client.Do(newRequest(s))
+	s.ErrorIs(err, ErrClosed)
+}
+
+func (s *requestEnricherTestSuite) TestDoServesStaleResponse_WhenAPIIsFailing() {
+	status := http.StatusOK
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := newFakeResponse(req)
+		resp.StatusCode = status
+		resp.Body = io.NopCloser(strings.NewReader(fmt.Sprintf(`{"status":%d}`, status)))
+		return resp, nil
+	})}).WithRetry(retry.Disabled(), nil)
+	staleServed := 0
+	client = client.WithStaleReads(NewStaleCache(time.Minute, clock.System), func() { staleServed++ })
+
+	resp, err := client.Do(AllowStale(newRequest(s)))
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	status = http.StatusServiceUnavailable
+	var meta result.CallMeta
+	resp, err = client.Do(AllowStale(newRequest(s)), RecordMeta(&meta, clock.System))
+
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Equal(`{"status":200}`, string(body))
+	s.Equal(http.StatusOK, meta.StatusCode)
+	s.True(meta.Stale)
+	s.Equal(1, staleServed)
+
+	resp, err = client.Do(newRequest(s))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+	s.Equal(1, staleServed)
+
+	post, err := http.NewRequest(http.MethodPost, testUrl, nil)
+	s.Require().NoError(err)
+	resp, err = client.Do(post)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func (s *requestEnricherTestSuite) TestRecordMeta() {
+	beforeHookCalled := false
+	afterHookCalled := false
+	en := re.RequestEnricher{
+		BeforeHook: func() { beforeHookCalled = true },
+		AfterHook:  func(r *http.Response) { afterHookCalled = true },
+	}
+	clock := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	client := EnrichClient(http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		clock.Advance(time.Second)
+		resp := newFakeResponse(req)
+		resp.StatusCode = http.StatusCreated
+		resp.Header.Set(result.RequestIDHeader, "request-1")
+		return resp, nil
+	})})
+
+	var meta result.CallMeta
+	recorder := RecordMeta(&meta, clock, en)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Do(newRequest(s), recorder)
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+
+	s.True(beforeHookCalled)
+	s.True(afterHookCalled)
+	s.Equal(2, meta.Requests)
+	s.Equal(http.StatusCreated, meta.StatusCode)
+	s.Equal("request-1", meta.RequestID)
+	s.Equal("application/json", meta.Header.Get("Content-Type"))
+	s.Equal(2*time.Second, meta.Duration)
+}
+
+func (s *requestEnricherTestSuite) TestRecordMetaRecordsTiming_WhenTraced() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := EnrichClient(*server.Client())
+	var afterHookTiming result.Timing
+	en := re.WithTrace()
+	en.AfterHook = func(resp *http.Response) { afterHookTiming, _ = re.TimingOf(resp) }
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	s.Require().NoError(err)
+
+	var meta result.CallMeta
+	resp, err := client.Do(req, RecordMeta(&meta, clock.System, en))
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Positive(meta.Timing.Connect)
+	s.Positive(meta.Timing.Total)
+	s.Equal(meta.Timing, afterHookTiming)
+}
+
+func (s *requestEnricherTestSuite) TestRecordMetaWithoutEnricher() {
+	var meta result.CallMeta
+	resp, err := s.client.Do(newRequest(s), RecordMeta(&meta, clock.System))
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal(1, meta.Requests)
+	s.Equal(http.StatusOK, meta.StatusCode)
+	s.Empty(meta.RequestID)
+	s.Empty(meta.IdempotencyKey)
+}
+
+func (s *requestEnricherTestSuite) TestRecordMetaRecordsIdempotencyKey() {
+	client := s.client.WithIdempotencyKeys(func() (string, error) { return "key-1", nil })
+	req, err := http.NewRequest(http.MethodPost, testUrl, nil)
+	s.Require().NoError(err)
+
+	var meta result.CallMeta
+	resp, err := client.Do(req, RecordMeta(&meta, clock.System))
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal("key-1", meta.IdempotencyKey)
+}
+
+func BenchmarkPlainClient(b *testing.B) {
+	client := newFakeClient()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest(http.MethodGet, testUrl, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+}
+
+func BenchmarkEnrichedClient(b *testing.B) {
+	for _, bench := range []struct {
+		name     string
+		enricher []re.RequestEnricher
+	}{
+		{name: "without enricher"},
+		{name: "with context", enricher: []re.RequestEnricher{{Ctx: context.Background()}}},
+		{name: "with before hook", enricher: []re.RequestEnricher{{BeforeHook: func() {}}}},
+		{name: "with after hook", enricher: []re.RequestEnricher{{AfterHook: func(*http.Response) {}}}},
+		{name: "with all", enricher: []re.RequestEnricher{{
+			Ctx:        context.Background(),
+			BeforeHook: func() {},
+			AfterHook:  func(*http.Response) {},
+		}}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			client := EnrichClient(newFakeClient())
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				req, _ := http.NewRequest(http.MethodGet, testUrl, nil)
+				resp, err := client.Do(req, bench.enricher...)
+				if err != nil {
+					b.Fatal(err)
+				}
+				resp.Body.Close()
+			}
+		})
+	}
+}
+
+func newRequest(s *requestEnricherTestSuite) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, testUrl, nil)
+	s.Require().NoError(err)
+	return req
+}
+
+func newFakeClient() http.Client {
+	return http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return newFakeResponse(req), nil
+	})}
+}
+
+func newFakeResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}
+}
+
+type closeTrackingBody struct {
+	io.ReadCloser
+	closed bool
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,134 @@
+package requestenricher
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"form3interview/pkg/clock"
+	"form3interview/pkg/result"
+	"form3interview/pkg/signing"
+)
+
+// staleCacheSize is the maximum number of responses kept by a StaleCache, the oldest one is evicted when it's full.
+const staleCacheSize = 1000
+
+// staleReadKey is the context key marking the requests which can be served from the StaleCache.
+type staleReadKey struct{}
+
+// AllowStale returns a copy of the request which can be served from the stale cache of the client (see
+// EnrichedHttpClient.WithStaleReads). The other requests never use the cache.
+func AllowStale(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), staleReadKey{}, true))
+}
+
+// staleAllowed tells whether the request was marked by AllowStale.
+func staleAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(staleReadKey{}).(bool)
+	return allowed
+}
+
+// StaleCache keeps the last successful responses of the GET requests by their method, url and headers, so they
+// can be served when the API is failing. It's safe for concurrent use.
+type StaleCache struct {
+	maxAge time.Duration
+	clock  clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]staleEntry
+}
+
+// staleEntry is a cached response.
+type staleEntry struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	storedAt   time.Time
+}
+
+// NewStaleCache creates a cache serving responses not older than maxAge measured on the clock. The age is not
+// limited if it's not positive.
+func NewStaleCache(maxAge time.Duration, clk clock.Clock) *StaleCache {
+	return &StaleCache{maxAge: maxAge, clock: clock.OrSystem(clk), entries: map[string]staleEntry{}}
+}
+
+func (s *StaleCache) store(req *http.Request, resp *http.Response, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := staleKey(req)
+	if _, ok := s.entries[key]; !ok && len(s.entries) >= staleCacheSize {
+		s.evictOldest()
+	}
+	s.entries[key] = staleEntry{statusCode: resp.StatusCode, header: resp.Header.Clone(), body: body, storedAt: s.clock.Now()}
+}
+
+// load returns the cached response of the request marked with the stale Warning header.
+func (s *StaleCache) load(req *http.Request) (*http.Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := staleKey(req)
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if s.maxAge > 0 && s.clock.Now().Sub(entry.storedAt) > s.maxAge {
+		delete(s.entries, key)
+		return nil, false
+	}
+
+	header := entry.header.Clone()
+	header.Set(result.WarningHeader, result.StaleWarning)
+	return &http.Response{
+		Status:        http.StatusText(entry.statusCode),
+		StatusCode:    entry.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.body)),
+		ContentLength: int64(len(entry.body)),
+		Request:       req,
+	}, true
+}
+
+func (s *StaleCache) evictOldest() {
+	var oldest string
+	var oldestAt time.Time
+	for key, entry := range s.entries {
+		if oldest == "" || entry.storedAt.Before(oldestAt) {
+			oldest, oldestAt = key, entry.storedAt
+		}
+	}
+	delete(s.entries, oldest)
+}
+
+// staleKey returns the cache key of the request: its method, url and headers, so the responses of the requests
+// with different headers (i.e. Accept or Authorization) are not mixed. The signing headers are left out, they
+// change with every request.
+func staleKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		switch name {
+		case signing.DateHeader, signing.DigestHeader, signing.SignatureHeader:
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(req.Method + " " + req.URL.String())
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			key.WriteString("\n" + name + ": " + value)
+		}
+	}
+	return key.String()
+}
@@ -0,0 +1,108 @@
+package requestenricher
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock/clocktest"
+	"form3interview/pkg/result"
+	"form3interview/pkg/signing"
+)
+
+type staleCacheTestSuite struct {
+	suite.Suite
+	clock *clocktest.Fake
+}
+
+func TestStaleCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(staleCacheTestSuite))
+}
+
+func (s *staleCacheTestSuite) SetupTest() {
+	s.clock = clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+}
+
+func (s *staleCacheTestSuite) newRequest() *http.Request {
+	return s.newRequestTo(testUrl)
+}
+
+func (s *staleCacheTestSuite) newRequestTo(url string) *http.Request {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	s.Require().NoError(err)
+	return req
+}
+
+func (s *staleCacheTestSuite) TestLoad() {
+	cache := NewStaleCache(time.Minute, s.clock)
+	req := s.newRequest()
+	cache.store(req, &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Request-Id": []string{"1"}}}, []byte(`{"id":"1"}`))
+
+	resp, ok := cache.load(req)
+
+	s.Require().True(ok)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal("1", resp.Header.Get("X-Request-Id"))
+	s.Equal(result.StaleWarning, resp.Header.Get(result.WarningHeader))
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Equal(`{"id":"1"}`, string(body))
+}
+
+func (s *staleCacheTestSuite) TestLoadReturnsFalse_WhenEntryIsTooOld() {
+	cache := NewStaleCache(time.Minute, s.clock)
+	req := s.newRequest()
+	cache.store(req, &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil)
+
+	s.clock.Advance(time.Minute + time.Second)
+	_, ok := cache.load(req)
+
+	s.False(ok)
+	s.Empty(cache.entries)
+}
+
+func (s *staleCacheTestSuite) TestLoadReturnsFalse_WhenHeadersDiffer() {
+	cache := NewStaleCache(time.Minute, s.clock)
+	req := s.newRequest()
+	req.Header.Set("Accept", "application/json")
+	cache.store(req, &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil)
+
+	other := s.newRequest()
+	other.Header.Set("Accept", "application/vnd.api+json")
+	_, ok := cache.load(other)
+
+	s.False(ok)
+}
+
+func (s *staleCacheTestSuite) TestLoadIgnoresSigningHeaders() {
+	cache := NewStaleCache(time.Minute, s.clock)
+	req := s.newRequest()
+	req.Header.Set(signing.DateHeader, "Mon, 14 Mar 2022 10:00:00 GMT")
+	req.Header.Set(signing.SignatureHeader, "first")
+	cache.store(req, &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil)
+
+	other := s.newRequest()
+	other.Header.Set(signing.DateHeader, "Mon, 14 Mar 2022 10:00:05 GMT")
+	other.Header.Set(signing.SignatureHeader, "second")
+	_, ok := cache.load(other)
+
+	s.True(ok)
+}
+
+func (s *staleCacheTestSuite) TestStoreEvictsOldest_WhenFull() {
+	cache := NewStaleCache(0, s.clock)
+	for i := 0; i < staleCacheSize; i++ {
+		cache.store(s.newRequestTo(fmt.Sprintf("http://testhost/things/%d", i)), &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil)
+		s.clock.Advance(time.Second)
+	}
+
+	cache.store(s.newRequestTo("http://testhost/things/new"), &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil)
+
+	s.Len(cache.entries, staleCacheSize)
+	s.NotContains(cache.entries, "GET http://testhost/things/0")
+	s.Contains(cache.entries, "GET http://testhost/things/new")
+}
@@ -0,0 +1,151 @@
+package resource
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	conf "form3interview/internal/config"
+	"form3interview/pkg/auth"
+	"form3interview/pkg/events"
+	"form3interview/pkg/secret"
+	"form3interview/pkg/signing"
+)
+
+// bearerToken authorizes the requests with the API key of the secret file.
+func bearerToken(apiKey *secret.File) func(*http.Request) error {
+	return func(req *http.Request) error {
+		key, err := apiKey.Value()
+		if err != nil {
+			return fmt.Errorf("failed to read API key: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+string(key))
+		return nil
+	}
+}
+
+// clientCredentials returns the provider of the bearer token of the OAuth client credentials grant.
+// The token is requested with the http client of the resource, so it uses the same transport.
+func clientCredentials(cfg conf.ClientConfig, httpClient *http.Client) auth.CredentialsProvider {
+	var clientSecret, tokenUrl string
+	if cfg.ClientSecret != nil {
+		clientSecret = *cfg.ClientSecret
+	}
+	if cfg.TokenUrl != nil {
+		tokenUrl = *cfg.TokenUrl
+	}
+	credentials := auth.NewClientCredentials(*cfg.ClientID, clientSecret, tokenUrl, httpClient)
+	if cfg.Events != nil {
+		credentials.OnRefresh(func(expiresAt time.Time) { cfg.Events.Publish(events.TokenRefreshed{ExpiresAt: expiresAt}) })
+	}
+	return credentials
+}
+
+// authorize sets the bearer token and signs the requests with the credentials of the provider.
+func authorize(provider auth.CredentialsProvider) func(*http.Request) error {
+	return func(req *http.Request) error {
+		return auth.Authorize(req, provider)
+	}
+}
+
+// signRequest signs the requests with the key returned by getKey.
+func signRequest(keyID string, getKey func() (crypto.Signer, error)) func(*http.Request) error {
+	return func(req *http.Request) error {
+		key, err := getKey()
+		if err != nil {
+			return err
+		}
+		return signing.SignRequest(req, keyID, key)
+	}
+}
+
+// signWith signs the requests with the signer.
+func signWith(signer signing.Signer) func(*http.Request) error {
+	return func(req *http.Request) error {
+		return signing.SignRequestWith(req, signer)
+	}
+}
+
+// signingKey returns the signing key of the config or a function loading it from the private key file.
+func signingKey(cfg conf.ClientConfig) func() (crypto.Signer, error) {
+	if cfg.SigningKey != nil {
+		return func() (crypto.Signer, error) { return cfg.SigningKey, nil }
+	}
+	if cfg.PrivateKeyFile != nil {
+		return (&keyFile{file: secret.NewFile(*cfg.PrivateKeyFile)}).key
+	}
+	return func() (crypto.Signer, error) { return nil, ErrSigningKeyNotConfigured }
+}
+
+// checkSigningConfig verifies that the private key can be loaded when the requests are signed.
+func checkSigningConfig(cfg conf.ClientConfig) error {
+	if cfg.Signer != nil {
+		if cfg.Signer.KeyID() == "" {
+			return signing.ErrMissingKeyID
+		}
+		return nil
+	}
+	if cfg.SigningKeyID == nil {
+		if cfg.SigningKey != nil {
+			return signing.ErrMissingKeyID
+		}
+		return nil
+	}
+
+	_, err := signingKey(cfg)()
+	return err
+}
+
+// verifyingTransport verifies the Digest and Signature headers of the responses.
+type verifyingTransport struct {
+	next      http.RoundTripper
+	publicKey crypto.PublicKey
+}
+
+func (t verifyingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := signing.VerifyResponse(resp, t.publicKey); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// keyFile is a private key of a secret file which is parsed again only when the file changes.
+type keyFile struct {
+	file *secret.File
+
+	mu     sync.Mutex
+	pem    []byte
+	signer crypto.Signer
+}
+
+func (k *keyFile) key() (crypto.Signer, error) {
+	data, err := k.file.Value()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.signer != nil && bytes.Equal(data, k.pem) {
+		return k.signer, nil
+	}
+
+	signer, err := signing.ParsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	k.pem, k.signer = data, signer
+	return signer, nil
+}
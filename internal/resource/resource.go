@@ -0,0 +1,557 @@
+// Package resource provides a generic client for the Form3 JSON:API resources.
+// It contains the request, decoding and error mapping logic shared by the resource clients
+// so a new resource client only has to define its models, URLs and resource specific errors.
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	ire "form3interview/internal/requestenricher"
+	istats "form3interview/internal/stats"
+	"form3interview/pkg/bulkhead"
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/metrics"
+	"form3interview/pkg/middleware"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/retry"
+	"form3interview/pkg/secret"
+	"form3interview/pkg/shim"
+	"form3interview/pkg/stats"
+)
+
+// Errors shared by the resource clients. The clients re-export them so errors.Is works with both.
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = errors.New("baseUrl not configured")
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = errors.New("organisationID not configured")
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = errors.New("nil UUID not allowed")
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = errors.New("server error")
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = errors.New("server unavailable")
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = errors.New("unexpected server response")
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = errors.New("rate limited")
+	// ErrResourceAlreadyExists server returned with 409 Conflict when a resource was created with an existing ID
+	ErrResourceAlreadyExists = errors.New("already exists")
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = errors.New("unauthorized: the API rejected the credentials, check the API key, the client credentials and the signing key")
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = errors.New("forbidden: the credentials are not permitted to access the resource, check the permissions of the user in the organisation")
+	// ErrCircuitOpen the request was not sent because the circuit breaker is open
+	ErrCircuitOpen = circuitbreaker.ErrCircuitOpen
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = ire.ErrClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = ire.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = ire.ErrConnection
+	// ErrBulkheadFull the request was not sent because the resource client had too many concurrent requests
+	ErrBulkheadFull = bulkhead.ErrFull
+	// ErrRetryBudgetExhausted the failed request was not retried because the retry budget is exhausted
+	ErrRetryBudgetExhausted = retry.ErrBudgetExhausted
+	// ErrReadOnly mutating request of a read-only client
+	ErrReadOnly = errors.New("client is read-only")
+	// ErrTokenUrlNotConfigured client ID is configured without the token endpoint
+	ErrTokenUrlNotConfigured = errors.New("token url not configured")
+	// ErrSigningKeyNotConfigured signing key ID is configured without a private key
+	ErrSigningKeyNotConfigured = errors.New("signing key not configured")
+	// ErrInvalidCACert the CA cert file contains no PEM encoded certificate
+	ErrInvalidCACert = errors.New("no certificate found in CA cert file")
+)
+
+type (
+	// HttpClient sends the requests of the resource clients (see requestenricher.EnrichedHttpClient).
+	HttpClient interface {
+		Do(*http.Request, ...re.RequestEnricher) (*http.Response, error)
+	}
+
+	// DataContainer is a simple container for the "data" JSON field.
+	DataContainer[T any] struct {
+		Data T `json:"data,omitempty"`
+	}
+
+	// ListContainer is a simple container for the "data" JSON field of list responses.
+	ListContainer[T any] struct {
+		Data []T `json:"data,omitempty"`
+	}
+)
+
+// Client is a client of a Form3 resource collection with data type T.
+type Client[T any] struct {
+	HTTP   HttpClient
+	Config conf.ClientConfig
+	// Url is the path of the resource collection (i.e. /organisation/accounts).
+	Url string
+	// ErrNotFound is returned when the resource is not found.
+	ErrNotFound error
+	// ErrInvalidVersion is returned when a resource is deleted with a wrong version.
+	ErrInvalidVersion error
+	// ErrAlreadyExists is returned when a resource is created with an existing ID. It should wrap
+	// ErrResourceAlreadyExists, which is returned if it's not set.
+	ErrAlreadyExists error
+	// Stats counts the feature usage. It is optional.
+	Stats *istats.Recorder
+	// OnInvalidRequest is called with the error message of the 400 Bad Request responses. It is optional.
+	OnInvalidRequest func(errorMessage string)
+}
+
+// CheckConfig verifies the config fields required by the resource clients.
+func CheckConfig(cfg conf.ClientConfig) error {
+	if cfg.BaseUrl == nil || *cfg.BaseUrl == "" {
+		return ErrBaseUrlNotConfigured
+	}
+
+	if cfg.OrganisationID == nil || *cfg.OrganisationID == uuid.Nil {
+		return ErrOrganisationIDNotConfigured
+	}
+
+	if _, err := TLSConfig(cfg); err != nil {
+		return err
+	}
+
+	for _, file := range []*string{cfg.APIKeyFile, cfg.PrivateKeyFile} {
+		if file == nil {
+			continue
+		}
+		if _, err := secret.NewFile(*file).Value(); err != nil {
+			return err
+		}
+	}
+	if cfg.ClientID != nil && (cfg.TokenUrl == nil || *cfg.TokenUrl == "") {
+		return ErrTokenUrlNotConfigured
+	}
+	if cfg.Metrics != nil {
+		if _, err := metrics.NewRecorder(cfg.Metrics); err != nil {
+			return err
+		}
+	}
+	if err := CheckMiddleware(cfg); err != nil {
+		return err
+	}
+	return checkSigningConfig(cfg)
+}
+
+// CheckMiddleware returns middleware.ErrNestedRetry if the config has a retrying middleware (i.e. middleware.Retry)
+// while the built-in retry of the clients is enabled. The clients without an organisation check only this and
+// the base url.
+func CheckMiddleware(cfg conf.ClientConfig) error {
+	if cfg.Retry().Enabled() && middleware.Retries(cfg.Middleware...) {
+		return middleware.ErrNestedRetry
+	}
+	return nil
+}
+
+// newMetrics returns the metrics recorder of the config or nil if the metrics are not enabled. The errors
+// creating the instruments are returned by CheckConfig.
+func newMetrics(cfg conf.ClientConfig) *metrics.Recorder {
+	if cfg.Metrics == nil {
+		return nil
+	}
+	m, err := metrics.NewRecorder(cfg.Metrics)
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+// NewHttpClient creates the enriched http client of a resource client.
+// It wraps the http client of the config if it's set. Otherwise it uses the shared transport of the config
+// or creates a new one.
+func NewHttpClient(cfg conf.ClientConfig) HttpClient {
+	return NewHttpClientWithStats(cfg, nil)
+}
+
+// NewHttpClientWithStats creates the enriched http client of a resource client which counts the retries
+// in the recorder (see NewHttpClient).
+func NewHttpClientWithStats(cfg conf.ClientConfig, recorder *istats.Recorder) HttpClient {
+	var httpClient http.Client
+	// ownTransport is the transport created for this client only, its idle connections are closed with the client.
+	var ownTransport *http.Client
+	if cfg.HttpClient != nil {
+		httpClient = *cfg.HttpClient
+	} else {
+		transport := cfg.SharedTransport
+		if transport == nil {
+			transport = NewTransport(cfg)
+			ownTransport = &http.Client{Transport: transport}
+		}
+		httpClient = http.Client{
+			Timeout:   *cfg.Timeout,
+			Transport: transport,
+		}
+	}
+
+	if cfg.RateLimiter != nil {
+		httpClient.Transport = rateLimitedTransport{next: httpClient.Transport, limiter: cfg.RateLimiter}
+	}
+	if cfg.CircuitBreaker != nil {
+		httpClient.Transport = circuitBreakerTransport{next: httpClient.Transport, breaker: cfg.CircuitBreaker}
+	}
+	if cfg.BulkheadSize > 0 {
+		var timeout time.Duration
+		if cfg.BulkheadTimeout != nil {
+			timeout = *cfg.BulkheadTimeout
+		}
+		httpClient.Transport = bulkheadTransport{next: httpClient.Transport, bulkhead: bulkhead.NewWithClock(cfg.BulkheadSize, timeout, cfg.ClockOrSystem())}
+	}
+	if cfg.SingleFlight {
+		httpClient.Transport = newSingleFlightTransport(httpClient.Transport)
+	}
+	if cfg.VerifyResponses {
+		httpClient.Transport = verifyingTransport{next: httpClient.Transport, publicKey: cfg.ResponseVerificationKey}
+	}
+	if cfg.DebugLog != nil {
+		httpClient.Transport = debugTransport{next: httpClient.Transport, toggle: cfg.DebugLog, log: cfg.Log().Logger, clock: cfg.ClockOrSystem()}
+	}
+
+	client := ire.EnrichClient(httpClient).
+		WithDefaultHeader(DefaultHeader(cfg)).
+		WithIdempotencyKeys(idempotencyKey(cfg)).
+		WithAuditSink(cfg.AuditSink).
+		WithRetry(cfg.Retry(), func() { recorder.Feature(stats.FeatureRetry) }).
+		WithClock(cfg.ClockOrSystem()).
+		WithMetrics(newMetrics(cfg)).
+		WithEvents(cfg.Events).
+		WithAfterHookBody(cfg.AfterHookBodyLimit).
+		WithMiddleware(cfg.Middleware...)
+	if cfg.StaleReadMaxAge != nil {
+		client = client.WithStaleReads(ire.NewStaleCache(*cfg.StaleReadMaxAge, cfg.ClockOrSystem()), func() { recorder.Feature(stats.FeatureCacheHit) })
+	}
+	if cfg.SlowRequestThreshold != nil {
+		client = client.WithSlowRequests(*cfg.SlowRequestThreshold, logSlowRequest(cfg, recorder))
+	}
+	client = client.WithDeprecations(onDeprecated(cfg))
+	if ownTransport != nil {
+		client = client.OnClose(ownTransport.CloseIdleConnections)
+	}
+	if cfg.ReadOnly {
+		client = client.WithPrepare(rejectMutating)
+	}
+	if cfg.APIKeyFile != nil {
+		client = client.WithPrepare(bearerToken(secret.NewFile(*cfg.APIKeyFile)))
+	}
+	if cfg.ClientID != nil {
+		client = client.WithPrepare(authorize(clientCredentials(cfg, &httpClient)))
+	}
+	if cfg.CredentialsProvider != nil {
+		client = client.WithPrepare(authorize(cfg.CredentialsProvider))
+	}
+	if cfg.Signer != nil {
+		client = client.WithPrepare(signWith(cfg.Signer))
+	} else if cfg.SigningKeyID != nil {
+		client = client.WithPrepare(signRequest(*cfg.SigningKeyID, signingKey(cfg)))
+	}
+	return client
+}
+
+// Close closes the http client of a resource client if it can be closed (see requestenricher.EnrichedHttpClient.Close).
+func Close(client HttpClient) error {
+	if closer, ok := client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// logSlowRequest returns a function logging the slow requests with their time breakdown and counting them
+// in the recorder.
+func logSlowRequest(cfg conf.ClientConfig, recorder *istats.Recorder) func(*http.Request, time.Duration, result.Timing) {
+	return func(req *http.Request, duration time.Duration, timing result.Timing) {
+		recorder.Feature(stats.FeatureSlowRequest)
+		cfg.Log().Logger.Log(logger.LevelWarn, "slow request",
+			logger.Field{Key: "operation", Value: metrics.Operation(req)},
+			logger.Field{Key: "duration", Value: duration.String()},
+			logger.Field{Key: "threshold", Value: cfg.SlowRequestThreshold.String()},
+			logger.Field{Key: "dns", Value: timing.DNS.String()},
+			logger.Field{Key: "connect", Value: timing.Connect.String()},
+			logger.Field{Key: "tls", Value: timing.TLS.String()},
+			logger.Field{Key: "ttfb", Value: timing.TTFB.String()},
+			logger.Field{Key: "reused_conn", Value: strconv.FormatBool(timing.ReusedConn)},
+		)
+	}
+}
+
+// onDeprecated returns a function passing the deprecations to the OnDeprecation handler of the config, or
+// logging the deprecation of each operation once at warn level if it's not set.
+func onDeprecated(cfg conf.ClientConfig) func(*http.Request, result.Deprecation) {
+	if cfg.OnDeprecation != nil {
+		return func(req *http.Request, deprecation result.Deprecation) {
+			cfg.OnDeprecation(metrics.Operation(req), deprecation)
+		}
+	}
+
+	var logged sync.Map
+	return func(req *http.Request, deprecation result.Deprecation) {
+		operation := metrics.Operation(req)
+		if _, loaded := logged.LoadOrStore(operation, true); loaded {
+			return
+		}
+		cfg.Log().Logger.Log(logger.LevelWarn, "deprecated endpoint",
+			logger.Field{Key: "operation", Value: operation},
+			logger.Field{Key: "deprecation", Value: deprecation.String()},
+		)
+	}
+}
+
+// rejectMutating fails the mutating requests of a read-only client before they are sent.
+func rejectMutating(req *http.Request) error {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// idempotencyKey returns a function generating the idempotency keys with the ID generator of the config.
+func idempotencyKey(cfg conf.ClientConfig) func() (string, error) {
+	return func() (string, error) {
+		id, err := cfg.NewID()
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+}
+
+// DefaultHeader returns the headers of the config added to every request.
+func DefaultHeader(cfg conf.ClientConfig) http.Header {
+	header := http.Header{}
+	for name, value := range cfg.Headers {
+		header.Set(name, value)
+	}
+	if cfg.UserAgent != nil {
+		header.Set("User-Agent", *cfg.UserAgent)
+	}
+	return header
+}
+
+// Create posts the data with the id to the collection and returns the created resource. When a resource with
+// the id already exists, it returns ErrAlreadyExists, or the existing resource if the config has
+// ReturnExistingOnCreate set.
+//
+// The request can be enriched by RequestEnricher
+func (c Client[T]) Create(id uuid.UUID, data T, en ...re.RequestEnricher) (*T, error) {
+	resp, err := c.post(c.Url, DataContainer[T]{Data: data}, en...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusCreated:
+		return c.BodyToData(resp.Body)
+	case resp.StatusCode == http.StatusConflict && c.Config.ReturnExistingOnCreate:
+		c.Config.Log().Debugf("%s/%s already exists, fetching it", c.Url, id)
+		return c.Fetch(id, en...)
+	}
+	return nil, c.ErrorFromResponse(resp, Conflict(orDefault(c.ErrAlreadyExists, ErrResourceAlreadyExists)))
+}
+
+// Fetch a resource by it's ID.
+//
+// The request can be enriched by RequestEnricher
+func (c Client[T]) Fetch(id uuid.UUID, en ...re.RequestEnricher) (*T, error) {
+	if id == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.Config.Url(fmt.Sprintf("%s/%s", c.Url, id)), nil)
+	if err != nil {
+		return nil, err
+	}
+	// only the fetched resources are served from the stale cache (see config.WithStaleReads)
+	resp, err := c.send(ire.AllowStale(req), en...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return c.BodyToData(resp.Body)
+	}
+	return nil, c.ErrorFromResponse(resp)
+}
+
+// List resources page by page. Page numbers start from 0.
+//
+// The request can be enriched by RequestEnricher
+func (c Client[T]) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]T, error) {
+	resp, err := c.get(fmt.Sprintf("%s?page[number]=%d&page[size]=%d", c.Url, pageNumber, pageSize), en...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return c.BodyToList(resp.Body)
+	}
+	return nil, c.ErrorFromResponse(resp)
+}
+
+// Find lists the resources matching the query (i.e. filter[bic]=X), or all of them if the query is empty.
+// The query is added to the URL as it is, so its values have to be escaped.
+//
+// The request can be enriched by RequestEnricher
+func (c Client[T]) Find(query string, en ...re.RequestEnricher) ([]T, error) {
+	url := c.Url
+	if query != "" {
+		url += "?" + query
+	}
+	resp, err := c.get(url, en...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return c.BodyToList(resp.Body)
+	}
+	return nil, c.ErrorFromResponse(resp)
+}
+
+// Delete deletes a resource which is not versioned by it's ID.
+//
+// The request can be enriched by RequestEnricher
+func (c Client[T]) Delete(id uuid.UUID, en ...re.RequestEnricher) error {
+	if id == uuid.Nil {
+		return ErrNilUUID
+	}
+
+	resp, err := c.delete(fmt.Sprintf("%s/%s", c.Url, id), en...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return c.ErrorFromResponse(resp)
+}
+
+// Download streams the file of a resource, found at the path relative to the resource (i.e. /file), to w and
+// returns the number of bytes written. If the download breaks, the bytes already written are returned together
+// with the error.
+//
+// The request can be enriched by RequestEnricher
+func (c Client[T]) Download(id uuid.UUID, path string, w io.Writer, en ...re.RequestEnricher) (int64, error) {
+	if id == uuid.Nil {
+		return 0, ErrNilUUID
+	}
+
+	resp, err := c.get(fmt.Sprintf("%s/%s%s", c.Url, id, path), en...)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return io.Copy(w, resp.Body)
+	}
+	return 0, c.ErrorFromResponse(resp)
+}
+
+// DeleteVersion deletes a resource by it's ID having a specific version.
+//
+// The request can be enriched by RequestEnricher
+func (c Client[T]) DeleteVersion(id uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	if id == uuid.Nil {
+		return ErrNilUUID
+	}
+
+	resp, err := c.delete(fmt.Sprintf("%s/%s?version=%d", c.Url, id, version), en...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return c.ErrorFromResponse(resp)
+}
+
+// BodyToData decodes a single resource response with the shims of the configured API version.
+func (c Client[T]) BodyToData(body io.Reader) (*T, error) {
+	var container DataContainer[T]
+	if err := shim.Decode(c.Config.APIVersion(), body, &container); err != nil {
+		return nil, result.Wrap(ErrUnexpectedServerResponse, err)
+	}
+	return &container.Data, nil
+}
+
+// BodyToList decodes a list response with the shims of the configured API version.
+func (c Client[T]) BodyToList(body io.Reader) ([]T, error) {
+	var container ListContainer[T]
+	if err := shim.Decode(c.Config.APIVersion(), body, &container); err != nil {
+		return nil, result.Wrap(ErrUnexpectedServerResponse, err)
+	}
+	return container.Data, nil
+}
+
+// ErrorFromResponse maps an unsuccessful response to an error. The resource errors of the client are used for
+// the 404 Not Found and 409 Conflict responses if they are set.
+func (c Client[T]) ErrorFromResponse(resp *http.Response, statusErrors ...StatusError) error {
+	mapper := ErrorMapper{Config: c.Config, OnInvalidRequest: c.OnInvalidRequest}
+	return mapper.ErrorFromResponse(resp, append(statusErrors, NotFound(c.ErrNotFound), Conflict(c.ErrInvalidVersion))...)
+}
+
+// RateLimited returns ErrRateLimited for a 429 Too Many Requests response, wrapped with the wait requested
+// by the server if the response tells it.
+func RateLimited(resp *http.Response) error {
+	if retryAfter := retry.RetryAfter(resp); retryAfter > 0 {
+		return fmt.Errorf("%w: retry after %s", ErrRateLimited, retryAfter)
+	}
+	return ErrRateLimited
+}
+
+func (c Client[T]) get(url string, en ...re.RequestEnricher) (*http.Response, error) {
+	return c.do(http.MethodGet, url, nil, en...)
+}
+
+func (c Client[T]) post(url string, container interface{}, en ...re.RequestEnricher) (*http.Response, error) {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(container); err != nil {
+		return nil, err
+	}
+	return c.do(http.MethodPost, url, buf, en...)
+}
+
+func (c Client[T]) delete(url string, en ...re.RequestEnricher) (*http.Response, error) {
+	return c.do(http.MethodDelete, url, nil, en...)
+}
+
+func (c Client[T]) do(method, url string, body io.Reader, en ...re.RequestEnricher) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.Config.Url(url), body)
+	if err != nil {
+		return nil, err
+	}
+	return c.send(req, en...)
+}
+
+func (c Client[T]) send(req *http.Request, en ...re.RequestEnricher) (*http.Response, error) {
+	c.Stats.Enrichers(en)
+	return c.HTTP.Do(req, en...)
+}
@@ -0,0 +1,1236 @@
+package resource
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	ire "form3interview/internal/requestenricher"
+	istats "form3interview/internal/stats"
+	"form3interview/pkg/auth"
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/clock/clocktest"
+	"form3interview/pkg/debuglog"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/metrics"
+	"form3interview/pkg/metrics/metricstest"
+	"form3interview/pkg/middleware"
+	"form3interview/pkg/ratelimit"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/retry"
+	"form3interview/pkg/signing"
+	"form3interview/pkg/stats"
+)
+
+const (
+	Do          = "Do"
+	testBaseUrl = "testhost"
+	testUrl     = "/things"
+)
+
+var (
+	errThingNotFound       = errors.New("thing not found")
+	errInvalidThingVersion = errors.New("invalid thing version")
+)
+
+type thing struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type resourceTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	client         Client[thing]
+	invalidRequest string
+}
+
+func TestResourceTestSuite(t *testing.T) {
+	suite.Run(t, new(resourceTestSuite))
+}
+
+func (s *resourceTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	s.invalidRequest = ""
+	baseUrl := testBaseUrl
+	s.client = Client[thing]{
+		HTTP:              s.mockHttpClient,
+		Config:            config.ClientConfig{BaseUrl: &baseUrl},
+		Url:               testUrl,
+		ErrNotFound:       errThingNotFound,
+		ErrInvalidVersion: errInvalidThingVersion,
+		Stats:             istats.NewRecorder(),
+		OnInvalidRequest:  func(msg string) { s.invalidRequest = msg },
+	}
+}
+
+func (s *resourceTestSuite) TestCheckConfig() {
+	baseUrl := testBaseUrl
+	orgID := uuid.New()
+
+	s.ErrorIs(CheckConfig(config.ClientConfig{}), ErrBaseUrlNotConfigured)
+	s.ErrorIs(CheckConfig(config.ClientConfig{BaseUrl: &baseUrl}), ErrOrganisationIDNotConfigured)
+	s.NoError(CheckConfig(config.ClientConfig{BaseUrl: &baseUrl, OrganisationID: &orgID}))
+}
+
+func (s *resourceTestSuite) TestCheckConfigReturnsError_WhenRetryMiddlewareIsNested() {
+	baseUrl := testBaseUrl
+	orgID := uuid.New()
+	cfg := config.ClientConfig{
+		BaseUrl:        &baseUrl,
+		OrganisationID: &orgID,
+		Middleware:     []re.Middleware{middleware.Retry(retry.DefaultPolicy())},
+	}
+
+	s.ErrorIs(CheckConfig(cfg), middleware.ErrNestedRetry)
+	disabled := retry.Disabled()
+	cfg.RetryPolicy = &disabled
+	s.NoError(CheckConfig(cfg))
+}
+
+func (s *resourceTestSuite) TestNewHttpClientUsesConfiguredHttpClient() {
+	var requested string
+	timeout, idleConnTimeout := time.Second, time.Second
+	cfg := config.ClientConfig{
+		Timeout:         &timeout,
+		IdleConnTimeout: &idleConnTimeout,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requested = req.URL.String()
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	resp, err := NewHttpClient(cfg).Do(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal("http://testhost/things", requested)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientSendsRequestsThroughMiddleware() {
+	var intercepted []string
+	cfg := config.ClientConfig{
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+		Middleware: []re.Middleware{func(next re.Doer) re.Doer {
+			return re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				intercepted = append(intercepted, req.URL.String())
+				return next.Do(req)
+			})
+		}},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	_, err = NewHttpClient(cfg).Do(req)
+
+	s.Require().NoError(err)
+	s.Equal([]string{"http://testhost/things"}, intercepted)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientAuthorizesWithAPIKeyFile() {
+	apiKeyFile := filepath.Join(s.T().TempDir(), "api-key")
+	s.Require().NoError(os.WriteFile(apiKeyFile, []byte("secret-key\n"), 0o600))
+	var authorization string
+	cfg := config.ClientConfig{
+		APIKeyFile: &apiKeyFile,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			authorization = req.Header.Get("Authorization")
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodGet, testBaseUrl+testUrl, nil)
+	s.Require().NoError(err)
+
+	resp, err := NewHttpClient(cfg).Do(req)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal("Bearer secret-key", authorization)
+}
+
+func (s *resourceTestSuite) TestCheckConfigReturnsError_WhenSecretFileIsMissing() {
+	baseUrl, orgID := testBaseUrl, uuid.New()
+	missing := filepath.Join(s.T().TempDir(), "private-key.pem")
+
+	err := CheckConfig(config.ClientConfig{BaseUrl: &baseUrl, OrganisationID: &orgID, PrivateKeyFile: &missing})
+
+	s.ErrorIs(err, os.ErrNotExist)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientAuthorizesWithClientCredentials() {
+	clientID, clientSecret, tokenUrl := "client-id", "client-secret", "http://testhost/v1/oauth2/token"
+	var authorization string
+	tokenRequests := 0
+	cfg := config.ClientConfig{
+		ClientID:     &clientID,
+		ClientSecret: &clientSecret,
+		TokenUrl:     &tokenUrl,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.String() == tokenUrl {
+				tokenRequests++
+				return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody(`{"access_token":"access-token","expires_in":3600}`)}, nil
+			}
+			authorization = req.Header.Get("Authorization")
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+		s.Require().NoError(err)
+		resp, err := client.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+
+	s.Equal("Bearer access-token", authorization)
+	s.Equal(1, tokenRequests)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientAuthorizesWithCredentialsProvider() {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	rotations := 0
+	var authorization, signature string
+	cfg := config.ClientConfig{
+		CredentialsProvider: auth.CredentialsProviderFunc(func(context.Context) (auth.Credentials, error) {
+			rotations++
+			return auth.Credentials{Token: fmt.Sprintf("token-%d", rotations), KeyID: "key-1", SigningKey: key}, nil
+		}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			authorization, signature = req.Header.Get("Authorization"), req.Header.Get(signing.SignatureHeader)
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+
+	for _, expected := range []string{"Bearer token-1", "Bearer token-2"} {
+		req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+		s.Require().NoError(err)
+		resp, err := client.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+
+		s.Equal(expected, authorization)
+		s.True(strings.HasPrefix(signature, `keyId="key-1",algorithm="ed25519",`))
+	}
+}
+
+func (s *resourceTestSuite) TestNewHttpClientReturnsError_WhenCredentialsProviderFails() {
+	errVault := errors.New("vault is sealed")
+	cfg := config.ClientConfig{
+		CredentialsProvider: auth.CredentialsProviderFunc(func(context.Context) (auth.Credentials, error) {
+			return auth.Credentials{}, errVault
+		}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			s.Fail("request should not be sent")
+			return nil, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	_, err = NewHttpClient(cfg).Do(req)
+
+	s.ErrorIs(err, errVault)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientSetsIdempotencyKeyWithUUIDGenerator() {
+	id := uuid.MustParse("0ea2e2a4-6b5c-4d6f-8a3b-1e2f3a4b5c6d")
+	var idempotencyKey string
+	cfg := config.ClientConfig{
+		UUIDGenerator: func() (uuid.UUID, error) { return id, nil },
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			idempotencyKey = req.Header.Get(re.IdempotencyKeyHeader)
+			return &http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("")}, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://testhost/things", strings.NewReader("{}"))
+	s.Require().NoError(err)
+
+	resp, err := NewHttpClient(cfg).Do(req)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(id.String(), idempotencyKey)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientVerifiesResponseDigest() {
+	digest := signing.Digest([]byte(`{"id":"1"}`))
+	cfg := config.ClientConfig{
+		VerifyResponses: true,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{signing.DigestHeader: []string{digest}}
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: toResponseBody(`{"id":"2"}`)}, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things/1", nil)
+	s.Require().NoError(err)
+
+	_, err = NewHttpClient(cfg).Do(req)
+
+	s.ErrorIs(err, signing.ErrDigestMismatch)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientRejectsMutatingRequests_WhenReadOnly() {
+	var sent []string
+	cfg := config.ClientConfig{
+		ReadOnly: true,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.Method)
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, err := http.NewRequest(method, "http://testhost/things", nil)
+		s.Require().NoError(err)
+
+		resp, err := client.Do(req)
+
+		if method == http.MethodGet {
+			s.Require().NoError(err)
+			resp.Body.Close()
+		} else {
+			s.ErrorIs(err, ErrReadOnly, method)
+		}
+	}
+	s.Equal([]string{http.MethodGet}, sent)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientWaitsForRateLimiter() {
+	sent := 0
+	cfg := config.ClientConfig{
+		RateLimiter: ratelimit.New(1, 1),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	resp, err := client.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	_, err = client.Do(req, re.WithTimeout(10*time.Millisecond))
+
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Equal(1, sent)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientShortCircuits_WhenCircuitBreakerIsOpen() {
+	sent := 0
+	cfg := config.ClientConfig{
+		CircuitBreaker: circuitbreaker.New(circuitbreaker.Settings{FailureThreshold: 2}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+	_, err = client.Do(req)
+
+	s.ErrorIs(err, ErrCircuitOpen)
+	s.Equal(2, sent)
+	s.Equal(circuitbreaker.Open, cfg.CircuitBreaker.State())
+}
+
+func (s *resourceTestSuite) TestNewHttpClientCollapsesConcurrentReads_WhenSingleFlight() {
+	var sent int32
+	release := make(chan struct{})
+	cfg := config.ClientConfig{
+		SingleFlight: true,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&sent, 1)
+			<-release
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: toResponseBody(`{"id":"1"}`)}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 3)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, "http://testhost/things/1", nil)
+			s.Require().NoError(err)
+			resp, err := client.Do(req)
+			s.Require().NoError(err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			s.Require().NoError(err)
+			bodies[i] = string(body)
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	s.Equal(int32(1), atomic.LoadInt32(&sent))
+	s.Equal([]string{`{"id":"1"}`, `{"id":"1"}`, `{"id":"1"}`}, bodies)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientDoesntCollapseConcurrentReads_WhenHeadersDiffer() {
+	var sent int32
+	release := make(chan struct{})
+	cfg := config.ClientConfig{
+		SingleFlight: true,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&sent, 1)
+			<-release
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: toResponseBody(req.Header.Get("Authorization"))}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 2)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, "http://testhost/things/1", nil)
+			s.Require().NoError(err)
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer token-%d", i))
+			resp, err := client.Do(req)
+			s.Require().NoError(err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			s.Require().NoError(err)
+			bodies[i] = string(body)
+		}(i)
+	}
+	s.Eventually(func() bool { return atomic.LoadInt32(&sent) == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	s.Equal([]string{"Bearer token-0", "Bearer token-1"}, bodies)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientLimitsConcurrentRequests_WhenBulkheadIsSet() {
+	timeout := 10 * time.Millisecond
+	cfg := config.ClientConfig{
+		BulkheadSize:    1,
+		BulkheadTimeout: &timeout,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	resp, err := client.Do(req)
+	s.Require().NoError(err)
+	_, err = client.Do(req)
+	s.ErrorIs(err, ErrBulkheadFull)
+
+	resp.Body.Close()
+	resp, err = client.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+}
+
+func (s *resourceTestSuite) TestNewHttpClientClassifiesTransportErrors() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	client := NewHttpClient(config.ClientConfig{HttpClient: &http.Client{}})
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	s.Require().NoError(err)
+
+	_, err = client.Do(req, re.WithTimeout(10*time.Millisecond))
+	s.ErrorIs(err, ErrTimeout)
+	s.ErrorIs(err, context.DeadlineExceeded)
+
+	server.Close()
+	_, err = client.Do(req)
+	s.ErrorIs(err, ErrConnection)
+	s.NotErrorIs(err, ErrTimeout)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientLogsRequests_WhenDebugLoggingIsOn() {
+	var entries []map[string]string
+	toggle := debuglog.NewToggle(true)
+	fake := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	cfg := config.ClientConfig{
+		DebugLog: toggle,
+		Clock:    fake,
+		Logger: logger.Func(func(level logger.Level, msg string, fields ...logger.Field) {
+			entry := map[string]string{"level": level.String(), "msg": msg}
+			for _, f := range fields {
+				entry[f.Key] = f.Value
+			}
+			entries = append(entries, entry)
+		}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			fake.Advance(time.Second)
+			return &http.Response{StatusCode: http.StatusCreated, Header: http.Header{"X-Request-Id": []string{"1"}},
+				Body: toResponseBody(`{"data":{"id":"1","attributes":{"iban":"GB33BUKB20201555555555"}}}`)}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "http://testhost/things", strings.NewReader(`{"data":{"attributes":{"account_number":"41426819"}}}`))
+		s.Require().NoError(err)
+		req.Header.Set("Authorization", "Bearer secret")
+		return req
+	}
+
+	resp, err := client.Do(newRequest())
+	s.Require().NoError(err)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	toggle.Disable()
+	resp, err = client.Do(newRequest())
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal(`{"data":{"id":"1","attributes":{"iban":"GB33BUKB20201555555555"}}}`, string(body))
+	s.Require().Len(entries, 2)
+	s.Equal("debug", entries[0]["level"])
+	s.Equal("request", entries[0]["msg"])
+	s.Equal("POST", entries[0]["method"])
+	s.Equal("http://testhost/things", entries[0]["url"])
+	s.Contains(entries[0]["header"], "Authorization: REDACTED; Idempotency-Key: ")
+	s.NotContains(entries[0]["header"], "secret")
+	s.Equal(`{"data":{"attributes":{"account_number":"REDACTED"}}}`, entries[0]["body"])
+	s.Equal("response", entries[1]["msg"])
+	s.Equal("201", entries[1]["status"])
+	s.Equal("1s", entries[1]["duration"])
+	s.Equal("X-Request-Id: 1", entries[1]["header"])
+	s.Equal(`{"data":{"attributes":{"iban":"REDACTED"},"id":"1"}}`, entries[1]["body"])
+}
+
+func (s *resourceTestSuite) TestNewHttpClientLogsBodyPrefix_WhenDebugLoggingIsOn() {
+	var bodies []string
+	expected := strings.Repeat("a", re.DefaultBodyLimit+10)
+	responseBody := &countingReader{Reader: strings.NewReader(expected)}
+	cfg := config.ClientConfig{
+		DebugLog: debuglog.NewToggle(true),
+		Logger: logger.Func(func(level logger.Level, msg string, fields ...logger.Field) {
+			for _, f := range fields {
+				if f.Key == "body" {
+					bodies = append(bodies, f.Value)
+				}
+			}
+		}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(responseBody)}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things/1", nil)
+	s.Require().NoError(err)
+
+	resp, err := client.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(re.DefaultBodyLimit, responseBody.read)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+
+	s.Equal(expected, string(body))
+	s.Equal([]string{"", fmt.Sprintf("<%d bytes>", re.DefaultBodyLimit)}, bodies)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientWithStatsLogsSlowRequests() {
+	var messages []string
+	var fields []logger.Field
+	threshold := 10 * time.Millisecond
+	cfg := config.ClientConfig{
+		SlowRequestThreshold: &threshold,
+		Logger: logger.Func(func(level logger.Level, msg string, f ...logger.Field) {
+			messages = append(messages, level.String()+": "+msg)
+			fields = f
+		}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			time.Sleep(20 * time.Millisecond)
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	recorder := istats.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things/"+uuid.NewString(), nil)
+	s.Require().NoError(err)
+
+	resp, err := NewHttpClientWithStats(cfg, recorder).Do(req)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal([]string{"warn: slow request"}, messages)
+	s.Require().NotEmpty(fields)
+	s.Equal(logger.Field{Key: "operation", Value: "GET /things/:id"}, fields[0])
+	s.Equal(uint64(1), recorder.Snapshot().Features[stats.FeatureSlowRequest])
+}
+
+func (s *resourceTestSuite) TestNewHttpClientLogsDeprecationsOnce() {
+	var messages []string
+	var fields []logger.Field
+	cfg := config.ClientConfig{
+		Logger: logger.Func(func(level logger.Level, msg string, f ...logger.Field) {
+			messages = append(messages, level.String()+": "+msg)
+			fields = f
+		}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{result.DeprecationHeader: []string{"@1767225600"}}
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, "http://testhost/things/"+uuid.NewString(), nil)
+		s.Require().NoError(err)
+		resp, err := client.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+
+	s.Equal([]string{"warn: deprecated endpoint"}, messages)
+	s.Equal([]logger.Field{
+		{Key: "operation", Value: "GET /things/:id"},
+		{Key: "deprecation", Value: "deprecated, since 2026-01-01"},
+	}, fields)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientPassesDeprecationsToHandler() {
+	var operations []string
+	cfg := config.ClientConfig{
+		OnDeprecation: func(operation string, deprecation result.Deprecation) {
+			operations = append(operations, operation)
+		},
+		Logger: logger.Func(func(level logger.Level, msg string, f ...logger.Field) {
+			s.Fail("unexpected log entry", msg)
+		}),
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{result.DeprecationHeader: []string{"true"}}
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodDelete, "http://testhost/things/"+uuid.NewString(), nil)
+		s.Require().NoError(err)
+		resp, err := client.Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+
+	s.Equal([]string{"DELETE /things/:id", "DELETE /things/:id"}, operations)
+}
+
+func (s *resourceTestSuite) TestClose() {
+	cfg := config.ClientConfig{
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	client := NewHttpClient(cfg)
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	s.Require().NoError(Close(client))
+	_, err = client.Do(req)
+
+	s.ErrorIs(err, ErrClientClosed)
+	s.NoError(Close(s.mockHttpClient))
+}
+
+func (s *resourceTestSuite) TestNewHttpClientWithStatsCountsStaleReads() {
+	maxAge := time.Minute
+	status := http.StatusOK
+	cfg := config.ClientConfig{
+		StaleReadMaxAge: &maxAge,
+		RetryPolicy:     &retry.Policy{},
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Header: http.Header{}, Body: toResponseBody(`{"id":"1"}`)}, nil
+		})},
+	}
+	recorder := istats.NewRecorder()
+	client := NewHttpClientWithStats(cfg, recorder)
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things/1", nil)
+	s.Require().NoError(err)
+	req = ire.AllowStale(req)
+
+	resp, err := client.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	status = http.StatusBadGateway
+	resp, err = client.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(uint64(1), recorder.Snapshot().Features[stats.FeatureCacheHit])
+}
+
+func (s *resourceTestSuite) TestNewHttpClientWithStatsCountsRetries() {
+	attempts := 0
+	policy := retry.DefaultPolicy()
+	policy.BaseDelay, policy.Jitter = time.Millisecond, 0
+	cfg := config.ClientConfig{
+		RetryPolicy: &policy,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return &http.Response{StatusCode: http.StatusBadGateway, Body: toResponseBody("")}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	recorder := istats.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	resp, err := NewHttpClientWithStats(cfg, recorder).Do(req)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(uint64(1), recorder.Snapshot().Features[stats.FeatureRetry])
+}
+
+func (s *resourceTestSuite) TestNewHttpClientRecordsMetrics() {
+	fake := &metricstest.Fake{}
+	cfg := config.ClientConfig{
+		Metrics: fake,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	for i := 0; i < 2; i++ {
+		resp, err := NewHttpClient(cfg).Do(req)
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+
+	s.Equal(float64(2), fake.Value(metrics.RequestsTotal, "GET /things", "200"))
+}
+
+func (s *resourceTestSuite) TestCheckConfigReturnsError_WhenMetricsCantBeRegistered() {
+	baseUrl := testBaseUrl
+	orgID := uuid.New()
+	errRegister := errors.New("duplicate metrics collector registration attempted")
+
+	err := CheckConfig(config.ClientConfig{BaseUrl: &baseUrl, OrganisationID: &orgID, Metrics: &metricstest.Fake{Err: errRegister}})
+
+	s.ErrorIs(err, errRegister)
+}
+
+func (s *resourceTestSuite) TestCheckConfigReturnsError_WhenTokenUrlIsMissing() {
+	baseUrl, orgID, clientID := testBaseUrl, uuid.New(), "client-id"
+
+	err := CheckConfig(config.ClientConfig{BaseUrl: &baseUrl, OrganisationID: &orgID, ClientID: &clientID})
+
+	s.ErrorIs(err, ErrTokenUrlNotConfigured)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientSignsRequestsWithPrivateKeyFile() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	keyFile := filepath.Join(s.T().TempDir(), "private-key.pem")
+	s.Require().NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0o600))
+	keyID := "key-1"
+	var signature, digest string
+	cfg := config.ClientConfig{
+		SigningKeyID:   &keyID,
+		PrivateKeyFile: &keyFile,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			signature, digest = req.Header.Get(signing.SignatureHeader), req.Header.Get(signing.DigestHeader)
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://testhost/things", strings.NewReader(`{"id":"1"}`))
+	s.Require().NoError(err)
+
+	resp, err := NewHttpClient(cfg).Do(req)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.True(strings.HasPrefix(signature, `keyId="key-1",algorithm="rsa-sha256",`))
+	s.Equal(signing.Digest([]byte(`{"id":"1"}`)), digest)
+}
+
+func (s *resourceTestSuite) TestNewHttpClientSignsRequestsWithSigner() {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	signer, err := signing.NewKeySigner("kms-key", key)
+	s.Require().NoError(err)
+	keyID := "ignored-key"
+	var signature string
+	cfg := config.ClientConfig{
+		Signer:       signer,
+		SigningKeyID: &keyID,
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			signature = req.Header.Get(signing.SignatureHeader)
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+	s.Require().NoError(err)
+
+	resp, err := NewHttpClient(cfg).Do(req)
+
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.True(strings.HasPrefix(signature, `keyId="kms-key",algorithm="ed25519",`))
+
+	baseUrl, orgID := testBaseUrl, uuid.New()
+	s.NoError(CheckConfig(config.ClientConfig{BaseUrl: &baseUrl, OrganisationID: &orgID, Signer: signer}))
+}
+
+func (s *resourceTestSuite) TestCheckConfigReturnsError_WhenSigningKeyIsInvalid() {
+	baseUrl, orgID, keyID := testBaseUrl, uuid.New(), "key-1"
+	keyFile := filepath.Join(s.T().TempDir(), "private-key.pem")
+	s.Require().NoError(os.WriteFile(keyFile, []byte("not a key"), 0o600))
+
+	s.ErrorIs(CheckConfig(config.ClientConfig{BaseUrl: &baseUrl, OrganisationID: &orgID, SigningKeyID: &keyID}), ErrSigningKeyNotConfigured)
+	s.ErrorIs(CheckConfig(config.ClientConfig{BaseUrl: &baseUrl, OrganisationID: &orgID, SigningKeyID: &keyID, PrivateKeyFile: &keyFile}), signing.ErrInvalidPrivateKey)
+}
+
+func (s *resourceTestSuite) TestDefaultHeader() {
+	userAgent := "form3-client/1.0"
+	cfg := config.ClientConfig{
+		UserAgent: &userAgent,
+		Headers:   map[string]string{"x-client-version": "1.2.0"},
+	}
+
+	header := DefaultHeader(cfg)
+
+	s.Equal(http.Header{
+		"User-Agent":       []string{userAgent},
+		"X-Client-Version": []string{"1.2.0"},
+	}, header)
+}
+
+func (s *resourceTestSuite) TestErrorFromResponseLogsWithConfiguredLogger() {
+	var messages []string
+	s.client.Config.Logger = logger.Func(func(level logger.Level, msg string, fields ...logger.Field) {
+		messages = append(messages, level.String()+": "+msg)
+	})
+
+	err := s.client.ErrorFromResponse(&http.Response{StatusCode: http.StatusInternalServerError, Body: toResponseBody(`{"error_message":"backend error"}`)})
+
+	s.ErrorIs(err, ErrServerError)
+	s.Equal([]string{"error: server error: [500] backend error"}, messages)
+}
+
+func (s *resourceTestSuite) TestErrorFromResponse() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"name is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "unauthorized",
+			responseStatus: http.StatusUnauthorized,
+			expectedError:  ErrUnauthorized,
+		},
+		{
+			name:           "forbidden",
+			responseStatus: http.StatusForbidden,
+			expectedError:  ErrForbidden,
+		},
+		{
+			name:           "not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  errThingNotFound,
+		},
+		{
+			name:           "invalid version",
+			responseStatus: http.StatusConflict,
+			expectedError:  errInvalidThingVersion,
+		},
+		{
+			name:           "server bad gateway",
+			responseStatus: http.StatusBadGateway,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "rate limited",
+			responseStatus: http.StatusTooManyRequests,
+			expectedError:  ErrRateLimited,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			err := s.client.ErrorFromResponse(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)})
+
+			s.ErrorIs(err, test.expectedError)
+		})
+	}
+	s.Equal("name is required", s.invalidRequest)
+}
+
+func (s *resourceTestSuite) TestErrorFromResponseReturnsAPIError() {
+	resp := &http.Response{
+		StatusCode: http.StatusConflict,
+		Header:     http.Header{result.RequestIDHeader: []string{"request-1"}},
+		Body:       toResponseBody(`{"error_code":"409-1","error_message":"invalid version"}`),
+	}
+
+	err := s.client.ErrorFromResponse(resp)
+
+	s.ErrorIs(err, errInvalidThingVersion)
+	var apiErr *result.APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal(http.StatusConflict, apiErr.StatusCode)
+	s.Equal("409-1", apiErr.ErrorCode)
+	s.Equal("invalid version", apiErr.ErrorMessage)
+	s.Equal("request-1", apiErr.RequestID)
+}
+
+func (s *resourceTestSuite) TestErrorFromResponseParsesValidationErrors() {
+	resp := &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Body:       toResponseBody(`{"error_message":"validation failure list:\nname in body is required"}`),
+	}
+
+	err := s.client.ErrorFromResponse(resp)
+
+	s.ErrorIs(err, ErrInvalidRequest)
+	s.Equal([]result.ValidationError{{Field: "name", Rule: result.RuleRequired, Message: "name in body is required"}},
+		result.ValidationErrors(err))
+}
+
+func (s *resourceTestSuite) TestErrorFromResponseGivesGuidance_WhenCredentialsAreRejected() {
+	err := s.client.ErrorFromResponse(&http.Response{StatusCode: http.StatusUnauthorized, Body: toResponseBody("")})
+
+	s.ErrorIs(err, ErrUnauthorized)
+	s.NotErrorIs(err, ErrUnexpectedServerResponse)
+	s.Contains(err.Error(), "check the API key")
+}
+
+func (s *resourceTestSuite) TestErrorFromResponseReturnsRetryAfter_WhenRateLimited() {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: toResponseBody("")}
+	resp.Header.Set("Retry-After", "5")
+
+	err := s.client.ErrorFromResponse(resp)
+
+	s.ErrorIs(err, ErrRateLimited)
+	s.EqualError(err, "rate limited: retry after 5s: [429]")
+}
+
+func (s *resourceTestSuite) TestErrorFromResponseReturnsUnexpectedResponse_WhenResourceErrorsNotSet() {
+	s.client.ErrNotFound = nil
+	s.client.ErrInvalidVersion = nil
+
+	s.ErrorIs(s.client.ErrorFromResponse(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}), ErrUnexpectedServerResponse)
+	s.ErrorIs(s.client.ErrorFromResponse(&http.Response{StatusCode: http.StatusConflict, Body: toResponseBody("")}), ErrUnexpectedServerResponse)
+}
+
+func (s *resourceTestSuite) TestErrorFromResponseCallsOnError() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	s.client.HTTP = NewHttpClient(config.ClientConfig{HttpClient: server.Client()})
+	s.client.Config.BaseUrl = &server.URL
+	var failed []string
+	var errs []error
+	onError := re.WithOnError(func(req *http.Request, err error) {
+		failed = append(failed, req.Method+" "+req.URL.Path)
+		errs = append(errs, err)
+	})
+
+	_, err := s.client.Fetch(uuid.New(), onError)
+
+	s.ErrorIs(err, errThingNotFound)
+	s.Require().Len(failed, 1)
+	s.Contains(failed[0], "GET "+testUrl+"/")
+	s.Require().Len(errs, 1)
+	s.ErrorIs(errs[0], errThingNotFound)
+
+	_, err = s.client.List(0, 10)
+	s.ErrorIs(err, errThingNotFound)
+	s.Len(errs, 1)
+}
+
+func (s *resourceTestSuite) TestCreate() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testBaseUrl+testUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{\"id\":\"1\",\"name\":\"created\"}}")}, nil).
+		Once()
+
+	created, err := s.client.Create(uuid.New(), thing{ID: "1", Name: "new"}, re.RequestEnricher{})
+	s.Require().NoError(err)
+	s.Equal(thing{ID: "1", Name: "created"}, *created)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.client.BodyToData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(thing{ID: "1", Name: "new"}, *requested)
+	s.Equal(uint64(1), s.client.Stats.Snapshot().Features[stats.FeatureEnricher])
+}
+
+func (s *resourceTestSuite) TestCreateReturnsAlreadyExistsError() {
+	errThingExists := fmt.Errorf("thing %w", ErrResourceAlreadyExists)
+	conflict := func() {
+		s.mockHttpClient.
+			On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testBaseUrl+testUrl)), mock.Anything).
+			Return(&http.Response{StatusCode: http.StatusConflict, Body: toResponseBody(`{"error_message":"id already exists"}`)}, nil).
+			Once()
+	}
+
+	conflict()
+	_, err := s.client.Create(uuid.New(), thing{ID: "1"})
+	s.ErrorIs(err, ErrResourceAlreadyExists)
+	s.NotErrorIs(err, errInvalidThingVersion)
+
+	conflict()
+	s.client.ErrAlreadyExists = errThingExists
+	_, err = s.client.Create(uuid.New(), thing{ID: "1"})
+	s.ErrorIs(err, errThingExists)
+	s.ErrorIs(err, ErrResourceAlreadyExists)
+	s.EqualError(err, "thing already exists: [409] id already exists")
+}
+
+func (s *resourceTestSuite) TestCreateReturnsExistingResource_WhenConfigured() {
+	id := uuid.New()
+	s.client.Config.ReturnExistingOnCreate = true
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testBaseUrl+testUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusConflict, Body: toResponseBody("")}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s%s/%s", testBaseUrl, testUrl, id))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(`{"data":{"id":"1","name":"existing"}}`)}, nil).
+		Once()
+
+	existing, err := s.client.Create(id, thing{ID: "1", Name: "new"})
+
+	s.Require().NoError(err)
+	s.Equal(thing{ID: "1", Name: "existing"}, *existing)
+}
+
+func (s *resourceTestSuite) TestFetch() {
+	_, err := s.client.Fetch(uuid.Nil)
+	s.ErrorIs(err, ErrNilUUID)
+
+	id := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s%s/%s", testBaseUrl, testUrl, id))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("{\"data\":{\"id\":\"" + id.String() + "\"}}")}, nil).
+		Once()
+
+	actual, err := s.client.Fetch(id)
+	s.Require().NoError(err)
+	s.Equal(id.String(), actual.ID)
+}
+
+func (s *resourceTestSuite) TestFetchServesStaleResponse_WhenAPIIsFailing() {
+	id := uuid.New()
+	maxAge := time.Minute
+	baseUrl := "http://testhost"
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	cfg := config.ClientConfig{
+		BaseUrl:         &baseUrl,
+		StaleReadMaxAge: &maxAge,
+		RetryPolicy:     &retry.Policy{},
+		HttpClient: &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body := "{\"data\":[{\"id\":\"" + id.String() + "\"}]}"
+			if req.URL.Path == testUrl+"/"+id.String() {
+				body = "{\"data\":{\"id\":\"" + id.String() + "\"}}"
+			}
+			return &http.Response{StatusCode: int(status.Load()), Header: http.Header{}, Body: toResponseBody(body)}, nil
+		})},
+	}
+	s.client.Config = cfg
+	s.client.HTTP = NewHttpClient(cfg)
+
+	_, err := s.client.Fetch(id)
+	s.Require().NoError(err)
+	_, err = s.client.List(0, 10)
+	s.Require().NoError(err)
+	status.Store(http.StatusBadGateway)
+
+	actual, err := s.client.Fetch(id)
+	s.Require().NoError(err)
+	s.Equal(id.String(), actual.ID)
+	_, err = s.client.List(0, 10)
+	s.Error(err)
+}
+
+func (s *resourceTestSuite) TestFetchReturnsError_WhenResponseCannotBeDecoded() {
+	id := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s%s/%s", testBaseUrl, testUrl, id))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("{\"data\":{\"id\":1}}")}, nil).
+		Once()
+
+	_, err := s.client.Fetch(id)
+
+	s.ErrorIs(err, ErrUnexpectedServerResponse)
+	var typeErr *json.UnmarshalTypeError
+	s.ErrorAs(err, &typeErr)
+}
+
+func (s *resourceTestSuite) TestList() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testBaseUrl+testUrl+"?page[number]=1&page[size]=2")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("{\"data\":[{\"id\":\"1\"},{\"id\":\"2\"}]}")}, nil).
+		Once()
+
+	actual, err := s.client.List(1, 2)
+	s.Require().NoError(err)
+	s.Equal([]thing{{ID: "1"}, {ID: "2"}}, actual)
+}
+
+func (s *resourceTestSuite) TestDeleteVersion() {
+	id := uuid.New()
+	url := fmt.Sprintf("%s%s/%s?version=3", testBaseUrl, testUrl, id)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, url)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, url)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusTeapot, Body: toResponseBody("")}, nil).
+		Once()
+
+	s.NoError(s.client.DeleteVersion(id, 3))
+	s.ErrorIs(s.client.DeleteVersion(id, 3), ErrUnexpectedServerResponse)
+}
+
+func (s *resourceTestSuite) TestFind() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testBaseUrl+testUrl+"?filter[name]=a")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("{\"data\":[{\"id\":\"1\",\"name\":\"a\"}]}")}, nil).
+		Once()
+
+	actual, err := s.client.Find("filter[name]=a")
+	s.Require().NoError(err)
+	s.Equal([]thing{{ID: "1", Name: "a"}}, actual)
+}
+
+func (s *resourceTestSuite) TestDelete() {
+	id := uuid.New()
+	url := fmt.Sprintf("%s%s/%s", testBaseUrl, testUrl, id)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, url)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, url)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	s.NoError(s.client.Delete(id))
+	s.ErrorIs(s.client.Delete(id), errThingNotFound)
+	s.ErrorIs(s.client.Delete(uuid.Nil), ErrNilUUID)
+}
+
+func (s *resourceTestSuite) TestDownload() {
+	id := uuid.New()
+	url := fmt.Sprintf("%s%s/%s/file", testBaseUrl, testUrl, id)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, url)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("a,b\n1,2\n")}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, url)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	var buf strings.Builder
+	written, err := s.client.Download(id, "/file", &buf)
+	s.Require().NoError(err)
+	s.Equal(int64(8), written)
+	s.Equal("a,b\n1,2\n", buf.String())
+
+	_, err = s.client.Download(id, "/file", &buf)
+	s.ErrorIs(err, errThingNotFound)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+// countingReader counts the bytes read from the reader.
+type countingReader struct {
+	io.Reader
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += n
+	return n, err
+}
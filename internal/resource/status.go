@@ -0,0 +1,109 @@
+package resource
+
+import (
+	"errors"
+	"net/http"
+
+	conf "form3interview/internal/config"
+	ire "form3interview/internal/requestenricher"
+	"form3interview/pkg/result"
+)
+
+// StatusError maps the unsuccessful responses with the status code to the error.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+// NotFound maps the 404 Not Found responses to the error.
+func NotFound(err error) StatusError {
+	return StatusError{Code: http.StatusNotFound, Err: err}
+}
+
+// Conflict maps the 409 Conflict responses to the error.
+func Conflict(err error) StatusError {
+	return StatusError{Code: http.StatusConflict, Err: err}
+}
+
+// ErrorMapper maps the unsuccessful responses of a client to errors. The status handlers of the config
+// (see config.WithStatusHandler) are tried first, then the status errors of the call, and then the default
+// mapping table. The responses with other status codes are mapped to ErrUnexpectedServerResponse.
+// The errors are returned as *result.APIError and reported to the OnError hook of the RequestEnricher.
+type ErrorMapper struct {
+	Config conf.ClientConfig
+	// ErrInvalidRequest, ErrServerError, ErrServerUnavailable and ErrUnexpectedServerResponse replace the shared
+	// errors of the mapping table when they are set, so the clients can return their own errors.
+	ErrInvalidRequest           error
+	ErrServerError              error
+	ErrServerUnavailable        error
+	ErrUnexpectedServerResponse error
+	// OnInvalidRequest is called with the error message of the 400 Bad Request responses. It is optional.
+	OnInvalidRequest func(errorMessage string)
+}
+
+// table returns the default mapping table with the errors of the client.
+func (m ErrorMapper) table() map[int]error {
+	serverError := orDefault(m.ErrServerError, ErrServerError)
+	return map[int]error{
+		http.StatusBadRequest:          orDefault(m.ErrInvalidRequest, ErrInvalidRequest),
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusInternalServerError: serverError,
+		http.StatusBadGateway:          serverError,
+		http.StatusGatewayTimeout:      serverError,
+		http.StatusServiceUnavailable:  orDefault(m.ErrServerUnavailable, ErrServerUnavailable),
+	}
+}
+
+// ErrorFromResponse maps the unsuccessful response to an error. The status errors replace the errors of the
+// default mapping table for the call, i.e. with the resource specific not found errors.
+func (m ErrorMapper) ErrorFromResponse(resp *http.Response, statusErrors ...StatusError) (err error) {
+	defer func() { err = ire.ReportError(resp, err) }()
+
+	if handle, ok := m.Config.StatusHandlers[resp.StatusCode]; ok {
+		if err := handle(resp); err != nil {
+			return err
+		}
+	}
+
+	mapped := m.mappedError(resp, statusErrors)
+	err = result.NewAPIErrorWithLimit(resp, mapped, m.Config.ErrorBodyLimit)
+	switch {
+	case mapped == orDefault(m.ErrUnexpectedServerResponse, ErrUnexpectedServerResponse):
+		m.Config.Log().Infof("%s", err)
+	case resp.StatusCode == http.StatusTooManyRequests:
+		m.Config.Log().Warnf("%s", err)
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusServiceUnavailable:
+		m.Config.Log().Debugf("%s", err)
+	default:
+		m.Config.Log().Errorf("%s", err)
+	}
+
+	var apiErr *result.APIError
+	if resp.StatusCode == http.StatusBadRequest && m.OnInvalidRequest != nil && errors.As(err, &apiErr) {
+		m.OnInvalidRequest(apiErr.ErrorMessage)
+	}
+	return err
+}
+
+func (m ErrorMapper) mappedError(resp *http.Response, statusErrors []StatusError) error {
+	for _, se := range statusErrors {
+		if se.Code == resp.StatusCode && se.Err != nil {
+			return se.Err
+		}
+	}
+	if err, ok := m.table()[resp.StatusCode]; ok {
+		return err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return RateLimited(resp)
+	}
+	return orDefault(m.ErrUnexpectedServerResponse, ErrUnexpectedServerResponse)
+}
+
+func orDefault(err, defaultErr error) error {
+	if err != nil {
+		return err
+	}
+	return defaultErr
+}
@@ -0,0 +1,175 @@
+package resource
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/internal/config"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/result"
+)
+
+var (
+	errThingInvalidRequest = errors.New("invalid thing request")
+	errThingUnexpected     = errors.New("unexpected thing response")
+)
+
+type statusTestSuite struct {
+	suite.Suite
+	mapper   ErrorMapper
+	messages []string
+}
+
+func TestStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(statusTestSuite))
+}
+
+func (s *statusTestSuite) SetupTest() {
+	s.messages = nil
+	s.mapper = ErrorMapper{
+		Config: config.ClientConfig{
+			Logger: logger.Func(func(level logger.Level, msg string, fields ...logger.Field) {
+				s.messages = append(s.messages, level.String()+": "+msg)
+			}),
+		},
+	}
+}
+
+func (s *statusTestSuite) response(status int) *http.Response {
+	return &http.Response{StatusCode: status, Body: toResponseBody(`{"error_message":"failed"}`)}
+}
+
+func (s *statusTestSuite) TestDefaultTable() {
+	for status, expected := range map[int]error{
+		http.StatusBadRequest:          ErrInvalidRequest,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusNotFound:            ErrUnexpectedServerResponse,
+		http.StatusConflict:            ErrUnexpectedServerResponse,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: ErrServerError,
+		http.StatusBadGateway:          ErrServerError,
+		http.StatusServiceUnavailable:  ErrServerUnavailable,
+		http.StatusGatewayTimeout:      ErrServerError,
+		http.StatusTeapot:              ErrUnexpectedServerResponse,
+	} {
+		s.Run(http.StatusText(status), func() {
+			err := s.mapper.ErrorFromResponse(s.response(status))
+
+			s.ErrorIs(err, expected)
+			var apiErr *result.APIError
+			s.Require().ErrorAs(err, &apiErr)
+			s.Equal(status, apiErr.StatusCode)
+			s.Equal("failed", apiErr.ErrorMessage)
+		})
+	}
+}
+
+func (s *statusTestSuite) TestClientErrorsReplaceSharedErrors() {
+	s.mapper.ErrInvalidRequest = errThingInvalidRequest
+	s.mapper.ErrUnexpectedServerResponse = errThingUnexpected
+
+	s.ErrorIs(s.mapper.ErrorFromResponse(s.response(http.StatusBadRequest)), errThingInvalidRequest)
+	s.ErrorIs(s.mapper.ErrorFromResponse(s.response(http.StatusTeapot)), errThingUnexpected)
+	s.ErrorIs(s.mapper.ErrorFromResponse(s.response(http.StatusInternalServerError)), ErrServerError)
+}
+
+func (s *statusTestSuite) TestStatusErrorsOverrideTable() {
+	err := s.mapper.ErrorFromResponse(s.response(http.StatusNotFound), NotFound(errThingNotFound), Conflict(errInvalidThingVersion))
+	s.ErrorIs(err, errThingNotFound)
+
+	err = s.mapper.ErrorFromResponse(s.response(http.StatusConflict), NotFound(errThingNotFound), Conflict(errInvalidThingVersion))
+	s.ErrorIs(err, errInvalidThingVersion)
+
+	err = s.mapper.ErrorFromResponse(s.response(http.StatusForbidden), StatusError{Code: http.StatusForbidden, Err: errThingNotFound})
+	s.ErrorIs(err, errThingNotFound)
+	s.NotErrorIs(err, ErrForbidden)
+}
+
+func (s *statusTestSuite) TestStatusErrorsWithoutErrorAreIgnored() {
+	err := s.mapper.ErrorFromResponse(s.response(http.StatusNotFound), NotFound(nil))
+
+	s.ErrorIs(err, ErrUnexpectedServerResponse)
+}
+
+func (s *statusTestSuite) TestStatusHandlerOverridesMapping() {
+	errGone := errors.New("gone")
+	s.mapper.Config.StatusHandlers = map[int]func(*http.Response) error{
+		http.StatusNotFound: func(resp *http.Response) error { return errGone },
+	}
+
+	err := s.mapper.ErrorFromResponse(s.response(http.StatusNotFound), NotFound(errThingNotFound))
+
+	s.Equal(errGone, err)
+	s.Empty(s.messages)
+}
+
+func (s *statusTestSuite) TestStatusHandlerFallsBackToMapping_WhenItReturnsNil() {
+	var handled int
+	s.mapper.Config.StatusHandlers = map[int]func(*http.Response) error{
+		http.StatusNotFound: func(resp *http.Response) error {
+			handled = resp.StatusCode
+			return nil
+		},
+	}
+
+	err := s.mapper.ErrorFromResponse(s.response(http.StatusNotFound), NotFound(errThingNotFound))
+
+	s.Equal(http.StatusNotFound, handled)
+	s.ErrorIs(err, errThingNotFound)
+}
+
+func (s *statusTestSuite) TestRateLimited() {
+	resp := s.response(http.StatusTooManyRequests)
+	resp.Header = http.Header{"Retry-After": []string{"5"}}
+
+	err := s.mapper.ErrorFromResponse(resp)
+
+	s.ErrorIs(err, ErrRateLimited)
+	s.EqualError(err, "rate limited: retry after 5s: [429] failed")
+	s.Equal([]string{"warn: rate limited: retry after 5s: [429] failed"}, s.messages)
+}
+
+func (s *statusTestSuite) TestLogLevels() {
+	for _, test := range []struct {
+		status   int
+		expected string
+	}{
+		{status: http.StatusBadRequest, expected: "error: invalid request: [400] failed"},
+		{status: http.StatusNotFound, expected: "info: unexpected server response: [404] failed"},
+		{status: http.StatusServiceUnavailable, expected: "debug: server unavailable: [503] failed"},
+		{status: http.StatusInternalServerError, expected: "error: server error: [500] failed"},
+	} {
+		s.Run(http.StatusText(test.status), func() {
+			s.messages = nil
+
+			s.mapper.ErrorFromResponse(s.response(test.status))
+
+			s.Equal([]string{test.expected}, s.messages)
+		})
+	}
+}
+
+func (s *statusTestSuite) TestErrorBodyLimit() {
+	s.mapper.Config.ErrorBodyLimit = 6
+
+	err := s.mapper.ErrorFromResponse(&http.Response{StatusCode: http.StatusBadGateway, Body: toResponseBody("<html>bad gateway</html>")})
+
+	s.ErrorIs(err, ErrServerError)
+	s.EqualError(err, "server error: [502] <html> [truncated]")
+}
+
+func (s *statusTestSuite) TestOnInvalidRequest() {
+	var messages []string
+	s.mapper.OnInvalidRequest = func(errorMessage string) {
+		messages = append(messages, errorMessage)
+	}
+
+	s.mapper.ErrorFromResponse(s.response(http.StatusBadRequest))
+	s.mapper.ErrorFromResponse(s.response(http.StatusInternalServerError))
+
+	s.Equal([]string{"failed"}, messages)
+}
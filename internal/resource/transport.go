@@ -0,0 +1,452 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	conf "form3interview/internal/config"
+	"form3interview/pkg/bulkhead"
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/clock"
+	"form3interview/pkg/debuglog"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/ratelimit"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/secret"
+)
+
+// The settings of the default transport's dialer.
+const (
+	defaultDialTimeout = 30 * time.Second
+	defaultKeepAlive   = 30 * time.Second
+)
+
+// failingTransport returns the error of the transport setup on every request, so a client created
+// without CheckConfig still reports an invalid TLS configuration.
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// rateLimitedTransport waits for the rate limiter before sending the requests. The retries wait too.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *ratelimit.Limiter
+}
+
+func (t rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return next.RoundTrip(req)
+}
+
+// circuitBreakerTransport rejects the requests while the circuit breaker is open and reports the network
+// errors and the 5xx responses as failures to it. Requests cancelled by the caller are not failures.
+type circuitBreakerTransport struct {
+	next    http.RoundTripper
+	breaker *circuitbreaker.Breaker
+}
+
+func (t circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	done, err := t.breaker.Allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		done(req.Context().Err() == nil)
+		return nil, err
+	}
+	done(resp.StatusCode >= http.StatusInternalServerError)
+	return resp, nil
+}
+
+// bulkheadTransport limits the concurrent requests with the bulkhead. The slot is held until the response body
+// is closed.
+type bulkheadTransport struct {
+	next     http.RoundTripper
+	bulkhead *bulkhead.Bulkhead
+}
+
+func (t bulkheadTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	release, err := t.bulkhead.Acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseOnClose gives back the bulkhead slot when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releaseOnClose) Close() error {
+	b.once.Do(b.release)
+	return b.ReadCloser.Close()
+}
+
+// singleFlightTransport collapses the concurrent GET requests of the same url and headers into one request. The
+// callers get a copy of the same response. The requests with other credentials or per-call headers are not collapsed,
+// so a caller never gets the response of a request it didn't authorize.
+type singleFlightTransport struct {
+	next http.RoundTripper
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is an in-flight request of the singleFlightTransport.
+type flight struct {
+	done chan struct{}
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+func newSingleFlightTransport(next http.RoundTripper) *singleFlightTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &singleFlightTransport{next: next, flights: map[string]*flight{}}
+}
+
+func (t *singleFlightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	key := flightKey(req)
+	t.mu.Lock()
+	if f, ok := t.flights[key]; ok {
+		t.mu.Unlock()
+		<-f.done
+		return f.response(req)
+	}
+	f := &flight{done: make(chan struct{})}
+	t.flights[key] = f
+	t.mu.Unlock()
+
+	f.resp, f.err = t.next.RoundTrip(req)
+	if f.err == nil {
+		f.body, f.err = io.ReadAll(f.resp.Body)
+		f.resp.Body.Close()
+	}
+
+	t.mu.Lock()
+	delete(t.flights, key)
+	t.mu.Unlock()
+	close(f.done)
+	return f.response(req)
+}
+
+// flightKey identifies the requests sharing a flight by their method, url and headers.
+func flightKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(req.Method + " " + req.URL.String())
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			key.WriteString("\n" + name + ": " + value)
+		}
+	}
+	return key.String()
+}
+
+// response returns a copy of the response of the flight with its own body.
+func (f *flight) response(req *http.Request) (*http.Response, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	resp.Header = f.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(f.body))
+	resp.Request = req
+	return &resp, nil
+}
+
+// debugTransport logs the requests and responses with their redacted headers and bodies at debug level while
+// the toggle is on. At most the first DefaultBodyLimit bytes of the bodies are logged, and the rest of the response
+// body is streamed to the caller.
+type debugTransport struct {
+	next   http.RoundTripper
+	toggle *debuglog.Toggle
+	log    logger.Logger
+	clock  clock.Clock
+}
+
+func (t debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if !t.toggle.Enabled() {
+		return next.RoundTrip(req)
+	}
+
+	t.log.Log(logger.LevelDebug, "request",
+		logger.Field{Key: "method", Value: req.Method},
+		logger.Field{Key: "url", Value: req.URL.Redacted()},
+		logger.Field{Key: "header", Value: debuglog.Header(req.Header)},
+		logger.Field{Key: "body", Value: debuglog.Body(requestBody(req))},
+	)
+	clk := clock.OrSystem(t.clock)
+	start := clk.Now()
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		t.log.Log(logger.LevelDebug, "request failed",
+			logger.Field{Key: "method", Value: req.Method},
+			logger.Field{Key: "url", Value: req.URL.Redacted()},
+			logger.Field{Key: "duration", Value: clk.Now().Sub(start).String()},
+			logger.Field{Key: "error", Value: err.Error()},
+		)
+		return nil, err
+	}
+
+	// the error of reading the body is returned to the caller reading the body
+	body, _ := io.ReadAll(re.PeekResponse(resp, re.DefaultBodyLimit).Body)
+	t.log.Log(logger.LevelDebug, "response",
+		logger.Field{Key: "method", Value: req.Method},
+		logger.Field{Key: "url", Value: req.URL.Redacted()},
+		logger.Field{Key: "status", Value: strconv.Itoa(resp.StatusCode)},
+		logger.Field{Key: "duration", Value: clk.Now().Sub(start).String()},
+		logger.Field{Key: "header", Value: debuglog.Header(resp.Header)},
+		logger.Field{Key: "body", Value: debuglog.Body(body)},
+	)
+	return resp, nil
+}
+
+// requestBody returns a copy of the body of the request if it can be read again.
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	b, _ := io.ReadAll(io.LimitReader(body, re.DefaultBodyLimit))
+	return b
+}
+
+// NewTransport creates the transport of the config with its connection settings and wrappers.
+func NewTransport(cfg conf.ClientConfig) http.RoundTripper {
+	var transport http.RoundTripper
+	switch base := cfg.Transport.(type) {
+	case nil:
+		transport = configureTransport(http.DefaultTransport.(*http.Transport).Clone(), cfg)
+	case *http.Transport:
+		transport = configureTransport(base.Clone(), cfg)
+	default:
+		transport = base
+	}
+
+	for _, wrap := range cfg.TransportWrappers {
+		transport = wrap(transport)
+	}
+	return transport
+}
+
+func configureTransport(transport *http.Transport, cfg conf.ClientConfig) http.RoundTripper {
+	transport.MaxConnsPerHost = cfg.MaxConns
+	transport.MaxIdleConnsPerHost = cfg.MaxConns
+	transport.MaxIdleConns = cfg.MaxConns
+	transport.IdleConnTimeout = *cfg.IdleConnTimeout
+	if cfg.DialTimeout != nil || cfg.KeepAlive != nil {
+		transport.DialContext = newDialer(cfg).DialContext
+	}
+	transport.DisableKeepAlives = transport.DisableKeepAlives || cfg.DisableKeepAlives
+	if cfg.ForceAttemptHTTP2 != nil {
+		transport.ForceAttemptHTTP2 = *cfg.ForceAttemptHTTP2
+	}
+	if cfg.TLSHandshakeTimeout != nil {
+		transport.TLSHandshakeTimeout = *cfg.TLSHandshakeTimeout
+	}
+	if cfg.ResponseHeaderTimeout != nil {
+		transport.ResponseHeaderTimeout = *cfg.ResponseHeaderTimeout
+	}
+	if cfg.ExpectContinueTimeout != nil {
+		transport.ExpectContinueTimeout = *cfg.ExpectContinueTimeout
+	}
+	if cfg.ProxyUrl != nil {
+		transport.Proxy = http.ProxyURL(cfg.ProxyUrl)
+	} else if transport.Proxy == nil {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	tlsConfig, err := loadTLSConfig(cfg, transport.TLSClientConfig)
+	if err != nil {
+		return failingTransport{err: err}
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
+
+// newDialer creates a dialer with the dial timeout and keep-alive period of the config
+// or the default transport's settings.
+func newDialer(cfg conf.ClientConfig) *net.Dialer {
+	dialer := &net.Dialer{Timeout: defaultDialTimeout, KeepAlive: defaultKeepAlive}
+	if cfg.DialTimeout != nil {
+		dialer.Timeout = *cfg.DialTimeout
+	}
+	if cfg.KeepAlive != nil {
+		dialer.KeepAlive = *cfg.KeepAlive
+	}
+	return dialer
+}
+
+// TLSConfig creates the TLS config of the TLS options. It returns nil if no TLS option is set.
+func TLSConfig(cfg conf.ClientConfig) (*tls.Config, error) {
+	return loadTLSConfig(cfg, nil)
+}
+
+// loadTLSConfig creates the TLS config of the TLS options on a clone of the configured or the given base TLS config.
+// It returns nil if no TLS option is set.
+func loadTLSConfig(cfg conf.ClientConfig, base *tls.Config) (*tls.Config, error) {
+	if cfg.TLSConfig == nil && cfg.CACertFile == nil && cfg.ClientCertFile == nil && cfg.MinTLSVersion == 0 {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if cfg.TLSConfig != nil {
+		tlsConfig = cfg.TLSConfig.Clone()
+	} else if base != nil {
+		tlsConfig = base.Clone()
+	}
+
+	if cfg.CACertFile != nil {
+		pool, err := loadCACertPool(*cfg.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertFile != nil {
+		keyFile := ""
+		if cfg.ClientKeyFile != nil {
+			keyFile = *cfg.ClientKeyFile
+		}
+		cert := &clientCert{certFile: secret.NewFile(*cfg.ClientCertFile), keyFile: secret.NewFile(keyFile), log: cfg.Log()}
+		if _, err := cert.load(); err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = cert.getClientCertificate
+	}
+
+	if cfg.MinTLSVersion != 0 {
+		tlsConfig.MinVersion = cfg.MinTLSVersion
+	}
+	return tlsConfig, nil
+}
+
+// clientCert is the client certificate of the cert and key files. The files are checked for changes on the
+// TLS handshakes, so the new connections use the rotated (i.e. renewed before expiry) certificate without
+// recreating the client. The previous certificate is used until both files form a valid key pair again.
+type clientCert struct {
+	certFile *secret.File
+	keyFile  *secret.File
+	log      logger.Printer
+
+	mu      sync.Mutex
+	certPEM []byte
+	keyPEM  []byte
+	cert    *tls.Certificate
+}
+
+func (c *clientCert) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return c.load()
+}
+
+// load returns the certificate of the files and parses it again if any of the files changed.
+func (c *clientCert) load() (*tls.Certificate, error) {
+	certPEM, err := c.certFile.Value()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client cert: %w", err)
+	}
+	keyPEM, err := c.keyFile.Value()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client cert: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cert != nil && bytes.Equal(certPEM, c.certPEM) && bytes.Equal(keyPEM, c.keyPEM) {
+		return c.cert, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		if c.cert != nil {
+			c.log.Warnf("failed to reload client cert, using the previous one: %s", err)
+			return c.cert, nil
+		}
+		return nil, fmt.Errorf("failed to load client cert: %w", err)
+	}
+	if c.cert != nil {
+		c.log.Infof("client cert reloaded from %s", c.certFile.Path())
+	}
+	c.certPEM, c.keyPEM, c.cert = certPEM, keyPEM, &cert
+	return c.cert, nil
+}
+
+// loadCACertPool adds the certificates of the CA cert file to the system cert pool.
+func loadCACertPool(caCertFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA cert file: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCACert, caCertFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,293 @@
+package resource
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"form3interview/internal/config"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/secret"
+)
+
+func (s *resourceTestSuite) TestNewTransport() {
+	idleConnTimeout := 42 * time.Second
+	cfg := config.ClientConfig{MaxConns: 42, IdleConnTimeout: &idleConnTimeout}
+
+	s.Run("default transport", func() {
+		transport, ok := NewTransport(cfg).(*http.Transport)
+
+		s.Require().True(ok)
+		s.Equal(42, transport.MaxConnsPerHost)
+		s.Equal(idleConnTimeout, transport.IdleConnTimeout)
+	})
+
+	s.Run("custom http transport", func() {
+		base := &http.Transport{DisableCompression: true}
+		cfg := cfg
+		cfg.Transport = base
+
+		transport, ok := NewTransport(cfg).(*http.Transport)
+
+		s.Require().True(ok)
+		s.NotSame(base, transport)
+		s.True(transport.DisableCompression)
+		s.Equal(42, transport.MaxConnsPerHost)
+		s.Zero(base.MaxConnsPerHost)
+	})
+
+	s.Run("transport timeouts", func() {
+		dialTimeout, tlsHandshakeTimeout := time.Second, 2*time.Second
+		responseHeaderTimeout, expectContinueTimeout := 3*time.Second, 4*time.Second
+		cfg := cfg
+		cfg.DialTimeout = &dialTimeout
+		cfg.TLSHandshakeTimeout = &tlsHandshakeTimeout
+		cfg.ResponseHeaderTimeout = &responseHeaderTimeout
+		cfg.ExpectContinueTimeout = &expectContinueTimeout
+		defaultTransport := http.DefaultTransport.(*http.Transport)
+
+		transport, ok := NewTransport(cfg).(*http.Transport)
+
+		s.Require().True(ok)
+		s.NotNil(transport.DialContext)
+		s.Equal(tlsHandshakeTimeout, transport.TLSHandshakeTimeout)
+		s.Equal(responseHeaderTimeout, transport.ResponseHeaderTimeout)
+		s.Equal(expectContinueTimeout, transport.ExpectContinueTimeout)
+		s.NotEqual(tlsHandshakeTimeout, defaultTransport.TLSHandshakeTimeout)
+	})
+
+	s.Run("keep-alive settings", func() {
+		forceAttemptHTTP2 := false
+		cfg := cfg
+		cfg.DisableKeepAlives = true
+		cfg.ForceAttemptHTTP2 = &forceAttemptHTTP2
+
+		transport, ok := NewTransport(cfg).(*http.Transport)
+
+		s.Require().True(ok)
+		s.True(transport.DisableKeepAlives)
+		s.False(transport.ForceAttemptHTTP2)
+	})
+
+	s.Run("default transport timeouts", func() {
+		defaultTransport := http.DefaultTransport.(*http.Transport)
+
+		transport, ok := NewTransport(cfg).(*http.Transport)
+
+		s.Require().True(ok)
+		s.Equal(defaultTransport.TLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+		s.Equal(defaultTransport.ExpectContinueTimeout, transport.ExpectContinueTimeout)
+	})
+
+	s.Run("proxy url", func() {
+		cfg := cfg
+		cfg.ProxyUrl = &url.URL{Scheme: "http", Host: "proxy:3128"}
+		req, err := http.NewRequest(http.MethodGet, "https://testhost/things", nil)
+		s.Require().NoError(err)
+
+		transport, ok := NewTransport(cfg).(*http.Transport)
+		s.Require().True(ok)
+		proxyUrl, err := transport.Proxy(req)
+
+		s.Require().NoError(err)
+		s.Equal("http://proxy:3128", proxyUrl.String())
+	})
+
+	s.Run("custom http transport without proxy uses env vars", func() {
+		cfg := cfg
+		cfg.Transport = &http.Transport{}
+
+		transport, ok := NewTransport(cfg).(*http.Transport)
+
+		s.Require().True(ok)
+		s.NotNil(transport.Proxy)
+	})
+
+	s.Run("custom round tripper with wrappers", func() {
+		var calls []string
+		cfg := cfg
+		cfg.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			calls = append(calls, "base")
+			return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+		})
+		wrapper := func(name string) func(http.RoundTripper) http.RoundTripper {
+			return func(next http.RoundTripper) http.RoundTripper {
+				return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name)
+					return next.RoundTrip(req)
+				})
+			}
+		}
+		cfg.TransportWrappers = []func(http.RoundTripper) http.RoundTripper{wrapper("inner"), wrapper("outer")}
+		req, err := http.NewRequest(http.MethodGet, "http://testhost/things", nil)
+		s.Require().NoError(err)
+
+		_, err = NewTransport(cfg).RoundTrip(req)
+
+		s.Require().NoError(err)
+		s.Equal([]string{"outer", "inner", "base"}, calls)
+	})
+}
+
+func (s *resourceTestSuite) TestNewDialer() {
+	dialTimeout, keepAlive := time.Second, -1*time.Second
+
+	s.Equal(&net.Dialer{Timeout: defaultDialTimeout, KeepAlive: defaultKeepAlive}, newDialer(config.ClientConfig{}))
+	s.Equal(&net.Dialer{Timeout: dialTimeout, KeepAlive: keepAlive}, newDialer(config.ClientConfig{DialTimeout: &dialTimeout, KeepAlive: &keepAlive}))
+}
+
+func (s *resourceTestSuite) TestNewTransportWithTLSOptions() {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	certFile, keyFile := s.writeServerCert(server)
+	idleConnTimeout := 42 * time.Second
+	orgID := uuid.New()
+	baseCfg := config.ClientConfig{BaseUrl: &server.URL, OrganisationID: &orgID, IdleConnTimeout: &idleConnTimeout}
+
+	s.Run("trusts the CA cert file", func() {
+		cfg := baseCfg
+		cfg.CACertFile = &certFile
+		client := http.Client{Transport: NewTransport(cfg)}
+
+		resp, err := client.Get(server.URL)
+
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+	})
+
+	s.Run("rejects unknown CA", func() {
+		client := http.Client{Transport: NewTransport(baseCfg)}
+
+		_, err := client.Get(server.URL)
+
+		var certErr x509.UnknownAuthorityError
+		s.ErrorAs(err, &certErr)
+	})
+
+	s.Run("applies client cert and min version on the TLS config", func() {
+		cfg := baseCfg
+		cfg.TLSConfig = &tls.Config{ServerName: "testhost"}
+		cfg.ClientCertFile = &certFile
+		cfg.ClientKeyFile = &keyFile
+		cfg.MinTLSVersion = tls.VersionTLS13
+
+		transport, ok := NewTransport(cfg).(*http.Transport)
+
+		s.Require().True(ok)
+		s.Equal("testhost", transport.TLSClientConfig.ServerName)
+		s.Require().NotNil(transport.TLSClientConfig.GetClientCertificate)
+		cert, err := transport.TLSClientConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		s.Require().NoError(err)
+		s.Len(cert.Certificate, 1)
+		s.Equal(uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+		s.Empty(cfg.TLSConfig.Certificates)
+	})
+
+	s.Run("invalid CA cert file", func() {
+		cfg := baseCfg
+		cfg.CACertFile = &keyFile
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		s.Require().NoError(err)
+
+		_, err = NewTransport(cfg).RoundTrip(req)
+
+		s.ErrorIs(err, ErrInvalidCACert)
+		s.ErrorIs(CheckConfig(cfg), ErrInvalidCACert)
+	})
+
+	s.Run("missing client cert file", func() {
+		cfg := baseCfg
+		missing := filepath.Join(s.T().TempDir(), "missing.pem")
+		cfg.ClientCertFile = &missing
+		cfg.ClientKeyFile = &keyFile
+
+		s.ErrorIs(CheckConfig(cfg), os.ErrNotExist)
+	})
+}
+
+func (s *resourceTestSuite) TestClientCertIsReloaded_WhenFilesChange() {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	certFile, keyFile := s.writeServerCert(server)
+	cert := &clientCert{certFile: secret.NewFile(certFile), keyFile: secret.NewFile(keyFile), log: logger.Printer{Logger: logger.Nop()}}
+
+	first, err := cert.load()
+	s.Require().NoError(err)
+
+	s.Run("keeps the certificate while the files are unchanged", func() {
+		same, err := cert.load()
+
+		s.Require().NoError(err)
+		s.Same(first, same)
+	})
+
+	s.Run("keeps the previous certificate while the key pair is invalid", func() {
+		cert.certFile = secret.NewFile(keyFile)
+
+		previous, err := cert.load()
+
+		s.Require().NoError(err)
+		s.Same(first, previous)
+	})
+
+	s.Run("loads the rotated certificate", func() {
+		rotatedCertFile, rotatedKeyFile, rotated := s.writeSelfSignedCert()
+		cert.certFile, cert.keyFile = secret.NewFile(rotatedCertFile), secret.NewFile(rotatedKeyFile)
+
+		reloaded, err := cert.getClientCertificate(&tls.CertificateRequestInfo{})
+
+		s.Require().NoError(err)
+		s.NotSame(first, reloaded)
+		s.Equal(rotated, reloaded.Certificate[0])
+	})
+}
+
+func (s *resourceTestSuite) writeSelfSignedCert() (string, string, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "form3-client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.Require().NoError(err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	s.Require().NoError(err)
+
+	dir := s.T().TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	s.Require().NoError(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	s.Require().NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile, der
+}
+
+func (s *resourceTestSuite) writeServerCert(server *httptest.Server) (string, string) {
+	dir := s.T().TempDir()
+	cert := server.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	s.Require().NoError(err)
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	s.Require().NoError(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600))
+	s.Require().NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0o600))
+	return certFile, keyFile
+}
@@ -0,0 +1,59 @@
+// Package schema detects when the API starts rejecting fields which are always sent by the clients,
+// so the users could upgrade before the rejection turns into hard failures.
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"form3interview/pkg/logger"
+)
+
+// EnvelopeFields are the fields every client sends regardless of the attributes given by the caller.
+var EnvelopeFields = []string{"data.id", "data.organisation_id", "data.type", "data.attributes"}
+
+// Warning describes a field which is always sent by the client but was rejected by the API.
+type Warning struct {
+	Resource    string
+	Field       string
+	Message     string
+	Remediation string
+}
+
+// Check returns a warning for each of the given fields referenced by the error message of a 400 Bad Request response.
+// A field is referenced when the message contains its JSON path or its name in the "<name> in body" form
+// used by the API validation errors. Nested fields (i.e. data.attributes.country) don't reference their parents.
+func Check(resource, errorMessage string, fields []string) []Warning {
+	var warnings []Warning
+	for _, field := range fields {
+		if !references(errorMessage, field) {
+			continue
+		}
+
+		warnings = append(warnings, Warning{
+			Resource: resource,
+			Field:    field,
+			Message:  errorMessage,
+			Remediation: "the field is always sent by the client so it can't be omitted by the caller; " +
+				"upgrade form3interview to a version supporting the current API schema or pin the API version in the base url",
+		})
+	}
+	return warnings
+}
+
+// Log emits the warning as a structured log entry.
+func (w Warning) Log(l logger.Logger) {
+	l.Log(logger.LevelWarn, "API rejected a field sent by the client",
+		logger.Field{Key: "resource", Value: w.Resource},
+		logger.Field{Key: "field", Value: w.Field},
+		logger.Field{Key: "error_message", Value: w.Message},
+		logger.Field{Key: "remediation", Value: w.Remediation},
+	)
+}
+
+func references(msg, field string) bool {
+	name := field[strings.LastIndex(field, ".")+1:]
+	pattern := fmt.Sprintf(`(^|[^\w.])(%s($|[^\w.])|%s in body)`, regexp.QuoteMeta(field), regexp.QuoteMeta(name))
+	return regexp.MustCompile(pattern).MatchString(msg)
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type schemaTestSuite struct {
+	suite.Suite
+}
+
+func TestSchemaTestSuite(t *testing.T) {
+	suite.Run(t, new(schemaTestSuite))
+}
+
+func (s *schemaTestSuite) TestCheck() {
+	for _, test := range []struct {
+		name           string
+		errorMessage   string
+		expectedFields []string
+	}{
+		{name: "json path", errorMessage: "data.organisation_id is not allowed", expectedFields: []string{"data.organisation_id"}},
+		{name: "validation error", errorMessage: "validation failure list:\ntype in body is not allowed", expectedFields: []string{"data.type"}},
+		{name: "message starting with the field", errorMessage: "id in body must be of type uuid", expectedFields: []string{"data.id"}},
+		{name: "multiple fields", errorMessage: "data.id and data.type are not allowed", expectedFields: []string{"data.id", "data.type"}},
+		{name: "nested field", errorMessage: "data.attributes.country in body is required"},
+		{name: "field name in other word", errorMessage: "bank_id in body is invalid"},
+		{name: "unrelated message", errorMessage: "invalid version"},
+	} {
+		s.Run(test.name, func() {
+			warnings := Check("accounts", test.errorMessage, EnvelopeFields)
+
+			var fields []string
+			for _, w := range warnings {
+				s.Equal("accounts", w.Resource)
+				s.Equal(test.errorMessage, w.Message)
+				s.NotEmpty(w.Remediation)
+				fields = append(fields, w.Field)
+			}
+			s.Equal(test.expectedFields, fields)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"sync"
+
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/stats"
+)
+
+// Recorder collects the counters of a client. It is safe for concurrent use and a nil Recorder ignores all records.
+type Recorder struct {
+	mu             sync.Mutex
+	schemaWarnings map[string]uint64
+	features       map[string]uint64
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		schemaWarnings: map[string]uint64{},
+		features:       map[string]uint64{},
+	}
+}
+
+// SchemaWarning counts a schema evolution warning for the given field.
+func (r *Recorder) SchemaWarning(field string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemaWarnings[field]++
+}
+
+// Feature counts the usage of a feature.
+func (r *Recorder) Feature(name string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.features[name]++
+}
+
+// Enrichers counts the usage of the RequestEnrichers passed to a request.
+func (r *Recorder) Enrichers(en []re.RequestEnricher) {
+	if len(en) == 0 {
+		return
+	}
+
+	r.Feature(stats.FeatureEnricher)
+	if len(en) > 1 {
+		r.Feature(stats.FeatureChainedEnrichers)
+	}
+}
+
+// Snapshot returns a copy of the current counters.
+func (r *Recorder) Snapshot() stats.Stats {
+	s := stats.Stats{
+		SchemaWarnings: map[string]uint64{},
+		Features:       map[string]uint64{},
+	}
+	if r == nil {
+		return s
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for field, count := range r.schemaWarnings {
+		s.SchemaWarnings[field] = count
+	}
+	for name, count := range r.features {
+		s.Features[name] = count
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+// Package testharness starts the dependencies of the integration tests: the fake account API of Form3 with its
+// Postgres and Vault, in Docker containers on a dedicated network. The containers are started with the docker CLI,
+// so the tests run anywhere a Docker daemon is available, and they are removed by Close.
+//
+// When API_BASE_URL is set the harness uses that API (i.e. the one started by docker-compose) and starts nothing.
+package testharness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v6"
+	"github.com/google/uuid"
+
+	"form3interview/pkg/config"
+	"form3interview/pkg/form3test"
+)
+
+const (
+	accountAPIPort = "8080/tcp"
+	vaultToken     = "8fb95528-57c6-422e-9722-d2147bcba8ed"
+)
+
+var (
+	// ErrDocker a docker command failed
+	ErrDocker = errors.New("docker command failed")
+	// ErrStartTimeout the account API was not healthy within the startup timeout
+	ErrStartTimeout = errors.New("account API didn't start in time")
+)
+
+// Settings are the settings of the harness. SettingsFromEnv reads them from the env vars.
+type Settings struct {
+	// BaseURL is the URL of a running account API including the version. No containers are started if it's set.
+	BaseURL         string        `env:"API_BASE_URL"`
+	AccountAPIImage string        `env:"TESTHARNESS_ACCOUNTAPI_IMAGE" envDefault:"form3tech/interview-accountapi:v1.0.0-46-gb3b6c766"`
+	PostgresImage   string        `env:"TESTHARNESS_POSTGRES_IMAGE" envDefault:"postgres:9.5-alpine"`
+	VaultImage      string        `env:"TESTHARNESS_VAULT_IMAGE" envDefault:"vault:1.6.3"`
+	StartupTimeout  time.Duration `env:"TESTHARNESS_STARTUP_TIMEOUT" envDefault:"2m"`
+	// InitScripts is the directory of the database init scripts, it's the scripts/db directory of the repo by default.
+	InitScripts string `env:"TESTHARNESS_INIT_SCRIPTS"`
+	// Docker is the docker CLI binary.
+	Docker string `env:"TESTHARNESS_DOCKER" envDefault:"docker"`
+}
+
+// SettingsFromEnv returns the settings set by the env vars or their defaults.
+func SettingsFromEnv() (Settings, error) {
+	var settings Settings
+	if err := env.Parse(&settings); err != nil {
+		return Settings{}, err
+	}
+	if settings.InitScripts == "" {
+		_, file, _, _ := runtime.Caller(0)
+		settings.InitScripts = filepath.Join(filepath.Dir(file), "..", "..", "scripts", "db")
+	}
+	return settings, nil
+}
+
+// Harness is a running account API.
+type Harness struct {
+	settings   Settings
+	baseURL    string
+	docker     func(ctx context.Context, args ...string) (string, error)
+	network    string
+	containers []string
+}
+
+// Start starts the account API and its dependencies and waits until the API is healthy. The started containers
+// are removed if it fails. The harness must be closed when the tests are done.
+func Start(ctx context.Context, settings Settings) (*Harness, error) {
+	h := &Harness{settings: settings, docker: dockerCLI(settings.Docker)}
+	if err := h.start(ctx); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func dockerCLI(binary string) func(ctx context.Context, args ...string) (string, error) {
+	return func(ctx context.Context, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, binary, args...)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("%w: %s %s: %v: %s", ErrDocker, binary, args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	}
+}
+
+func (h *Harness) start(ctx context.Context) error {
+	if h.settings.BaseURL != "" {
+		h.baseURL = strings.TrimRight(h.settings.BaseURL, "/")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.settings.StartupTimeout)
+	defer cancel()
+	err := h.startContainers(ctx)
+	if err == nil {
+		err = h.waitHealthy(ctx)
+	}
+	if err != nil {
+		if closeErr := h.Close(); closeErr != nil {
+			return fmt.Errorf("%w (cleanup failed: %v)", err, closeErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (h *Harness) startContainers(ctx context.Context) error {
+	h.network = "testharness-" + uuid.NewString()[:8]
+	if _, err := h.docker(ctx, "network", "create", h.network); err != nil {
+		h.network = ""
+		return err
+	}
+
+	if err := h.run(ctx, "postgresql", h.settings.PostgresImage,
+		"-e", "POSTGRES_USER=root",
+		"-e", "POSTGRES_PASSWORD=password",
+		"-v", h.settings.InitScripts+":/docker-entrypoint-initdb.d/:ro",
+	); err != nil {
+		return err
+	}
+	if err := h.run(ctx, "vault", h.settings.VaultImage,
+		"-e", "SKIP_SETCAP=1",
+		"-e", "VAULT_DEV_ROOT_TOKEN_ID="+vaultToken,
+	); err != nil {
+		return err
+	}
+	// the API restarts until the database is ready
+	if err := h.run(ctx, "accountapi", h.settings.AccountAPIImage,
+		"--restart", "on-failure",
+		"-p", "127.0.0.1::8080",
+		"-e", "VAULT_ADDR=http://vault:8200",
+		"-e", "VAULT_TOKEN="+vaultToken,
+		"-e", "PSQL_USER=root",
+		"-e", "PSQL_PASSWORD=password",
+		"-e", "PSQL_HOST=postgresql",
+		"-e", "PSQL_PORT=5432",
+		"-e", "STACK_NAME=f3-interview-accountapi",
+		"-e", "DATABASE-HOST=postgresql",
+		"-e", "DATABASE-SSL-MODE=disable",
+		"-e", "DATABASE-USERNAME=interview_accountapi_user",
+		"-e", "DATABASE-PASSWORD=123",
+	); err != nil {
+		return err
+	}
+
+	address, err := h.docker(ctx, "port", h.containers[len(h.containers)-1], accountAPIPort)
+	if err != nil {
+		return err
+	}
+	// the port may be listed for IPv4 and IPv6 too
+	address, _, _ = strings.Cut(address, "\n")
+	h.baseURL = "http://" + address + form3test.APIVersion
+	return nil
+}
+
+// run starts a detached container on the network of the harness reachable by the alias.
+func (h *Harness) run(ctx context.Context, alias, image string, args ...string) error {
+	args = append([]string{"run", "-d", "--network", h.network, "--network-alias", alias}, args...)
+	id, err := h.docker(ctx, append(args, image)...)
+	if err != nil {
+		return err
+	}
+	h.containers = append(h.containers, id)
+	return nil
+}
+
+// waitHealthy polls the health endpoint of the API until it responds with 200 OK.
+func (h *Harness) waitHealthy(ctx context.Context) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+form3test.HealthPath, nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s", ErrStartTimeout, h.baseURL)
+		case <-ticker.C:
+		}
+	}
+}
+
+// BaseURL returns the base URL of the API including the version, i.e. http://127.0.0.1:49153/v1.
+func (h *Harness) BaseURL() string {
+	return h.baseURL
+}
+
+// Options returns the config options connecting the clients to the API.
+func (h *Harness) Options() []config.Option {
+	return []config.Option{
+		config.WithBaseUrl(h.baseURL),
+		config.WithOrganisationID(form3test.OrganisationID),
+	}
+}
+
+// Close removes the started containers and their network. It does nothing if the harness uses a running API.
+// The network is removed even if removing the containers fails, the first error is returned.
+func (h *Harness) Close() error {
+	// the containers are removed even if the tests were cancelled
+	ctx := context.Background()
+	var err error
+	if len(h.containers) > 0 {
+		_, err = h.docker(ctx, append([]string{"rm", "-f", "-v"}, h.containers...)...)
+		h.containers = nil
+	}
+	if h.network != "" {
+		if _, networkErr := h.docker(ctx, "network", "rm", h.network); err == nil {
+			err = networkErr
+		}
+		h.network = ""
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+package testharness
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+var errDocker = errors.New("docker failed")
+
+type testHarnessTestSuite struct {
+	suite.Suite
+	api      *httptest.Server
+	healthy  bool
+	commands []string
+	failOn   string
+}
+
+func TestTestHarnessTestSuite(t *testing.T) {
+	suite.Run(t, new(testHarnessTestSuite))
+}
+
+func (s *testHarnessTestSuite) SetupTest() {
+	s.healthy = true
+	s.commands = nil
+	s.failOn = ""
+	s.api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health" || !s.healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+}
+
+func (s *testHarnessTestSuite) TearDownTest() {
+	s.api.Close()
+}
+
+// docker fakes the docker CLI, the containers are named by their image.
+func (s *testHarnessTestSuite) docker(_ context.Context, args ...string) (string, error) {
+	command := strings.Join(args, " ")
+	s.commands = append(s.commands, command)
+	if s.failOn != "" && strings.Contains(command, s.failOn) {
+		return "", errDocker
+	}
+	switch args[0] {
+	case "run":
+		return args[len(args)-1], nil
+	case "port":
+		return strings.TrimPrefix(s.api.URL, "http://") + "\n[::1]:49153", nil
+	}
+	return "", nil
+}
+
+func (s *testHarnessTestSuite) harness() *Harness {
+	settings, err := SettingsFromEnv()
+	s.Require().NoError(err)
+	settings.BaseURL = ""
+	settings.StartupTimeout = time.Second
+	return &Harness{settings: settings, docker: s.docker}
+}
+
+func (s *testHarnessTestSuite) TestSettingsFromEnv() {
+	s.T().Setenv("TESTHARNESS_POSTGRES_IMAGE", "postgres:15-alpine")
+	s.T().Setenv("TESTHARNESS_STARTUP_TIMEOUT", "30s")
+
+	settings, err := SettingsFromEnv()
+
+	s.Require().NoError(err)
+	s.Equal("postgres:15-alpine", settings.PostgresImage)
+	s.Equal("vault:1.6.3", settings.VaultImage)
+	s.Equal(30*time.Second, settings.StartupTimeout)
+	s.Equal("docker", settings.Docker)
+	s.FileExists(settings.InitScripts + "/10-init.sql")
+}
+
+func (s *testHarnessTestSuite) TestStartUsesRunningAPI_WhenBaseURLIsSet() {
+	h, err := Start(context.Background(), Settings{BaseURL: "http://accountapi:8080/v1/", Docker: "/nonexistent/docker"})
+
+	s.Require().NoError(err)
+	s.Equal("http://accountapi:8080/v1", h.BaseURL())
+	s.Len(h.Options(), 2)
+	s.NoError(h.Close())
+}
+
+func (s *testHarnessTestSuite) TestStartAndClose() {
+	h := s.harness()
+
+	s.Require().NoError(h.start(context.Background()))
+
+	s.Equal(s.api.URL+"/v1", h.BaseURL())
+	s.Require().Len(s.commands, 5)
+	s.Regexp(`^network create testharness-\w+$`, s.commands[0])
+	s.Contains(s.commands[1], "--network-alias postgresql")
+	s.Contains(s.commands[1], ":/docker-entrypoint-initdb.d/:ro")
+	s.Contains(s.commands[2], "--network-alias vault")
+	s.Contains(s.commands[3], "-p 127.0.0.1::8080")
+	s.True(strings.HasSuffix(s.commands[3], h.settings.AccountAPIImage))
+	s.Equal("port "+h.settings.AccountAPIImage+" 8080/tcp", s.commands[4])
+
+	s.NoError(h.Close())
+	s.Equal("rm -f -v postgres:9.5-alpine vault:1.6.3 "+h.settings.AccountAPIImage, s.commands[5])
+	s.Equal("network rm "+strings.TrimPrefix(s.commands[0], "network create "), s.commands[6])
+	s.NoError(h.Close())
+	s.Len(s.commands, 7)
+}
+
+func (s *testHarnessTestSuite) TestStartRemovesContainers_WhenDockerFails() {
+	h := s.harness()
+	s.failOn = "vault"
+
+	err := h.start(context.Background())
+
+	s.ErrorIs(err, errDocker)
+	s.Equal("rm -f -v postgres:9.5-alpine", s.commands[len(s.commands)-2])
+	s.True(strings.HasPrefix(s.commands[len(s.commands)-1], "network rm "))
+}
+
+func (s *testHarnessTestSuite) TestStartFails_WhenAPIIsNotHealthy() {
+	h := s.harness()
+	h.settings.StartupTimeout = 100 * time.Millisecond
+	s.healthy = false
+
+	err := h.start(context.Background())
+
+	s.ErrorIs(err, ErrStartTimeout)
+	s.True(strings.HasPrefix(s.commands[len(s.commands)-2], "rm -f -v"))
+}
+
+func (s *testHarnessTestSuite) TestCloseReturnsFirstError() {
+	h := s.harness()
+	s.Require().NoError(h.start(context.Background()))
+	s.failOn = "rm"
+
+	s.ErrorIs(h.Close(), errDocker)
+	s.True(strings.HasPrefix(s.commands[len(s.commands)-1], "network rm "))
+}
+
+func (s *testHarnessTestSuite) TestDockerCLI() {
+	_, err := dockerCLI("/nonexistent/docker")(context.Background(), "ps")
+
+	s.ErrorIs(err, ErrDocker)
+}
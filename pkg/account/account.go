@@ -3,21 +3,23 @@
 package account
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
 
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
 
 	conf "form3interview/internal/config"
 	ire "form3interview/internal/requestenricher"
+	"form3interview/internal/resource"
+	"form3interview/internal/schema"
+	istats "form3interview/internal/stats"
 	"form3interview/pkg/config"
+	"form3interview/pkg/conflict"
 	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/stats"
 )
 
 const (
@@ -27,13 +29,17 @@ const (
 
 var (
 	// ErrBaseUrlNotConfigured base url is not configured
-	ErrBaseUrlNotConfigured = errors.New("baseUrl not configured")
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
 	// ErrOrganisationIDNotConfigured organisation ID is not configured
-	ErrOrganisationIDNotConfigured = errors.New("organisationID not configured")
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
 	// ErrNilUUID nil UUID is not allowed
-	ErrNilUUID = errors.New("nil UUID not allowed")
+	ErrNilUUID = resource.ErrNilUUID
 	// ErrAccountNotFound account not found
 	ErrAccountNotFound = errors.New("account not found")
+	// ErrAccountAlreadyExists account with the same ID already exists
+	ErrAccountAlreadyExists = fmt.Errorf("account %w", resource.ErrResourceAlreadyExists)
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
 	// ErrInvalidAccountVersion account version not found
 	ErrInvalidAccountVersion = errors.New("invalid account version")
 	// ErrServerError server side error occured.
@@ -41,26 +47,59 @@ var (
 	// 		500 Internal Server Error
 	// 		502 Bad Gateway
 	// 		504 Gateway Timeout
-	ErrServerError = errors.New("server error")
+	ErrServerError = resource.ErrServerError
 	// ErrServerUnavailable server is unavailable
-	ErrServerUnavailable = errors.New("server unavailable")
+	ErrServerUnavailable = resource.ErrServerUnavailable
 	// ErrUnexpectedServerResponse server response not handled by the client
-	ErrUnexpectedServerResponse = errors.New("unexpected server response")
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
 	// ErrInvalidRequest server returned with 400 Bad Request
-	ErrInvalidRequest = errors.New("invalid request")
-
-	generateUUID func() (uuid.UUID, error) = uuid.NewUUID
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
 )
 
-type (
-	httpClient interface {
-		Do(*http.Request, ...re.RequestEnricher) (*http.Response, error)
-	}
-	accountClient struct {
-		client httpClient
-		config conf.ClientConfig
-	}
-)
+type accountClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+	stats  *istats.Recorder
+}
+
+// Client is the client of the Form3 accounts created by NewClient or the form3 facade.
+type Client = accountClient
+
+// AccountsService describes the methods of Client, so the code using it can be unit tested against a fake or
+// the mock of the form3interview/pkg/account/mock package.
+type AccountsService interface {
+	Create(attributes AccountAttributes, en ...re.RequestEnricher) (*AccountData, error)
+	CreateWithID(newID uuid.UUID, attributes AccountAttributes, en ...re.RequestEnricher) (*AccountData, error)
+	Fetch(accountID uuid.UUID, en ...re.RequestEnricher) (*AccountData, error)
+	Delete(accountID uuid.UUID, en ...re.RequestEnricher) error
+	DeleteVersion(accountID uuid.UUID, version uint, en ...re.RequestEnricher) error
+	CreateWithMeta(attributes AccountAttributes, en ...re.RequestEnricher) (result.Result[*AccountData], error)
+	FetchWithMeta(accountID uuid.UUID, en ...re.RequestEnricher) (result.Result[*AccountData], error)
+	DeleteWithMeta(accountID uuid.UUID, en ...re.RequestEnricher) (result.Result[struct{}], error)
+	DeleteVersionWithMeta(accountID uuid.UUID, version uint, en ...re.RequestEnricher) (result.Result[struct{}], error)
+	TouchAccounts(filter TouchFilter, en ...re.RequestEnricher) TouchReport
+	CreateBatch(accounts []AccountAttributes, en ...re.RequestEnricher) result.BatchResult[*AccountData]
+	DeleteBatch(accountIDs []uuid.UUID, en ...re.RequestEnricher) result.BatchResult[uuid.UUID]
+	Import(r io.Reader, en ...re.RequestEnricher) (result.BatchResult[*AccountData], error)
+	Stats() stats.Stats
+	Close() error
+}
+
+var _ AccountsService = (*Client)(nil)
 
 // NewClient creates a client for managing Form3 accounts.
 // The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
@@ -68,33 +107,41 @@ func NewClient(options ...config.Option) (*accountClient, error) {
 	cfg := conf.NewConfig()
 	config.ApplyOptions(&cfg, options)
 
-	if cfg.BaseUrl == nil || *cfg.BaseUrl == "" {
-		return nil, ErrBaseUrlNotConfigured
-	}
-
-	if cfg.OrganisationID == nil || *cfg.OrganisationID == uuid.Nil {
-		return nil, ErrOrganisationIDNotConfigured
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
 	}
 
+	recorder := istats.NewRecorder()
 	return &accountClient{
-		client: ire.EnrichClient(http.Client{
-			Timeout:   *cfg.Timeout,
-			Transport: createTransport(cfg),
-		}),
+		client: resource.NewHttpClientWithStats(cfg, recorder),
 		config: cfg,
+		stats:  recorder,
 	}, nil
 }
 
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (a accountClient) Close() error {
+	return resource.Close(a.client)
+}
+
 // Create an account with attributes.
 // See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/accounts/accounts/create-an-account
 //
 // The request can be enriched by RequestEnricher
 func (a accountClient) Create(attributes AccountAttributes, en ...re.RequestEnricher) (*AccountData, error) {
-	newID, err := generateUUID()
+	newID, err := a.config.NewID()
 	if err != nil {
 		return nil, err
 	}
+	return a.CreateWithID(newID, attributes, en...)
+}
 
+// CreateWithID works like Create but it uses the given account ID instead of generating one (i.e. to create the
+// accounts of definitions with stable IDs, see the form3interview/pkg/accountdef package).
+//
+// The request can be enriched by RequestEnricher
+func (a accountClient) CreateWithID(newID uuid.UUID, attributes AccountAttributes, en ...re.RequestEnricher) (*AccountData, error) {
 	acc := AccountData{
 		ID:             newID.String(),
 		OrganisationID: a.config.OrganisationID.String(),
@@ -102,40 +149,12 @@ func (a accountClient) Create(attributes AccountAttributes, en ...re.RequestEnri
 		Attributes:     &attributes,
 	}
 
-	resp, err := a.post(acc, en...)
+	created, err := a.resource().Create(newID, acc, en...)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	switch resp.StatusCode {
-	case http.StatusBadRequest:
-		msg, err := getErrorResponse(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-		log.Error().Msgf("%s: %s", ErrInvalidRequest, msg)
-		return nil, ErrInvalidRequest
-	case http.StatusInternalServerError, http.StatusGatewayTimeout, http.StatusBadGateway:
-		msg, err := getErrorResponse(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-		log.Error().Msgf("%s: [%d] %s", ErrServerError, resp.StatusCode, msg)
-		return nil, ErrServerError
-	case http.StatusServiceUnavailable:
-		return nil, ErrServerUnavailable
-	case http.StatusCreated:
-		log.Debug().Msgf("account %s created", acc.ID)
-		return bodyToAccountData(resp.Body)
-	}
-
-	body := make([]byte, resp.ContentLength)
-	if _, err := resp.Body.Read(body); err != nil {
-		return nil, err
-	}
-	log.Info().Msgf("%s: [%d] %s", ErrUnexpectedServerResponse, resp.StatusCode, body)
-	return nil, ErrUnexpectedServerResponse
+	a.config.Log().Debugf("account %s created", acc.ID)
+	return created, nil
 }
 
 // Fetch an account by it's ID
@@ -143,38 +162,7 @@ func (a accountClient) Create(attributes AccountAttributes, en ...re.RequestEnri
 //
 // The request can be enriched by RequestEnricher
 func (a accountClient) Fetch(accountID uuid.UUID, en ...re.RequestEnricher) (*AccountData, error) {
-	if accountID == uuid.Nil {
-		return nil, ErrNilUUID
-	}
-
-	resp, err := a.get(fmt.Sprintf("%s/%s", accountsUrl, accountID), en...)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	switch resp.StatusCode {
-	case http.StatusNotFound:
-		return nil, ErrAccountNotFound
-	case http.StatusInternalServerError, http.StatusGatewayTimeout, http.StatusBadGateway:
-		msg, err := getErrorResponse(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-		log.Error().Msgf("%s: [%d] %s", ErrServerError, resp.StatusCode, msg)
-		return nil, ErrServerError
-	case http.StatusServiceUnavailable:
-		return nil, ErrServerUnavailable
-	case http.StatusOK:
-		return bodyToAccountData(resp.Body)
-	}
-
-	body := make([]byte, resp.ContentLength)
-	if _, err := resp.Body.Read(body); err != nil {
-		return nil, err
-	}
-	log.Info().Msgf("%s: [%d] %s", ErrUnexpectedServerResponse, resp.StatusCode, body)
-	return nil, ErrUnexpectedServerResponse
+	return a.resource().Fetch(accountID, en...)
 }
 
 // Delete is a convenience function to delete an account by it's ID having the latest version.
@@ -183,14 +171,13 @@ func (a accountClient) Fetch(accountID uuid.UUID, en ...re.RequestEnricher) (*Ac
 // Under the hood it fetches the latest account and delete that with the specific version returned.
 // The request can be enriched by RequestEnricher
 func (a accountClient) Delete(accountID uuid.UUID, en ...re.RequestEnricher) error {
-	acc, err := a.Fetch(accountID, en...)
-	if err != nil {
-		return err
+	if a.config.ReadOnly {
+		return ErrReadOnly
 	}
 
-	version := uint(0)
-	if acc.Version != nil {
-		version = uint(*acc.Version)
+	version, _, err := a.latestVersion(accountID, en...)
+	if err != nil {
+		return err
 	}
 	return a.DeleteVersion(accountID, version, en...)
 }
@@ -198,104 +185,109 @@ func (a accountClient) Delete(accountID uuid.UUID, en ...re.RequestEnricher) err
 // DeleteVersion deletes an account by it's ID having a specific version. 
 // See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/accounts/accounts/delete-an-account
 //
+// A version conflict is resolved with the conflict policy of the config (see config.WithConflictPolicy).
 // The request can be enriched by RequestEnricher
 func (a accountClient) DeleteVersion(accountID uuid.UUID, version uint, en ...re.RequestEnricher) error {
-	if accountID == uuid.Nil {
-		return ErrNilUUID
-	}
-
-	url := fmt.Sprintf("%s/%s?version=%d", accountsUrl, accountID, version)
-	resp, err := a.delete(url, en...)
+	err := a.config.ConflictPolicy.Resolve(conflict.Write{
+		ID:          accountID,
+		Version:     version,
+		ErrConflict: ErrInvalidAccountVersion,
+		Do: func(version uint) error {
+			return a.resource().DeleteVersion(accountID, version, en...)
+		},
+		Latest: func() (uint, any, error) {
+			return a.latestVersion(accountID, en...)
+		},
+	})
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	switch resp.StatusCode {
-	case http.StatusNotFound:
-		return ErrAccountNotFound
-	case http.StatusConflict:
-		msg, err := getErrorResponse(resp.Body)
-		if err != nil {
-			return err
-		}
-		log.Error().Msgf("%s: %s", ErrInvalidAccountVersion, msg)
-		return ErrInvalidAccountVersion
-	case http.StatusInternalServerError, http.StatusGatewayTimeout, http.StatusBadGateway:
-		msg, err := getErrorResponse(resp.Body)
-		if err != nil {
-			return err
-		}
-		log.Error().Msgf("%s: [%d] %s", ErrServerError, resp.StatusCode, msg)
-		return ErrServerError
-	case http.StatusServiceUnavailable:
-		return ErrServerUnavailable
-	case http.StatusNoContent:
-		log.Debug().Msgf("account %s deleted", accountID)
-		return nil
-	default:
-		return err
-	}
+	a.config.Log().Debugf("account %s deleted", accountID)
+	return nil
 }
 
-func (a accountClient) get(url string, en ...re.RequestEnricher) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, *a.config.BaseUrl+url, nil)
+// latestVersion fetches the account and returns its current version.
+func (a accountClient) latestVersion(accountID uuid.UUID, en ...re.RequestEnricher) (uint, any, error) {
+	acc, err := a.Fetch(accountID, en...)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
-	return a.client.Do(req, en...)
-}
 
-func (a accountClient) post(account AccountData, en ...re.RequestEnricher) (*http.Response, error) {
-	container := dataContainer{Data: account}
-	buf := new(bytes.Buffer)
-	if err := json.NewEncoder(buf).Encode(container); err != nil {
-		return nil, err
+	version := uint(0)
+	if acc.Version != nil {
+		version = uint(*acc.Version)
 	}
+	return version, acc, nil
+}
 
-	req, err := http.NewRequest(http.MethodPost, *a.config.BaseUrl+accountsUrl, buf)
-	if err != nil {
-		return nil, err
-	}
-	return a.client.Do(req, en...)
+// Stats returns the counters collected by the client since it was created.
+func (a accountClient) Stats() stats.Stats {
+	return a.stats.Snapshot()
 }
 
-func (a accountClient) delete(url string, en ...re.RequestEnricher) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodDelete, *a.config.BaseUrl+url, nil)
-	if err != nil {
-		return nil, err
+// CreateWithMeta works like Create but it also returns the metadata (status, headers, request ID, timing) of the call.
+func (a accountClient) CreateWithMeta(attributes AccountAttributes, en ...re.RequestEnricher) (result.Result[*AccountData], error) {
+	a.stats.Feature(stats.FeatureWithMeta)
+	var res result.Result[*AccountData]
+	var err error
+	res.Value, err = a.Create(attributes, ire.RecordMeta(&res.Meta, a.config.ClockOrSystem(), en...))
+	return res, err
+}
+
+// FetchWithMeta works like Fetch but it also returns the metadata (status, headers, request ID, timing) of the call.
+func (a accountClient) FetchWithMeta(accountID uuid.UUID, en ...re.RequestEnricher) (result.Result[*AccountData], error) {
+	a.stats.Feature(stats.FeatureWithMeta)
+	var res result.Result[*AccountData]
+	var err error
+	res.Value, err = a.Fetch(accountID, ire.RecordMeta(&res.Meta, a.config.ClockOrSystem(), en...))
+	return res, err
+}
+
+// DeleteWithMeta works like Delete but it also returns the metadata (status, headers, request ID, timing) of the calls.
+func (a accountClient) DeleteWithMeta(accountID uuid.UUID, en ...re.RequestEnricher) (result.Result[struct{}], error) {
+	a.stats.Feature(stats.FeatureWithMeta)
+	var res result.Result[struct{}]
+	err := a.Delete(accountID, ire.RecordMeta(&res.Meta, a.config.ClockOrSystem(), en...))
+	return res, err
+}
+
+// DeleteVersionWithMeta works like DeleteVersion but it also returns the metadata (status, headers, request ID, timing) of the call.
+func (a accountClient) DeleteVersionWithMeta(accountID uuid.UUID, version uint, en ...re.RequestEnricher) (result.Result[struct{}], error) {
+	a.stats.Feature(stats.FeatureWithMeta)
+	var res result.Result[struct{}]
+	err := a.DeleteVersion(accountID, version, ire.RecordMeta(&res.Meta, a.config.ClockOrSystem(), en...))
+	return res, err
+}
+
+// resource returns the generic client of the accounts collection.
+func (a accountClient) resource() resource.Client[AccountData] {
+	return resource.Client[AccountData]{
+		HTTP:              a.client,
+		Config:            a.config,
+		Url:               accountsUrl,
+		ErrNotFound:       ErrAccountNotFound,
+		ErrInvalidVersion: ErrInvalidAccountVersion,
+		ErrAlreadyExists:  ErrAccountAlreadyExists,
+		Stats:             a.stats,
+		OnInvalidRequest:  a.checkSchema,
 	}
-	return a.client.Do(req, en...)
 }
 
-func getErrorResponse(body io.ReadCloser) (string, error) {
-	var se serverError
-	if err := json.NewDecoder(body).Decode(&se); err != nil {
-		if errors.Is(err, io.EOF) {
-			return "", nil
-		}
-		return "", err
+func (a accountClient) checkSchema(errorMessage string) {
+	if !a.config.StrictMode {
+		return
+	}
+
+	for _, warning := range schema.Check(accountsType, errorMessage, schema.EnvelopeFields) {
+		warning.Log(a.config.Log().Logger)
+		a.stats.SchemaWarning(warning.Field)
 	}
-	return se.ErrorMessage, nil
 }
 
 func toResponseBody(body string) io.ReadCloser {
 	return io.NopCloser(strings.NewReader(body))
 }
 
-func bodyToAccountData(body io.Reader) (*AccountData, error) {
-	var container dataContainer
-	if err := json.NewDecoder(body).Decode(&container); err != nil {
-		return nil, err
-	}
-	return &container.Data, nil
-}
-
-func createTransport(cfg conf.ClientConfig) *http.Transport {
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	transport.MaxConnsPerHost = cfg.MaxConns
-	transport.MaxIdleConnsPerHost = cfg.MaxConns
-	transport.MaxIdleConns = cfg.MaxConns
-	transport.IdleConnTimeout = *cfg.IdleConnTimeout
-	return transport
+func (a accountClient) bodyToAccountData(body io.Reader) (*AccountData, error) {
+	return a.resource().BodyToData(body)
 }
@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"form3interview/internal/config"
 	"form3interview/internal/mocks"
+	istats "form3interview/internal/stats"
+	"form3interview/pkg/conflict"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/stats"
 	"net/http"
 	"testing"
 
@@ -41,6 +45,7 @@ func (s *accountTestSuite) SetupTest() {
 			BaseUrl:        &baseUrl,
 			OrganisationID: &orgID,
 		},
+		stats: istats.NewRecorder(),
 	}
 }
 
@@ -57,6 +62,12 @@ func (s *accountTestSuite) TestCreateReturnsError() {
 			responseBody:   "{\"error_message\":\"base_currency is required\"}",
 			expectedError:  ErrInvalidRequest,
 		},
+		{
+			name:           "account already exists",
+			responseStatus: http.StatusConflict,
+			responseBody:   "{\"error_message\":\"id already exists\"}",
+			expectedError:  ErrAccountAlreadyExists,
+		},
 		{
 			name:           "server error",
 			responseStatus: http.StatusInternalServerError,
@@ -93,7 +104,7 @@ func (s *accountTestSuite) TestCreateReturnsError() {
 				Once()
 
 			_, actualErr := s.accountClient.Create(AccountAttributes{})
-			s.ErrorIs(test.expectedError, actualErr)
+			s.ErrorIs(actualErr, test.expectedError)
 		})
 	}
 }
@@ -107,16 +118,12 @@ func (s *accountTestSuite) TestCreateReturnsHttpClientError() {
 
 	_, actualError := s.accountClient.Create(AccountAttributes{})
 
-	s.ErrorIs(expectedError, actualError)
+	s.ErrorIs(actualError, expectedError)
 }
 
 func (s *accountTestSuite) TestCreateAccount() {
 	accountID := uuid.New()
-	originalGenerateUUID := generateUUID
-	generateUUID = func() (uuid.UUID, error) { return accountID, nil }
-	defer func() {
-		generateUUID = originalGenerateUUID
-	}()
+	s.accountClient.config.UUIDGenerator = func() (uuid.UUID, error) { return accountID, nil }
 
 	atr := AccountAttributes{BaseCurrency: "EUR"}
 
@@ -129,7 +136,7 @@ func (s *accountTestSuite) TestCreateAccount() {
 	_, err := s.accountClient.Create(atr)
 	s.NoError(err)
 	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
-	requestedAccount, err := bodyToAccountData(request.Body)
+	requestedAccount, err := s.accountClient.bodyToAccountData(request.Body)
 	s.Require().NoError(err)
 	s.Equal(accountID.String(), requestedAccount.ID)
 	s.Equal(testOrganisationID, requestedAccount.OrganisationID)
@@ -137,10 +144,69 @@ func (s *accountTestSuite) TestCreateAccount() {
 	s.Equal("EUR", requestedAccount.Attributes.BaseCurrency)
 }
 
+func (s *accountTestSuite) TestCreateAccountWithID() {
+	accountID := uuid.New()
+	s.accountClient.config.UUIDGenerator = func() (uuid.UUID, error) { return uuid.Nil, errors.New("not called") }
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postRequestMatcher(AccountData{})), mock.Anything).
+		Return(&http.Response{Body: toResponseBody("{\"data\":{}}"), StatusCode: http.StatusCreated}, nil).
+		Once()
+
+	_, err := s.accountClient.CreateWithID(accountID, AccountAttributes{BaseCurrency: "EUR"})
+
+	s.Require().NoError(err)
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requestedAccount, err := s.accountClient.bodyToAccountData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(accountID.String(), requestedAccount.ID)
+}
+
+func (s *accountTestSuite) TestCreateCountsSchemaWarnings_InStrictMode() {
+	for _, test := range []struct {
+		name             string
+		strictMode       bool
+		errorMessage     string
+		expectedWarnings map[string]uint64
+	}{
+		{
+			name:             "rejected envelope field",
+			strictMode:       true,
+			errorMessage:     "validation failure list:\norganisation_id in body is not allowed",
+			expectedWarnings: map[string]uint64{"data.organisation_id": 1},
+		},
+		{
+			name:             "rejected attribute",
+			strictMode:       true,
+			errorMessage:     "data.attributes.country in body should match '^[A-Z]{2}$'",
+			expectedWarnings: map[string]uint64{},
+		},
+		{
+			name:             "strict mode disabled",
+			errorMessage:     "data.type is not allowed",
+			expectedWarnings: map[string]uint64{},
+		},
+	} {
+		s.Run(test.name, func() {
+			s.SetupTest()
+			s.accountClient.config.StrictMode = test.strictMode
+			body := fmt.Sprintf("{\"error_message\":%q}", test.errorMessage)
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(postRequestMatcher(AccountData{})), mock.Anything).
+				Return(&http.Response{Body: toResponseBody(body), StatusCode: http.StatusBadRequest}, nil).
+				Once()
+
+			_, actualErr := s.accountClient.Create(AccountAttributes{})
+
+			s.ErrorIs(actualErr, ErrInvalidRequest)
+			s.Equal(test.expectedWarnings, s.accountClient.Stats().SchemaWarnings)
+		})
+	}
+}
+
 func (s *accountTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
 	_, actualError := s.accountClient.Fetch(uuid.Nil)
 
-	s.ErrorIs(ErrNilUUID, actualError)
+	s.ErrorIs(actualError, ErrNilUUID)
 	s.mockHttpClient.AssertNotCalled(s.T(), Do)
 }
 
@@ -200,7 +266,7 @@ func (s *accountTestSuite) TestFetchReturnsError() {
 
 			_, actualError := s.accountClient.Fetch(test.accountID)
 
-			s.ErrorIs(test.expectedError, actualError)
+			s.ErrorIs(actualError, test.expectedError)
 		})
 	}
 }
@@ -215,7 +281,7 @@ func (s *accountTestSuite) TestFetchReturnsHttpClientError() {
 
 	_, actualError := s.accountClient.Fetch(accountID)
 
-	s.ErrorIs(expectedError, actualError)
+	s.ErrorIs(actualError, expectedError)
 }
 
 func (s *accountTestSuite) TestFetchAccount() {
@@ -236,10 +302,19 @@ func (s *accountTestSuite) TestFetchAccount() {
 	s.Equal(accountID.String(), acc.ID)
 }
 
+func (s *accountTestSuite) TestDeleteReturnsError_WhenClientIsReadOnly() {
+	s.accountClient.config.ReadOnly = true
+
+	actualError := s.accountClient.Delete(uuid.New())
+
+	s.ErrorIs(actualError, ErrReadOnly)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
 func (s *accountTestSuite) TestDeleteVersionedAccountReturnsError_WhenNilUuidGiven() {
 	actualError := s.accountClient.DeleteVersion(uuid.Nil, 0)
 
-	s.ErrorIs(ErrNilUUID, actualError)
+	s.ErrorIs(actualError, ErrNilUUID)
 	s.mockHttpClient.AssertNotCalled(s.T(), Do)
 }
 
@@ -297,7 +372,7 @@ func (s *accountTestSuite) TestDeleteVersionedAccountReturnsError() {
 
 			actualError := s.accountClient.DeleteVersion(test.accountID, test.version)
 
-			s.ErrorIs(test.expectedError, actualError)
+			s.ErrorIs(actualError, test.expectedError)
 		})
 	}
 }
@@ -312,7 +387,7 @@ func (s *accountTestSuite) TestDeleteVersionedAccountReturnsHttpClientError() {
 
 	actualError := s.accountClient.DeleteVersion(accountID, 0)
 
-	s.ErrorIs(expectedError, actualError)
+	s.ErrorIs(actualError, expectedError)
 }
 
 func (s *accountTestSuite) TestDeleteVersionedAccount() {
@@ -349,6 +424,118 @@ func (s *accountTestSuite) TestDeleteLatestAccountVersion() {
 	s.mockHttpClient.AssertExpectations(s.T())
 }
 
+func (s *accountTestSuite) TestDeleteVersionedAccountRetriesWithLatestVersion_WhenConflictPolicyRefetches() {
+	s.accountClient.config.ConflictPolicy = conflict.Policy{Strategy: conflict.Refetch}
+	accountID := uuid.New()
+	version := int64(42)
+	body, err := json.Marshal(dataContainer{Data: AccountData{ID: accountID.String(), Version: &version}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(deleteRequestMatcher(accountID, 1)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusConflict, Body: toResponseBody(`{"error_message":"invalid version"}`)}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getRequestMatcher(accountID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(deleteRequestMatcher(accountID, uint(version))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+
+	s.NoError(s.accountClient.DeleteVersion(accountID, 1))
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func (s *accountTestSuite) TestFetchWithMeta() {
+	accountID := uuid.New()
+	body, err := json.Marshal(dataContainer{Data: AccountData{ID: accountID.String()}})
+	s.Require().NoError(err)
+	header := http.Header{"X-Request-Id": []string{"request-1"}}
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getRequestMatcher(accountID)), mock.Anything).
+		Run(runHooks(&http.Response{StatusCode: http.StatusOK, Header: header})).
+		Return(&http.Response{StatusCode: http.StatusOK, Header: header, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	res, err := s.accountClient.FetchWithMeta(accountID)
+	s.Require().NoError(err)
+	s.Equal(accountID.String(), res.Value.ID)
+	s.Equal(http.StatusOK, res.Meta.StatusCode)
+	s.Equal("request-1", res.Meta.RequestID)
+	s.Equal(1, res.Meta.Requests)
+}
+
+func (s *accountTestSuite) TestDeleteWithMeta() {
+	accountID := uuid.New()
+	body, err := json.Marshal(dataContainer{Data: AccountData{ID: accountID.String()}})
+	s.Require().NoError(err)
+	callerHookCalls := 0
+	en := re.RequestEnricher{AfterHook: func(*http.Response) { callerHookCalls++ }}
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getRequestMatcher(accountID)), mock.Anything).
+		Run(runHooks(&http.Response{StatusCode: http.StatusOK})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(deleteRequestMatcher(accountID, 0)), mock.Anything).
+		Run(runHooks(&http.Response{StatusCode: http.StatusNoContent})).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+
+	res, err := s.accountClient.DeleteWithMeta(accountID, en)
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, res.Meta.StatusCode)
+	s.Equal(2, res.Meta.Requests)
+	s.Equal(2, callerHookCalls)
+}
+
+func (s *accountTestSuite) TestStatsCountsFeatures() {
+	accountID := uuid.New()
+	body, err := json.Marshal(dataContainer{Data: AccountData{ID: accountID.String()}})
+	s.Require().NoError(err)
+	for i := 0; i < 4; i++ {
+		s.mockHttpClient.
+			On(Do, mock.MatchedBy(getRequestMatcher(accountID)), mock.Anything).
+			Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+			Once()
+	}
+
+	_, err = s.accountClient.Fetch(accountID)
+	s.Require().NoError(err)
+	_, err = s.accountClient.Fetch(accountID, re.RequestEnricher{})
+	s.Require().NoError(err)
+	_, err = s.accountClient.Fetch(accountID, re.RequestEnricher{}, re.RequestEnricher{})
+	s.Require().NoError(err)
+	_, err = s.accountClient.FetchWithMeta(accountID)
+	s.Require().NoError(err)
+
+	s.Equal(map[string]uint64{
+		stats.FeatureEnricher:         3,
+		stats.FeatureChainedEnrichers: 1,
+		stats.FeatureWithMeta:         1,
+	}, s.accountClient.Stats().Features)
+}
+
+// runHooks simulates the hook calls of the enriched http client.
+func runHooks(hookResp *http.Response) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		en := args.Get(1).([]re.RequestEnricher)
+		if len(en) == 0 {
+			return
+		}
+		if en[0].BeforeHook != nil {
+			en[0].BeforeHook()
+		}
+		if en[0].AfterHook != nil {
+			en[0].AfterHook(hookResp)
+		}
+	}
+}
+
 func postRequestMatcher(data AccountData) func(input *http.Request) bool {
 	return func(input *http.Request) bool {
 		return input.Method == http.MethodPost &&
@@ -0,0 +1,64 @@
+package account
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/google/uuid"
+
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/stats"
+)
+
+// CreateBatch creates the accounts one by one in the order of the input. The batch continues after the failed
+// accounts, their errors are collected in the returned result with the index of the attributes.
+//
+// The requests can be enriched by RequestEnricher
+func (a accountClient) CreateBatch(accounts []AccountAttributes, en ...re.RequestEnricher) result.BatchResult[*AccountData] {
+	a.stats.Feature(stats.FeatureBatch)
+	var batch result.BatchResult[*AccountData]
+	for i, attributes := range accounts {
+		created, err := a.Create(attributes, en...)
+		if err != nil {
+			batch.Fail(i, "", err)
+			continue
+		}
+		batch.Succeed(created)
+	}
+
+	a.config.Log().Debugf("created %d of %d accounts", len(batch.Succeeded), batch.Total())
+	return batch
+}
+
+// DeleteBatch deletes the latest version of the accounts one by one in the order of the input (see Delete).
+// The batch continues after the failed accounts, their errors are collected in the returned result with the
+// account IDs as the keys.
+//
+// The requests can be enriched by RequestEnricher
+func (a accountClient) DeleteBatch(accountIDs []uuid.UUID, en ...re.RequestEnricher) result.BatchResult[uuid.UUID] {
+	a.stats.Feature(stats.FeatureBatch)
+	var batch result.BatchResult[uuid.UUID]
+	for i, accountID := range accountIDs {
+		if err := a.Delete(accountID, en...); err != nil {
+			batch.Fail(i, accountID.String(), err)
+			continue
+		}
+		batch.Succeed(accountID)
+	}
+
+	a.config.Log().Debugf("deleted %d of %d accounts", len(batch.Succeeded), batch.Total())
+	return batch
+}
+
+// Import creates the accounts of a JSON array of account attributes (i.e. an export of another organisation) with
+// CreateBatch. The returned error is not nil only if the input can't be decoded, in that case nothing is created.
+//
+// The requests can be enriched by RequestEnricher
+func (a accountClient) Import(r io.Reader, en ...re.RequestEnricher) (result.BatchResult[*AccountData], error) {
+	var accounts []AccountAttributes
+	if err := json.NewDecoder(r).Decode(&accounts); err != nil {
+		return result.BatchResult[*AccountData]{}, err
+	}
+	return a.CreateBatch(accounts, en...), nil
+}
@@ -0,0 +1,74 @@
+package account
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"form3interview/pkg/stats"
+)
+
+func (s *accountTestSuite) TestCreateBatch() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postRequestMatcher(AccountData{})), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody(`{"data":{"id":"1"}}`)}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postRequestMatcher(AccountData{})), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusServiceUnavailable, Body: toResponseBody("")}, nil).
+		Once()
+
+	batch := s.accountClient.CreateBatch([]AccountAttributes{{BaseCurrency: "EUR"}, {BaseCurrency: "GBP"}})
+
+	s.Require().Len(batch.Succeeded, 1)
+	s.Equal("1", batch.Succeeded[0].ID)
+	s.Require().Len(batch.Failed, 1)
+	s.Equal(1, batch.Failed[0].Index)
+	s.ErrorIs(batch.Err(), ErrServerUnavailable)
+	s.Equal(uint64(1), s.accountClient.Stats().Features[stats.FeatureBatch])
+}
+
+func (s *accountTestSuite) TestDeleteBatch() {
+	deletedID, missingID := uuid.New(), uuid.New()
+	s.mockFetch(deletedID, http.StatusOK, 2)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(deleteRequestMatcher(deletedID, 2)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+	s.mockFetch(missingID, http.StatusNotFound, 0)
+
+	batch := s.accountClient.DeleteBatch([]uuid.UUID{deletedID, missingID})
+
+	s.Equal([]uuid.UUID{deletedID}, batch.Succeeded)
+	s.Require().Len(batch.Failed, 1)
+	s.Equal(1, batch.Failed[0].Index)
+	s.Equal(missingID.String(), batch.Failed[0].Key)
+	s.ErrorIs(batch.Err(), ErrAccountNotFound)
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func (s *accountTestSuite) TestImport() {
+	for _, id := range []string{"1", "2"} {
+		s.mockHttpClient.
+			On(Do, mock.MatchedBy(postRequestMatcher(AccountData{})), mock.Anything).
+			Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody(`{"data":{"id":"` + id + `"}}`)}, nil).
+			Once()
+	}
+
+	batch, err := s.accountClient.Import(strings.NewReader(`[{"base_currency":"EUR"},{"base_currency":"GBP"}]`))
+
+	s.Require().NoError(err)
+	s.NoError(batch.Err())
+	s.Len(batch.Succeeded, 2)
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func (s *accountTestSuite) TestImportReturnsError_WhenInputIsInvalid() {
+	batch, err := s.accountClient.Import(strings.NewReader(`{"base_currency":"EUR"}`))
+
+	s.Error(err)
+	s.Zero(batch.Total())
+	s.mockHttpClient.AssertNotCalled(s.T(), Do, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,110 @@
+// Package mock provides the testify mock of account.AccountsService, so the code using the accounts client can be
+// unit tested without the API:
+//
+//	accounts := &mock.AccountsServiceMock{}
+//	accounts.On("Fetch", accountID, mock.NoEnrichers).Return(&account.AccountData{ID: accountID.String()}, nil)
+//	defer accounts.AssertExpectations(t)
+package mock
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"form3interview/pkg/account"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/stats"
+)
+
+// NoEnrichers matches the calls without request enrichers, the enrichers are passed to the mock as a slice.
+var NoEnrichers []re.RequestEnricher
+
+// AccountsServiceMock is the mock of account.AccountsService. The request enrichers are recorded as the last
+// argument of the calls, use mock.Anything to ignore them.
+type AccountsServiceMock struct{ mock.Mock }
+
+var _ account.AccountsService = (*AccountsServiceMock)(nil)
+
+func (m *AccountsServiceMock) Create(attributes account.AccountAttributes, en ...re.RequestEnricher) (*account.AccountData, error) {
+	args := m.Called(attributes, en)
+	return accountData(args.Get(0)), args.Error(1)
+}
+
+func (m *AccountsServiceMock) CreateWithID(newID uuid.UUID, attributes account.AccountAttributes, en ...re.RequestEnricher) (*account.AccountData, error) {
+	args := m.Called(newID, attributes, en)
+	return accountData(args.Get(0)), args.Error(1)
+}
+
+func (m *AccountsServiceMock) Fetch(accountID uuid.UUID, en ...re.RequestEnricher) (*account.AccountData, error) {
+	args := m.Called(accountID, en)
+	return accountData(args.Get(0)), args.Error(1)
+}
+
+func (m *AccountsServiceMock) Delete(accountID uuid.UUID, en ...re.RequestEnricher) error {
+	args := m.Called(accountID, en)
+	return args.Error(0)
+}
+
+func (m *AccountsServiceMock) DeleteVersion(accountID uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	args := m.Called(accountID, version, en)
+	return args.Error(0)
+}
+
+func (m *AccountsServiceMock) CreateWithMeta(attributes account.AccountAttributes, en ...re.RequestEnricher) (result.Result[*account.AccountData], error) {
+	args := m.Called(attributes, en)
+	return args.Get(0).(result.Result[*account.AccountData]), args.Error(1)
+}
+
+func (m *AccountsServiceMock) FetchWithMeta(accountID uuid.UUID, en ...re.RequestEnricher) (result.Result[*account.AccountData], error) {
+	args := m.Called(accountID, en)
+	return args.Get(0).(result.Result[*account.AccountData]), args.Error(1)
+}
+
+func (m *AccountsServiceMock) DeleteWithMeta(accountID uuid.UUID, en ...re.RequestEnricher) (result.Result[struct{}], error) {
+	args := m.Called(accountID, en)
+	return args.Get(0).(result.Result[struct{}]), args.Error(1)
+}
+
+func (m *AccountsServiceMock) DeleteVersionWithMeta(accountID uuid.UUID, version uint, en ...re.RequestEnricher) (result.Result[struct{}], error) {
+	args := m.Called(accountID, version, en)
+	return args.Get(0).(result.Result[struct{}]), args.Error(1)
+}
+
+func (m *AccountsServiceMock) TouchAccounts(filter account.TouchFilter, en ...re.RequestEnricher) account.TouchReport {
+	args := m.Called(filter, en)
+	return args.Get(0).(account.TouchReport)
+}
+
+func (m *AccountsServiceMock) CreateBatch(accounts []account.AccountAttributes, en ...re.RequestEnricher) result.BatchResult[*account.AccountData] {
+	args := m.Called(accounts, en)
+	return args.Get(0).(result.BatchResult[*account.AccountData])
+}
+
+func (m *AccountsServiceMock) DeleteBatch(accountIDs []uuid.UUID, en ...re.RequestEnricher) result.BatchResult[uuid.UUID] {
+	args := m.Called(accountIDs, en)
+	return args.Get(0).(result.BatchResult[uuid.UUID])
+}
+
+func (m *AccountsServiceMock) Import(r io.Reader, en ...re.RequestEnricher) (result.BatchResult[*account.AccountData], error) {
+	args := m.Called(r, en)
+	return args.Get(0).(result.BatchResult[*account.AccountData]), args.Error(1)
+}
+
+func (m *AccountsServiceMock) Stats() stats.Stats {
+	args := m.Called()
+	return args.Get(0).(stats.Stats)
+}
+
+func (m *AccountsServiceMock) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func accountData(v interface{}) *account.AccountData {
+	if v == nil {
+		return nil
+	}
+	return v.(*account.AccountData)
+}
@@ -0,0 +1,76 @@
+package mock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/account"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/stats"
+)
+
+type mockTestSuite struct {
+	suite.Suite
+	accounts *AccountsServiceMock
+}
+
+func TestMockTestSuite(t *testing.T) {
+	suite.Run(t, new(mockTestSuite))
+}
+
+func (s *mockTestSuite) SetupTest() {
+	s.accounts = &AccountsServiceMock{}
+}
+
+func (s *mockTestSuite) TearDownTest() {
+	s.accounts.AssertExpectations(s.T())
+}
+
+// closeAccount is the code under test using the client through the interface.
+func closeAccount(accounts account.AccountsService, accountID uuid.UUID) error {
+	acc, err := accounts.Fetch(accountID)
+	if err != nil {
+		return err
+	}
+	return accounts.DeleteVersion(accountID, uint(*acc.Version))
+}
+
+func (s *mockTestSuite) TestFetchAndDelete() {
+	accountID := uuid.New()
+	version := int64(2)
+	s.accounts.On("Fetch", accountID, NoEnrichers).Return(&account.AccountData{ID: accountID.String(), Version: &version}, nil)
+	s.accounts.On("DeleteVersion", accountID, uint(2), NoEnrichers).Return(nil)
+
+	s.NoError(closeAccount(s.accounts, accountID))
+}
+
+func (s *mockTestSuite) TestReturnsErrors() {
+	accountID := uuid.New()
+	s.accounts.On("Fetch", accountID, NoEnrichers).Return(nil, account.ErrAccountNotFound)
+
+	s.ErrorIs(closeAccount(s.accounts, accountID), account.ErrAccountNotFound)
+}
+
+func (s *mockTestSuite) TestRecordsEnrichers() {
+	attributes := account.AccountAttributes{Bic: "NWBKGB22"}
+	s.accounts.On("Create", attributes, mock.Anything).Return(&account.AccountData{}, nil)
+	s.accounts.On("DeleteWithMeta", mock.Anything, mock.Anything).Return(result.Result[struct{}]{}, errors.New("failed"))
+
+	_, err := s.accounts.Create(attributes, re.WithHeader("X-Test", "1"))
+	s.NoError(err)
+	_, err = s.accounts.DeleteWithMeta(uuid.New())
+	s.EqualError(err, "failed")
+}
+
+func (s *mockTestSuite) TestStatsAndClose() {
+	s.accounts.On("Stats").Return(stats.Stats{})
+	s.accounts.On("Close").Return(nil)
+
+	s.Equal(stats.Stats{}, s.accounts.Stats())
+	s.NoError(s.accounts.Close())
+}
@@ -1,14 +1,9 @@
 package account
 
-// dataContainer is a simple container for the "data" JSON field.
-type dataContainer struct {
-	Data AccountData `json:"data,omitempty"`
-}
+import "form3interview/internal/resource"
 
-// serverError is a simple container for the "error_message" JSON response field.
-type serverError struct {
-	ErrorMessage string `json:"error_message,omitempty"`
-}
+// dataContainer is a simple container for the "data" JSON field.
+type dataContainer = resource.DataContainer[AccountData]
 
 // Account represents an account in the form3 org section.
 // See https://api-docs.form3.tech/api.html#organisation-accounts for
@@ -0,0 +1,90 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+	"form3interview/pkg/stats"
+)
+
+// LocalAccount is the locally stored state of an account which is verified by TouchAccounts.
+type LocalAccount struct {
+	ID      uuid.UUID
+	Version int64
+}
+
+// TouchFilter selects the accounts verified by TouchAccounts.
+type TouchFilter struct {
+	// Accounts are the locally stored accounts to verify.
+	Accounts []LocalAccount
+	// OnlyMismatching skips reporting the accounts with matching versions in TouchReport.Matching.
+	OnlyMismatching bool
+}
+
+// VersionMismatch describes an account which has a different version stored locally than on the server.
+type VersionMismatch struct {
+	AccountID     uuid.UUID
+	LocalVersion  int64
+	RemoteVersion int64
+}
+
+// TouchReport is the result of TouchAccounts which can be used for reconciliation.
+type TouchReport struct {
+	// Matching are the IDs of the accounts having the same version locally and remotely.
+	Matching []uuid.UUID
+	// Mismatching are the accounts having a different version remotely.
+	Mismatching []VersionMismatch
+	// Missing are the IDs of the accounts which were not found on the server.
+	Missing []uuid.UUID
+	// Failed are the errors of the accounts which couldn't be verified in the order of the filter, the same way
+	// as the failed items of the bulk operations (see result.BatchResult). Their keys are the account IDs.
+	Failed []result.BatchItemError
+}
+
+// Consistent tells if all the verified accounts were found with the same version as the locally stored one.
+func (r TouchReport) Consistent() bool {
+	return len(r.Mismatching) == 0 && len(r.Missing) == 0 && len(r.Failed) == 0
+}
+
+// TouchAccounts verifies that the locally stored account versions match the remote ones.
+// The accounts are verified one by one in the order of the filter by fetching them (the accounts API
+// doesn't support no-op updates) and all the differences are collected in the returned report.
+//
+// The requests can be enriched by RequestEnricher
+func (a accountClient) TouchAccounts(filter TouchFilter, en ...re.RequestEnricher) TouchReport {
+	a.stats.Feature(stats.FeatureBatch)
+	var report TouchReport
+	for i, local := range filter.Accounts {
+		remote, err := a.Fetch(local.ID, en...)
+		switch {
+		case errors.Is(err, ErrAccountNotFound):
+			report.Missing = append(report.Missing, local.ID)
+			continue
+		case err != nil:
+			report.Failed = append(report.Failed, result.BatchItemError{Index: i, Key: local.ID.String(), Err: err})
+			continue
+		}
+
+		remoteVersion := int64(0)
+		if remote.Version != nil {
+			remoteVersion = *remote.Version
+		}
+
+		if remoteVersion != local.Version {
+			report.Mismatching = append(report.Mismatching, VersionMismatch{
+				AccountID:     local.ID,
+				LocalVersion:  local.Version,
+				RemoteVersion: remoteVersion,
+			})
+		} else if !filter.OnlyMismatching {
+			report.Matching = append(report.Matching, local.ID)
+		}
+	}
+
+	a.config.Log().Debugf("touched %d accounts: %d matching, %d mismatching, %d missing, %d failed",
+		len(filter.Accounts), len(report.Matching), len(report.Mismatching), len(report.Missing), len(report.Failed))
+	return report
+}
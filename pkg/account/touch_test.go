@@ -0,0 +1,63 @@
+package account
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"form3interview/pkg/stats"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *accountTestSuite) TestTouchAccounts() {
+	matchingID, mismatchingID, missingID, failingID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	s.mockFetch(matchingID, http.StatusOK, 1)
+	s.mockFetch(mismatchingID, http.StatusOK, 3)
+	s.mockFetch(missingID, http.StatusNotFound, 0)
+	s.mockFetch(failingID, http.StatusServiceUnavailable, 0)
+
+	report := s.accountClient.TouchAccounts(TouchFilter{Accounts: []LocalAccount{
+		{ID: matchingID, Version: 1},
+		{ID: mismatchingID, Version: 2},
+		{ID: missingID, Version: 0},
+		{ID: failingID, Version: 0},
+	}})
+
+	s.False(report.Consistent())
+	s.Equal([]uuid.UUID{matchingID}, report.Matching)
+	s.Equal([]VersionMismatch{{AccountID: mismatchingID, LocalVersion: 2, RemoteVersion: 3}}, report.Mismatching)
+	s.Equal([]uuid.UUID{missingID}, report.Missing)
+	s.Require().Len(report.Failed, 1)
+	s.Equal(3, report.Failed[0].Index)
+	s.Equal(failingID.String(), report.Failed[0].Key)
+	s.ErrorIs(report.Failed[0], ErrServerUnavailable)
+	s.Equal(uint64(1), s.accountClient.Stats().Features[stats.FeatureBatch])
+}
+
+func (s *accountTestSuite) TestTouchAccountsSkipsMatching_WhenOnlyMismatchingRequested() {
+	accountID := uuid.New()
+	s.mockFetch(accountID, http.StatusOK, 5)
+
+	report := s.accountClient.TouchAccounts(TouchFilter{
+		Accounts:        []LocalAccount{{ID: accountID, Version: 5}},
+		OnlyMismatching: true,
+	})
+
+	s.True(report.Consistent())
+	s.Empty(report.Matching)
+}
+
+func (s *accountTestSuite) mockFetch(accountID uuid.UUID, status int, version int64) {
+	body := ""
+	if status == http.StatusOK {
+		b, err := json.Marshal(dataContainer{Data: AccountData{ID: accountID.String(), Version: &version}})
+		s.Require().NoError(err)
+		body = string(b)
+	}
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getRequestMatcher(accountID)), mock.Anything).
+		Return(&http.Response{StatusCode: status, Body: toResponseBody(body)}, nil).
+		Once()
+}
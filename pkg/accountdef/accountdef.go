@@ -0,0 +1,283 @@
+// Package accountdef loads the desired state of accounts from declarative YAML definitions and applies it to the
+// Form3 API.
+//
+// A definition file lists the accounts by a unique name together with their ID, their attributes
+// (using the same field names as the Form3 API) and optional template variables:
+//
+//	variables:
+//	  bank_id: "400300"
+//	accounts:
+//	  - name: main-gbp
+//	    id: 3f2a1c6e-8d4b-4f0a-9c1e-2b7d5e6f8a90
+//	    attributes:
+//	      country: GB
+//	      base_currency: GBP
+//	      bank_id: "{{ .bank_id }}"
+//	      bank_id_code: GBDSC
+//
+// Overlays (i.e. accounts.prod.yaml next to accounts.yaml) use the same format and are applied on top of the base
+// definitions: variables are replaced, attributes of the accounts with the same name are merged
+// and accounts with new names are added.
+// The string attributes are rendered as Go templates (see text/template) after all the overlays are applied.
+//
+// Diff compares the definitions with the accounts of the API and Apply creates the missing accounts and recreates
+// the changed ones (see Plan).
+package accountdef
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"form3interview/pkg/account"
+)
+
+var (
+	// ErrMissingName account definition has no name
+	ErrMissingName = errors.New("account name is missing")
+	// ErrDuplicateName account name is defined more than once in the same file
+	ErrDuplicateName = errors.New("duplicate account name")
+	// ErrMissingID account definition has no ID, it can't be compared with the accounts of the API
+	ErrMissingID = errors.New("account id is missing")
+)
+
+// Definition is the desired state of an account.
+type Definition struct {
+	// Name identifies the account in the definitions and overlays. It is not sent to Form3.
+	Name string `json:"name"`
+	// ID of the account in Form3. It's uuid.Nil if the definition has no ID, such definitions can only be rendered.
+	ID         uuid.UUID                 `json:"id"`
+	Attributes account.AccountAttributes `json:"attributes"`
+}
+
+// Options of Load.
+type Options struct {
+	// Environment selects the overlay file of the environment (see OverlayPath). No overlay is used if it's empty.
+	Environment string
+	// Overlays are extra overlay files applied in order after the one of the environment.
+	Overlays []string
+	// Variables override the variables of the definition files.
+	Variables map[string]string
+}
+
+type (
+	document struct {
+		Variables map[string]string `yaml:"variables"`
+		Accounts  []accountDocument `yaml:"accounts"`
+	}
+	accountDocument struct {
+		Name       string                 `yaml:"name"`
+		ID         string                 `yaml:"id"`
+		Attributes map[string]interface{} `yaml:"attributes"`
+	}
+)
+
+// OverlayPath returns the path of the overlay file of an environment for a base definition file
+// (i.e. accounts.yaml and prod gives accounts.prod.yaml).
+func OverlayPath(basePath, environment string) string {
+	ext := filepath.Ext(basePath)
+	return strings.TrimSuffix(basePath, ext) + "." + environment + ext
+}
+
+// Load reads the base definition file, applies the overlays and renders the templates.
+func Load(basePath string, opts Options) ([]Definition, error) {
+	base, err := os.ReadFile(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := opts.Overlays
+	if opts.Environment != "" {
+		paths = append([]string{OverlayPath(basePath, opts.Environment)}, paths...)
+	}
+
+	overlays := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		overlay, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		overlays = append(overlays, overlay)
+	}
+
+	return Parse(base, overlays, opts.Variables)
+}
+
+// Parse works like Load but it takes the contents of the base definition and the overlays.
+func Parse(base []byte, overlays [][]byte, variables map[string]string) ([]Definition, error) {
+	merged, err := parseDocument(base)
+	if err != nil {
+		return nil, fmt.Errorf("base: %w", err)
+	}
+
+	for i, data := range overlays {
+		overlay, err := parseDocument(data)
+		if err != nil {
+			return nil, fmt.Errorf("overlay %d: %w", i+1, err)
+		}
+		merged.apply(overlay)
+	}
+
+	for name, value := range variables {
+		merged.Variables[name] = value
+	}
+
+	definitions := make([]Definition, 0, len(merged.Accounts))
+	for _, acc := range merged.Accounts {
+		def, err := acc.render(merged.Variables)
+		if err != nil {
+			return nil, fmt.Errorf("account %q: %w", acc.Name, err)
+		}
+		definitions = append(definitions, def)
+	}
+	return definitions, nil
+}
+
+func parseDocument(data []byte) (document, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return doc, err
+	}
+	if doc.Variables == nil {
+		doc.Variables = map[string]string{}
+	}
+
+	names := map[string]bool{}
+	for _, acc := range doc.Accounts {
+		if acc.Name == "" {
+			return doc, ErrMissingName
+		}
+		if names[acc.Name] {
+			return doc, fmt.Errorf("%w: %s", ErrDuplicateName, acc.Name)
+		}
+		names[acc.Name] = true
+	}
+	return doc, nil
+}
+
+func (d *document) apply(overlay document) {
+	for name, value := range overlay.Variables {
+		d.Variables[name] = value
+	}
+
+	for _, acc := range overlay.Accounts {
+		i := d.indexOf(acc.Name)
+		if i < 0 {
+			d.Accounts = append(d.Accounts, acc)
+			continue
+		}
+		if acc.ID != "" {
+			d.Accounts[i].ID = acc.ID
+		}
+		d.Accounts[i].Attributes = merge(d.Accounts[i].Attributes, acc.Attributes)
+	}
+}
+
+func (d document) indexOf(name string) int {
+	for i, acc := range d.Accounts {
+		if acc.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// merge returns the base map with the values of the overlay. Nested maps are merged, everything else is replaced.
+func merge(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range overlay {
+		baseMap, baseIsMap := merged[key].(map[string]interface{})
+		overlayMap, overlayIsMap := value.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			merged[key] = merge(baseMap, overlayMap)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+func (a accountDocument) render(variables map[string]string) (Definition, error) {
+	def := Definition{Name: a.Name}
+	if a.ID != "" {
+		id, err := uuid.Parse(a.ID)
+		if err != nil {
+			return def, fmt.Errorf("invalid id: %w", err)
+		}
+		def.ID = id
+	}
+
+	rendered, err := renderValue(a.Attributes, variables)
+	if err != nil {
+		return def, err
+	}
+
+	// the attributes are converted through JSON to reuse the API field names and to reject unknown fields
+	data, err := json.Marshal(rendered)
+	if err != nil {
+		return def, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def.Attributes); err != nil {
+		return def, err
+	}
+	return def, nil
+}
+
+func renderValue(value interface{}, variables map[string]string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderString(v, variables)
+	case []interface{}:
+		rendered := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			r, err := renderValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			rendered = append(rendered, r)
+		}
+		return rendered, nil
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			r, err := renderValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			rendered[key] = r
+		}
+		return rendered, nil
+	}
+	return value, nil
+}
+
+func renderString(text string, variables map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, variables); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
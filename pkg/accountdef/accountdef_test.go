@@ -0,0 +1,145 @@
+package accountdef
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const testBase = `
+variables:
+  bank_id: "400300"
+  country: GB
+accounts:
+  - name: main-gbp
+    attributes:
+      country: "{{ .country }}"
+      base_currency: GBP
+      bank_id: "{{ .bank_id }}"
+      bank_id_code: GBDSC
+      name: ["{{ .owner }}"]
+  - name: savings
+    attributes:
+      country: "{{ .country }}"
+      base_currency: GBP
+`
+
+const testProdOverlay = `
+variables:
+  bank_id: "400302"
+accounts:
+  - name: main-gbp
+    attributes:
+      account_classification: Business
+  - name: euro
+    attributes:
+      country: FR
+      base_currency: EUR
+`
+
+type accountDefTestSuite struct {
+	suite.Suite
+}
+
+func TestAccountDefTestSuite(t *testing.T) {
+	suite.Run(t, new(accountDefTestSuite))
+}
+
+func (s *accountDefTestSuite) TestParse() {
+	defs, err := Parse([]byte(testBase), nil, map[string]string{"owner": "Samantha Holder"})
+
+	s.Require().NoError(err)
+	s.Require().Len(defs, 2)
+	s.Equal("main-gbp", defs[0].Name)
+	s.Equal("GB", *defs[0].Attributes.Country)
+	s.Equal("400300", defs[0].Attributes.BankID)
+	s.Equal([]string{"Samantha Holder"}, defs[0].Attributes.Name)
+	s.Equal("savings", defs[1].Name)
+}
+
+func (s *accountDefTestSuite) TestParseAppliesOverlays() {
+	defs, err := Parse([]byte(testBase), [][]byte{[]byte(testProdOverlay)}, map[string]string{"owner": "Samantha Holder"})
+
+	s.Require().NoError(err)
+	s.Require().Len(defs, 3)
+	s.Equal("400302", defs[0].Attributes.BankID)
+	s.Equal("Business", *defs[0].Attributes.AccountClassification)
+	s.Equal("GBP", defs[0].Attributes.BaseCurrency)
+	s.Equal("euro", defs[2].Name)
+	s.Equal("EUR", defs[2].Attributes.BaseCurrency)
+}
+
+func (s *accountDefTestSuite) TestParseReturnsError() {
+	for _, test := range []struct {
+		name          string
+		base          string
+		expectedError error
+	}{
+		{
+			name:          "missing name",
+			base:          "accounts:\n  - attributes:\n      country: GB\n",
+			expectedError: ErrMissingName,
+		},
+		{
+			name:          "duplicate name",
+			base:          "accounts:\n  - name: a\n  - name: a\n",
+			expectedError: ErrDuplicateName,
+		},
+	} {
+		s.Run(test.name, func() {
+			_, err := Parse([]byte(test.base), nil, nil)
+
+			s.ErrorIs(err, test.expectedError)
+		})
+	}
+}
+
+func (s *accountDefTestSuite) TestParseReadsIDs() {
+	base := "accounts:\n  - name: a\n    id: 3f2a1c6e-8d4b-4f0a-9c1e-2b7d5e6f8a90\n  - name: b\n"
+	overlay := "accounts:\n  - name: b\n    id: 0b5e3d7a-1c2f-4e6b-8a9d-7f1e2c3b4a50\n"
+
+	defs, err := Parse([]byte(base), [][]byte{[]byte(overlay)}, nil)
+
+	s.Require().NoError(err)
+	s.Equal(uuid.MustParse("3f2a1c6e-8d4b-4f0a-9c1e-2b7d5e6f8a90"), defs[0].ID)
+	s.Equal(uuid.MustParse("0b5e3d7a-1c2f-4e6b-8a9d-7f1e2c3b4a50"), defs[1].ID)
+}
+
+func (s *accountDefTestSuite) TestParseReturnsError_WhenIDInvalid() {
+	_, err := Parse([]byte("accounts:\n  - name: a\n    id: not-a-uuid\n"), nil, nil)
+
+	s.ErrorContains(err, `account "a": invalid id`)
+}
+
+func (s *accountDefTestSuite) TestParseReturnsError_WhenVariableMissing() {
+	_, err := Parse([]byte(testBase), nil, nil)
+
+	s.ErrorContains(err, `account "main-gbp"`)
+	s.ErrorContains(err, "owner")
+}
+
+func (s *accountDefTestSuite) TestParseReturnsError_WhenUnknownAttributeGiven() {
+	_, err := Parse([]byte("accounts:\n  - name: a\n    attributes:\n      contry: GB\n"), nil, nil)
+
+	s.ErrorContains(err, "contry")
+}
+
+func (s *accountDefTestSuite) TestLoadUsesOverlayOfEnvironment() {
+	dir := s.T().TempDir()
+	basePath := filepath.Join(dir, "accounts.yaml")
+	s.Require().NoError(os.WriteFile(basePath, []byte(testBase), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "accounts.prod.yaml"), []byte(testProdOverlay), 0o600))
+
+	defs, err := Load(basePath, Options{Environment: "prod", Variables: map[string]string{"owner": "Samantha Holder"}})
+
+	s.Require().NoError(err)
+	s.Len(defs, 3)
+	s.Equal("400302", defs[0].Attributes.BankID)
+}
+
+func (s *accountDefTestSuite) TestOverlayPath() {
+	s.Equal(filepath.Join("defs", "accounts.prod.yaml"), OverlayPath(filepath.Join("defs", "accounts.yaml"), "prod"))
+}
@@ -0,0 +1,178 @@
+package accountdef
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"form3interview/pkg/account"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+)
+
+// Accounts is the part of account.AccountsService used by Diff and Apply.
+type Accounts interface {
+	Fetch(accountID uuid.UUID, en ...re.RequestEnricher) (*account.AccountData, error)
+	CreateWithID(newID uuid.UUID, attributes account.AccountAttributes, en ...re.RequestEnricher) (*account.AccountData, error)
+	DeleteVersion(accountID uuid.UUID, version uint, en ...re.RequestEnricher) error
+}
+
+// Action is the change planned for the account of a definition.
+type Action string
+
+const (
+	// ActionNone the account exists with the attributes of the definition
+	ActionNone Action = "none"
+	// ActionCreate the account doesn't exist
+	ActionCreate Action = "create"
+	// ActionRecreate the account exists with different attributes. It's deleted and created again because the API
+	// doesn't support updating accounts.
+	ActionRecreate Action = "recreate"
+)
+
+// Change is the planned change of the account of a definition.
+type Change struct {
+	Definition Definition `json:"definition"`
+	Action     Action     `json:"action"`
+	// Version of the existing account deleted by ActionRecreate.
+	Version uint `json:"version,omitempty"`
+	// Diff lists the attributes (by API field name) of the definition which differ from the existing account.
+	Diff []string `json:"diff,omitempty"`
+}
+
+// Plan is the list of changes which brings the accounts of the API to the state of the definitions.
+// The accounts missing from the definitions are left untouched.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Pending returns the changes which modify accounts (i.e. all but ActionNone).
+func (p Plan) Pending() []Change {
+	pending := make([]Change, 0, len(p.Changes))
+	for _, change := range p.Changes {
+		if change.Action != ActionNone {
+			pending = append(pending, change)
+		}
+	}
+	return pending
+}
+
+// Diff fetches the accounts of the definitions and plans the changes. Only the attributes set in a definition are
+// compared, the ones populated by the API are ignored.
+// All the definitions must have an ID (ErrMissingID).
+//
+// The requests can be enriched by RequestEnricher
+func Diff(accounts Accounts, defs []Definition, en ...re.RequestEnricher) (Plan, error) {
+	plan := Plan{Changes: make([]Change, 0, len(defs))}
+	for _, def := range defs {
+		if def.ID == uuid.Nil {
+			return plan, fmt.Errorf("account %q: %w", def.Name, ErrMissingID)
+		}
+
+		change, err := diff(accounts, def, en...)
+		if err != nil {
+			return plan, fmt.Errorf("account %q: %w", def.Name, err)
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return plan, nil
+}
+
+func diff(accounts Accounts, def Definition, en ...re.RequestEnricher) (Change, error) {
+	change := Change{Definition: def, Action: ActionNone}
+
+	existing, err := accounts.Fetch(def.ID, en...)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		change.Action = ActionCreate
+		return change, nil
+	}
+	if err != nil {
+		return change, err
+	}
+
+	change.Diff, err = diffAttributes(def.Attributes, existing.Attributes)
+	if err != nil {
+		return change, err
+	}
+	if len(change.Diff) > 0 {
+		change.Action = ActionRecreate
+		if existing.Version != nil {
+			change.Version = uint(*existing.Version)
+		}
+	}
+	return change, nil
+}
+
+// diffAttributes returns the sorted API field names set in the desired attributes with a different actual value.
+func diffAttributes(desired account.AccountAttributes, actual *account.AccountAttributes) ([]string, error) {
+	want, err := toFields(desired)
+	if err != nil {
+		return nil, err
+	}
+	got, err := toFields(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff []string
+	for field, value := range want {
+		if !reflect.DeepEqual(value, got[field]) {
+			diff = append(diff, field)
+		}
+	}
+	sort.Strings(diff)
+	return diff, nil
+}
+
+// toFields converts the attributes through JSON to compare them by the API field names.
+func toFields(attributes any) (map[string]any, error) {
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Apply executes the pending changes of the plan one by one. It continues after the failed changes, their errors
+// are collected in the returned result with the index of the change in the plan and the name of the definition as
+// the key. The plan should be made by Diff right before, a recreate fails with account.ErrInvalidAccountVersion
+// if the account was changed since (depending on the conflict policy of the client).
+//
+// The requests can be enriched by RequestEnricher
+func Apply(accounts Accounts, plan Plan, en ...re.RequestEnricher) result.BatchResult[Change] {
+	var batch result.BatchResult[Change]
+	for i, change := range plan.Changes {
+		if change.Action == ActionNone {
+			continue
+		}
+		if err := apply(accounts, change, en...); err != nil {
+			batch.Fail(i, change.Definition.Name, err)
+			continue
+		}
+		batch.Succeed(change)
+	}
+	return batch
+}
+
+func apply(accounts Accounts, change Change, en ...re.RequestEnricher) error {
+	def := change.Definition
+	if change.Action == ActionRecreate {
+		if err := accounts.DeleteVersion(def.ID, change.Version, en...); err != nil {
+			return err
+		}
+	}
+
+	_, err := accounts.CreateWithID(def.ID, def.Attributes, en...)
+	if err != nil && change.Action == ActionRecreate {
+		return fmt.Errorf("account was deleted but not created again: %w", err)
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package accountdef
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/account/mock"
+)
+
+type planTestSuite struct {
+	suite.Suite
+	accounts *mock.AccountsServiceMock
+}
+
+func TestPlanTestSuite(t *testing.T) {
+	suite.Run(t, new(planTestSuite))
+}
+
+func (s *planTestSuite) SetupTest() {
+	s.accounts = &mock.AccountsServiceMock{}
+}
+
+func (s *planTestSuite) TearDownTest() {
+	s.accounts.AssertExpectations(s.T())
+}
+
+func (s *planTestSuite) TestDiff() {
+	missing := definition("missing", "GB")
+	unchanged := definition("unchanged", "GB")
+	changed := definition("changed", "GB")
+	version := int64(3)
+	s.accounts.On("Fetch", missing.ID, mock.NoEnrichers).Return(nil, account.ErrAccountNotFound).Once()
+	s.accounts.On("Fetch", unchanged.ID, mock.NoEnrichers).
+		Return(&account.AccountData{Attributes: &account.AccountAttributes{Country: stringPtr("GB"), BaseCurrency: "GBP", Status: stringPtr("confirmed")}}, nil).
+		Once()
+	s.accounts.On("Fetch", changed.ID, mock.NoEnrichers).
+		Return(&account.AccountData{Version: &version, Attributes: &account.AccountAttributes{Country: stringPtr("FR"), BaseCurrency: "GBP"}}, nil).
+		Once()
+
+	plan, err := Diff(s.accounts, []Definition{missing, unchanged, changed})
+
+	s.Require().NoError(err)
+	s.Equal([]Change{
+		{Definition: missing, Action: ActionCreate},
+		{Definition: unchanged, Action: ActionNone},
+		{Definition: changed, Action: ActionRecreate, Version: 3, Diff: []string{"country"}},
+	}, plan.Changes)
+	s.Len(plan.Pending(), 2)
+}
+
+func (s *planTestSuite) TestDiffReturnsError_WhenIDMissing() {
+	_, err := Diff(s.accounts, []Definition{{Name: "no-id"}})
+
+	s.ErrorIs(err, ErrMissingID)
+	s.ErrorContains(err, "no-id")
+}
+
+func (s *planTestSuite) TestDiffReturnsError_WhenFetchFails() {
+	def := definition("main", "GB")
+	s.accounts.On("Fetch", def.ID, mock.NoEnrichers).Return(nil, account.ErrServerUnavailable).Once()
+
+	_, err := Diff(s.accounts, []Definition{def})
+
+	s.ErrorIs(err, account.ErrServerUnavailable)
+}
+
+func (s *planTestSuite) TestApply() {
+	created := definition("created", "GB")
+	recreated := definition("recreated", "GB")
+	failed := definition("failed", "GB")
+	s.accounts.On("CreateWithID", created.ID, created.Attributes, mock.NoEnrichers).Return(&account.AccountData{}, nil).Once()
+	s.accounts.On("DeleteVersion", recreated.ID, uint(3), mock.NoEnrichers).Return(nil).Once()
+	s.accounts.On("CreateWithID", recreated.ID, recreated.Attributes, mock.NoEnrichers).Return(&account.AccountData{}, nil).Once()
+	s.accounts.On("DeleteVersion", failed.ID, uint(1), mock.NoEnrichers).Return(nil).Once()
+	s.accounts.On("CreateWithID", failed.ID, failed.Attributes, mock.NoEnrichers).Return(nil, account.ErrInvalidRequest).Once()
+
+	batch := Apply(s.accounts, Plan{Changes: []Change{
+		{Definition: created, Action: ActionCreate},
+		{Definition: definition("unchanged", "GB"), Action: ActionNone},
+		{Definition: recreated, Action: ActionRecreate, Version: 3},
+		{Definition: failed, Action: ActionRecreate, Version: 1},
+	}})
+
+	s.Len(batch.Succeeded, 2)
+	s.Require().Len(batch.Failed, 1)
+	s.Equal(3, batch.Failed[0].Index)
+	s.Equal("failed", batch.Failed[0].Key)
+	s.ErrorIs(batch.Err(), account.ErrInvalidRequest)
+	s.ErrorContains(batch.Err(), "deleted but not created again")
+}
+
+func (s *planTestSuite) TestApplySkipsCreate_WhenDeleteFails() {
+	def := definition("conflict", "GB")
+	s.accounts.On("DeleteVersion", def.ID, uint(2), mock.NoEnrichers).Return(account.ErrInvalidAccountVersion).Once()
+
+	batch := Apply(s.accounts, Plan{Changes: []Change{{Definition: def, Action: ActionRecreate, Version: 2}}})
+
+	s.ErrorIs(batch.Err(), account.ErrInvalidAccountVersion)
+	s.Empty(batch.Succeeded)
+}
+
+func definition(name, countryCode string) Definition {
+	return Definition{
+		Name:       name,
+		ID:         uuid.New(),
+		Attributes: account.AccountAttributes{Country: stringPtr(countryCode), BaseCurrency: "GBP"},
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,201 @@
+// Package audit provides Form3 client to read the audit trail (who changed what and when) of accounts and payments.
+// See https://www.api-docs.form3.tech/api/audit
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	entriesUrl      = "/audit/entries"
+	defaultPageSize = 100
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrRecordNotFound audited record not found
+	ErrRecordNotFound = errors.New("audited record not found")
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type auditClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 audit entries created by NewClient or the form3 facade.
+type Client = auditClient
+
+// NewClient creates a client for reading Form3 audit entries.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*auditClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &auditClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (a auditClient) Close() error {
+	return resource.Close(a.client)
+}
+
+// ListEntries lists the audit entries of a record page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/audit/list-audit-entries
+//
+// The request can be enriched by RequestEnricher
+func (a auditClient) ListEntries(recordType RecordType, recordID uuid.UUID, pageNumber, pageSize uint, en ...re.RequestEnricher) ([]EntryData, error) {
+	if recordID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return a.resource(recordType, recordID).List(pageNumber, pageSize, en...)
+}
+
+// Stream returns the audit events of a record. The pages are fetched while the events are read.
+// A page size of 0 means the default page size.
+func (a auditClient) Stream(recordType RecordType, recordID uuid.UUID, pageSize uint) *EventStream {
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	return &EventStream{client: a, recordType: recordType, recordID: recordID, pageSize: pageSize}
+}
+
+func (a auditClient) resource(recordType RecordType, recordID uuid.UUID) resource.Client[EntryData] {
+	return resource.Client[EntryData]{
+		HTTP:        a.client,
+		Config:      a.config,
+		Url:         fmt.Sprintf("%s/%s/%s", entriesUrl, recordType, recordID),
+		ErrNotFound: ErrRecordNotFound,
+	}
+}
+
+// EventStream reads the audit events of a record page by page.
+// It is used like bufio.Scanner:
+//
+//	stream := client.Stream(audit.RecordTypeAccounts, accountID, 0)
+//	for stream.Next(ctx) {
+//		persist(stream.Event())
+//	}
+//	if err := stream.Err(); err != nil {
+//		return err
+//	}
+type EventStream struct {
+	client     auditClient
+	recordType RecordType
+	recordID   uuid.UUID
+	pageSize   uint
+	nextPage   uint
+	page       []EntryData
+	event      Event
+	lastPage   bool
+	err        error
+}
+
+// Next advances the stream to the next event. It returns false when there are no more events or an error occurred.
+func (s *EventStream) Next(ctx context.Context) bool {
+	if s.err != nil {
+		return false
+	}
+
+	for len(s.page) == 0 {
+		if s.lastPage {
+			return false
+		}
+		if s.err = ctx.Err(); s.err != nil {
+			return false
+		}
+
+		s.page, s.err = s.client.ListEntries(s.recordType, s.recordID, s.nextPage, s.pageSize, re.RequestEnricher{Ctx: ctx})
+		if s.err != nil {
+			return false
+		}
+		s.nextPage++
+		s.lastPage = uint(len(s.page)) < s.pageSize
+	}
+
+	entry := s.page[0]
+	s.page = s.page[1:]
+	s.event, s.err = s.toEvent(entry)
+	return s.err == nil
+}
+
+// Event returns the current event.
+func (s *EventStream) Event() Event {
+	return s.event
+}
+
+// Err returns the error which stopped the stream.
+func (s *EventStream) Err() error {
+	return s.err
+}
+
+func (s *EventStream) toEvent(entry EntryData) (Event, error) {
+	event := Event{
+		ID:         entry.ID,
+		RecordType: s.recordType,
+		RecordID:   s.recordID.String(),
+	}
+	if entry.Attributes == nil {
+		return event, nil
+	}
+
+	attr := entry.Attributes
+	if attr.ActionTime != "" {
+		actionTime, err := time.Parse(time.RFC3339, attr.ActionTime)
+		if err != nil {
+			return event, fmt.Errorf("audit entry %s: %w", entry.ID, err)
+		}
+		event.ActionTime = actionTime
+	}
+	event.ActionedBy = attr.ActionedBy
+	event.Description = attr.Description
+	event.Before = attr.BeforeData
+	event.After = attr.AfterData
+	return event, nil
+}
@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testEntriesUrl     = testBaseUrl + entriesUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type auditTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	auditClient    auditClient
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(auditTestSuite))
+}
+
+func (s *auditTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.auditClient = auditClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *auditTestSuite) TestListEntriesReturnsError() {
+	_, actualError := s.auditClient.ListEntries(RecordTypeAccounts, uuid.Nil, 0, 10)
+	s.ErrorIs(actualError, ErrNilUUID)
+
+	accountID := uuid.New()
+	s.mockEntries(RecordTypeAccounts, accountID, 0, 10, http.StatusNotFound, nil)
+
+	_, actualError = s.auditClient.ListEntries(RecordTypeAccounts, accountID, 0, 10)
+	s.ErrorIs(actualError, ErrRecordNotFound)
+}
+
+func (s *auditTestSuite) TestListEntries() {
+	paymentID := uuid.New()
+	s.mockEntries(RecordTypePayments, paymentID, 1, 2, http.StatusOK, []EntryData{entry("1", "alice")})
+
+	actual, err := s.auditClient.ListEntries(RecordTypePayments, paymentID, 1, 2)
+
+	s.Require().NoError(err)
+	s.Require().Len(actual, 1)
+	s.Equal("alice", actual[0].Attributes.ActionedBy)
+}
+
+func (s *auditTestSuite) TestStream() {
+	accountID := uuid.New()
+	s.mockEntries(RecordTypeAccounts, accountID, 0, 2, http.StatusOK, []EntryData{entry("1", "alice"), entry("2", "bob")})
+	s.mockEntries(RecordTypeAccounts, accountID, 1, 2, http.StatusOK, []EntryData{entry("3", "carol")})
+
+	stream := s.auditClient.Stream(RecordTypeAccounts, accountID, 2)
+	var events []Event
+	for stream.Next(context.Background()) {
+		events = append(events, stream.Event())
+	}
+
+	s.Require().NoError(stream.Err())
+	s.Require().Len(events, 3)
+	s.Equal("3", events[2].ID)
+	s.Equal("carol", events[2].ActionedBy)
+	s.Equal(RecordTypeAccounts, events[2].RecordType)
+	s.Equal(accountID.String(), events[2].RecordID)
+	s.Equal(time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC), events[2].ActionTime)
+	s.JSONEq(`{"status":"confirmed"}`, string(events[2].After))
+	s.mockHttpClient.AssertNumberOfCalls(s.T(), Do, 2)
+}
+
+func (s *auditTestSuite) TestStreamStops_WhenPageFails() {
+	accountID := uuid.New()
+	s.mockEntries(RecordTypeAccounts, accountID, 0, 1, http.StatusOK, []EntryData{entry("1", "alice")})
+	s.mockEntries(RecordTypeAccounts, accountID, 1, 1, http.StatusServiceUnavailable, nil)
+
+	stream := s.auditClient.Stream(RecordTypeAccounts, accountID, 1)
+
+	s.True(stream.Next(context.Background()))
+	s.False(stream.Next(context.Background()))
+	s.ErrorIs(stream.Err(), ErrServerUnavailable)
+	s.False(stream.Next(context.Background()))
+}
+
+func (s *auditTestSuite) TestStreamStops_WhenContextCancelled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stream := s.auditClient.Stream(RecordTypeAccounts, uuid.New(), 0)
+
+	s.False(stream.Next(ctx))
+	s.ErrorIs(stream.Err(), context.Canceled)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *auditTestSuite) mockEntries(recordType RecordType, recordID uuid.UUID, pageNumber, pageSize uint, status int, entries []EntryData) {
+	body := ""
+	if status == http.StatusOK {
+		b, err := json.Marshal(map[string]interface{}{"data": entries})
+		s.Require().NoError(err)
+		body = string(b)
+	}
+
+	url := fmt.Sprintf("%s/%s/%s?page[number]=%d&page[size]=%d", testEntriesUrl, recordType, recordID, pageNumber, pageSize)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, url)), mock.Anything).
+		Return(&http.Response{StatusCode: status, Body: toResponseBody(body)}, nil).
+		Once()
+}
+
+func entry(id, actionedBy string) EntryData {
+	return EntryData{
+		ID: id,
+		Attributes: &EntryAttributes{
+			ActionTime: "2022-10-01T12:00:00Z",
+			ActionedBy: actionedBy,
+			AfterData:  json.RawMessage(`{"status":"confirmed"}`),
+		},
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EntryData represents an audit entry of a change made on a resource.
+// See https://www.api-docs.form3.tech/api/audit for
+// more information about fields.
+type EntryData struct {
+	Attributes     *EntryAttributes `json:"attributes,omitempty"`
+	ID             string           `json:"id,omitempty"`
+	OrganisationID string           `json:"organisation_id,omitempty"`
+	Type           string           `json:"type,omitempty"`
+	Version        *int64           `json:"version,omitempty"`
+}
+
+type EntryAttributes struct {
+	ActionTime  string          `json:"action_time,omitempty"`
+	ActionedBy  string          `json:"actioned_by,omitempty"`
+	AfterData   json.RawMessage `json:"after_data,omitempty"`
+	BeforeData  json.RawMessage `json:"before_data,omitempty"`
+	Description string          `json:"description,omitempty"`
+	RecordType  RecordType      `json:"record_type,omitempty"`
+}
+
+// RecordType is the type of the audited resource.
+type RecordType string
+
+// Audited resource types.
+const (
+	RecordTypeAccounts RecordType = "accounts"
+	RecordTypePayments RecordType = "payments"
+)
+
+// Event is an audit entry in a form which can be persisted by the compliance tools.
+type Event struct {
+	ID         string     `json:"id"`
+	RecordType RecordType `json:"record_type"`
+	RecordID   string     `json:"record_id"`
+	// ActionedBy is the user who made the change.
+	ActionedBy  string    `json:"actioned_by"`
+	ActionTime  time.Time `json:"action_time"`
+	Description string    `json:"description,omitempty"`
+	// Before and After are the resource data before and after the change.
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
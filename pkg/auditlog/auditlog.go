@@ -0,0 +1,89 @@
+// Package auditlog records the mutating calls (Create, Update and Delete) of the clients into a pluggable sink,
+// so regulated environments can track who changed what. The actor of the calls is supplied by the caller
+// in the context of the RequestEnricher.
+package auditlog
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"form3interview/pkg/logger"
+)
+
+type actorKey struct{}
+
+// Entry is the audit record of a mutating call.
+type Entry struct {
+	// Time is when the request was sent.
+	Time time.Time
+	// Method is the http method of the request (POST, PUT, PATCH or DELETE).
+	Method string
+	// Path is the url path of the request.
+	Path string
+	// ResourceID is the ID of the created, updated or deleted resource, if it's known.
+	ResourceID string
+	// IdempotencyKey is the Idempotency-Key header of the request.
+	IdempotencyKey string
+	// Actor is the actor of the context set by WithActor.
+	Actor string
+	// StatusCode is the status code of the response, it's 0 if no response was received.
+	StatusCode int
+	// Err is the error of the request if no response was received.
+	Err error
+	// Duration is the time spent waiting for the response.
+	Duration time.Duration
+}
+
+// Succeeded returns true if the call got a 2xx response.
+func (e Entry) Succeeded() bool {
+	return e.Err == nil && e.StatusCode >= 200 && e.StatusCode < 300
+}
+
+// Sink receives the audit entries. It's called synchronously after every mutating call, so it should not block
+// and it must be safe for concurrent use.
+type Sink interface {
+	Record(entry Entry)
+}
+
+// SinkFunc is an adapter to use a function as a Sink.
+type SinkFunc func(entry Entry)
+
+// Record calls f.
+func (f SinkFunc) Record(entry Entry) {
+	f(entry)
+}
+
+// WithActor returns a copy of the context with the actor (i.e. the user or the service) of the calls.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor of the context or an empty string if it has none.
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// LoggerSink returns a Sink which logs the entries at info level, or at warn level if the call failed.
+func LoggerSink(l logger.Logger) Sink {
+	return SinkFunc(func(e Entry) {
+		level, outcome := logger.LevelInfo, "success"
+		if !e.Succeeded() {
+			level, outcome = logger.LevelWarn, "failure"
+		}
+		fields := []logger.Field{
+			{Key: "method", Value: e.Method},
+			{Key: "path", Value: e.Path},
+			{Key: "resource_id", Value: e.ResourceID},
+			{Key: "idempotency_key", Value: e.IdempotencyKey},
+			{Key: "actor", Value: e.Actor},
+			{Key: "status", Value: strconv.Itoa(e.StatusCode)},
+			{Key: "outcome", Value: outcome},
+		}
+		if e.Err != nil {
+			fields = append(fields, logger.Field{Key: "error", Value: e.Err.Error()})
+		}
+		l.Log(level, "audit", fields...)
+	})
+}
@@ -0,0 +1,50 @@
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/logger"
+)
+
+type auditLogTestSuite struct {
+	suite.Suite
+}
+
+func TestAuditLogTestSuite(t *testing.T) {
+	suite.Run(t, new(auditLogTestSuite))
+}
+
+func (s *auditLogTestSuite) TestActor() {
+	s.Empty(Actor(context.Background()))
+	s.Equal("alice", Actor(WithActor(context.Background(), "alice")))
+}
+
+func (s *auditLogTestSuite) TestSucceeded() {
+	s.True(Entry{StatusCode: http.StatusCreated}.Succeeded())
+	s.False(Entry{StatusCode: http.StatusConflict}.Succeeded())
+	s.False(Entry{Err: errors.New("connection refused")}.Succeeded())
+}
+
+func (s *auditLogTestSuite) TestLoggerSink() {
+	var levels []logger.Level
+	var fields [][]logger.Field
+	sink := LoggerSink(logger.Func(func(level logger.Level, msg string, f ...logger.Field) {
+		s.Equal("audit", msg)
+		levels = append(levels, level)
+		fields = append(fields, f)
+	}))
+
+	sink.Record(Entry{Method: http.MethodPost, Path: "/v1/organisation/accounts", ResourceID: "1", Actor: "alice", StatusCode: http.StatusCreated})
+	sink.Record(Entry{Method: http.MethodDelete, Path: "/v1/organisation/accounts/1", Err: errors.New("timeout")})
+
+	s.Equal([]logger.Level{logger.LevelInfo, logger.LevelWarn}, levels)
+	s.Contains(fields[0], logger.Field{Key: "actor", Value: "alice"})
+	s.Contains(fields[0], logger.Field{Key: "outcome", Value: "success"})
+	s.Contains(fields[1], logger.Field{Key: "outcome", Value: "failure"})
+	s.Contains(fields[1], logger.Field{Key: "error", Value: "timeout"})
+}
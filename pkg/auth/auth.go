@@ -0,0 +1,132 @@
+// Package auth provides the OAuth 2.0 client credentials flow of the Form3 API. The access token is requested
+// from the token endpoint, cached and refreshed shortly before it expires, so the clients always send a valid
+// bearer token.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"form3interview/pkg/result"
+)
+
+// DefaultRefreshBefore is how long before its expiry the access token is refreshed.
+const DefaultRefreshBefore = 30 * time.Second
+
+var (
+	// ErrTokenRequest the token endpoint rejected the token request
+	ErrTokenRequest = errors.New("token request failed")
+	// ErrInvalidTokenResponse the token endpoint returned no access token
+	ErrInvalidTokenResponse = errors.New("invalid token response")
+)
+
+// ClientCredentials is an access token obtained with the OAuth 2.0 client credentials grant.
+type ClientCredentials struct {
+	clientID      string
+	clientSecret  string
+	tokenURL      string
+	client        *http.Client
+	refreshBefore time.Duration
+	now           func() time.Time
+	onRefresh     func(expiresAt time.Time)
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// NewClientCredentials creates the client credentials of the token endpoint. The token is requested with the
+// client, http.DefaultClient is used if it's nil.
+func NewClientCredentials(clientID, clientSecret, tokenURL string, client *http.Client) *ClientCredentials {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &ClientCredentials{
+		clientID:      clientID,
+		clientSecret:  clientSecret,
+		tokenURL:      tokenURL,
+		client:        client,
+		refreshBefore: DefaultRefreshBefore,
+		now:           time.Now,
+	}
+}
+
+// OnRefresh sets fn to be called with the expiry of every new access token, i.e. to alert on the token refreshes.
+// It must be set before the token is requested.
+func (c *ClientCredentials) OnRefresh(fn func(expiresAt time.Time)) *ClientCredentials {
+	c.onRefresh = fn
+	return c
+}
+
+// Token returns the cached access token or requests a new one if it expires within DefaultRefreshBefore.
+// Concurrent calls wait for the same token request.
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.now().Add(c.refreshBefore).Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	token, err := c.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.token = token.AccessToken
+	c.expiresAt = c.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	if c.onRefresh != nil {
+		c.onRefresh(c.expiresAt)
+	}
+	return c.token, nil
+}
+
+func (c *ClientCredentials) requestToken(ctx context.Context) (*tokenResponse, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, result.Wrap(ErrTokenRequest, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, result.Wrap(ErrTokenRequest, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrTokenRequest, resp.Status)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, result.Wrap(ErrInvalidTokenResponse, err)
+	}
+	if token.AccessToken == "" {
+		return nil, ErrInvalidTokenResponse
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return nil, fmt.Errorf("%w: unsupported token type %s", ErrInvalidTokenResponse, token.TokenType)
+	}
+	return &token, nil
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testClientID     = "client-id"
+	testClientSecret = "client-secret"
+)
+
+type authTestSuite struct {
+	suite.Suite
+	server      *httptest.Server
+	requests    int
+	status      int
+	response    string
+	now         time.Time
+	credentials *ClientCredentials
+}
+
+func TestAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(authTestSuite))
+}
+
+func (s *authTestSuite) SetupTest() {
+	s.requests = 0
+	s.status = http.StatusOK
+	s.response = ""
+	s.now = time.Now()
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests++
+		clientID, clientSecret, _ := r.BasicAuth()
+		s.Equal(http.MethodPost, r.Method)
+		s.Equal(testClientID, clientID)
+		s.Equal(testClientSecret, clientSecret)
+		s.Equal("client_credentials", r.PostFormValue("grant_type"))
+
+		w.WriteHeader(s.status)
+		if s.response != "" {
+			fmt.Fprint(w, s.response)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600}`, s.requests)
+	}))
+	s.credentials = NewClientCredentials(testClientID, testClientSecret, s.server.URL, nil)
+	s.credentials.now = func() time.Time { return s.now }
+}
+
+func (s *authTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *authTestSuite) TestTokenIsCached() {
+	token, err := s.credentials.Token(context.Background())
+	s.Require().NoError(err)
+	s.Equal("token-1", token)
+
+	s.now = s.now.Add(time.Hour - DefaultRefreshBefore - time.Second)
+	token, err = s.credentials.Token(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal("token-1", token)
+	s.Equal(1, s.requests)
+}
+
+func (s *authTestSuite) TestTokenIsRefreshedBeforeExpiry() {
+	_, err := s.credentials.Token(context.Background())
+	s.Require().NoError(err)
+
+	s.now = s.now.Add(time.Hour - DefaultRefreshBefore)
+	token, err := s.credentials.Token(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal("token-2", token)
+	s.Equal(2, s.requests)
+}
+
+func (s *authTestSuite) TestOnRefreshIsCalledWithExpiry() {
+	var refreshes []time.Time
+	s.credentials.OnRefresh(func(expiresAt time.Time) { refreshes = append(refreshes, expiresAt) })
+
+	_, err := s.credentials.Token(context.Background())
+	s.Require().NoError(err)
+	_, err = s.credentials.Token(context.Background())
+	s.Require().NoError(err)
+
+	s.Equal([]time.Time{s.now.Add(time.Hour)}, refreshes)
+}
+
+func (s *authTestSuite) TestTokenReturnsError() {
+	testCases := []struct {
+		name        string
+		status      int
+		response    string
+		expectedErr error
+	}{
+		{"rejected", http.StatusUnauthorized, `{"error":"invalid_client"}`, ErrTokenRequest},
+		{"invalid json", http.StatusOK, `not json`, ErrInvalidTokenResponse},
+		{"missing token", http.StatusOK, `{"expires_in":3600}`, ErrInvalidTokenResponse},
+		{"unsupported token type", http.StatusOK, `{"access_token":"t","token_type":"mac"}`, ErrInvalidTokenResponse},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.status, s.response = tc.status, tc.response
+
+			_, err := s.credentials.Token(context.Background())
+
+			s.ErrorIs(err, tc.expectedErr)
+		})
+	}
+}
+
+func (s *authTestSuite) TestTokenReturnsError_WhenEndpointIsUnreachable() {
+	s.server.Close()
+
+	_, err := s.credentials.Token(context.Background())
+
+	s.ErrorIs(err, ErrTokenRequest)
+	var netErr net.Error
+	s.ErrorAs(err, &netErr)
+}
+
+func (s *authTestSuite) TestTokenWrapsDecodeError() {
+	s.status, s.response = http.StatusOK, `not json`
+
+	_, err := s.credentials.Token(context.Background())
+
+	s.ErrorIs(err, ErrInvalidTokenResponse)
+	var syntaxErr *json.SyntaxError
+	s.ErrorAs(err, &syntaxErr)
+}
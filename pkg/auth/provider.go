@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"net/http"
+
+	"form3interview/pkg/signing"
+)
+
+// Credentials are the authentication material of the requests.
+type Credentials struct {
+	// Token is sent as a bearer token in the Authorization header if it's not empty.
+	Token string
+	// KeyID is the ID of the SigningKey registered in Form3.
+	KeyID string
+	// SigningKey signs the requests with Form3 HTTP Signatures if it's not nil.
+	SigningKey crypto.Signer
+}
+
+// CredentialsProvider provides the credentials of the requests, so the authentication can be backed by any
+// secret store (i.e. Vault, AWS Secrets Manager or an own token service).
+// It's called for every request, so rotated credentials are used as soon as they are returned. The provider
+// should cache the credentials and it must be safe for concurrent use.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc is a function used as a CredentialsProvider.
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls the function.
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a provider of the same credentials for every request.
+func StaticCredentials(credentials Credentials) CredentialsProvider {
+	return CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return credentials, nil
+	})
+}
+
+// Authorize sets the bearer token and signs the request with the credentials of the provider.
+func Authorize(req *http.Request, provider CredentialsProvider) error {
+	credentials, err := provider.Credentials(req.Context())
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+	if credentials.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+credentials.Token)
+	}
+	if credentials.SigningKey != nil {
+		return signing.SignRequest(req, credentials.KeyID, credentials.SigningKey)
+	}
+	return nil
+}
+
+// Credentials returns the access token of the client credentials grant.
+func (c *ClientCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return Credentials{Token: token}, nil
+}
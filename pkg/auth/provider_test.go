@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type providerTestSuite struct {
+	suite.Suite
+}
+
+func TestProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(providerTestSuite))
+}
+
+func (s *providerTestSuite) TestStaticCredentials() {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	expected := Credentials{Token: "token", KeyID: "key-1", SigningKey: key}
+
+	credentials, err := StaticCredentials(expected).Credentials(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal(expected, credentials)
+}
+
+func (s *providerTestSuite) TestCredentialsProviderFunc() {
+	errVault := errors.New("vault is sealed")
+	var provider CredentialsProvider = CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{}, errVault
+	})
+
+	_, err := provider.Credentials(context.Background())
+
+	s.ErrorIs(err, errVault)
+}
+
+func (s *providerTestSuite) TestAuthorize() {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/v1/organisation/accounts", nil)
+	s.Require().NoError(err)
+
+	err = Authorize(req, StaticCredentials(Credentials{Token: "token", KeyID: "key-1", SigningKey: key}))
+
+	s.Require().NoError(err)
+	s.Equal("Bearer token", req.Header.Get("Authorization"))
+	s.Contains(req.Header.Get("Signature"), `keyId="key-1"`)
+}
+
+func (s *providerTestSuite) TestAuthorizeReturnsError_WhenCredentialsCantBeProvided() {
+	errVault := errors.New("vault is sealed")
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/v1/organisation/accounts", nil)
+	s.Require().NoError(err)
+
+	err = Authorize(req, CredentialsProviderFunc(func(context.Context) (Credentials, error) {
+		return Credentials{}, errVault
+	}))
+
+	s.ErrorIs(err, errVault)
+	s.Empty(req.Header.Get("Authorization"))
+}
+
+func (s *providerTestSuite) TestClientCredentialsIsCredentialsProvider() {
+	var provider CredentialsProvider = NewClientCredentials("client-id", "client-secret", "http://localhost:0/token", nil)
+
+	_, err := provider.Credentials(context.Background())
+
+	s.ErrorIs(err, ErrTokenRequest)
+}
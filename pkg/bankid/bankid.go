@@ -0,0 +1,160 @@
+// Package bankid provides a read-only Form3 client to look up banks by their national bank IDs (i.e. sort codes) or BICs.
+// It is useful for validating counterparty details before creating accounts or payments.
+// See https://www.api-docs.form3.tech/api/validations/bank-id-lookup
+package bankid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	bankIDsUrl = "/validations/bankids"
+	bicsUrl    = "/validations/bics"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrEmptyLookup lookup value is empty
+	ErrEmptyLookup = errors.New("empty lookup value")
+	// ErrBankNotFound no bank found with the given identifiers
+	ErrBankNotFound = errors.New("bank not found")
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type bankIDClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 bank ID lookups created by NewClient or the form3 facade.
+type Client = bankIDClient
+
+// NewClient creates a client for looking up banks.
+// The lookups don't belong to an organisation so only the base url has to be configured.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*bankIDClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if cfg.BaseUrl == nil || *cfg.BaseUrl == "" {
+		return nil, ErrBaseUrlNotConfigured
+	}
+	if err := resource.CheckMiddleware(cfg); err != nil {
+		return nil, err
+	}
+
+	return &bankIDClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (b bankIDClient) Close() error {
+	return resource.Close(b.client)
+}
+
+// LookupBankID lists the banks registered with a national bank ID (i.e. a UK sort code with the GBDSC bank ID code).
+// See https://www.api-docs.form3.tech/api/validations/bank-id-lookup/list-bank-ids
+//
+// The request can be enriched by RequestEnricher
+func (b bankIDClient) LookupBankID(bankIDCode, bankID string, en ...re.RequestEnricher) ([]BankIDData, error) {
+	if bankIDCode == "" || bankID == "" {
+		return nil, ErrEmptyLookup
+	}
+
+	query := fmt.Sprintf("filter[bank_id_code]=%s&filter[bank_id]=%s", url.QueryEscape(bankIDCode), url.QueryEscape(bankID))
+	return validations[BankIDData](b, bankIDsUrl).Find(query, en...)
+}
+
+// LookupBIC lists the banks registered with a BIC.
+// See https://www.api-docs.form3.tech/api/validations/bank-id-lookup/list-bics
+//
+// The request can be enriched by RequestEnricher
+func (b bankIDClient) LookupBIC(bic string, en ...re.RequestEnricher) ([]BICData, error) {
+	if bic == "" {
+		return nil, ErrEmptyLookup
+	}
+
+	return validations[BICData](b, bicsUrl).Find("filter[bic]="+url.QueryEscape(bic), en...)
+}
+
+// ResolveBank is a convenience function which returns the bank registered with a national bank ID.
+// When the BIC of the bank is known it is resolved too, so the returned Bank has all the details
+// needed to validate the counterparty of an account or a payment.
+//
+// The requests can be enriched by RequestEnricher
+func (b bankIDClient) ResolveBank(bankIDCode, bankID string, en ...re.RequestEnricher) (*Bank, error) {
+	bankIDs, err := b.LookupBankID(bankIDCode, bankID, en...)
+	if err != nil {
+		return nil, err
+	}
+	if len(bankIDs) == 0 || bankIDs[0].Attributes == nil {
+		return nil, ErrBankNotFound
+	}
+
+	attr := bankIDs[0].Attributes
+	bank := Bank{
+		BankID:     attr.BankID,
+		BankIDCode: attr.BankIDCode,
+		BIC:        attr.Bic,
+		Name:       attr.Name,
+		Address:    attr.Address,
+		Country:    attr.Country,
+	}
+	if bank.BIC == "" {
+		return &bank, nil
+	}
+
+	bics, err := b.LookupBIC(bank.BIC, en...)
+	if err != nil {
+		return nil, err
+	}
+	if len(bics) > 0 && bics[0].Attributes != nil {
+		bank.InstitutionName = bics[0].Attributes.InstitutionName
+	}
+	return &bank, nil
+}
+
+// validations returns the generic client of a validations collection.
+func validations[T any](b bankIDClient, path string) resource.Client[T] {
+	return resource.Client[T]{
+		HTTP:   b.client,
+		Config: b.config,
+		Url:    path,
+	}
+}
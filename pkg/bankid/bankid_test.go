@@ -0,0 +1,154 @@
+package bankid
+
+import (
+	"encoding/json"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do             = "Do"
+	testBaseUrl    = "testhost"
+	testBankIDsUrl = testBaseUrl + bankIDsUrl
+	testBicsUrl    = testBaseUrl + bicsUrl
+)
+
+type bankIDTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	bankIDClient   bankIDClient
+}
+
+func TestBankIDTestSuite(t *testing.T) {
+	suite.Run(t, new(bankIDTestSuite))
+}
+
+func (s *bankIDTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	baseUrl := testBaseUrl
+	s.bankIDClient = bankIDClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl: &baseUrl,
+		},
+	}
+}
+
+func (s *bankIDTestSuite) TestLookupBankIDReturnsError() {
+	_, actualError := s.bankIDClient.LookupBankID(BankIDCodeUK, "")
+	s.ErrorIs(actualError, ErrEmptyLookup)
+
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"bank_id_code is invalid\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server bad gateway",
+			responseStatus: http.StatusBadGateway,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(testBankIDsUrl+"?filter[bank_id_code]=GBDSC&filter[bank_id]=400300")), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.bankIDClient.LookupBankID(BankIDCodeUK, "400300")
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *bankIDTestSuite) TestLookupBIC() {
+	body, err := json.Marshal(bicListContainer{Data: []BICData{{Attributes: &BICAttributes{Bic: "NWBKGB22", InstitutionName: "NatWest"}}}})
+	s.Require().NoError(err)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(testBicsUrl+"?filter[bic]=NWBKGB22")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.bankIDClient.LookupBIC("NWBKGB22")
+
+	s.Require().NoError(err)
+	s.Require().Len(actual, 1)
+	s.Equal("NatWest", actual[0].Attributes.InstitutionName)
+}
+
+func (s *bankIDTestSuite) TestResolveBank() {
+	bankIDs, err := json.Marshal(bankIDListContainer{Data: []BankIDData{{Attributes: &BankIDAttributes{
+		BankID:     "400300",
+		BankIDCode: BankIDCodeUK,
+		Bic:        "HBUKGB4B",
+		Country:    "GB",
+		Name:       "HSBC UK Bank",
+	}}}})
+	s.Require().NoError(err)
+	bics, err := json.Marshal(bicListContainer{Data: []BICData{{Attributes: &BICAttributes{Bic: "HBUKGB4B", InstitutionName: "HSBC UK Bank plc"}}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(testBankIDsUrl+"?filter[bank_id_code]=GBDSC&filter[bank_id]=400300")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(bankIDs))}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(testBicsUrl+"?filter[bic]=HBUKGB4B")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(bics))}, nil).
+		Once()
+
+	actual, err := s.bankIDClient.ResolveBank(BankIDCodeUK, "400300")
+
+	s.Require().NoError(err)
+	s.Equal(Bank{
+		BankID:          "400300",
+		BankIDCode:      BankIDCodeUK,
+		BIC:             "HBUKGB4B",
+		Country:         "GB",
+		InstitutionName: "HSBC UK Bank plc",
+		Name:            "HSBC UK Bank",
+	}, *actual)
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func (s *bankIDTestSuite) TestResolveBankReturnsError_WhenBankNotFound() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(testBankIDsUrl+"?filter[bank_id_code]=DEBLZ&filter[bank_id]=00000000")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("{\"data\":[]}")}, nil).
+		Once()
+
+	_, actualError := s.bankIDClient.ResolveBank(BankIDCodeGermany, "00000000")
+
+	s.ErrorIs(actualError, ErrBankNotFound)
+}
+
+func requestMatcher(expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == http.MethodGet &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
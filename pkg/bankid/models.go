@@ -0,0 +1,65 @@
+package bankid
+
+import "form3interview/internal/resource"
+
+// bankIDListContainer is a simple container for the "data" JSON field of list responses.
+type bankIDListContainer = resource.ListContainer[BankIDData]
+
+// bicListContainer is a simple container for the "data" JSON field of list responses.
+type bicListContainer = resource.ListContainer[BICData]
+
+// Bank ID codes of the national bank ID schemes.
+const (
+	BankIDCodeUK          = "GBDSC"
+	BankIDCodeGermany     = "DEBLZ"
+	BankIDCodeFrance      = "FR"
+	BankIDCodeBelgium     = "BE"
+	BankIDCodeItaly       = "ITNCC"
+	BankIDCodeSpain       = "ESNCC"
+	BankIDCodeSwitzerland = "CHBCC"
+	BankIDCodeAustralia   = "AUBSB"
+	BankIDCodeCanada      = "CACPA"
+)
+
+// BankIDData represents a bank registered with a national bank ID.
+// See https://www.api-docs.form3.tech/api/validations/bank-id-lookup for
+// more information about fields.
+type BankIDData struct {
+	Attributes *BankIDAttributes `json:"attributes,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	Type       string            `json:"type,omitempty"`
+}
+
+type BankIDAttributes struct {
+	Address    []string `json:"address,omitempty"`
+	BankID     string   `json:"bank_id,omitempty"`
+	BankIDCode string   `json:"bank_id_code,omitempty"`
+	Bic        string   `json:"bic,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	Name       string   `json:"name,omitempty"`
+}
+
+// BICData represents a bank registered with a BIC.
+type BICData struct {
+	Attributes *BICAttributes `json:"attributes,omitempty"`
+	ID         string         `json:"id,omitempty"`
+	Type       string         `json:"type,omitempty"`
+}
+
+type BICAttributes struct {
+	Address         []string `json:"address,omitempty"`
+	Bic             string   `json:"bic,omitempty"`
+	Country         string   `json:"country,omitempty"`
+	InstitutionName string   `json:"institution_name,omitempty"`
+}
+
+// Bank summarizes the details of a bank resolved by ResolveBank.
+type Bank struct {
+	Address         []string
+	BankID          string
+	BankIDCode      string
+	BIC             string
+	Country         string
+	InstitutionName string
+	Name            string
+}
@@ -0,0 +1,80 @@
+// Package bulkhead provides the concurrency limiter of the Form3 resource clients. Every resource client
+// has its own bulkhead, so the traffic of one resource type can't starve the others sharing the same
+// transport.
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"form3interview/pkg/clock"
+)
+
+// ErrFull the request was not sent because the bulkhead was full for the whole queue timeout
+var ErrFull = errors.New("too many concurrent requests")
+
+// Bulkhead is a semaphore limiting the number of the concurrent requests. It's safe for concurrent use.
+type Bulkhead struct {
+	slots   chan struct{}
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+// New creates a bulkhead allowing maxConcurrent requests at once. The requests wait at most timeout for
+// a free slot, they wait as long as their context allows if it's not positive.
+func New(maxConcurrent int, timeout time.Duration) *Bulkhead {
+	return NewWithClock(maxConcurrent, timeout, clock.System)
+}
+
+// NewWithClock creates a bulkhead (see New) which measures the timeout on the clock.
+func NewWithClock(maxConcurrent int, timeout time.Duration, clk clock.Clock) *Bulkhead {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &Bulkhead{slots: make(chan struct{}, maxConcurrent), timeout: timeout, clock: clk}
+}
+
+// MaxConcurrent returns the number of the requests allowed at once.
+func (b *Bulkhead) MaxConcurrent() int {
+	return cap(b.slots)
+}
+
+// Timeout returns how long the requests wait for a free slot.
+func (b *Bulkhead) Timeout() time.Duration {
+	return b.timeout
+}
+
+// InFlight returns the number of the requests holding a slot.
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// Acquire waits for a free slot. It returns ErrFull if there was no free slot within the timeout and
+// the error of the context if it's done first. The slot must be given back by calling release once.
+func (b *Bulkhead) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.release, nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if b.timeout > 0 {
+		timer := b.clock.NewTimer(b.timeout)
+		defer timer.Stop()
+		timeout = timer.C()
+	}
+	select {
+	case b.slots <- struct{}{}:
+		return b.release, nil
+	case <-timeout:
+		return nil, ErrFull
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *Bulkhead) release() {
+	<-b.slots
+}
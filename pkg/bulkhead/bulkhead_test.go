@@ -0,0 +1,80 @@
+package bulkhead
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock/clocktest"
+)
+
+type bulkheadTestSuite struct {
+	suite.Suite
+}
+
+func TestBulkheadTestSuite(t *testing.T) {
+	suite.Run(t, new(bulkheadTestSuite))
+}
+
+func (s *bulkheadTestSuite) TestAcquire() {
+	bulkhead := New(2, time.Millisecond)
+
+	first, err := bulkhead.Acquire(context.Background())
+	s.Require().NoError(err)
+	_, err = bulkhead.Acquire(context.Background())
+	s.Require().NoError(err)
+	s.Equal(2, bulkhead.InFlight())
+
+	_, err = bulkhead.Acquire(context.Background())
+	s.ErrorIs(err, ErrFull)
+
+	first()
+	_, err = bulkhead.Acquire(context.Background())
+	s.NoError(err)
+}
+
+func (s *bulkheadTestSuite) TestAcquireWaitsForFreeSlot() {
+	bulkhead := New(1, time.Second)
+	release, err := bulkhead.Acquire(context.Background())
+	s.Require().NoError(err)
+	time.AfterFunc(10*time.Millisecond, release)
+
+	_, err = bulkhead.Acquire(context.Background())
+
+	s.NoError(err)
+}
+
+func (s *bulkheadTestSuite) TestAcquireMeasuresTimeoutOnTheClock() {
+	clock := clocktest.NewFake(time.Date(2022, 10, 24, 1, 0, 0, 0, time.UTC))
+	bulkhead := NewWithClock(1, time.Minute, clock)
+	_, err := bulkhead.Acquire(context.Background())
+	s.Require().NoError(err)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := bulkhead.Acquire(context.Background())
+		errs <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	s.ErrorIs(<-errs, ErrFull)
+}
+
+func (s *bulkheadTestSuite) TestAcquireReturnsContextError_WhenContextIsDoneFirst() {
+	bulkhead := New(1, 0)
+	_, err := bulkhead.Acquire(context.Background())
+	s.Require().NoError(err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = bulkhead.Acquire(ctx)
+
+	s.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *bulkheadTestSuite) TestNewAllowsAtLeastOneRequest() {
+	s.Equal(1, New(0, 0).MaxConcurrent())
+}
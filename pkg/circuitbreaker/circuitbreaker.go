@@ -0,0 +1,176 @@
+// Package circuitbreaker provides the circuit breaker of the Form3 clients. When the API keeps failing the
+// calls are short-circuited with ErrCircuitOpen instead of waiting for the timeouts, so an outage of the API
+// doesn't eat up the latency budget of the callers.
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"form3interview/pkg/clock"
+)
+
+// The default settings of the breaker.
+const (
+	DefaultFailureThreshold = 5
+	DefaultOpenDuration     = 30 * time.Second
+	DefaultHalfOpenProbes   = 1
+)
+
+// ErrCircuitOpen the call was not sent because the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// State is the state of the circuit breaker.
+type State int
+
+const (
+	// Closed lets every call through.
+	Closed State = iota
+	// Open rejects every call with ErrCircuitOpen.
+	Open
+	// HalfOpen lets a limited number of probe calls through to check if the API recovered.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Settings of the breaker. Zero values are replaced by the defaults.
+type Settings struct {
+	// FailureThreshold is the number of consecutive failed calls opening the circuit. Default is 5.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before the probe calls are let through. Default is 30s.
+	OpenDuration time.Duration
+	// HalfOpenProbes is the number of probe calls let through in the half-open state. The circuit is closed
+	// when all of them succeed and opened again when any of them fails. Default is 1.
+	HalfOpenProbes int
+	// OnStateChange is called when the breaker changes its state, i.e. to log or alert on it.
+	OnStateChange func(from, to State)
+	// Clock measures the open duration. Default is the system clock.
+	Clock clock.Clock
+}
+
+// Breaker is a circuit breaker. It's safe for concurrent use.
+type Breaker struct {
+	settings Settings
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	failures   int
+	openedAt   time.Time
+	probes     int
+	successes  int
+}
+
+// New creates a closed circuit breaker with the given settings.
+func New(settings Settings) *Breaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = DefaultFailureThreshold
+	}
+	if settings.OpenDuration <= 0 {
+		settings.OpenDuration = DefaultOpenDuration
+	}
+	if settings.HalfOpenProbes <= 0 {
+		settings.HalfOpenProbes = DefaultHalfOpenProbes
+	}
+	settings.Clock = clock.OrSystem(settings.Clock)
+	return &Breaker{settings: settings}
+}
+
+// Settings returns the settings of the breaker with the defaults applied.
+func (b *Breaker) Settings() Settings {
+	return b.settings
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.halfOpenIfExpired()
+	return b.state
+}
+
+// Allow returns ErrCircuitOpen if the call is not allowed. Otherwise the result of the call must be reported
+// by calling done once with true if the call failed.
+func (b *Breaker) Allow() (done func(failed bool), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.halfOpenIfExpired()
+	switch b.state {
+	case Open:
+		return nil, ErrCircuitOpen
+	case HalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			return nil, ErrCircuitOpen
+		}
+		b.probes++
+	}
+
+	generation := b.generation
+	var once sync.Once
+	return func(failed bool) {
+		once.Do(func() { b.done(generation, failed) })
+	}, nil
+}
+
+func (b *Breaker) done(generation uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// the result of a call started before the last state change tells nothing about the current state
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case Closed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.settings.FailureThreshold {
+			b.setState(Open)
+		}
+	case HalfOpen:
+		if failed {
+			b.setState(Open)
+			return
+		}
+		b.successes++
+		if b.successes >= b.settings.HalfOpenProbes {
+			b.setState(Closed)
+		}
+	}
+}
+
+func (b *Breaker) halfOpenIfExpired() {
+	if b.state == Open && b.settings.Clock.Now().Sub(b.openedAt) >= b.settings.OpenDuration {
+		b.setState(HalfOpen)
+	}
+}
+
+func (b *Breaker) setState(state State) {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.failures, b.probes, b.successes = 0, 0, 0
+	if state == Open {
+		b.openedAt = b.settings.Clock.Now()
+	}
+	if b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(from, state)
+	}
+}
@@ -0,0 +1,105 @@
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock/clocktest"
+)
+
+type circuitBreakerTestSuite struct {
+	suite.Suite
+	clock   *clocktest.Fake
+	changes []string
+	breaker *Breaker
+}
+
+func TestCircuitBreakerTestSuite(t *testing.T) {
+	suite.Run(t, new(circuitBreakerTestSuite))
+}
+
+func (s *circuitBreakerTestSuite) SetupTest() {
+	s.clock = clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	s.changes = nil
+	s.breaker = New(Settings{
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+		HalfOpenProbes:   2,
+		OnStateChange:    func(from, to State) { s.changes = append(s.changes, from.String()+"->"+to.String()) },
+		Clock:            s.clock,
+	})
+}
+
+func (s *circuitBreakerTestSuite) call(failed bool) {
+	done, err := s.breaker.Allow()
+	s.Require().NoError(err)
+	done(failed)
+}
+
+func (s *circuitBreakerTestSuite) TestNewAppliesDefaults() {
+	settings := New(Settings{}).Settings()
+
+	s.Equal(DefaultFailureThreshold, settings.FailureThreshold)
+	s.Equal(DefaultOpenDuration, settings.OpenDuration)
+	s.Equal(DefaultHalfOpenProbes, settings.HalfOpenProbes)
+}
+
+func (s *circuitBreakerTestSuite) TestOpensAfterConsecutiveFailures() {
+	s.call(true)
+	s.call(false)
+	s.call(true)
+	s.Equal(Closed, s.breaker.State())
+
+	s.call(true)
+
+	s.Equal(Open, s.breaker.State())
+	_, err := s.breaker.Allow()
+	s.ErrorIs(err, ErrCircuitOpen)
+	s.Equal([]string{"closed->open"}, s.changes)
+}
+
+func (s *circuitBreakerTestSuite) TestClosesAfterSuccessfulProbes() {
+	s.call(true)
+	s.call(true)
+
+	s.clock.Advance(time.Minute)
+	s.Equal(HalfOpen, s.breaker.State())
+	first, err := s.breaker.Allow()
+	s.Require().NoError(err)
+	second, err := s.breaker.Allow()
+	s.Require().NoError(err)
+	_, err = s.breaker.Allow()
+	s.ErrorIs(err, ErrCircuitOpen)
+
+	first(false)
+	second(false)
+
+	s.Equal(Closed, s.breaker.State())
+	s.Equal([]string{"closed->open", "open->half-open", "half-open->closed"}, s.changes)
+}
+
+func (s *circuitBreakerTestSuite) TestOpensAgain_WhenProbeFails() {
+	s.call(true)
+	s.call(true)
+	s.clock.Advance(time.Minute)
+
+	s.call(true)
+
+	s.Equal(Open, s.breaker.State())
+	s.clock.Advance(59 * time.Second)
+	s.Equal(Open, s.breaker.State())
+}
+
+func (s *circuitBreakerTestSuite) TestIgnoresResultsOfCallsStartedBeforeStateChange() {
+	late, err := s.breaker.Allow()
+	s.Require().NoError(err)
+	s.call(true)
+	s.call(true)
+
+	late(false)
+	late(false)
+
+	s.Equal(Open, s.breaker.State())
+}
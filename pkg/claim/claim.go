@@ -0,0 +1,193 @@
+// Package claim provides Form3 client to manage direct debit claims (indemnity claims and chargebacks).
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/claims
+package claim
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	claimsUrl     = "/transaction/claims"
+	claimsType    = "claims"
+	responsesType = "claim_responses"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrClaimNotFound claim not found
+	ErrClaimNotFound = errors.New("claim not found")
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type claimClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 direct debit claims created by NewClient or the form3 facade.
+type Client = claimClient
+
+// NewClient creates a client for managing Form3 claims.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*claimClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &claimClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (c claimClient) Close() error {
+	return resource.Close(c.client)
+}
+
+// Create a claim with attributes.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/claims/create-a-claim
+//
+// The request can be enriched by RequestEnricher
+func (c claimClient) Create(attributes ClaimAttributes, en ...re.RequestEnricher) (*ClaimData, error) {
+	newID, err := c.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	claim := ClaimData{
+		ID:             newID.String(),
+		OrganisationID: c.config.OrganisationID.String(),
+		Type:           claimsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := c.claims().Create(newID, claim, en...)
+	if err != nil {
+		return nil, err
+	}
+	c.config.Log().Debugf("claim %s created", claim.ID)
+	return created, nil
+}
+
+// Fetch a claim by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/claims/fetch-a-claim
+//
+// The request can be enriched by RequestEnricher
+func (c claimClient) Fetch(claimID uuid.UUID, en ...re.RequestEnricher) (*ClaimData, error) {
+	return c.claims().Fetch(claimID, en...)
+}
+
+// List claims page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/claims/list-claims
+//
+// The request can be enriched by RequestEnricher
+func (c claimClient) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]ClaimData, error) {
+	return c.claims().List(pageNumber, pageSize, en...)
+}
+
+// Respond to a received claim by accepting or rejecting it.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/claims/create-a-claim-response
+//
+// The request can be enriched by RequestEnricher
+func (c claimClient) Respond(claimID uuid.UUID, attributes ResponseAttributes, en ...re.RequestEnricher) (*ResponseData, error) {
+	if claimID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := c.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	response := ResponseData{
+		ID:             newID.String(),
+		OrganisationID: c.config.OrganisationID.String(),
+		Type:           responsesType,
+		Attributes:     &attributes,
+	}
+
+	created, err := c.responses(claimID).Create(newID, response, en...)
+	if err != nil {
+		return nil, err
+	}
+	c.config.Log().Debugf("claim %s response %s created", claimID, response.ID)
+	return created, nil
+}
+
+func (c claimClient) bodyToClaimData(body io.Reader) (*ClaimData, error) {
+	return resource.Client[ClaimData]{Config: c.config}.BodyToData(body)
+}
+
+func (c claimClient) bodyToResponseData(body io.Reader) (*ResponseData, error) {
+	return resource.Client[ResponseData]{Config: c.config}.BodyToData(body)
+}
+
+func responsesUrl(claimID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/responses", claimsUrl, claimID)
+}
+
+// claims returns the generic client of the claims collection.
+func (c claimClient) claims() resource.Client[ClaimData] {
+	return resource.Client[ClaimData]{
+		HTTP:        c.client,
+		Config:      c.config,
+		Url:         claimsUrl,
+		ErrNotFound: ErrClaimNotFound,
+	}
+}
+
+// responses returns the generic client of the responses of a claim.
+func (c claimClient) responses(claimID uuid.UUID) resource.Client[ResponseData] {
+	return resource.Client[ResponseData]{
+		HTTP:        c.client,
+		Config:      c.config,
+		Url:         responsesUrl(claimID),
+		ErrNotFound: ErrClaimNotFound,
+	}
+}
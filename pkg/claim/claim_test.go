@@ -0,0 +1,246 @@
+package claim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testClaimsUrl      = testBaseUrl + claimsUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type claimTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	claimClient    claimClient
+}
+
+func TestClaimTestSuite(t *testing.T) {
+	suite.Run(t, new(claimTestSuite))
+}
+
+func (s *claimTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.claimClient = claimClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *claimTestSuite) TestCreateReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"claim_type is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server gateway timeout",
+			responseStatus: http.StatusGatewayTimeout,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testClaimsUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.claimClient.Create(ClaimAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *claimTestSuite) TestCreateReturnsHttpClientError() {
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testClaimsUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.claimClient.Create(ClaimAttributes{})
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *claimTestSuite) TestCreateClaim() {
+	claimID := uuid.New()
+	s.claimClient.config.UUIDGenerator = func() (uuid.UUID, error) { return claimID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testClaimsUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.claimClient.Create(ClaimAttributes{ClaimType: ClaimTypeIndemnity, ReasonCode: "MD06"})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.claimClient.bodyToClaimData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(claimID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(claimsType, requested.Type)
+	s.Equal(ClaimTypeIndemnity, requested.Attributes.ClaimType)
+	s.Equal("MD06", requested.Attributes.ReasonCode)
+}
+
+func (s *claimTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.claimClient.Fetch(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *claimTestSuite) TestFetchReturnsError_WhenClaimNotFound() {
+	claimID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testClaimsUrl, claimID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.claimClient.Fetch(claimID)
+
+	s.ErrorIs(actualError, ErrClaimNotFound)
+}
+
+func (s *claimTestSuite) TestFetchClaim() {
+	claimID := uuid.New()
+	body, err := json.Marshal(claimContainer{Data: ClaimData{ID: claimID.String()}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testClaimsUrl, claimID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.claimClient.Fetch(claimID)
+	s.NoError(err)
+	s.Equal(claimID.String(), actual.ID)
+}
+
+func (s *claimTestSuite) TestListClaims() {
+	firstID, secondID := uuid.New(), uuid.New()
+	body, err := json.Marshal(claimListContainer{Data: []ClaimData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testClaimsUrl+"?page[number]=1&page[size]=2")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.claimClient.List(1, 2)
+	s.NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func (s *claimTestSuite) TestRespondReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		expectedError  error
+	}{
+		{
+			name:           "claim not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrClaimNotFound,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			expectedError:  ErrServerError,
+		},
+	} {
+		s.Run(test.name, func() {
+			claimID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/responses", testClaimsUrl, claimID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody("")}, nil).
+				Once()
+
+			_, actualError := s.claimClient.Respond(claimID, ResponseAttributes{Answer: AnswerAccepted})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *claimTestSuite) TestRespondReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.claimClient.Respond(uuid.Nil, ResponseAttributes{})
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *claimTestSuite) TestRespondToClaim() {
+	claimID, responseID := uuid.New(), uuid.New()
+	s.claimClient.config.UUIDGenerator = func() (uuid.UUID, error) { return responseID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/responses", testClaimsUrl, claimID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{\"attributes\":{\"status\":\"pending\"}}}")}, nil).
+		Once()
+
+	actual, err := s.claimClient.Respond(claimID, ResponseAttributes{Answer: AnswerRejected, RejectReason: "MD01"})
+	s.Require().NoError(err)
+	s.Equal("pending", actual.Attributes.Status)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.claimClient.bodyToResponseData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(responseID.String(), requested.ID)
+	s.Equal(responsesType, requested.Type)
+	s.Equal(AnswerRejected, requested.Attributes.Answer)
+	s.Equal("MD01", requested.Attributes.RejectReason)
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
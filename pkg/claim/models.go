@@ -0,0 +1,82 @@
+package claim
+
+import "form3interview/internal/resource"
+
+// claimContainer is a simple container for the "data" JSON field.
+type claimContainer = resource.DataContainer[ClaimData]
+
+// claimListContainer is a simple container for the "data" JSON field of list responses.
+type claimListContainer = resource.ListContainer[ClaimData]
+
+// responseContainer is a simple container for the "data" JSON field.
+type responseContainer = resource.DataContainer[ResponseData]
+
+// ClaimData represents a claim raised against a collected direct debit.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/claims for
+// more information about fields.
+type ClaimData struct {
+	Attributes     *ClaimAttributes `json:"attributes,omitempty"`
+	ID             string           `json:"id,omitempty"`
+	OrganisationID string           `json:"organisation_id,omitempty"`
+	Type           string           `json:"type,omitempty"`
+	Version        *int64           `json:"version,omitempty"`
+}
+
+type ClaimAttributes struct {
+	Amount           string    `json:"amount,omitempty"`
+	ClaimType        ClaimType `json:"claim_type,omitempty"`
+	Currency         string    `json:"currency,omitempty"`
+	DebtorParty      *Party    `json:"debtor_party,omitempty"`
+	DirectDebitID    string    `json:"direct_debit_id,omitempty"`
+	MandateReference string    `json:"mandate_reference,omitempty"`
+	ProcessingDate   string    `json:"processing_date,omitempty"`
+	ReasonCode       string    `json:"reason_code,omitempty"`
+	Reference        string    `json:"reference,omitempty"`
+	Status           string    `json:"status,omitempty"`
+}
+
+// ClaimType tells what kind of exception the claim was raised for.
+type ClaimType string
+
+// Types of a claim.
+const (
+	ClaimTypeIndemnity  ClaimType = "indemnity"
+	ClaimTypeChargeback ClaimType = "chargeback"
+)
+
+// Party represents the debtor of a claimed direct debit.
+type Party struct {
+	AccountName       string `json:"account_name,omitempty"`
+	AccountNumber     string `json:"account_number,omitempty"`
+	AccountNumberCode string `json:"account_number_code,omitempty"`
+	Address           string `json:"address,omitempty"`
+	BankID            string `json:"bank_id,omitempty"`
+	BankIDCode        string `json:"bank_id_code,omitempty"`
+	Country           string `json:"country,omitempty"`
+	Name              string `json:"name,omitempty"`
+}
+
+// ResponseData represents the answer given to a received claim.
+type ResponseData struct {
+	Attributes     *ResponseAttributes `json:"attributes,omitempty"`
+	ID             string              `json:"id,omitempty"`
+	OrganisationID string              `json:"organisation_id,omitempty"`
+	Type           string              `json:"type,omitempty"`
+	Version        *int64              `json:"version,omitempty"`
+}
+
+type ResponseAttributes struct {
+	Answer       Answer `json:"answer,omitempty"`
+	RejectReason string `json:"reject_reason,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+// Answer is the decision made about a claim.
+type Answer string
+
+// Answers of a claim response.
+const (
+	AnswerAccepted Answer = "accepted"
+	AnswerRejected Answer = "rejected"
+)
@@ -0,0 +1,100 @@
+// Package clock abstracts the time of the Form3 clients: the retry delays, the polling of the submissions and the
+// health checks, the circuit breaker and the rate limiter wait for the timers of the configured Clock. The System
+// clock is used by default, and the tests can pass a fake clock (see clocktest.Fake) with config.WithClock to
+// control the time without sleeping.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and creates timers and tickers.
+type Clock interface {
+	Now() time.Time
+	// NewTimer creates a timer sending the time on its channel after the duration.
+	NewTimer(d time.Duration) Timer
+	// NewTicker creates a ticker sending the time on its channel in every period. The period must be positive.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event of a Clock, like time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false if the timer already fired or was stopped.
+	Stop() bool
+}
+
+// Ticker delivers the ticks of a Clock, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// System is the clock of the operating system.
+var System Clock = systemClock{}
+
+// OrSystem returns the clock or the System clock if it's nil.
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System
+	}
+	return c
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context with the clock. The clients pass the clock of their config to the
+// middlewares in the context of the requests.
+func NewContext(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the clock of the context or the System clock if it has none.
+func FromContext(ctx context.Context) Clock {
+	c, _ := ctx.Value(contextKey{}).(Clock)
+	return OrSystem(c)
+}
+
+// Sleep waits for the duration on the clock or until the context is done, in which case it returns the error of
+// the context.
+func Sleep(ctx context.Context, c Clock, d time.Duration) error {
+	timer := c.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
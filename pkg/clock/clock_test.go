@@ -0,0 +1,57 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type clockTestSuite struct {
+	suite.Suite
+}
+
+func TestClockTestSuite(t *testing.T) {
+	suite.Run(t, new(clockTestSuite))
+}
+
+func (s *clockTestSuite) TestSystem() {
+	s.WithinDuration(time.Now(), System.Now(), time.Second)
+
+	timer := System.NewTimer(time.Millisecond)
+	s.WithinDuration(time.Now(), <-timer.C(), time.Second)
+	s.False(timer.Stop())
+
+	ticker := System.NewTicker(time.Millisecond)
+	<-ticker.C()
+	<-ticker.C()
+	ticker.Stop()
+}
+
+func (s *clockTestSuite) TestOrSystem() {
+	s.Equal(System, OrSystem(nil))
+	custom := systemClock{}
+	s.Equal(custom, OrSystem(custom))
+}
+
+func (s *clockTestSuite) TestFromContext() {
+	s.Equal(System, FromContext(context.Background()))
+	custom := systemClock{}
+	s.Equal(custom, FromContext(NewContext(context.Background(), custom)))
+}
+
+func (s *clockTestSuite) TestSleep() {
+	start := time.Now()
+
+	s.NoError(Sleep(context.Background(), System, 5*time.Millisecond))
+
+	s.GreaterOrEqual(time.Since(start), 5*time.Millisecond)
+}
+
+func (s *clockTestSuite) TestSleepReturns_WhenContextIsDone() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s.ErrorIs(Sleep(ctx, System, time.Hour), context.Canceled)
+}
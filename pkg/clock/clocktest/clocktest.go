@@ -0,0 +1,155 @@
+// Package clocktest provides a fake clock for testing the time dependent behaviour of the Form3 clients (retries,
+// polling, circuit breaker, rate limiting) without sleeping. The time of the Fake only moves when it's advanced
+// by the test, and the timers and tickers waiting for the passed time fire right away.
+package clocktest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"form3interview/pkg/clock"
+)
+
+// Fake is a clock.Clock controlled by the test. It's safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+var _ clock.Clock = (*Fake)(nil)
+
+// NewFake creates a fake clock set to the start time.
+func NewFake(start time.Time) *Fake {
+	f := &Fake{now: start}
+	f.changed = sync.NewCond(&f.mu)
+	return f
+}
+
+// Now returns the time of the clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTimer creates a timer firing when the clock is advanced by the duration. It fires right away if the duration
+// is not positive.
+func (f *Fake) NewTimer(d time.Duration) clock.Timer {
+	return f.add(d, 0)
+}
+
+// NewTicker creates a ticker firing every time the clock is advanced by the period. It panics if the period is not
+// positive, like time.NewTicker. Like time.Ticker, it drops the ticks a slow receiver can't keep up with.
+func (f *Fake) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clocktest: non-positive interval for NewTicker")
+	}
+	return ticker{f.add(d, d)}
+}
+
+// Advance moves the clock forward by the duration and fires the timers and tickers which are due, in the order
+// of their deadlines.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := f.now.Add(d)
+	for {
+		sort.SliceStable(f.waiters, func(i, j int) bool {
+			return f.waiters[i].at.Before(f.waiters[j].at)
+		})
+		if len(f.waiters) == 0 || f.waiters[0].at.After(end) {
+			break
+		}
+		w := f.waiters[0]
+		f.now = w.at
+		w.fire(f.now)
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			f.remove(w)
+		}
+	}
+	f.now = end
+	f.changed.Broadcast()
+}
+
+// Waiters returns the number of the timers and tickers which were not fired or stopped yet.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n timers and tickers are waiting for the clock, so the test can advance the
+// clock when the code under test started to wait (i.e. for the delay of a retry) in another goroutine.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.changed.Wait()
+	}
+}
+
+func (f *Fake) add(d, period time.Duration) *waiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &waiter{clock: f, at: f.now.Add(d), period: period, c: make(chan time.Time, 1)}
+	if d <= 0 && period == 0 {
+		w.fire(f.now)
+		return w
+	}
+	f.waiters = append(f.waiters, w)
+	f.changed.Broadcast()
+	return w
+}
+
+func (f *Fake) remove(w *waiter) bool {
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// waiter is a timer or a ticker of the fake clock.
+type waiter struct {
+	clock  *Fake
+	at     time.Time
+	period time.Duration
+	c      chan time.Time
+}
+
+func (w *waiter) C() <-chan time.Time {
+	return w.c
+}
+
+// Stop stops the timer or ticker. It returns false if the timer already fired or was stopped.
+func (w *waiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.remove(w)
+}
+
+// ticker is a waiter with the Stop method of clock.Ticker.
+type ticker struct {
+	*waiter
+}
+
+func (t ticker) Stop() {
+	t.waiter.Stop()
+}
+
+func (w *waiter) fire(t time.Time) {
+	select {
+	case w.c <- t:
+	default:
+	}
+}
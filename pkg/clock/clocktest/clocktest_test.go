@@ -0,0 +1,105 @@
+package clocktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock"
+)
+
+type fakeTestSuite struct {
+	suite.Suite
+	start time.Time
+	clock *Fake
+}
+
+func TestFakeTestSuite(t *testing.T) {
+	suite.Run(t, new(fakeTestSuite))
+}
+
+func (s *fakeTestSuite) SetupTest() {
+	s.start = time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC)
+	s.clock = NewFake(s.start)
+}
+
+func (s *fakeTestSuite) TestNow() {
+	s.Equal(s.start, s.clock.Now())
+
+	s.clock.Advance(time.Minute)
+
+	s.Equal(s.start.Add(time.Minute), s.clock.Now())
+}
+
+func (s *fakeTestSuite) TestTimer() {
+	timer := s.clock.NewTimer(time.Minute)
+	s.Equal(1, s.clock.Waiters())
+
+	s.clock.Advance(59 * time.Second)
+	s.Empty(timer.C())
+
+	s.clock.Advance(2 * time.Second)
+	s.Equal(s.start.Add(time.Minute), <-timer.C())
+	s.Zero(s.clock.Waiters())
+	s.False(timer.Stop())
+}
+
+func (s *fakeTestSuite) TestTimerFiresRightAway_WhenDurationIsNotPositive() {
+	timer := s.clock.NewTimer(0)
+
+	s.Equal(s.start, <-timer.C())
+	s.Zero(s.clock.Waiters())
+}
+
+func (s *fakeTestSuite) TestStoppedTimerDoesNotFire() {
+	timer := s.clock.NewTimer(time.Minute)
+
+	s.True(timer.Stop())
+	s.clock.Advance(time.Hour)
+
+	s.Empty(timer.C())
+	s.Zero(s.clock.Waiters())
+}
+
+func (s *fakeTestSuite) TestTicker() {
+	ticker := s.clock.NewTicker(time.Minute)
+
+	s.clock.Advance(time.Minute)
+	s.Equal(s.start.Add(time.Minute), <-ticker.C())
+
+	// the ticks the receiver can't keep up with are dropped
+	s.clock.Advance(3 * time.Minute)
+	s.Equal(s.start.Add(2*time.Minute), <-ticker.C())
+	s.Empty(ticker.C())
+
+	ticker.Stop()
+	s.clock.Advance(time.Hour)
+	s.Empty(ticker.C())
+	s.Panics(func() { s.clock.NewTicker(0) })
+}
+
+func (s *fakeTestSuite) TestAdvanceFiresTimersAtTheirDeadlines() {
+	var fired []time.Duration
+	late := s.clock.NewTimer(2 * time.Minute)
+	early := s.clock.NewTimer(time.Minute)
+
+	s.clock.Advance(time.Hour)
+
+	for _, timer := range []clock.Timer{early, late} {
+		fired = append(fired, (<-timer.C()).Sub(s.start))
+	}
+	s.Equal([]time.Duration{time.Minute, 2 * time.Minute}, fired)
+}
+
+func (s *fakeTestSuite) TestBlockUntil() {
+	done := make(chan error)
+	go func() { done <- clock.Sleep(context.Background(), s.clock, time.Minute) }()
+
+	s.clock.BlockUntil(1)
+	s.clock.Advance(time.Minute)
+
+	s.NoError(<-done)
+	s.Equal(s.start.Add(time.Minute), s.clock.Now())
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"time"
+
+	conf "form3interview/internal/config"
+)
+
+// Environment is a preset of the settings of a Form3 API environment.
+type Environment struct {
+	Name         string
+	BaseUrl      string
+	APIVersion   string
+	Timeout      time.Duration
+	PollInterval time.Duration
+	PollTimeout  time.Duration
+}
+
+var (
+	// Production is the Form3 production API.
+	Production = Environment{
+		Name:         "production",
+		BaseUrl:      "https://api.form3.tech",
+		APIVersion:   "v1",
+		Timeout:      5 * time.Second,
+		PollInterval: time.Second,
+		PollTimeout:  30 * time.Second,
+	}
+	// Sandbox is the Form3 staging API. It is slower than production so it uses longer timeouts.
+	Sandbox = Environment{
+		Name:         "sandbox",
+		BaseUrl:      "https://api.staging-form3.tech",
+		APIVersion:   "v1",
+		Timeout:      10 * time.Second,
+		PollInterval: 2 * time.Second,
+		PollTimeout:  60 * time.Second,
+	}
+	// Local is the fake account API started by docker-compose.
+	Local = Environment{
+		Name:         "local",
+		BaseUrl:      "http://localhost:8080",
+		APIVersion:   "v1",
+		Timeout:      5 * time.Second,
+		PollInterval: 100 * time.Millisecond,
+		PollTimeout:  5 * time.Second,
+	}
+)
+
+// WithEnvironment will set the base url, the API version and the timeouts of the environment preset (i.e. config.Sandbox),
+// so the Form3 hostnames don't have to be hardcoded.
+// This will override the FORM3_BASE_URL, FORM3_API_VERSION, FORM3_TIMEOUT, FORM3_POLL_INTERVAL and FORM3_POLL_TIMEOUT env vars.
+// Options after it (i.e. WithTimeout) override the values of the preset.
+func WithEnvironment(env Environment) Option {
+	return func(c *conf.ClientConfig) {
+		WithBaseUrl(env.BaseUrl)(c)
+		WithAPIVersion(env.APIVersion)(c)
+		WithTimeout(env.Timeout)(c)
+		WithPollInterval(env.PollInterval)(c)
+		WithPollTimeout(env.PollTimeout)(c)
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"form3interview/internal/config"
+	"time"
+)
+
+func (s *configTestSuite) TestWithEnvironment() {
+	s.T().Setenv(baseUrlKey, testBaseUrl)
+	s.T().Setenv(timeoutKey, "42s")
+
+	cfg := config.NewConfig()
+	ApplyOptions(&cfg, []Option{WithEnvironment(Sandbox), WithPollTimeout(42 * time.Second)})
+
+	s.Equal("https://api.staging-form3.tech", *cfg.BaseUrl)
+	s.Equal("v1", cfg.APIVersion())
+	s.Equal("https://api.staging-form3.tech/v1/organisation/accounts", cfg.Url("/organisation/accounts"))
+	s.Equal(10*time.Second, *cfg.Timeout)
+	s.Equal(2*time.Second, *cfg.PollInterval)
+	s.Equal(42*time.Second, *cfg.PollTimeout)
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"form3interview/pkg/logger"
+)
+
+var (
+	// ErrUnsupportedFileFormat the config file is not a YAML or JSON file
+	ErrUnsupportedFileFormat = errors.New("unsupported config file format")
+	// ErrUnknownEnvironment the environment of the config file is not a known preset
+	ErrUnknownEnvironment = errors.New("unknown environment")
+	// ErrUnknownTLSVersion the min TLS version of the config file is not a known TLS version
+	ErrUnknownTLSVersion = errors.New("unknown TLS version")
+)
+
+var environments = map[string]Environment{
+	Production.Name: Production,
+	Sandbox.Name:    Sandbox,
+	Local.Name:      Local,
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// fileConfig is the content of a config file. The keys are the snake case names of the FORM3_* env vars.
+type fileConfig struct {
+	Environment            *string           `yaml:"environment"`
+	OrganisationID         *uuid.UUID        `yaml:"organisation_id"`
+	BaseUrl                *string           `yaml:"base_url"`
+	APIVersion             *string           `yaml:"api_version"`
+	Timeout                *time.Duration    `yaml:"timeout"`
+	MaxConns               *int              `yaml:"max_conns"`
+	IdleConnTimeout        *time.Duration    `yaml:"idle_conn_timeout"`
+	PollInterval           *time.Duration    `yaml:"poll_interval"`
+	PollTimeout            *time.Duration    `yaml:"poll_timeout"`
+	StrictMode             *bool             `yaml:"strict_mode"`
+	ReadOnly               *bool             `yaml:"read_only"`
+	SingleFlight           *bool             `yaml:"single_flight"`
+	ReturnExistingOnCreate *bool             `yaml:"return_existing_on_create"`
+	BulkheadSize           *int              `yaml:"bulkhead_size"`
+	BulkheadTimeout        *time.Duration    `yaml:"bulkhead_timeout"`
+	StaleReadMaxAge        *time.Duration    `yaml:"stale_read_max_age"`
+	SlowRequestThreshold   *time.Duration    `yaml:"slow_request_threshold"`
+	AfterHookBodyLimit     *int              `yaml:"after_hook_body_limit"`
+	ErrorBodyLimit         *int              `yaml:"error_body_limit"`
+	UserAgent              *string           `yaml:"user_agent"`
+	DialTimeout            *time.Duration    `yaml:"dial_timeout"`
+	TLSHandshakeTimeout    *time.Duration    `yaml:"tls_handshake_timeout"`
+	ResponseHeaderTimeout  *time.Duration    `yaml:"response_header_timeout"`
+	ExpectContinueTimeout  *time.Duration    `yaml:"expect_continue_timeout"`
+	DisableKeepAlives      *bool             `yaml:"disable_keep_alives"`
+	KeepAlive              *time.Duration    `yaml:"keep_alive"`
+	ForceAttemptHTTP2      *bool             `yaml:"force_attempt_http2"`
+	Headers                map[string]string `yaml:"headers"`
+	ProxyUrl               *string           `yaml:"proxy_url"`
+	APIKeyFile             *string           `yaml:"api_key_file"`
+	PrivateKeyFile         *string           `yaml:"private_key_file"`
+	SigningKeyID           *string           `yaml:"signing_key_id"`
+	VerifyResponses        *bool             `yaml:"verify_responses"`
+	CACertFile             *string           `yaml:"ca_cert_file"`
+	ClientCertFile         *string           `yaml:"client_cert_file"`
+	ClientKeyFile          *string           `yaml:"client_key_file"`
+	MinTLSVersion          *string           `yaml:"min_tls_version"`
+	LogLevel               *logger.Level     `yaml:"log_level"`
+}
+
+// FromFile reads the options from a YAML (.yaml, .yml) or JSON (.json) config file, so deployments managing
+// the settings with mounted config files don't need to translate them into env vars.
+// The keys are the snake case names of the FORM3_* env vars (i.e. base_url, timeout: 10s) and environment
+// can be set to a preset name (production, sandbox or local). Env vars in the file (i.e. ${ORG_ID}) are expanded.
+// The options override the env vars and they can be overridden by the options after them:
+//
+//	options, err := config.FromFile("/etc/form3/client.yaml")
+//	...
+//	client, err := form3.New(append(options, config.WithTimeout(time.Second))...)
+func FromFile(path string) ([]Option, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFileFormat, path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML so the YAML decoder handles both formats
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(os.ExpandEnv(string(content)))))
+	decoder.KnownFields(true)
+	var fc fileConfig
+	if err := decoder.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fc.options()
+}
+
+func (fc fileConfig) options() ([]Option, error) {
+	var options []Option
+	if fc.Environment != nil {
+		env, ok := environments[*fc.Environment]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEnvironment, *fc.Environment)
+		}
+		options = append(options, WithEnvironment(env))
+	}
+	if fc.OrganisationID != nil {
+		options = append(options, WithOrganisationID(*fc.OrganisationID))
+	}
+	if fc.BaseUrl != nil {
+		options = append(options, WithBaseUrl(*fc.BaseUrl))
+	}
+	if fc.APIVersion != nil {
+		options = append(options, WithAPIVersion(*fc.APIVersion))
+	}
+	if fc.Timeout != nil {
+		options = append(options, WithTimeout(*fc.Timeout))
+	}
+	if fc.MaxConns != nil {
+		options = append(options, WithMaxConns(*fc.MaxConns))
+	}
+	if fc.IdleConnTimeout != nil {
+		options = append(options, WithIdleConnTimeout(*fc.IdleConnTimeout))
+	}
+	if fc.DialTimeout != nil {
+		options = append(options, WithDialTimeout(*fc.DialTimeout))
+	}
+	if fc.TLSHandshakeTimeout != nil {
+		options = append(options, WithTLSHandshakeTimeout(*fc.TLSHandshakeTimeout))
+	}
+	if fc.ResponseHeaderTimeout != nil {
+		options = append(options, WithResponseHeaderTimeout(*fc.ResponseHeaderTimeout))
+	}
+	if fc.ExpectContinueTimeout != nil {
+		options = append(options, WithExpectContinueTimeout(*fc.ExpectContinueTimeout))
+	}
+	if fc.DisableKeepAlives != nil {
+		options = append(options, WithDisableKeepAlives(*fc.DisableKeepAlives))
+	}
+	if fc.KeepAlive != nil {
+		options = append(options, WithKeepAlive(*fc.KeepAlive))
+	}
+	if fc.ForceAttemptHTTP2 != nil {
+		options = append(options, WithForceAttemptHTTP2(*fc.ForceAttemptHTTP2))
+	}
+	if fc.PollInterval != nil {
+		options = append(options, WithPollInterval(*fc.PollInterval))
+	}
+	if fc.PollTimeout != nil {
+		options = append(options, WithPollTimeout(*fc.PollTimeout))
+	}
+	if fc.StrictMode != nil {
+		options = append(options, WithStrictMode(*fc.StrictMode))
+	}
+	if fc.ReadOnly != nil && *fc.ReadOnly {
+		options = append(options, WithReadOnly())
+	}
+	if fc.SingleFlight != nil && *fc.SingleFlight {
+		options = append(options, WithSingleFlight())
+	}
+	if fc.ReturnExistingOnCreate != nil && *fc.ReturnExistingOnCreate {
+		options = append(options, WithReturnExistingOnCreate())
+	}
+	if fc.BulkheadSize != nil {
+		var timeout time.Duration
+		if fc.BulkheadTimeout != nil {
+			timeout = *fc.BulkheadTimeout
+		}
+		options = append(options, WithBulkhead(*fc.BulkheadSize, timeout))
+	}
+	if fc.StaleReadMaxAge != nil {
+		options = append(options, WithStaleReads(*fc.StaleReadMaxAge))
+	}
+	if fc.SlowRequestThreshold != nil {
+		options = append(options, WithSlowRequestThreshold(*fc.SlowRequestThreshold))
+	}
+	if fc.AfterHookBodyLimit != nil {
+		options = append(options, WithAfterHookBodyLimit(*fc.AfterHookBodyLimit))
+	}
+	if fc.ErrorBodyLimit != nil {
+		options = append(options, WithErrorBodyLimit(*fc.ErrorBodyLimit))
+	}
+	if fc.UserAgent != nil {
+		options = append(options, WithUserAgent(*fc.UserAgent))
+	}
+	if fc.Headers != nil {
+		options = append(options, WithDefaultHeaders(fc.Headers))
+	}
+	if fc.ProxyUrl != nil {
+		proxyUrl, err := url.Parse(*fc.ProxyUrl)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithProxyURL(proxyUrl))
+	}
+	if fc.APIKeyFile != nil {
+		options = append(options, WithAPIKeyFromFile(*fc.APIKeyFile))
+	}
+	if fc.PrivateKeyFile != nil {
+		options = append(options, WithPrivateKeyFromFile(*fc.PrivateKeyFile))
+	}
+	if fc.SigningKeyID != nil {
+		options = append(options, WithSigningKeyID(*fc.SigningKeyID))
+	}
+	if fc.VerifyResponses != nil {
+		options = append(options, WithVerifyResponses(*fc.VerifyResponses))
+	}
+	if fc.CACertFile != nil {
+		options = append(options, WithCACertFile(*fc.CACertFile))
+	}
+	if fc.ClientCertFile != nil || fc.ClientKeyFile != nil {
+		options = append(options, WithClientCert(deref(fc.ClientCertFile), deref(fc.ClientKeyFile)))
+	}
+	if fc.MinTLSVersion != nil {
+		version, ok := tlsVersions[*fc.MinTLSVersion]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTLSVersion, *fc.MinTLSVersion)
+		}
+		options = append(options, WithMinTLSVersion(version))
+	}
+	if fc.LogLevel != nil {
+		options = append(options, WithLogLevel(*fc.LogLevel))
+	}
+	return options, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
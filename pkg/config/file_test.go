@@ -0,0 +1,110 @@
+package config
+
+import (
+	"crypto/tls"
+	"form3interview/internal/config"
+	"form3interview/pkg/logger"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func (s *configTestSuite) TestFromFile() {
+	s.T().Setenv("TEST_ORGANISATION_ID", testOrganisationID)
+	s.T().Setenv("TEST_TENANT", "acme")
+
+	for _, tc := range []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "client.yaml",
+			content: `
+environment: sandbox
+organisation_id: ${TEST_ORGANISATION_ID}
+timeout: 42s
+max_conns: 42
+strict_mode: true
+headers:
+  X-Tenant: ${TEST_TENANT}
+proxy_url: http://proxy:3128
+min_tls_version: "1.3"
+log_level: warn
+`,
+		},
+		{
+			name: "json",
+			file: "client.json",
+			content: `{
+	"environment": "sandbox",
+	"organisation_id": "${TEST_ORGANISATION_ID}",
+	"timeout": "42s",
+	"max_conns": 42,
+	"strict_mode": true,
+	"headers": {"X-Tenant": "${TEST_TENANT}"},
+	"proxy_url": "http://proxy:3128",
+	"min_tls_version": "1.3",
+	"log_level": "warn"
+}`,
+		},
+	} {
+		s.Run(tc.name, func() {
+			path := s.writeFile(tc.file, tc.content)
+
+			options, err := FromFile(path)
+			s.Require().NoError(err)
+			cfg := config.NewConfig()
+			ApplyOptions(&cfg, options)
+
+			s.Equal(testOrganisationID, cfg.OrganisationID.String())
+			s.Equal(Sandbox.BaseUrl, *cfg.BaseUrl)
+			s.Equal(42*time.Second, *cfg.Timeout)
+			s.Equal(Sandbox.PollTimeout, *cfg.PollTimeout)
+			s.Equal(42, cfg.MaxConns)
+			s.True(cfg.StrictMode)
+			s.Equal("acme", cfg.Headers["X-Tenant"])
+			s.Equal("proxy:3128", cfg.ProxyUrl.Host)
+			s.Equal(uint16(tls.VersionTLS13), cfg.MinTLSVersion)
+			s.Equal(logger.LevelWarn, cfg.LogLevel)
+		})
+	}
+}
+
+func (s *configTestSuite) TestFromFileErrors() {
+	for _, tc := range []struct {
+		name        string
+		file        string
+		content     string
+		expectedErr error
+	}{
+		{name: "unsupported format", file: "client.toml", content: `timeout = "5s"`, expectedErr: ErrUnsupportedFileFormat},
+		{name: "unknown environment", file: "client.yaml", content: "environment: qa", expectedErr: ErrUnknownEnvironment},
+		{name: "unknown TLS version", file: "client.yaml", content: `min_tls_version: "1.4"`, expectedErr: ErrUnknownTLSVersion},
+	} {
+		s.Run(tc.name, func() {
+			_, err := FromFile(s.writeFile(tc.file, tc.content))
+
+			s.ErrorIs(err, tc.expectedErr)
+		})
+	}
+
+	s.Run("unknown field", func() {
+		_, err := FromFile(s.writeFile("client.yaml", "base_uri: http://localhost:8080/v1"))
+
+		s.ErrorContains(err, "base_uri")
+	})
+
+	s.Run("missing file", func() {
+		_, err := FromFile(filepath.Join(s.T().TempDir(), "client.yaml"))
+
+		s.ErrorIs(err, os.ErrNotExist)
+	})
+}
+
+func (s *configTestSuite) writeFile(name, content string) string {
+	path := filepath.Join(s.T().TempDir(), name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"form3interview/pkg/logger"
+)
+
+// FlagPrefix is the prefix of the flags registered by RegisterFlags.
+const FlagPrefix = "form3-"
+
+// Flags are the client settings registered on a flag.FlagSet.
+type Flags struct {
+	fc fileConfig
+}
+
+// RegisterFlags registers the client settings on the flag set, so CLIs built on the clients get the
+// -form3-base-url, -form3-timeout etc. flags. The flags are named after the FORM3_* env vars and Options returns
+// the options of the flags given on the command line, so they take precedence over the env vars:
+//
+//	fs := flag.NewFlagSet("mycli", flag.ExitOnError)
+//	flags := config.RegisterFlags(fs)
+//	_ = fs.Parse(os.Args[1:])
+//	options, err := flags.Options()
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fc := &f.fc
+	fs.Func(FlagPrefix+"environment", "environment preset (production, sandbox or local)", stringFlag(&fc.Environment))
+	fs.Func(FlagPrefix+"organisation-id", "organisation ID used by the API calls", uuidFlag(&fc.OrganisationID))
+	fs.Func(FlagPrefix+"base-url", "base url of the API", stringFlag(&fc.BaseUrl))
+	fs.Func(FlagPrefix+"api-version", "API version path appended to the base url (i.e. v1)", stringFlag(&fc.APIVersion))
+	fs.Func(FlagPrefix+"timeout", "global request timeout (default 5s)", durationFlag(&fc.Timeout))
+	fs.Func(FlagPrefix+"max-conns", "maximum number of connections (default 100)", intFlag(&fc.MaxConns))
+	fs.Func(FlagPrefix+"idle-conn-timeout", "timeout of the idle connections (default 90s)", durationFlag(&fc.IdleConnTimeout))
+	fs.Func(FlagPrefix+"dial-timeout", "timeout of establishing a TCP connection (default 30s)", durationFlag(&fc.DialTimeout))
+	fs.Func(FlagPrefix+"tls-handshake-timeout", "timeout of the TLS handshake (default 10s)", durationFlag(&fc.TLSHandshakeTimeout))
+	fs.Func(FlagPrefix+"response-header-timeout", "timeout of waiting for the response headers", durationFlag(&fc.ResponseHeaderTimeout))
+	fs.Func(FlagPrefix+"poll-interval", "interval of polling asynchronous operations (default 1s)", durationFlag(&fc.PollInterval))
+	fs.Func(FlagPrefix+"poll-timeout", "timeout of waiting for asynchronous operations (default 30s)", durationFlag(&fc.PollTimeout))
+	fs.Var(boolFlag{&fc.StrictMode}, FlagPrefix+"strict-mode", "detect API changes which break the client")
+	fs.Var(boolFlag{&fc.ReadOnly}, FlagPrefix+"read-only", "fail the mutating requests before they are sent")
+	fs.Var(boolFlag{&fc.SingleFlight}, FlagPrefix+"single-flight", "collapse the concurrent GET requests of the same url and headers")
+	fs.Var(boolFlag{&fc.ReturnExistingOnCreate}, FlagPrefix+"return-existing-on-create", "return the existing resource when a resource is created with a taken ID")
+	fs.Func(FlagPrefix+"bulkhead-size", "maximum number of concurrent requests per resource client (default unlimited)", intFlag(&fc.BulkheadSize))
+	fs.Func(FlagPrefix+"bulkhead-timeout", "timeout of waiting for a free bulkhead slot", durationFlag(&fc.BulkheadTimeout))
+	fs.Func(FlagPrefix+"stale-read-max-age", "serve cached reads not older than this while the API is failing", durationFlag(&fc.StaleReadMaxAge))
+	fs.Func(FlagPrefix+"slow-request-threshold", "log the requests taking longer than this", durationFlag(&fc.SlowRequestThreshold))
+	fs.Func(FlagPrefix+"after-hook-body-limit", "bytes of the response body passed to the AfterHook (default 65536)", intFlag(&fc.AfterHookBodyLimit))
+	fs.Func(FlagPrefix+"error-body-limit", "bytes of the error response bodies captured in the errors (default 65536)", intFlag(&fc.ErrorBodyLimit))
+	fs.Func(FlagPrefix+"user-agent", "User-Agent header of the requests", stringFlag(&fc.UserAgent))
+	fs.Func(FlagPrefix+"header", "header added to every request as Name:Value (can be repeated)", headerFlag(&fc.Headers))
+	fs.Func(FlagPrefix+"proxy-url", "proxy used for all requests (HTTP_PROXY, HTTPS_PROXY and NO_PROXY are used by default)", stringFlag(&fc.ProxyUrl))
+	fs.Func(FlagPrefix+"api-key-file", "file of the API key sent as a bearer token", stringFlag(&fc.APIKeyFile))
+	fs.Func(FlagPrefix+"private-key-file", "PEM file of the private key used to sign the requests", stringFlag(&fc.PrivateKeyFile))
+	fs.Func(FlagPrefix+"signing-key-id", "ID of the key the requests are signed with", stringFlag(&fc.SigningKeyID))
+	fs.Var(boolFlag{&fc.VerifyResponses}, FlagPrefix+"verify-responses", "verify the Digest header of the responses")
+	fs.Func(FlagPrefix+"ca-cert-file", "PEM file of the trusted CA certificates", stringFlag(&fc.CACertFile))
+	fs.Func(FlagPrefix+"client-cert-file", "PEM file of the client certificate used for mTLS", stringFlag(&fc.ClientCertFile))
+	fs.Func(FlagPrefix+"client-key-file", "PEM file of the client private key used for mTLS", stringFlag(&fc.ClientKeyFile))
+	fs.Func(FlagPrefix+"min-tls-version", "minimum TLS version (1.0, 1.1, 1.2 or 1.3)", stringFlag(&fc.MinTLSVersion))
+	fs.Func(FlagPrefix+"log-level", "minimum level of the logged entries (debug, info, warn, error or disabled)", logLevelFlag(&fc.LogLevel))
+	return f
+}
+
+// Options returns the options of the flags given on the command line. It must be called after the flag set is parsed.
+func (f *Flags) Options() ([]Option, error) {
+	return f.fc.options()
+}
+
+func stringFlag(p **string) func(string) error {
+	return func(value string) error {
+		*p = &value
+		return nil
+	}
+}
+
+func uuidFlag(p **uuid.UUID) func(string) error {
+	return func(value string) error {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return err
+		}
+		*p = &id
+		return nil
+	}
+}
+
+func durationFlag(p **time.Duration) func(string) error {
+	return func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*p = &d
+		return nil
+	}
+}
+
+func intFlag(p **int) func(string) error {
+	return func(value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*p = &i
+		return nil
+	}
+}
+
+func logLevelFlag(p **logger.Level) func(string) error {
+	return func(value string) error {
+		var level logger.Level
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return err
+		}
+		*p = &level
+		return nil
+	}
+}
+
+func headerFlag(p *map[string]string) func(string) error {
+	return func(value string) error {
+		name, val, ok := strings.Cut(value, ":")
+		if !ok {
+			return fmt.Errorf("header %q is not in Name:Value format", value)
+		}
+		if *p == nil {
+			*p = map[string]string{}
+		}
+		(*p)[strings.TrimSpace(name)] = strings.TrimSpace(val)
+		return nil
+	}
+}
+
+// boolFlag is a flag.Value which can be given without a value (i.e. -form3-strict-mode).
+type boolFlag struct {
+	p **bool
+}
+
+func (b boolFlag) String() string {
+	if b.p == nil || *b.p == nil {
+		return "false"
+	}
+	return strconv.FormatBool(**b.p)
+}
+
+func (b boolFlag) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*b.p = &v
+	return nil
+}
+
+func (b boolFlag) IsBoolFlag() bool {
+	return true
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"flag"
+	"form3interview/internal/config"
+	"io"
+	"time"
+)
+
+func (s *configTestSuite) TestRegisterFlags() {
+	s.T().Setenv(baseUrlKey, testBaseUrl)
+	s.T().Setenv(timeoutKey, "42s")
+	s.T().Setenv(maxConnsKey, "42")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+
+	err := fs.Parse([]string{
+		"-form3-organisation-id", testOrganisationID,
+		"-form3-base-url", "http://localhost:8080",
+		"-form3-api-version", "v1",
+		"-form3-timeout", "1s",
+		"-form3-strict-mode",
+		"-form3-header", "X-Tenant:acme",
+		"-form3-header", "X-Client-Version: 1.2.0",
+	})
+	s.Require().NoError(err)
+	options, err := flags.Options()
+	s.Require().NoError(err)
+	cfg := config.NewConfig()
+	ApplyOptions(&cfg, options)
+
+	s.Equal(testOrganisationID, cfg.OrganisationID.String())
+	s.Equal("http://localhost:8080/v1/organisation/accounts", cfg.Url("/organisation/accounts"))
+	s.Equal(time.Second, *cfg.Timeout)
+	s.Equal(42, cfg.MaxConns)
+	s.True(cfg.StrictMode)
+	s.Equal(map[string]string{"X-Tenant": "acme", "X-Client-Version": "1.2.0"}, cfg.Headers)
+}
+
+func (s *configTestSuite) TestRegisterFlagsErrors() {
+	for _, test := range []struct {
+		name string
+		args []string
+	}{
+		{name: "invalid organisation id", args: []string{"-form3-organisation-id", "not-a-uuid"}},
+		{name: "invalid duration", args: []string{"-form3-timeout", "42"}},
+		{name: "invalid int", args: []string{"-form3-max-conns", "many"}},
+		{name: "invalid bool", args: []string{"-form3-strict-mode=maybe"}},
+		{name: "invalid header", args: []string{"-form3-header", "X-Tenant"}},
+	} {
+		s.Run(test.name, func() {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			RegisterFlags(fs)
+
+			s.Error(fs.Parse(test.args))
+		})
+	}
+
+	s.Run("unknown environment", func() {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := RegisterFlags(fs)
+		s.Require().NoError(fs.Parse([]string{"-form3-environment", "qa"}))
+
+		_, err := flags.Options()
+
+		s.ErrorIs(err, ErrUnknownEnvironment)
+	})
+}
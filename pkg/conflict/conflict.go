@@ -0,0 +1,110 @@
+// Package conflict provides the policies resolving the version conflicts (409 Conflict) of the versioned
+// writes (i.e. DeleteVersion), so the callers can choose the consistency behavior once in the client config
+// instead of writing their own retry loops.
+package conflict
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxAttempts is the number of attempts of a write including the first one if it's not configured.
+const DefaultMaxAttempts = 3
+
+// Strategy decides what happens when a versioned write hits a version conflict.
+type Strategy int
+
+const (
+	// Fail returns the version conflict error to the caller. This is the default.
+	Fail Strategy = iota
+	// Refetch fetches the latest version of the resource and retries the write with it.
+	Refetch
+	// Merge fetches the latest version of the resource and calls the Merge callback of the policy to decide
+	// if the write is retried with it.
+	Merge
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case Fail:
+		return "fail"
+	case Refetch:
+		return "refetch"
+	case Merge:
+		return "merge"
+	}
+	return fmt.Sprintf("Strategy(%d)", int(s))
+}
+
+// Conflict describes a version conflict passed to the Merge callback.
+type Conflict struct {
+	// ID of the resource.
+	ID uuid.UUID
+	// Version is the version the write was attempted with.
+	Version uint
+	// LatestVersion is the current version of the resource.
+	LatestVersion uint
+	// Latest is the current resource (i.e. *account.AccountData).
+	Latest any
+}
+
+// Policy resolves the version conflicts. The zero value fails on the first conflict.
+type Policy struct {
+	Strategy Strategy
+	// MaxAttempts is the maximum number of attempts of a write including the first one. Default is 3.
+	MaxAttempts int
+	// Merge is called with the conflict by the Merge strategy. The write is retried with the latest version
+	// if it returns nil, otherwise its error is returned to the caller. It behaves like Refetch if it's nil.
+	Merge func(Conflict) error
+}
+
+// Write is a versioned write of a resource.
+type Write struct {
+	ID      uuid.UUID
+	Version uint
+	// ErrConflict is the error returned by Do on a version conflict.
+	ErrConflict error
+	// Do writes the resource with the given version.
+	Do func(version uint) error
+	// Latest fetches the current version of the resource and the resource itself.
+	Latest func() (uint, any, error)
+}
+
+// Resolve runs the write and resolves its version conflicts with the policy. The error of the last attempt is
+// returned when the conflicts are not resolved within MaxAttempts.
+func (p Policy) Resolve(w Write) error {
+	version := w.Version
+	err := w.Do(version)
+	for attempt := 1; p.Strategy != Fail && attempt < p.maxAttempts() && errors.Is(err, w.ErrConflict); attempt++ {
+		latestVersion, latest, fetchErr := w.Latest()
+		if fetchErr != nil {
+			return fetchErr
+		}
+		if p.Strategy == Merge && p.Merge != nil {
+			conflict := Conflict{ID: w.ID, Version: version, LatestVersion: latestVersion, Latest: latest}
+			if mergeErr := p.Merge(conflict); mergeErr != nil {
+				return mergeErr
+			}
+		}
+		version = latestVersion
+		err = w.Do(version)
+	}
+	return err
+}
+
+func (p Policy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// String describes the policy for the config dumps.
+func (p Policy) String() string {
+	if p.Strategy == Fail {
+		return p.Strategy.String()
+	}
+	return fmt.Sprintf("%s max_attempts=%d", p.Strategy, p.maxAttempts())
+}
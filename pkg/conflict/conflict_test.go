@@ -0,0 +1,109 @@
+package conflict
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+var errConflict = errors.New("invalid version")
+
+type conflictTestSuite struct {
+	suite.Suite
+	written []uint
+	fetches int
+}
+
+func TestConflictTestSuite(t *testing.T) {
+	suite.Run(t, new(conflictTestSuite))
+}
+
+func (s *conflictTestSuite) SetupTest() {
+	s.written = nil
+	s.fetches = 0
+}
+
+// write returns a write which conflicts until it's done with the current version.
+func (s *conflictTestSuite) write(id uuid.UUID, current uint) Write {
+	return Write{
+		ID:          id,
+		Version:     1,
+		ErrConflict: errConflict,
+		Do: func(version uint) error {
+			s.written = append(s.written, version)
+			if version != current {
+				return errConflict
+			}
+			return nil
+		},
+		Latest: func() (uint, any, error) {
+			s.fetches++
+			return current, "resource", nil
+		},
+	}
+}
+
+func (s *conflictTestSuite) TestResolveFails_WithDefaultPolicy() {
+	err := Policy{}.Resolve(s.write(uuid.New(), 2))
+
+	s.ErrorIs(err, errConflict)
+	s.Equal([]uint{1}, s.written)
+	s.Zero(s.fetches)
+}
+
+func (s *conflictTestSuite) TestResolveRefetches() {
+	err := Policy{Strategy: Refetch}.Resolve(s.write(uuid.New(), 2))
+
+	s.NoError(err)
+	s.Equal([]uint{1, 2}, s.written)
+	s.Equal(1, s.fetches)
+}
+
+func (s *conflictTestSuite) TestResolveGivesUpAfterMaxAttempts() {
+	w := s.write(uuid.New(), 2)
+	w.Latest = func() (uint, any, error) { return 3, nil, nil }
+
+	err := Policy{Strategy: Refetch, MaxAttempts: 2}.Resolve(w)
+
+	s.ErrorIs(err, errConflict)
+	s.Equal([]uint{1, 3}, s.written)
+}
+
+func (s *conflictTestSuite) TestResolveMerges() {
+	id := uuid.New()
+	var conflicts []Conflict
+
+	err := Policy{Strategy: Merge, Merge: func(c Conflict) error {
+		conflicts = append(conflicts, c)
+		return nil
+	}}.Resolve(s.write(id, 2))
+
+	s.NoError(err)
+	s.Equal([]Conflict{{ID: id, Version: 1, LatestVersion: 2, Latest: "resource"}}, conflicts)
+	s.Equal([]uint{1, 2}, s.written)
+}
+
+func (s *conflictTestSuite) TestResolveReturnsMergeError() {
+	errKeep := errors.New("keep the latest")
+
+	err := Policy{Strategy: Merge, Merge: func(Conflict) error { return errKeep }}.Resolve(s.write(uuid.New(), 2))
+
+	s.ErrorIs(err, errKeep)
+	s.Equal([]uint{1}, s.written)
+}
+
+func (s *conflictTestSuite) TestResolveReturnsFetchError() {
+	errFetch := errors.New("not found")
+	w := s.write(uuid.New(), 2)
+	w.Latest = func() (uint, any, error) { return 0, nil, errFetch }
+
+	s.ErrorIs(Policy{Strategy: Refetch}.Resolve(w), errFetch)
+}
+
+func (s *conflictTestSuite) TestString() {
+	s.Equal("fail", Policy{}.String())
+	s.Equal("refetch max_attempts=3", Policy{Strategy: Refetch}.String())
+	s.Equal("merge max_attempts=5", Policy{Strategy: Merge, MaxAttempts: 5}.String())
+}
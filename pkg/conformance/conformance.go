@@ -0,0 +1,356 @@
+// Package conformance provides a contract test suite which runs the operations of the SDK against a Form3 API and
+// reports if it behaves like the production API: the status codes, the error payloads, the versioning and the
+// conflicts of the accounts. It can be run against the form3test fake, a local stack, the sandbox or a proxy in
+// front of them, so their setup can be verified before the clients are pointed to them.
+//
+// The suite creates and deletes accounts in the configured organisation.
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/config"
+	"form3interview/pkg/form3test"
+	"form3interview/pkg/health"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+)
+
+// Country is the country of the accounts created by the suite.
+const Country = "GB"
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name     string
+	Status   Status
+	Message  string
+	Duration time.Duration
+}
+
+// Report contains the results of all the checks in the order they were run.
+type Report struct {
+	Results []Result
+}
+
+// Compliant tells if none of the checks failed.
+func (r Report) Compliant() bool {
+	for _, res := range r.Results {
+		if res.Status == StatusFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// Print writes a human readable compliance report to w.
+func (r Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, res := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.ToUpper(string(res.Status)), res.Name, res.Duration.Round(time.Millisecond), res.Message)
+	}
+
+	summary := "the API conforms to the SDK"
+	if !r.Compliant() {
+		summary = "the API doesn't conform to the SDK, see the failed checks above"
+	}
+	fmt.Fprintf(tw, "\n%s\n", summary)
+	return tw.Flush()
+}
+
+// skipped is returned by a check which can't run because a check it depends on failed.
+type skipped string
+
+func (s skipped) Error() string {
+	return string(s)
+}
+
+type runner struct {
+	ctx      context.Context
+	options  []config.Option
+	accounts *account.Client
+	created  *account.AccountData
+	deleted  bool
+	report   Report
+}
+
+// Run runs the checks against the Form3 API configured by the options (and env vars) and returns a report.
+// The checks depending on the account created by the create check are skipped if it fails, and all the checks
+// are skipped if the config is invalid. The created account is deleted even if the delete check fails.
+func Run(ctx context.Context, options ...config.Option) Report {
+	r := &runner{ctx: ctx, options: options}
+	checks := []struct {
+		name string
+		fn   func() (string, error)
+	}{
+		{"health", r.checkHealth},
+		{"create", r.checkCreate},
+		{"fetch", r.checkFetch},
+		{"fetch with meta", r.checkFetchWithMeta},
+		{"touch", r.checkTouch},
+		{"duplicate create", r.checkDuplicateCreate},
+		{"invalid create", r.checkInvalidCreate},
+		{"fetch missing", r.checkFetchMissing},
+		{"delete with wrong version", r.checkDeleteWrongVersion},
+		{"delete", r.checkDelete},
+		{"fetch deleted", r.checkFetchDeleted},
+		{"delete missing", r.checkDeleteMissing},
+	}
+
+	if !r.check("config", r.checkConfig) {
+		for _, c := range checks {
+			r.report.Results = append(r.report.Results, Result{Name: c.name, Status: StatusSkipped, Message: "invalid config"})
+		}
+		return r.report
+	}
+	defer r.accounts.Close()
+
+	for _, c := range checks {
+		r.check(c.name, c.fn)
+	}
+	if r.created != nil && !r.deleted {
+		_ = r.accounts.Delete(uuid.MustParse(r.created.ID), r.enricher())
+	}
+	return r.report
+}
+
+func (r *runner) check(name string, fn func() (string, error)) bool {
+	start := time.Now()
+	msg, err := fn()
+	res := Result{Name: name, Status: StatusPassed, Message: msg, Duration: time.Since(start)}
+	var reason skipped
+	switch {
+	case errors.As(err, &reason):
+		res.Status = StatusSkipped
+		res.Message = reason.Error()
+	case err != nil:
+		res.Status = StatusFailed
+		res.Message = err.Error()
+	}
+	r.report.Results = append(r.report.Results, res)
+	return res.Status == StatusPassed
+}
+
+func (r *runner) enricher() re.RequestEnricher {
+	return re.RequestEnricher{Ctx: r.ctx}
+}
+
+// createdID returns the ID of the account created by the create check.
+func (r *runner) createdID() (uuid.UUID, error) {
+	if r.created == nil {
+		return uuid.Nil, skipped("no account was created")
+	}
+	return uuid.MustParse(r.created.ID), nil
+}
+
+func (r *runner) createdVersion() int64 {
+	if r.created.Version == nil {
+		return 0
+	}
+	return *r.created.Version
+}
+
+func (r *runner) checkConfig() (string, error) {
+	var err error
+	r.accounts, err = account.NewClient(r.options...)
+	if err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+func (r *runner) checkHealth() (string, error) {
+	client, err := health.NewClient(r.options...)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	status, err := client.HealthCheck(r.ctx)
+	if err != nil {
+		return "", err
+	}
+	if !status.Up() {
+		return "", fmt.Errorf("API is %s", status.State)
+	}
+	return "API is up", nil
+}
+
+func (r *runner) checkCreate() (string, error) {
+	attributes := form3test.NewValidAccount(Country)
+	created, err := r.accounts.Create(attributes, r.enricher())
+	if err != nil {
+		return "", err
+	}
+	if _, err := uuid.Parse(created.ID); err != nil {
+		return "", fmt.Errorf("invalid account ID %q", created.ID)
+	}
+	r.created = created
+
+	if created.Version == nil || *created.Version != 0 {
+		return "", fmt.Errorf("the version of the new account is %s, expected 0", version(created))
+	}
+	if created.Attributes == nil || created.Attributes.Iban != attributes.Iban {
+		return "", errors.New("the attributes of the account were not returned")
+	}
+	return fmt.Sprintf("account %s created", created.ID), nil
+}
+
+func (r *runner) checkFetch() (string, error) {
+	id, err := r.createdID()
+	if err != nil {
+		return "", err
+	}
+	fetched, err := r.accounts.Fetch(id, r.enricher())
+	if err != nil {
+		return "", err
+	}
+	if fetched.ID != r.created.ID || version(fetched) != version(r.created) {
+		return "", fmt.Errorf("fetched account %s with version %s, expected %s with version %s", fetched.ID, version(fetched), r.created.ID, version(r.created))
+	}
+	return "", nil
+}
+
+func (r *runner) checkFetchWithMeta() (string, error) {
+	id, err := r.createdID()
+	if err != nil {
+		return "", err
+	}
+	res, err := r.accounts.FetchWithMeta(id, r.enricher())
+	if err != nil {
+		return "", err
+	}
+	if res.Meta.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status code %d, expected %d", res.Meta.StatusCode, http.StatusOK)
+	}
+	if res.Meta.RequestID == "" {
+		return "no request ID returned", nil
+	}
+	return "request ID " + res.Meta.RequestID, nil
+}
+
+func (r *runner) checkTouch() (string, error) {
+	id, err := r.createdID()
+	if err != nil {
+		return "", err
+	}
+	local := account.LocalAccount{ID: id, Version: r.createdVersion()}
+	report := r.accounts.TouchAccounts(account.TouchFilter{Accounts: []account.LocalAccount{local}}, r.enricher())
+	if len(report.Failed) > 0 {
+		return "", report.Failed[0].Err
+	}
+	if !report.Consistent() {
+		return "", fmt.Errorf("account %s is missing or has a different version", id)
+	}
+	return "", nil
+}
+
+func (r *runner) checkDuplicateCreate() (string, error) {
+	id, err := r.createdID()
+	if err != nil {
+		return "", err
+	}
+	options := append(append([]config.Option{}, r.options...), config.WithUUIDGenerator(func() (uuid.UUID, error) { return id, nil }))
+	client, err := account.NewClient(options...)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	_, err = client.Create(form3test.NewValidAccount(Country), r.enricher())
+	return expectError(err, account.ErrAccountAlreadyExists, result.CodeConflict)
+}
+
+func (r *runner) checkInvalidCreate() (string, error) {
+	attributes := form3test.NewValidAccount(Country)
+	attributes.Bic = "invalid"
+
+	created, err := r.accounts.Create(attributes, r.enricher())
+	if err == nil {
+		_ = r.accounts.Delete(uuid.MustParse(created.ID), r.enricher())
+		return "", errors.New("an account with invalid BIC was created")
+	}
+	if msg, err := expectError(err, account.ErrInvalidRequest, result.CodeInvalidBIC); err != nil {
+		return msg, err
+	}
+	return fmt.Sprintf("%d validation errors", len(result.ValidationErrors(err))), nil
+}
+
+func (r *runner) checkFetchMissing() (string, error) {
+	_, err := r.accounts.Fetch(uuid.New(), r.enricher())
+	return expectError(err, account.ErrAccountNotFound, result.CodeNotFound)
+}
+
+func (r *runner) checkDeleteWrongVersion() (string, error) {
+	id, err := r.createdID()
+	if err != nil {
+		return "", err
+	}
+	err = r.accounts.DeleteVersion(id, uint(r.createdVersion())+1, r.enricher())
+	return expectError(err, account.ErrInvalidAccountVersion, result.CodeConflict)
+}
+
+func (r *runner) checkDelete() (string, error) {
+	id, err := r.createdID()
+	if err != nil {
+		return "", err
+	}
+	if err := r.accounts.DeleteVersion(id, uint(r.createdVersion()), r.enricher()); err != nil {
+		return "", err
+	}
+	r.deleted = true
+	return fmt.Sprintf("account %s deleted", id), nil
+}
+
+func (r *runner) checkFetchDeleted() (string, error) {
+	id, err := r.createdID()
+	if err != nil {
+		return "", err
+	}
+	_, err = r.accounts.Fetch(id, r.enricher())
+	return expectError(err, account.ErrAccountNotFound, result.CodeNotFound)
+}
+
+func (r *runner) checkDeleteMissing() (string, error) {
+	err := r.accounts.DeleteVersion(uuid.New(), 0, r.enricher())
+	return expectError(err, account.ErrAccountNotFound, result.CodeNotFound)
+}
+
+// expectError checks that the operation failed with the expected error and error code.
+func expectError(err, expected error, code result.ErrorCode) (string, error) {
+	if err == nil {
+		return "", fmt.Errorf("succeeded, expected %q", expected)
+	}
+	if !errors.Is(err, expected) {
+		return "", fmt.Errorf("failed with %q, expected %q", err, expected)
+	}
+	if actual := result.CodeOf(err); actual != code {
+		return "", fmt.Errorf("failed with error code %q, expected %q", actual, code)
+	}
+	return "", nil
+}
+
+func version(acc *account.AccountData) string {
+	if acc.Version == nil {
+		return "none"
+	}
+	return fmt.Sprint(*acc.Version)
+}
@@ -0,0 +1,103 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/config"
+	"form3interview/pkg/form3test"
+)
+
+type conformanceTestSuite struct {
+	suite.Suite
+	server *form3test.Server
+}
+
+func TestConformanceTestSuite(t *testing.T) {
+	suite.Run(t, new(conformanceTestSuite))
+}
+
+func (s *conformanceTestSuite) SetupTest() {
+	s.server = form3test.NewServer()
+}
+
+func (s *conformanceTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *conformanceTestSuite) TestRunPassesAllChecks_WhenRunAgainstTheFakeAPI() {
+	report := Run(context.Background(), s.server.Options()...)
+
+	for _, res := range report.Results {
+		s.Equal(StatusPassed, res.Status, "%s: %s", res.Name, res.Message)
+	}
+	s.Len(report.Results, 13)
+	s.True(report.Compliant())
+	s.Empty(s.server.Accounts())
+}
+
+func (s *conformanceTestSuite) TestRunReportsFailedChecks_WhenDeleteFails() {
+	s.server.Inject(form3test.ServerErrors(1).Always().On(http.MethodDelete, form3test.AccountsPath))
+
+	report := Run(context.Background(), s.server.Options()...)
+
+	s.False(report.Compliant())
+	actual := statuses(report)
+	s.Equal(StatusPassed, actual["create"])
+	s.Equal(StatusFailed, actual["delete with wrong version"])
+	s.Equal(StatusFailed, actual["delete"])
+	s.Equal(StatusFailed, actual["fetch deleted"])
+	s.Equal(StatusFailed, actual["delete missing"])
+}
+
+func (s *conformanceTestSuite) TestRunSkipsAccountChecks_WhenCreateFails() {
+	s.server.Inject(form3test.Status(1, http.StatusBadRequest).On(http.MethodPost, form3test.AccountsPath))
+
+	report := Run(context.Background(), s.server.Options()...)
+
+	s.False(report.Compliant())
+	actual := statuses(report)
+	s.Equal(StatusFailed, actual["create"])
+	s.Equal(StatusSkipped, actual["fetch"])
+	s.Equal(StatusSkipped, actual["delete"])
+	s.Equal(StatusPassed, actual["invalid create"])
+	s.Equal(StatusPassed, actual["fetch missing"])
+}
+
+func (s *conformanceTestSuite) TestRunSkipsChecks_WhenConfigIsInvalid() {
+	report := Run(context.Background(), config.WithBaseUrl(s.server.BaseURL()))
+
+	s.False(report.Compliant())
+	actual := statuses(report)
+	s.Equal(StatusFailed, actual["config"])
+	s.Equal(StatusSkipped, actual["health"])
+	s.Equal(StatusSkipped, actual["delete missing"])
+	s.Zero(s.server.Requests())
+}
+
+func (s *conformanceTestSuite) TestPrint() {
+	report := Report{Results: []Result{
+		{Name: "create", Status: StatusPassed, Message: "account created"},
+		{Name: "duplicate create", Status: StatusFailed, Message: "succeeded, expected an error"},
+	}}
+
+	var buf bytes.Buffer
+	s.Require().NoError(report.Print(&buf))
+
+	s.Contains(buf.String(), "PASSED  create")
+	s.Contains(buf.String(), "FAILED  duplicate create")
+	s.Contains(buf.String(), "succeeded, expected an error")
+	s.Contains(buf.String(), "doesn't conform")
+}
+
+func statuses(report Report) map[string]Status {
+	actual := map[string]Status{}
+	for _, res := range report.Results {
+		actual[res.Name] = res.Status
+	}
+	return actual
+}
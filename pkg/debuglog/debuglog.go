@@ -0,0 +1,135 @@
+// Package debuglog provides the request/response debug logging of the Form3 clients. The requests and responses
+// are logged with their headers and bodies through the configured logger, with the credentials, signatures and
+// personal data redacted. It is enabled with config.WithDebugLogging and can be toggled at runtime.
+package debuglog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Redacted replaces the sensitive values.
+const Redacted = "REDACTED"
+
+// sensitiveHeaderParts are the parts of the header names which may carry credentials or signatures.
+var sensitiveHeaderParts = []string{"authorization", "cookie", "secret", "signature", "token", "api-key"}
+
+// sensitiveFields are the JSON fields of the request and response bodies carrying credentials or personal data.
+var sensitiveFields = map[string]bool{
+	"account_number":           true,
+	"iban":                     true,
+	"name":                     true,
+	"alternative_names":        true,
+	"secondary_identification": true,
+	"bank_id":                  true,
+	"bic":                      true,
+	"client_secret":            true,
+	"access_token":             true,
+	"refresh_token":            true,
+	"password":                 true,
+}
+
+// Toggle switches the debug logging on and off at runtime. It's safe for concurrent use and a nil Toggle is off.
+type Toggle struct {
+	enabled atomic.Bool
+}
+
+// NewToggle creates a toggle switched on if enabled is true.
+func NewToggle(enabled bool) *Toggle {
+	t := &Toggle{}
+	t.enabled.Store(enabled)
+	return t
+}
+
+// Enable switches the debug logging on.
+func (t *Toggle) Enable() {
+	t.enabled.Store(true)
+}
+
+// Disable switches the debug logging off.
+func (t *Toggle) Disable() {
+	t.enabled.Store(false)
+}
+
+// Enabled returns true if the debug logging is on.
+func (t *Toggle) Enabled() bool {
+	return t != nil && t.enabled.Load()
+}
+
+// String returns the state of the toggle for the config dumps.
+func (t *Toggle) String() string {
+	if t.Enabled() {
+		return "on"
+	}
+	return "off"
+}
+
+// Header formats the header in the Name: value; Name: value format sorted by name with the sensitive values redacted.
+func Header(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ",")
+		if SensitiveHeader(name) {
+			value = Redacted
+		}
+		pairs = append(pairs, name+": "+value)
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// SensitiveHeader returns true if the header may carry credentials or signatures.
+func SensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range sensitiveHeaderParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Body returns the JSON body with the values of the sensitive fields redacted at any depth. Other bodies are
+// replaced with their size, so no unparsed data is logged.
+func Body(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	redacted, err := json.Marshal(redact(v))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	return string(redacted)
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = Redacted
+			} else {
+				v[key] = redact(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,75 @@
+package debuglog
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type debugLogTestSuite struct {
+	suite.Suite
+}
+
+func TestDebugLogTestSuite(t *testing.T) {
+	suite.Run(t, new(debugLogTestSuite))
+}
+
+func (s *debugLogTestSuite) TestToggle() {
+	toggle := NewToggle(false)
+	s.False(toggle.Enabled())
+	s.Equal("off", toggle.String())
+
+	toggle.Enable()
+	s.True(toggle.Enabled())
+	s.Equal("on", toggle.String())
+
+	toggle.Disable()
+	s.False(toggle.Enabled())
+}
+
+func (s *debugLogTestSuite) TestNilToggleIsOff() {
+	var toggle *Toggle
+
+	s.False(toggle.Enabled())
+	s.Equal("off", toggle.String())
+}
+
+func (s *debugLogTestSuite) TestHeader() {
+	header := http.Header{
+		"Authorization":   []string{"Bearer secret"},
+		"Signature":       []string{`keyId="1",signature="abc"`},
+		"X-Api-Key":       []string{"secret"},
+		"Accept":          []string{"application/json", "application/vnd.api+json"},
+		"Idempotency-Key": []string{"1"},
+	}
+
+	s.Equal("Accept: application/json,application/vnd.api+json; Authorization: REDACTED; Idempotency-Key: 1; "+
+		"Signature: REDACTED; X-Api-Key: REDACTED", Header(header))
+}
+
+func (s *debugLogTestSuite) TestBody() {
+	for _, test := range []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "empty", body: "", expected: ""},
+		{
+			name:     "personal data",
+			body:     `{"data":{"attributes":{"account_number":"41426819","iban":"GB11NWBK40030041426819","name":["Jane Doe"],"country":"GB"}}}`,
+			expected: `{"data":{"attributes":{"account_number":"REDACTED","country":"GB","iban":"REDACTED","name":"REDACTED"}}}`,
+		},
+		{
+			name:     "list",
+			body:     `{"data":[{"attributes":{"bic":"NWBKGB22"}},{"attributes":{"bank_id":"400300"}}]}`,
+			expected: `{"data":[{"attributes":{"bic":"REDACTED"}},{"attributes":{"bank_id":"REDACTED"}}]}`,
+		},
+		{name: "credentials", body: `{"access_token":"secret","expires_in":3600}`, expected: `{"access_token":"REDACTED","expires_in":3600}`},
+		{name: "not json", body: "client_secret=secret", expected: "<20 bytes>"},
+	} {
+		s.Run(test.name, func() {
+			s.Equal(test.expected, Body([]byte(test.body)))
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Package directdebit provides Form3 client to manage SEPA direct debits.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/direct-debits
+package directdebit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	directDebitsUrl  = "/transaction/directdebits"
+	directDebitsType = "direct_debits"
+	submissionsType  = "direct_debit_submissions"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrDirectDebitNotFound direct debit not found
+	ErrDirectDebitNotFound = errors.New("direct debit not found")
+	// ErrSubmissionNotFound direct debit submission not found
+	ErrSubmissionNotFound = errors.New("direct debit submission not found")
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type directDebitClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 direct debits created by NewClient or the form3 facade.
+type Client = directDebitClient
+
+// NewClient creates a client for managing Form3 direct debits.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*directDebitClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &directDebitClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (d directDebitClient) Close() error {
+	return resource.Close(d.client)
+}
+
+// Create a direct debit with attributes.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/direct-debits/create-a-direct-debit
+//
+// The request can be enriched by RequestEnricher
+func (d directDebitClient) Create(attributes DirectDebitAttributes, en ...re.RequestEnricher) (*DirectDebitData, error) {
+	newID, err := d.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	directDebit := DirectDebitData{
+		ID:             newID.String(),
+		OrganisationID: d.config.OrganisationID.String(),
+		Type:           directDebitsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := d.directDebits().Create(newID, directDebit, en...)
+	if err != nil {
+		return nil, err
+	}
+	d.config.Log().Debugf("direct debit %s created", directDebit.ID)
+	return created, nil
+}
+
+// Fetch a direct debit by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/direct-debits/fetch-a-direct-debit
+//
+// The request can be enriched by RequestEnricher
+func (d directDebitClient) Fetch(directDebitID uuid.UUID, en ...re.RequestEnricher) (*DirectDebitData, error) {
+	return d.directDebits().Fetch(directDebitID, en...)
+}
+
+// List direct debits page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/direct-debits/list-direct-debits
+//
+// The request can be enriched by RequestEnricher
+func (d directDebitClient) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]DirectDebitData, error) {
+	return d.directDebits().List(pageNumber, pageSize, en...)
+}
+
+// CreateSubmission submits a direct debit for processing.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/direct-debits/create-a-direct-debit-submission
+//
+// The request can be enriched by RequestEnricher
+func (d directDebitClient) CreateSubmission(directDebitID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if directDebitID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := d.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	submission := SubmissionData{
+		ID:             newID.String(),
+		OrganisationID: d.config.OrganisationID.String(),
+		Type:           submissionsType,
+	}
+
+	created, err := d.submissions(directDebitID, ErrDirectDebitNotFound).Create(newID, submission, en...)
+	if err != nil {
+		return nil, err
+	}
+	d.config.Log().Debugf("direct debit %s submission %s created", directDebitID, submission.ID)
+	return created, nil
+}
+
+// FetchSubmission fetches a direct debit submission by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/direct-debits/fetch-a-direct-debit-submission
+//
+// The request can be enriched by RequestEnricher
+func (d directDebitClient) FetchSubmission(directDebitID, submissionID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if directDebitID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return d.submissions(directDebitID, ErrSubmissionNotFound).Fetch(submissionID, en...)
+}
+
+// directDebits returns the generic client of the direct debits collection.
+func (d directDebitClient) directDebits() resource.Client[DirectDebitData] {
+	return resource.Client[DirectDebitData]{
+		HTTP:        d.client,
+		Config:      d.config,
+		Url:         directDebitsUrl,
+		ErrNotFound: ErrDirectDebitNotFound,
+	}
+}
+
+// submissions returns the generic client of the submissions of a direct debit. The not found responses are
+// mapped to errNotFound, so the missing direct debit and submission can be told apart.
+func (d directDebitClient) submissions(directDebitID uuid.UUID, errNotFound error) resource.Client[SubmissionData] {
+	return resource.Client[SubmissionData]{
+		HTTP:        d.client,
+		Config:      d.config,
+		Url:         fmt.Sprintf("%s/%s/submissions", directDebitsUrl, directDebitID),
+		ErrNotFound: errNotFound,
+	}
+}
+
+func (d directDebitClient) bodyToDirectDebitData(body io.Reader) (*DirectDebitData, error) {
+	return resource.Client[DirectDebitData]{Config: d.config}.BodyToData(body)
+}
+
+func (d directDebitClient) bodyToSubmissionData(body io.Reader) (*SubmissionData, error) {
+	return resource.Client[SubmissionData]{Config: d.config}.BodyToData(body)
+}
@@ -0,0 +1,282 @@
+package directdebit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                  = "Do"
+	testBaseUrl         = "testhost"
+	testDirectDebitsUrl = testBaseUrl + directDebitsUrl
+	testOrganisationID  = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type directDebitTestSuite struct {
+	suite.Suite
+	mockHttpClient    *mocks.HttpClientMock
+	directDebitClient directDebitClient
+}
+
+func TestDirectDebitTestSuite(t *testing.T) {
+	suite.Run(t, new(directDebitTestSuite))
+}
+
+func (s *directDebitTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.directDebitClient = directDebitClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *directDebitTestSuite) TestCreateReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"amount is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "unauthorized",
+			responseStatus: http.StatusUnauthorized,
+			expectedError:  ErrUnauthorized,
+		},
+		{
+			name:           "forbidden",
+			responseStatus: http.StatusForbidden,
+			expectedError:  ErrForbidden,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"error_message\": \"backend error\"}",
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testDirectDebitsUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.directDebitClient.Create(DirectDebitAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *directDebitTestSuite) TestCreateReturnsHttpClientError() {
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testDirectDebitsUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.directDebitClient.Create(DirectDebitAttributes{})
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *directDebitTestSuite) TestCreateDirectDebit() {
+	directDebitID := uuid.New()
+	s.directDebitClient.config.UUIDGenerator = func() (uuid.UUID, error) { return directDebitID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testDirectDebitsUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.directDebitClient.Create(DirectDebitAttributes{Amount: "10.00", Currency: "EUR"})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.directDebitClient.bodyToDirectDebitData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(directDebitID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(directDebitsType, requested.Type)
+	s.Equal("10.00", requested.Attributes.Amount)
+	s.Equal("EUR", requested.Attributes.Currency)
+}
+
+func (s *directDebitTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.directDebitClient.Fetch(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *directDebitTestSuite) TestFetchReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		expectedError  error
+	}{
+		{
+			name:           "direct debit not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrDirectDebitNotFound,
+		},
+		{
+			name:           "server bad gateway",
+			responseStatus: http.StatusBadGateway,
+			expectedError:  ErrServerError,
+		},
+	} {
+		s.Run(test.name, func() {
+			directDebitID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testDirectDebitsUrl, directDebitID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody("")}, nil).
+				Once()
+
+			_, actualError := s.directDebitClient.Fetch(directDebitID)
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *directDebitTestSuite) TestFetchDirectDebit() {
+	directDebitID := uuid.New()
+	body, err := json.Marshal(directDebitContainer{Data: DirectDebitData{ID: directDebitID.String()}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testDirectDebitsUrl, directDebitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.directDebitClient.Fetch(directDebitID)
+	s.NoError(err)
+	s.Equal(directDebitID.String(), actual.ID)
+}
+
+func (s *directDebitTestSuite) TestListReturnsError() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testDirectDebitsUrl+"?page[number]=0&page[size]=10")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusServiceUnavailable, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.directDebitClient.List(0, 10)
+
+	s.ErrorIs(actualError, ErrServerUnavailable)
+}
+
+func (s *directDebitTestSuite) TestListDirectDebits() {
+	firstID, secondID := uuid.New(), uuid.New()
+	body, err := json.Marshal(directDebitListContainer{Data: []DirectDebitData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testDirectDebitsUrl+"?page[number]=2&page[size]=10")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.directDebitClient.List(2, 10)
+	s.NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func (s *directDebitTestSuite) TestCreateSubmissionReturnsError_WhenDirectDebitNotFound() {
+	directDebitID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/submissions", testDirectDebitsUrl, directDebitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.directDebitClient.CreateSubmission(directDebitID)
+
+	s.ErrorIs(actualError, ErrDirectDebitNotFound)
+}
+
+func (s *directDebitTestSuite) TestCreateSubmission() {
+	directDebitID, submissionID := uuid.New(), uuid.New()
+	s.directDebitClient.config.UUIDGenerator = func() (uuid.UUID, error) { return submissionID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/submissions", testDirectDebitsUrl, directDebitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{\"attributes\":{\"status\":\"accepted\"}}}")}, nil).
+		Once()
+
+	actual, err := s.directDebitClient.CreateSubmission(directDebitID)
+	s.Require().NoError(err)
+	s.Equal("accepted", actual.Attributes.Status)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.directDebitClient.bodyToSubmissionData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(submissionID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(submissionsType, requested.Type)
+}
+
+func (s *directDebitTestSuite) TestFetchSubmissionReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.directDebitClient.FetchSubmission(uuid.Nil, uuid.New())
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *directDebitTestSuite) TestFetchSubmission() {
+	directDebitID, submissionID := uuid.New(), uuid.New()
+	body, err := json.Marshal(submissionContainer{Data: SubmissionData{ID: submissionID.String()}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/submissions/%s", testDirectDebitsUrl, directDebitID, submissionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.directDebitClient.FetchSubmission(directDebitID, submissionID)
+	s.NoError(err)
+	s.Equal(submissionID.String(), actual.ID)
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
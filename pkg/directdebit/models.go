@@ -0,0 +1,66 @@
+package directdebit
+
+import "form3interview/internal/resource"
+
+// directDebitContainer is a simple container for the "data" JSON field.
+type directDebitContainer = resource.DataContainer[DirectDebitData]
+
+// directDebitListContainer is a simple container for the "data" JSON field of list responses.
+type directDebitListContainer = resource.ListContainer[DirectDebitData]
+
+// submissionContainer is a simple container for the "data" JSON field.
+type submissionContainer = resource.DataContainer[SubmissionData]
+
+// DirectDebitData represents a SEPA direct debit.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/direct-debits for
+// more information about fields.
+type DirectDebitData struct {
+	Attributes     *DirectDebitAttributes `json:"attributes,omitempty"`
+	ID             string                 `json:"id,omitempty"`
+	OrganisationID string                 `json:"organisation_id,omitempty"`
+	Type           string                 `json:"type,omitempty"`
+	Version        *int64                 `json:"version,omitempty"`
+}
+
+type DirectDebitAttributes struct {
+	Amount               string `json:"amount,omitempty"`
+	BeneficiaryParty     *Party `json:"beneficiary_party,omitempty"`
+	ClearingID           string `json:"clearing_id,omitempty"`
+	Currency             string `json:"currency,omitempty"`
+	DebtorParty          *Party `json:"debtor_party,omitempty"`
+	EndToEndReference    string `json:"end_to_end_reference,omitempty"`
+	MandateReference     string `json:"mandate_reference,omitempty"`
+	NumericReference     string `json:"numeric_reference,omitempty"`
+	PaymentScheme        string `json:"payment_scheme,omitempty"`
+	ProcessingDate       string `json:"processing_date,omitempty"`
+	Reference            string `json:"reference,omitempty"`
+	SchemePaymentSubType string `json:"scheme_payment_sub_type,omitempty"`
+	SchemePaymentType    string `json:"scheme_payment_type,omitempty"`
+}
+
+// Party represents the debtor or the beneficiary of a direct debit.
+type Party struct {
+	AccountName       string `json:"account_name,omitempty"`
+	AccountNumber     string `json:"account_number,omitempty"`
+	AccountNumberCode string `json:"account_number_code,omitempty"`
+	Address           string `json:"address,omitempty"`
+	BankID            string `json:"bank_id,omitempty"`
+	BankIDCode        string `json:"bank_id_code,omitempty"`
+	Country           string `json:"country,omitempty"`
+	Name              string `json:"name,omitempty"`
+}
+
+// SubmissionData represents a direct debit submission.
+type SubmissionData struct {
+	Attributes     *SubmissionAttributes `json:"attributes,omitempty"`
+	ID             string                `json:"id,omitempty"`
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	Version        *int64                `json:"version,omitempty"`
+}
+
+type SubmissionAttributes struct {
+	Status             string `json:"status,omitempty"`
+	StatusReason       string `json:"status_reason,omitempty"`
+	SubmissionDatetime string `json:"submission_datetime,omitempty"`
+}
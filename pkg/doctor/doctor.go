@@ -0,0 +1,296 @@
+// Package doctor provides non-destructive diagnostics to verify the connectivity and permissions of a Form3 client configuration.
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/account"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	healthUrl      = "/health"
+	listAccountUrl = "/organisation/accounts?page[size]=1"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name     string
+	Status   Status
+	Message  string
+	Duration time.Duration
+}
+
+// Report contains the results of all the checks in the order they were run.
+type Report struct {
+	Results []Result
+}
+
+// Healthy tells if none of the checks failed.
+func (r Report) Healthy() bool {
+	for _, res := range r.Results {
+		if res.Status == StatusFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// Print writes a human readable diagnosis to w.
+func (r Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, res := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.ToUpper(string(res.Status)), res.Name, res.Duration.Round(time.Millisecond), res.Message)
+	}
+
+	diagnosis := "all checks passed"
+	if !r.Healthy() {
+		diagnosis = "some checks failed, see the details above"
+	}
+	fmt.Fprintf(tw, "\n%s\n", diagnosis)
+	return tw.Flush()
+}
+
+// skipped is returned by a check which is not applicable to the configuration.
+type skipped string
+
+func (s skipped) Error() string {
+	return string(s)
+}
+
+type doctor struct {
+	cfg     conf.ClientConfig
+	options []config.Option
+	client  http.Client
+	baseUrl *url.URL
+	report  Report
+}
+
+// Run runs the checks against the Form3 API configured by the options (and env vars) and returns a report.
+// Checks are run in this order: config, DNS, TLS, health endpoint, authentication, list permission and
+// create+delete. The create+delete check creates and deletes an account in the sandbox organisation,
+// it is skipped when sandboxOrganisationID is nil.
+func Run(ctx context.Context, sandboxOrganisationID uuid.UUID, options ...config.Option) Report {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+	d := &doctor{
+		cfg:     cfg,
+		options: options,
+		client:  http.Client{Timeout: *cfg.Timeout, Transport: resource.NewTransport(cfg)},
+	}
+	if cfg.HttpClient != nil {
+		d.client = *cfg.HttpClient
+	}
+
+	if !d.check("config", d.checkConfig) {
+		d.skip("invalid config", "dns", "tls", "health", "auth", "list permission", "create and delete")
+		return d.report
+	}
+	if !d.check("dns", func() (string, error) { return d.checkDNS(ctx) }) {
+		d.skip("host cannot be resolved", "tls", "health", "auth", "list permission", "create and delete")
+		return d.report
+	}
+	d.check("tls", func() (string, error) { return d.checkTLS(ctx) })
+	d.check("health", func() (string, error) { return d.checkHealth(ctx) })
+
+	statusCode, err := d.listStatus(ctx)
+	d.check("auth", func() (string, error) { return checkAuth(statusCode, err) })
+	d.check("list permission", func() (string, error) { return checkListPermission(statusCode, err) })
+
+	d.check("create and delete", func() (string, error) { return d.checkCreateAndDelete(ctx, sandboxOrganisationID) })
+	return d.report
+}
+
+func (d *doctor) check(name string, fn func() (string, error)) bool {
+	start := time.Now()
+	msg, err := fn()
+	res := Result{Name: name, Status: StatusPassed, Message: msg, Duration: time.Since(start)}
+	var reason skipped
+	switch {
+	case errors.As(err, &reason):
+		res.Status = StatusSkipped
+		res.Message = reason.Error()
+	case err != nil:
+		res.Status = StatusFailed
+		res.Message = err.Error()
+	}
+	d.report.Results = append(d.report.Results, res)
+	return res.Status != StatusFailed
+}
+
+func (d *doctor) skip(reason string, names ...string) {
+	for _, name := range names {
+		d.report.Results = append(d.report.Results, Result{Name: name, Status: StatusSkipped, Message: reason})
+	}
+}
+
+func (d *doctor) checkConfig() (string, error) {
+	if d.cfg.BaseUrl == nil || *d.cfg.BaseUrl == "" {
+		return "", account.ErrBaseUrlNotConfigured
+	}
+	if d.cfg.OrganisationID == nil || *d.cfg.OrganisationID == uuid.Nil {
+		return "", account.ErrOrganisationIDNotConfigured
+	}
+
+	u, err := url.Parse(*d.cfg.BaseUrl)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("baseUrl %q has no host", *d.cfg.BaseUrl)
+	}
+	d.baseUrl = u
+	return fmt.Sprintf("base url %s, organisation %s", u, d.cfg.OrganisationID), nil
+}
+
+func (d *doctor) checkDNS(ctx context.Context) (string, error) {
+	addrs, err := net.DefaultResolver.LookupHost(ctx, d.baseUrl.Hostname())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s resolved to %s", d.baseUrl.Hostname(), strings.Join(addrs, ", ")), nil
+}
+
+func (d *doctor) checkTLS(ctx context.Context) (string, error) {
+	if d.baseUrl.Scheme != "https" {
+		return "", skipped("base url is not https")
+	}
+
+	port := d.baseUrl.Port()
+	if port == "" {
+		port = "443"
+	}
+	tlsConfig, err := resource.TLSConfig(d.cfg)
+	if err != nil {
+		return "", err
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.ServerName = d.baseUrl.Hostname()
+
+	dialer := tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: *d.cfg.Timeout},
+		Config:    tlsConfig,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(d.baseUrl.Hostname(), port))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	cert := state.PeerCertificates[0]
+	return fmt.Sprintf("%s, certificate of %s expires at %s", conf.TLSVersionName(state.Version), cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339)), nil
+}
+
+func (d *doctor) checkHealth(ctx context.Context) (string, error) {
+	statusCode, err := d.get(ctx, healthUrl)
+	if err != nil {
+		return "", err
+	}
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("health endpoint returned %d", statusCode)
+	}
+	return "API is up", nil
+}
+
+func (d *doctor) listStatus(ctx context.Context) (int, error) {
+	return d.get(ctx, listAccountUrl)
+}
+
+func checkAuth(statusCode int, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if statusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("credentials were rejected (%d)", statusCode)
+	}
+	return "credentials accepted", nil
+}
+
+func checkListPermission(statusCode int, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	switch statusCode {
+	case http.StatusOK:
+		return "accounts can be listed", nil
+	case http.StatusForbidden:
+		return "", fmt.Errorf("listing accounts is forbidden (%d)", statusCode)
+	}
+	return "", fmt.Errorf("listing accounts returned %d", statusCode)
+}
+
+func (d *doctor) checkCreateAndDelete(ctx context.Context, sandboxOrganisationID uuid.UUID) (string, error) {
+	if sandboxOrganisationID == uuid.Nil {
+		return "", skipped("no sandbox organisation given")
+	}
+
+	options := append(append([]config.Option{}, d.options...), config.WithOrganisationID(sandboxOrganisationID))
+	client, err := account.NewClient(options...)
+	if err != nil {
+		return "", err
+	}
+
+	country := "GB"
+	acc, err := client.Create(account.AccountAttributes{
+		BankID:       "400300",
+		BankIDCode:   "GBDSC",
+		BaseCurrency: "GBP",
+		Bic:          "NWBKGB22",
+		Country:      &country,
+		Name:         []string{"form3 doctor"},
+	}, re.RequestEnricher{Ctx: ctx})
+	if err != nil {
+		return "", fmt.Errorf("failed to create account: %w", err)
+	}
+
+	accountID, err := uuid.Parse(acc.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := client.Delete(accountID, re.RequestEnricher{Ctx: ctx}); err != nil {
+		return "", fmt.Errorf("failed to delete account %s: %w", accountID, err)
+	}
+	return fmt.Sprintf("account %s created and deleted in organisation %s", accountID, sandboxOrganisationID), nil
+}
+
+func (d *doctor) get(ctx context.Context, path string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.cfg.Url(path), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,161 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/config"
+)
+
+const testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+
+type doctorTestSuite struct {
+	suite.Suite
+	listStatus int
+	requests   []string
+	server     *httptest.Server
+}
+
+func TestDoctorTestSuite(t *testing.T) {
+	suite.Run(t, new(doctorTestSuite))
+}
+
+func (s *doctorTestSuite) SetupTest() {
+	s.listStatus = http.StatusOK
+	s.requests = nil
+	s.server = httptest.NewServer(http.HandlerFunc(s.fakeApi))
+}
+
+func (s *doctorTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *doctorTestSuite) TestRunReportsAllChecks() {
+	sandboxOrgID := uuid.New()
+
+	report := Run(context.Background(), sandboxOrgID, s.options()...)
+
+	s.True(report.Healthy())
+	s.Equal(map[string]Status{
+		"config":            StatusPassed,
+		"dns":               StatusPassed,
+		"tls":               StatusSkipped,
+		"health":            StatusPassed,
+		"auth":              StatusPassed,
+		"list permission":   StatusPassed,
+		"create and delete": StatusPassed,
+	}, statuses(report))
+	s.Contains(s.requests, "POST /v1/organisation/accounts")
+	s.Contains(s.requests, "DELETE /v1/organisation/accounts/")
+}
+
+func (s *doctorTestSuite) TestRunSkipsCreateAndDelete_WhenNoSandboxOrganisationGiven() {
+	report := Run(context.Background(), uuid.Nil, s.options()...)
+
+	s.True(report.Healthy())
+	s.Equal(StatusSkipped, statuses(report)["create and delete"])
+	s.NotContains(s.requests, "POST /v1/organisation/accounts")
+}
+
+func (s *doctorTestSuite) TestRunReportsFailedAuthAndPermission() {
+	for _, test := range []struct {
+		name             string
+		listStatus       int
+		expectedStatuses map[string]Status
+	}{
+		{
+			name:             "unauthorized",
+			listStatus:       http.StatusUnauthorized,
+			expectedStatuses: map[string]Status{"auth": StatusFailed, "list permission": StatusFailed},
+		},
+		{
+			name:             "forbidden",
+			listStatus:       http.StatusForbidden,
+			expectedStatuses: map[string]Status{"auth": StatusPassed, "list permission": StatusFailed},
+		},
+	} {
+		s.Run(test.name, func() {
+			s.listStatus = test.listStatus
+
+			report := Run(context.Background(), uuid.Nil, s.options()...)
+
+			s.False(report.Healthy())
+			actual := statuses(report)
+			for name, status := range test.expectedStatuses {
+				s.Equal(status, actual[name], name)
+			}
+		})
+	}
+}
+
+func (s *doctorTestSuite) TestRunSkipsChecks_WhenConfigIsInvalid() {
+	report := Run(context.Background(), uuid.Nil, config.WithBaseUrl(s.server.URL+"/v1"))
+
+	s.False(report.Healthy())
+	s.Equal(StatusFailed, statuses(report)["config"])
+	s.Equal(StatusSkipped, statuses(report)["health"])
+	s.Empty(s.requests)
+}
+
+func (s *doctorTestSuite) TestPrint() {
+	report := Report{Results: []Result{
+		{Name: "config", Status: StatusPassed, Message: "ok"},
+		{Name: "health", Status: StatusFailed, Message: "health endpoint returned 503"},
+	}}
+
+	var buf bytes.Buffer
+	s.Require().NoError(report.Print(&buf))
+
+	s.Contains(buf.String(), "PASSED  config")
+	s.Contains(buf.String(), "FAILED  health")
+	s.Contains(buf.String(), "health endpoint returned 503")
+	s.Contains(buf.String(), "some checks failed")
+}
+
+func (s *doctorTestSuite) options() []config.Option {
+	return []config.Option{
+		config.WithBaseUrl(s.server.URL + "/v1"),
+		config.WithOrganisationID(uuid.MustParse(testOrganisationID)),
+	}
+}
+
+func (s *doctorTestSuite) fakeApi(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	if strings.HasPrefix(path, "/v1/organisation/accounts/") {
+		path = "/v1/organisation/accounts/"
+	}
+	s.requests = append(s.requests, r.Method+" "+path)
+
+	switch r.Method + " " + path {
+	case "GET /v1/health":
+		_, _ = io.WriteString(w, `{"status":"up"}`)
+	case "GET /v1/organisation/accounts":
+		w.WriteHeader(s.listStatus)
+		_, _ = io.WriteString(w, `{"data":[]}`)
+	case "POST /v1/organisation/accounts":
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.Copy(w, r.Body)
+	case "GET /v1/organisation/accounts/":
+		_, _ = io.WriteString(w, `{"data":{"version":0}}`)
+	case "DELETE /v1/organisation/accounts/":
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func statuses(report Report) map[string]Status {
+	actual := map[string]Status{}
+	for _, res := range report.Results {
+		actual[res.Name] = res.Status
+	}
+	return actual
+}
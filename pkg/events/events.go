@@ -0,0 +1,129 @@
+// Package events provides the typed event bus of the Form3 clients. The clients publish their lifecycle events
+// (i.e. retries, circuit breaker state changes, token refreshes, rate limiting and deprecations) to the bus, so applications
+// can subscribe to them for alerting instead of scraping the logs. It is enabled with config.WithEventBus.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"form3interview/pkg/result"
+)
+
+type (
+	// Event is one of the event types of this package.
+	Event interface {
+		event()
+	}
+
+	// RequestRetried is published before a failed request is sent again.
+	RequestRetried struct {
+		// Operation is the method and path of the request (see metrics.Operation).
+		Operation string
+		// Attempt is the number of the failed attempt, the retry is the next one.
+		Attempt int
+		// StatusCode is the status code of the failed attempt, it's 0 if no response was received.
+		StatusCode int
+		// Err is the error of the failed attempt if no response was received.
+		Err error
+		// Delay is the wait before the retry.
+		Delay time.Duration
+	}
+
+	// RateLimited is published when the API responds with 429 Too Many Requests.
+	RateLimited struct {
+		// Operation is the method and path of the request (see metrics.Operation).
+		Operation string
+		// RetryAfter is the wait requested by the API, it's 0 if it's not known.
+		RetryAfter time.Duration
+	}
+
+	// CircuitOpened is published when the circuit breaker opens and starts short-circuiting the requests.
+	CircuitOpened struct{}
+
+	// CircuitClosed is published when the circuit breaker closes after the probe requests succeeded.
+	CircuitClosed struct{}
+
+	// TokenRefreshed is published when a new OAuth access token is obtained.
+	TokenRefreshed struct {
+		// ExpiresAt is when the new token expires.
+		ExpiresAt time.Time
+	}
+
+	// Deprecated is published when a response announces the deprecation of its endpoint.
+	Deprecated struct {
+		// Operation is the method and path of the request (see metrics.Operation).
+		Operation string
+		// Deprecation is the deprecation announced by the headers of the response.
+		Deprecation result.Deprecation
+	}
+)
+
+func (RequestRetried) event() {}
+func (RateLimited) event()    {}
+func (CircuitOpened) event()  {}
+func (CircuitClosed) event()  {}
+func (TokenRefreshed) event() {}
+func (Deprecated) event()     {}
+
+// Bus delivers the published events to the subscribers in the order they subscribed. It's safe for concurrent
+// use and a nil Bus drops all events.
+type Bus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers []subscriber
+}
+
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// NewBus creates a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// SubscribeAll calls fn with all events published to the bus until the returned unsubscribe function is called.
+func (b *Bus) SubscribeAll(fn func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers = append(b.subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subscribers {
+			if s.id == id {
+				b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Subscribe calls fn with the events of type T published to the bus until the returned unsubscribe function is called.
+func Subscribe[T Event](b *Bus, fn func(T)) (unsubscribe func()) {
+	return b.SubscribeAll(func(e Event) {
+		if event, ok := e.(T); ok {
+			fn(event)
+		}
+	})
+}
+
+// Publish calls the subscribers with the event synchronously, so they should return quickly.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		s.fn(e)
+	}
+}
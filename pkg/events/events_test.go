@@ -0,0 +1,101 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type eventsTestSuite struct {
+	suite.Suite
+}
+
+func TestEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(eventsTestSuite))
+}
+
+func (s *eventsTestSuite) TestPublishCallsSubscribersInOrder() {
+	bus := NewBus()
+	var received []string
+	bus.SubscribeAll(func(e Event) { received = append(received, "all") })
+	Subscribe(bus, func(e RequestRetried) { received = append(received, e.Operation) })
+
+	bus.Publish(RequestRetried{Operation: "GET /v1/organisation/accounts/:id"})
+
+	s.Equal([]string{"all", "GET /v1/organisation/accounts/:id"}, received)
+}
+
+func (s *eventsTestSuite) TestSubscribeFiltersByType() {
+	bus := NewBus()
+	var opened, retried int
+	Subscribe(bus, func(CircuitOpened) { opened++ })
+	Subscribe(bus, func(RequestRetried) { retried++ })
+
+	bus.Publish(CircuitOpened{})
+	bus.Publish(CircuitClosed{})
+	bus.Publish(TokenRefreshed{ExpiresAt: time.Now()})
+
+	s.Equal(1, opened)
+	s.Zero(retried)
+}
+
+func (s *eventsTestSuite) TestUnsubscribe() {
+	bus := NewBus()
+	var first, second int
+	unsubscribe := Subscribe(bus, func(RateLimited) { first++ })
+	Subscribe(bus, func(RateLimited) { second++ })
+
+	bus.Publish(RateLimited{})
+	unsubscribe()
+	unsubscribe()
+	bus.Publish(RateLimited{})
+
+	s.Equal(1, first)
+	s.Equal(2, second)
+}
+
+func (s *eventsTestSuite) TestSubscriberCanUnsubscribeWhilePublishing() {
+	bus := NewBus()
+	calls := 0
+	var unsubscribe func()
+	unsubscribe = bus.SubscribeAll(func(Event) {
+		calls++
+		unsubscribe()
+	})
+
+	bus.Publish(CircuitOpened{})
+	bus.Publish(CircuitOpened{})
+
+	s.Equal(1, calls)
+}
+
+func (s *eventsTestSuite) TestNilBusDropsEvents() {
+	var bus *Bus
+
+	s.NotPanics(func() { bus.Publish(CircuitOpened{}) })
+}
+
+func (s *eventsTestSuite) TestConcurrentUse() {
+	bus := NewBus()
+	var mu sync.Mutex
+	calls := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unsubscribe := bus.SubscribeAll(func(Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+			})
+			bus.Publish(CircuitClosed{})
+			unsubscribe()
+		}()
+	}
+	wg.Wait()
+
+	s.Positive(calls)
+}
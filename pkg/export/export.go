@@ -0,0 +1,161 @@
+// Package export provides resumable exports of the resources which can be listed page by page.
+// The progress of an export is checkpointed to a pluggable store, so an interrupted export
+// continues from the last checkpoint instead of restarting from the first page.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"form3interview/pkg/clock"
+	"form3interview/pkg/logger"
+	re "form3interview/pkg/requestenricher"
+)
+
+const defaultPageSize = 100
+
+var (
+	// ErrMissingID export ID is required to checkpoint the export
+	ErrMissingID = errors.New("export ID is required")
+)
+
+// ListFunc fetches a page of resources. The List methods of the clients (i.e. mandate or direct debit) can be used as it is.
+type ListFunc[T any] func(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]T, error)
+
+// SinkFunc receives the exported resources page by page. The export stops when it returns an error.
+type SinkFunc[T any] func(page []T) error
+
+// Checkpoint is the progress of an export.
+type Checkpoint struct {
+	// ID identifies the export.
+	ID string `json:"id"`
+	// NextPage is the number of the first page which is not exported yet.
+	NextPage uint `json:"next_page"`
+	// Exported is the number of the exported resources.
+	Exported uint64 `json:"exported"`
+	// UpdatedAt is the time of the checkpoint.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Options configures an export.
+type Options struct {
+	// ID identifies the export in the checkpoint store. It must be stable between the runs of the same export.
+	ID string
+	// PageSize is the number of resources fetched at once which is 100 by default.
+	// It must not change between runs, otherwise the checkpointed page numbers point to different resources.
+	PageSize uint
+	// Store persists the checkpoints. Checkpointing is disabled when it is nil.
+	Store CheckpointStore
+	// CheckpointEvery is the number of pages exported between two checkpoints which is 1 by default.
+	CheckpointEvery uint
+	// Enricher is passed to the ListFunc. The export context is set as it's Ctx.
+	Enricher re.RequestEnricher
+	// Logger receives the progress of the export, the global zerolog logger is used if it's nil.
+	Logger logger.Logger
+	// Clock tells the time of the checkpoints, the system clock is used if it's nil.
+	Clock clock.Clock
+}
+
+func (o Options) printer() logger.Printer {
+	if o.Logger == nil {
+		return logger.Printer{Logger: logger.Zerolog()}
+	}
+	return logger.Printer{Logger: o.Logger}
+}
+
+// ExportAll lists all the resources page by page and passes them to the sink.
+// When a checkpoint store is given the export resumes from the stored checkpoint and saves a new one
+// after every CheckpointEvery pages. The checkpoint is deleted when the export completes.
+// Pages exported after the last checkpoint are exported again on resume, so the sink should be idempotent.
+//
+// The last checkpoint is returned in both successful and failed cases.
+func ExportAll[T any](ctx context.Context, list ListFunc[T], sink SinkFunc[T], opts Options) (Checkpoint, error) {
+	if opts.Store != nil && opts.ID == "" {
+		return Checkpoint{}, ErrMissingID
+	}
+	if opts.PageSize == 0 {
+		opts.PageSize = defaultPageSize
+	}
+	if opts.CheckpointEvery == 0 {
+		opts.CheckpointEvery = 1
+	}
+
+	cp, err := load(opts)
+	if err != nil {
+		return cp, err
+	}
+	if cp.NextPage > 0 {
+		opts.printer().Infof("resuming export %s from page %d", opts.ID, cp.NextPage)
+	}
+
+	en := opts.Enricher
+	en.Ctx = ctx
+	pagesSinceCheckpoint := uint(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return cp, save(opts, cp, pagesSinceCheckpoint, err)
+		}
+
+		page, err := list(cp.NextPage, opts.PageSize, en)
+		if err != nil {
+			return cp, save(opts, cp, pagesSinceCheckpoint, fmt.Errorf("list page %d: %w", cp.NextPage, err))
+		}
+
+		if len(page) > 0 {
+			if err := sink(page); err != nil {
+				return cp, save(opts, cp, pagesSinceCheckpoint, fmt.Errorf("sink page %d: %w", cp.NextPage, err))
+			}
+		}
+
+		cp.NextPage++
+		cp.Exported += uint64(len(page))
+		pagesSinceCheckpoint++
+
+		if uint(len(page)) < opts.PageSize {
+			break
+		}
+
+		if pagesSinceCheckpoint >= opts.CheckpointEvery {
+			if err := save(opts, cp, pagesSinceCheckpoint, nil); err != nil {
+				return cp, err
+			}
+			pagesSinceCheckpoint = 0
+		}
+	}
+
+	opts.printer().Debugf("export %s completed with %d resources", opts.ID, cp.Exported)
+	if opts.Store != nil {
+		return cp, opts.Store.Delete(opts.ID)
+	}
+	return cp, nil
+}
+
+func load(opts Options) (Checkpoint, error) {
+	if opts.Store == nil {
+		return Checkpoint{ID: opts.ID}, nil
+	}
+
+	cp, err := opts.Store.Load(opts.ID)
+	if errors.Is(err, ErrCheckpointNotFound) {
+		return Checkpoint{ID: opts.ID}, nil
+	}
+	return cp, err
+}
+
+// save stores the checkpoint when there is unsaved progress and returns the cause (if any) or the store error.
+func save(opts Options, cp Checkpoint, pagesSinceCheckpoint uint, cause error) error {
+	if opts.Store == nil || pagesSinceCheckpoint == 0 {
+		return cause
+	}
+
+	cp.UpdatedAt = clock.OrSystem(opts.Clock).Now()
+	if err := opts.Store.Save(cp); err != nil {
+		if cause != nil {
+			return fmt.Errorf("%w (checkpoint not saved: %s)", cause, err)
+		}
+		return err
+	}
+	return cause
+}
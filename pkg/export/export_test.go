@@ -0,0 +1,139 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock/clocktest"
+	re "form3interview/pkg/requestenricher"
+)
+
+type exportTestSuite struct {
+	suite.Suite
+}
+
+func TestExportTestSuite(t *testing.T) {
+	suite.Run(t, new(exportTestSuite))
+}
+
+// fakeList lists the numbers from 0 to total-1 and fails once when the page failAt is requested.
+type fakeList struct {
+	total     int
+	failAt    int
+	requested []uint
+}
+
+func (f *fakeList) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]int, error) {
+	f.requested = append(f.requested, pageNumber)
+	if int(pageNumber) == f.failAt {
+		f.failAt = -1
+		return nil, errors.New("list failed")
+	}
+
+	var page []int
+	for i := int(pageNumber * pageSize); i < f.total && len(page) < int(pageSize); i++ {
+		page = append(page, i)
+	}
+	return page, nil
+}
+
+func (s *exportTestSuite) TestExportAll() {
+	list := &fakeList{total: 7, failAt: -1}
+	var exported []int
+
+	cp, err := ExportAll(context.Background(), list.List, func(page []int) error {
+		exported = append(exported, page...)
+		return nil
+	}, Options{PageSize: 3})
+
+	s.Require().NoError(err)
+	s.Equal([]int{0, 1, 2, 3, 4, 5, 6}, exported)
+	s.Equal(uint64(7), cp.Exported)
+	s.Equal([]uint{0, 1, 2}, list.requested)
+}
+
+func (s *exportTestSuite) TestExportAllStopsOnEmptyPage() {
+	list := &fakeList{total: 4, failAt: -1}
+
+	cp, err := ExportAll(context.Background(), list.List, func([]int) error { return nil }, Options{PageSize: 2})
+
+	s.Require().NoError(err)
+	s.Equal(uint64(4), cp.Exported)
+	s.Equal([]uint{0, 1, 2}, list.requested)
+}
+
+func (s *exportTestSuite) TestExportAllResumesFromCheckpoint() {
+	store, err := NewFileStore(s.T().TempDir())
+	s.Require().NoError(err)
+	list := &fakeList{total: 10, failAt: 2}
+	var exported []int
+	sink := func(page []int) error {
+		exported = append(exported, page...)
+		return nil
+	}
+	opts := Options{ID: "accounts", PageSize: 2, Store: store}
+
+	cp, err := ExportAll(context.Background(), list.List, sink, opts)
+	s.Error(err)
+	s.Equal(uint(2), cp.NextPage)
+	stored, err := store.Load("accounts")
+	s.Require().NoError(err)
+	s.Equal(uint(2), stored.NextPage)
+	s.Equal(uint64(4), stored.Exported)
+
+	cp, err = ExportAll(context.Background(), list.List, sink, opts)
+	s.Require().NoError(err)
+	s.Equal(uint64(10), cp.Exported)
+	s.Equal([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, exported)
+	s.Equal([]uint{0, 1, 2, 2, 3, 4, 5}, list.requested)
+	_, err = store.Load("accounts")
+	s.ErrorIs(err, ErrCheckpointNotFound)
+}
+
+func (s *exportTestSuite) TestExportAllSavesProgress_WhenSinkFails() {
+	store := NewMemoryStore()
+	list := &fakeList{total: 10, failAt: -1}
+	pages := 0
+	sinkErr := errors.New("sink failed")
+	now := time.Date(2022, 10, 24, 1, 0, 0, 0, time.UTC)
+
+	_, err := ExportAll(context.Background(), list.List, func([]int) error {
+		pages++
+		if pages == 4 {
+			return sinkErr
+		}
+		return nil
+	}, Options{ID: "mandates", PageSize: 2, Store: store, CheckpointEvery: 2, Clock: clocktest.NewFake(now)})
+
+	s.ErrorIs(err, sinkErr)
+	stored, err := store.Load("mandates")
+	s.Require().NoError(err)
+	s.Equal(uint(3), stored.NextPage)
+	s.Equal(uint64(6), stored.Exported)
+	s.Equal(now, stored.UpdatedAt)
+}
+
+func (s *exportTestSuite) TestExportAllPassesContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	var actualCtx context.Context
+	list := func(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]int, error) {
+		actualCtx = en[0].Ctx
+		cancel()
+		return []int{1}, nil
+	}
+
+	_, err := ExportAll(ctx, list, func([]int) error { return nil }, Options{PageSize: 1})
+
+	s.ErrorIs(err, context.Canceled)
+	s.Equal(ctx, actualCtx)
+}
+
+func (s *exportTestSuite) TestExportAllReturnsError_WhenStoreWithoutID() {
+	_, err := ExportAll(context.Background(), (&fakeList{}).List, func([]int) error { return nil }, Options{Store: NewMemoryStore()})
+
+	s.ErrorIs(err, ErrMissingID)
+}
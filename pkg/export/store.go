@@ -0,0 +1,118 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrCheckpointNotFound no checkpoint is stored for the export
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// CheckpointStore persists the checkpoints of the exports.
+type CheckpointStore interface {
+	// Load returns the checkpoint of the export or ErrCheckpointNotFound if there is none.
+	Load(id string) (Checkpoint, error)
+	// Save stores the checkpoint replacing the previous one of the same export.
+	Save(cp Checkpoint) error
+	// Delete removes the checkpoint of the export. It doesn't fail when there is none.
+	Delete(id string) error
+}
+
+// MemoryStore keeps the checkpoints in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: map[string]Checkpoint{}}
+}
+
+func (m *MemoryStore) Load(id string) (Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp, ok := m.checkpoints[id]
+	if !ok {
+		return Checkpoint{}, ErrCheckpointNotFound
+	}
+	return cp, nil
+}
+
+func (m *MemoryStore) Save(cp Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checkpoints[cp.ID] = cp
+	return nil
+}
+
+func (m *MemoryStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.checkpoints, id)
+	return nil
+}
+
+// FileStore keeps the checkpoints as JSON files (named by the export ID) in a directory.
+// The files are replaced atomically so a crash during saving doesn't corrupt the previous checkpoint.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a store in the given directory. The directory is created if it doesn't exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (f *FileStore) Load(id string) (Checkpoint, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(f.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return cp, err
+	}
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return Checkpoint{}, err
+	}
+	return cp, nil
+}
+
+func (f *FileStore) Save(cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(f.dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(cp.ID))
+}
+
+func (f *FileStore) Delete(id string) error {
+	if err := os.Remove(f.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (f *FileStore) path(id string) string {
+	return filepath.Join(f.dir, filepath.Base(id)+".json")
+}
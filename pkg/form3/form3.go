@@ -0,0 +1,245 @@
+// Package form3 provides a single entry point to the Form3 resource clients.
+//
+// The clients created by New share one config and one transport (with its connection pool),
+// so the connection limits apply to all the resources together
+// (when an http client is given with config.WithHttpClient, that one is shared instead):
+//
+//	client, err := form3.New(config.WithBaseUrl("http://localhost:8080/v1"), config.WithOrganisationID(orgID))
+//	if err != nil {
+//		return err
+//	}
+//	acc, err := client.Accounts().Fetch(accountID)
+package form3
+
+import (
+	"net/http"
+
+	"form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/account"
+	"form3interview/pkg/audit"
+	"form3interview/pkg/bankid"
+	"form3interview/pkg/claim"
+	pconfig "form3interview/pkg/config"
+	"form3interview/pkg/directdebit"
+	"form3interview/pkg/events"
+	"form3interview/pkg/health"
+	"form3interview/pkg/limit"
+	"form3interview/pkg/mandate"
+	"form3interview/pkg/organisation"
+	"form3interview/pkg/payment"
+	"form3interview/pkg/report"
+	"form3interview/pkg/security"
+	"form3interview/pkg/subscription"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrCircuitOpen the request was not sent because the circuit breaker configured with config.WithCircuitBreaker is open
+	ErrCircuitOpen = resource.ErrCircuitOpen
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out, it wraps the original network or context error
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken, it wraps the original network error
+	ErrConnection = resource.ErrConnection
+	// ErrBulkheadFull the request was not sent because the resource client had too many concurrent requests
+	// (see config.WithBulkhead)
+	ErrBulkheadFull = resource.ErrBulkheadFull
+	// ErrRetryBudgetExhausted the failed request was not retried because the retry budget of the retry policy is exhausted
+	ErrRetryBudgetExhausted = resource.ErrRetryBudgetExhausted
+)
+
+// Client gives access to the Form3 resource clients.
+type Client struct {
+	accounts      *account.Client
+	audit         *audit.Client
+	bankIDs       *bankid.Client
+	claims        *claim.Client
+	directDebits  *directdebit.Client
+	health        *health.Client
+	limits        *limit.Client
+	mandates      *mandate.Client
+	organisations *organisation.Client
+	payments      *payment.Client
+	reports       *report.Client
+	security      *security.Client
+	subscriptions *subscription.Client
+	config        config.ClientConfig
+	// transport is the transport shared by the resource clients. It's nil when an http client is configured.
+	transport http.RoundTripper
+}
+
+// New creates the Form3 resource clients with a shared config and transport.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func New(options ...pconfig.Option) (*Client, error) {
+	cfg := config.NewConfig()
+	pconfig.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.HttpClient == nil {
+		cfg.SharedTransport = resource.NewTransport(cfg)
+	}
+	if cfg.Events == nil {
+		cfg.Events = events.NewBus()
+	}
+	return newClient(cfg)
+}
+
+func newClient(cfg config.ClientConfig) (*Client, error) {
+	// the resource clients parse the env vars too, so the whole config is replaced to keep them in sync
+	shared := func(c *config.ClientConfig) { *c = cfg }
+
+	c := Client{config: cfg, transport: cfg.SharedTransport}
+	var err error
+	if c.accounts, err = account.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.audit, err = audit.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.bankIDs, err = bankid.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.claims, err = claim.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.directDebits, err = directdebit.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.health, err = health.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.limits, err = limit.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.mandates, err = mandate.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.organisations, err = organisation.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.payments, err = payment.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.reports, err = report.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.security, err = security.NewClient(shared); err != nil {
+		return nil, err
+	}
+	if c.subscriptions, err = subscription.NewClient(shared); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Close closes the resource clients, stops the health watchers and closes the idle connections of the shared
+// transport, so a service can shut down cleanly. The in-flight requests are waited for, the further requests
+// fail with ErrClientClosed.
+func (c *Client) Close() error {
+	closers := []interface{ Close() error }{
+		c.accounts, c.audit, c.bankIDs, c.claims, c.directDebits, c.health, c.limits,
+		c.mandates, c.organisations, c.payments, c.reports, c.security, c.subscriptions,
+	}
+	var err error
+	for _, closer := range closers {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if idle, ok := c.transport.(interface{ CloseIdleConnections() }); ok {
+		idle.CloseIdleConnections()
+	}
+	return err
+}
+
+// Events returns the event bus the resource clients publish their lifecycle events to (see config.WithEventBus).
+func (c *Client) Events() *events.Bus {
+	return c.config.Events
+}
+
+// DumpConfig returns the effective config of the client with the secrets redacted,
+// so it can be logged while debugging connection issues.
+func (c *Client) DumpConfig() string {
+	return c.config.String()
+}
+
+// Accounts returns the client of the accounts.
+func (c *Client) Accounts() *account.Client {
+	return c.accounts
+}
+
+// Audit returns the client of the audit entries.
+func (c *Client) Audit() *audit.Client {
+	return c.audit
+}
+
+// BankIDs returns the client of the bank ID lookups.
+func (c *Client) BankIDs() *bankid.Client {
+	return c.bankIDs
+}
+
+// Claims returns the client of the direct debit claims.
+func (c *Client) Claims() *claim.Client {
+	return c.claims
+}
+
+// DirectDebits returns the client of the direct debits.
+func (c *Client) DirectDebits() *directdebit.Client {
+	return c.directDebits
+}
+
+// Health returns the client of the API health checks.
+func (c *Client) Health() *health.Client {
+	return c.health
+}
+
+// Limits returns the client of the limits.
+func (c *Client) Limits() *limit.Client {
+	return c.limits
+}
+
+// Mandates returns the client of the mandates.
+func (c *Client) Mandates() *mandate.Client {
+	return c.mandates
+}
+
+// Organisations returns the client of the organisation units.
+func (c *Client) Organisations() *organisation.Client {
+	return c.organisations
+}
+
+// Payments returns the client of the payments.
+func (c *Client) Payments() *payment.Client {
+	return c.payments
+}
+
+// Reports returns the client of the reports.
+func (c *Client) Reports() *report.Client {
+	return c.reports
+}
+
+// Security returns the client of the users and roles.
+func (c *Client) Security() *security.Client {
+	return c.security
+}
+
+// Subscriptions returns the client of the notification subscriptions.
+func (c *Client) Subscriptions() *subscription.Client {
+	return c.subscriptions
+}
@@ -0,0 +1,146 @@
+package form3
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/internal/config"
+	"form3interview/pkg/account"
+	pconfig "form3interview/pkg/config"
+	"form3interview/pkg/limit"
+	"form3interview/pkg/ratelimit"
+)
+
+type form3TestSuite struct {
+	suite.Suite
+}
+
+func TestForm3TestSuite(t *testing.T) {
+	suite.Run(t, new(form3TestSuite))
+}
+
+// recordingTransport records the requested paths and responds with 404 Not Found.
+type recordingTransport struct {
+	paths []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.paths = append(r.paths, req.URL.Path)
+	return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func (s *form3TestSuite) TestNewReturnsError_WhenNotConfigured() {
+	_, err := New(pconfig.WithBaseUrl(""))
+	s.ErrorIs(err, ErrBaseUrlNotConfigured)
+
+	_, err = New(pconfig.WithBaseUrl("http://localhost/v1"), pconfig.WithOrganisationID(uuid.Nil))
+	s.ErrorIs(err, ErrOrganisationIDNotConfigured)
+}
+
+func (s *form3TestSuite) TestNew() {
+	client, err := New(pconfig.WithBaseUrl("http://localhost/v1"), pconfig.WithOrganisationID(uuid.New()))
+
+	s.Require().NoError(err)
+	s.NotNil(client.Accounts())
+	s.NotNil(client.Audit())
+	s.NotNil(client.BankIDs())
+	s.NotNil(client.Claims())
+	s.NotNil(client.DirectDebits())
+	s.NotNil(client.Health())
+	s.NotNil(client.Limits())
+	s.NotNil(client.Mandates())
+	s.NotNil(client.Organisations())
+	s.NotNil(client.Payments())
+	s.NotNil(client.Reports())
+	s.NotNil(client.Security())
+	s.NotNil(client.Subscriptions())
+}
+
+func (s *form3TestSuite) TestClientsShareRateLimit() {
+	transport := &recordingTransport{}
+	baseUrl := "http://localhost/v1"
+	orgID := uuid.New()
+	timeout := time.Second
+	client, err := newClient(config.ClientConfig{
+		BaseUrl:         &baseUrl,
+		OrganisationID:  &orgID,
+		Timeout:         &timeout,
+		SharedTransport: transport,
+		RateLimiter:     ratelimit.New(20, 1),
+	})
+	s.Require().NoError(err)
+
+	start := time.Now()
+	_, err = client.Accounts().Fetch(uuid.New())
+	s.ErrorIs(err, account.ErrAccountNotFound)
+	_, err = client.Limits().Fetch(uuid.New())
+	s.ErrorIs(err, limit.ErrLimitNotFound)
+
+	s.GreaterOrEqual(time.Since(start), 40*time.Millisecond)
+	s.Len(transport.paths, 2)
+}
+
+func (s *form3TestSuite) TestClose() {
+	transport := &recordingTransport{}
+	baseUrl := "http://localhost/v1"
+	orgID := uuid.New()
+	timeout := time.Second
+	client, err := newClient(config.ClientConfig{
+		BaseUrl:         &baseUrl,
+		OrganisationID:  &orgID,
+		Timeout:         &timeout,
+		SharedTransport: transport,
+	})
+	s.Require().NoError(err)
+
+	s.Require().NoError(client.Close())
+
+	_, err = client.Accounts().Fetch(uuid.New())
+	s.ErrorIs(err, ErrClientClosed)
+	_, err = client.Limits().Fetch(uuid.New())
+	s.ErrorIs(err, ErrClientClosed)
+	s.Empty(transport.paths)
+}
+
+func (s *form3TestSuite) TestDumpConfig() {
+	orgID := uuid.New()
+	client, err := New(pconfig.WithBaseUrl("http://localhost/v1"), pconfig.WithOrganisationID(orgID))
+	s.Require().NoError(err)
+
+	dump := client.DumpConfig()
+
+	s.Contains(dump, "organisation_id: "+orgID.String()+"\n")
+	s.Contains(dump, "base_url: http://localhost/v1\n")
+}
+
+func (s *form3TestSuite) TestClientsShareTransport() {
+	transport := &recordingTransport{}
+	baseUrl := "http://localhost/v1"
+	orgID := uuid.New()
+	timeout, idleConnTimeout := time.Second, time.Second
+	client, err := newClient(config.ClientConfig{
+		BaseUrl:         &baseUrl,
+		OrganisationID:  &orgID,
+		Timeout:         &timeout,
+		IdleConnTimeout: &idleConnTimeout,
+		SharedTransport: transport,
+	})
+	s.Require().NoError(err)
+
+	accountID, limitID := uuid.New(), uuid.New()
+	_, err = client.Accounts().Fetch(accountID)
+	s.ErrorIs(err, account.ErrAccountNotFound)
+	_, err = client.Limits().Fetch(limitID)
+	s.ErrorIs(err, limit.ErrLimitNotFound)
+
+	s.Equal([]string{
+		"/v1/organisation/accounts/" + accountID.String(),
+		"/v1/limits/" + limitID.String(),
+	}, transport.paths)
+}
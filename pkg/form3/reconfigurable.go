@@ -0,0 +1,58 @@
+package form3
+
+import (
+	"sync/atomic"
+
+	pconfig "form3interview/pkg/config"
+)
+
+// ReconfigurableClient holds a Client which can be replaced at runtime (i.e. by a config file watcher)
+// to change the base url, the credentials or the timeouts without restarting the process:
+//
+//	rc, err := form3.NewReconfigurable(config.WithEnvironment(config.Sandbox), config.WithOrganisationID(orgID))
+//	...
+//	acc, err := rc.Current().Accounts().Fetch(accountID)
+//	...
+//	err = rc.Reconfigure(config.WithEnvironment(config.Production), config.WithOrganisationID(orgID))
+//
+// Reconfigure swaps the client atomically and closes the previous one in the background: the requests in flight
+// finish with it, then its health watchers are stopped, its idle connections are closed and its further requests
+// fail with ErrClientClosed.
+type ReconfigurableClient struct {
+	current atomic.Pointer[Client]
+}
+
+// NewReconfigurable creates a ReconfigurableClient with the Client created by New.
+func NewReconfigurable(options ...pconfig.Option) (*ReconfigurableClient, error) {
+	c, err := New(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var rc ReconfigurableClient
+	rc.current.Store(c)
+	return &rc, nil
+}
+
+// Current returns the current client. The callers should not keep it, so they pick up the new client
+// after Reconfigure.
+func (rc *ReconfigurableClient) Current() *Client {
+	return rc.current.Load()
+}
+
+// Reconfigure creates a new client with the options and replaces the current one with it.
+// The options replace the previous options (the env vars still apply). The current client is kept
+// if the new one can't be created. The new client keeps the event bus of the current one unless the options
+// configure another, so the subscriptions are kept.
+func (rc *ReconfigurableClient) Reconfigure(options ...pconfig.Option) error {
+	options = append([]pconfig.Option{pconfig.WithEventBus(rc.Current().Events())}, options...)
+	c, err := New(options...)
+	if err != nil {
+		return err
+	}
+
+	previous := rc.current.Swap(c)
+	// closing waits for the requests in flight, so it doesn't hold up the caller
+	go previous.Close()
+	return nil
+}
@@ -0,0 +1,87 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/clock/clocktest"
+	pconfig "form3interview/pkg/config"
+	"form3interview/pkg/health"
+)
+
+func (s *form3TestSuite) TestReconfigurableClient() {
+	var hosts []string
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})}
+	orgID := uuid.New()
+	rc, err := NewReconfigurable(pconfig.WithBaseUrl("http://sandbox/v1"), pconfig.WithOrganisationID(orgID), pconfig.WithHttpClient(httpClient))
+	s.Require().NoError(err)
+	previous := rc.Current()
+
+	err = rc.Reconfigure(pconfig.WithBaseUrl("http://production/v1"), pconfig.WithOrganisationID(orgID), pconfig.WithHttpClient(httpClient))
+	s.Require().NoError(err)
+
+	_, err = rc.Current().Accounts().Fetch(uuid.New())
+	s.ErrorIs(err, account.ErrAccountNotFound)
+	s.NotSame(previous, rc.Current())
+	s.Equal([]string{"production"}, hosts)
+	s.Eventually(func() bool {
+		_, err := previous.Accounts().Fetch(uuid.New())
+		return errors.Is(err, account.ErrClientClosed)
+	}, time.Second, time.Millisecond)
+}
+
+func (s *form3TestSuite) TestReconfigureStopsWatchersOfPreviousClient() {
+	fake := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"status":"up"}`))}, nil
+	})}
+	options := []pconfig.Option{pconfig.WithBaseUrl("http://sandbox/v1"), pconfig.WithOrganisationID(uuid.New()),
+		pconfig.WithHttpClient(httpClient), pconfig.WithClock(fake)}
+	rc, err := NewReconfigurable(options...)
+	s.Require().NoError(err)
+	rc.Current().Health().Watch(context.Background(), health.WatchOptions{Interval: time.Minute})
+	fake.BlockUntil(1)
+
+	s.Require().NoError(rc.Reconfigure(options...))
+
+	s.Eventually(func() bool { return fake.Waiters() == 0 }, time.Second, time.Millisecond)
+}
+
+func (s *form3TestSuite) TestReconfigureKeepsEventBus() {
+	rc, err := NewReconfigurable(pconfig.WithBaseUrl("http://sandbox/v1"), pconfig.WithOrganisationID(uuid.New()))
+	s.Require().NoError(err)
+	bus := rc.Current().Events()
+	s.Require().NotNil(bus)
+
+	err = rc.Reconfigure(pconfig.WithBaseUrl("http://production/v1"), pconfig.WithOrganisationID(uuid.New()))
+
+	s.Require().NoError(err)
+	s.Same(bus, rc.Current().Events())
+}
+
+func (s *form3TestSuite) TestReconfigureKeepsCurrentClient_WhenNotConfigured() {
+	rc, err := NewReconfigurable(pconfig.WithBaseUrl("http://localhost/v1"), pconfig.WithOrganisationID(uuid.New()))
+	s.Require().NoError(err)
+	current := rc.Current()
+
+	err = rc.Reconfigure(pconfig.WithBaseUrl(""))
+
+	s.ErrorIs(err, ErrBaseUrlNotConfigured)
+	s.Same(current, rc.Current())
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
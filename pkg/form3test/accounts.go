@@ -0,0 +1,83 @@
+package form3test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// accountPatterns are the patterns of the account attributes validated by the API.
+var accountPatterns = []struct {
+	field   string
+	pattern *regexp.Regexp
+}{
+	{field: "country", pattern: regexp.MustCompile(`^[A-Z]{2}$`)},
+	{field: "base_currency", pattern: regexp.MustCompile(`^[A-Z]{3}$`)},
+	{field: "bank_id", pattern: regexp.MustCompile(`^[A-Z0-9]{0,16}$`)},
+	{field: "bank_id_code", pattern: regexp.MustCompile(`^[A-Z]{0,16}$`)},
+	{field: "bic", pattern: regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)},
+	{field: "account_number", pattern: regexp.MustCompile(`^[A-Z0-9]{0,64}$`)},
+	{field: "iban", pattern: regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$`)},
+}
+
+// accountEnums are the allowed values of the account attributes validated by the API.
+var accountEnums = []struct {
+	field  string
+	values []string
+}{
+	{field: "account_classification", values: []string{"Personal", "Business"}},
+	{field: "status", values: []string{"pending", "confirmed", "failed"}},
+}
+
+// validateAccount validates the attributes of an account like the API does.
+func validateAccount(data map[string]any) []string {
+	attributes, ok := data["attributes"].(map[string]any)
+	if !ok {
+		return []string{"attributes in body is required"}
+	}
+
+	var failures []string
+	if _, ok := attributes["country"]; !ok {
+		failures = append(failures, "country in body is required")
+	}
+	names, _ := attributes["name"].([]any)
+	switch {
+	case len(names) == 0:
+		failures = append(failures, "name in body is required")
+	case len(names) > 4:
+		failures = append(failures, "name in body should have at most 4 items")
+	}
+
+	for _, p := range accountPatterns {
+		value, ok := attributes[p.field]
+		if !ok {
+			continue
+		}
+		s, ok := value.(string)
+		switch {
+		case !ok:
+			failures = append(failures, fmt.Sprintf("%s in body must be of type string: %q", p.field, fmt.Sprint(value)))
+		case !p.pattern.MatchString(s):
+			failures = append(failures, fmt.Sprintf("%s in body should match '%s'", p.field, p.pattern))
+		}
+	}
+	for _, e := range accountEnums {
+		value, ok := attributes[e.field]
+		if !ok {
+			continue
+		}
+		if s, _ := value.(string); !contains(e.values, s) {
+			failures = append(failures, fmt.Sprintf("%s in body should be one of [%s]", e.field, strings.Join(e.values, " ")))
+		}
+	}
+	return failures
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
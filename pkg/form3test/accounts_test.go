@@ -0,0 +1,55 @@
+package form3test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type accountsTestSuite struct {
+	suite.Suite
+}
+
+func TestAccountsTestSuite(t *testing.T) {
+	suite.Run(t, new(accountsTestSuite))
+}
+
+func (s *accountsTestSuite) TestValidateAccount() {
+	for _, test := range []struct {
+		name       string
+		attributes map[string]any
+		expected   []string
+	}{
+		{
+			name:       "valid",
+			attributes: map[string]any{"country": "GB", "name": []any{"Jane Doe"}, "bic": "NWBKGB22", "account_classification": "Personal"},
+		},
+		{
+			name:       "missing required",
+			attributes: map[string]any{},
+			expected:   []string{"country in body is required", "name in body is required"},
+		},
+		{
+			name:       "too many names",
+			attributes: map[string]any{"country": "GB", "name": []any{"a", "b", "c", "d", "e"}},
+			expected:   []string{"name in body should have at most 4 items"},
+		},
+		{
+			name:       "invalid values",
+			attributes: map[string]any{"country": "gb", "name": []any{"Jane Doe"}, "base_currency": 826, "status": "closed"},
+			expected: []string{
+				"country in body should match '^[A-Z]{2}$'",
+				`base_currency in body must be of type string: "826"`,
+				"status in body should be one of [pending confirmed failed]",
+			},
+		},
+	} {
+		s.Run(test.name, func() {
+			s.Equal(test.expected, validateAccount(map[string]any{"attributes": test.attributes}))
+		})
+	}
+}
+
+func (s *accountsTestSuite) TestValidateAccountRequiresAttributes() {
+	s.Equal([]string{"attributes in body is required"}, validateAccount(map[string]any{}))
+}
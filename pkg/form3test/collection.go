@@ -0,0 +1,262 @@
+package form3test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// collection is an in-memory resource collection with the JSON:API endpoints of the API. The server
+// synchronizes the access to it.
+type collection struct {
+	path         string
+	resourceType string
+	validate     func(data map[string]any) []string
+	resources    map[string]map[string]any
+	// ids are the IDs of the resources in the order they were created.
+	ids []string
+}
+
+func newCollection(path, resourceType string, validate func(data map[string]any) []string) *collection {
+	return &collection{
+		path:         path,
+		resourceType: resourceType,
+		validate:     validate,
+		resources:    map[string]map[string]any{},
+	}
+}
+
+func (c *collection) all() []json.RawMessage {
+	all := make([]json.RawMessage, 0, len(c.ids))
+	for _, id := range c.ids {
+		data, _ := json.Marshal(c.resources[id])
+		all = append(all, data)
+	}
+	return all
+}
+
+func (c *collection) put(raw json.RawMessage) error {
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	id, _ := data["id"].(string)
+	if id == "" {
+		return errors.New("id is missing")
+	}
+	if _, ok := data["version"]; !ok {
+		data["version"] = 0
+	}
+	c.store(id, data)
+	return nil
+}
+
+func (c *collection) store(id string, data map[string]any) {
+	if _, ok := c.resources[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.resources[id] = data
+}
+
+func (c *collection) reset() {
+	c.resources = map[string]map[string]any{}
+	c.ids = nil
+}
+
+func (c *collection) serveCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		c.list(w, r)
+	case http.MethodPost:
+		c.create(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", r.Method))
+	}
+}
+
+func (c *collection) serveResource(w http.ResponseWriter, r *http.Request, id string) {
+	if !isUUID(id) {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		c.fetch(w, id)
+	case http.MethodDelete:
+		c.delete(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", r.Method))
+	}
+}
+
+func (c *collection) create(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+	if failures := c.validateData(body.Data); len(failures) > 0 {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(failures, "\n"))
+		return
+	}
+
+	data := body.Data
+	id := data["id"].(string)
+	if _, ok := c.resources[id]; ok {
+		writeError(w, http.StatusConflict, fmt.Sprintf("%s cannot be created as it violates a duplicate constraint", c.resourceType))
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	data["version"] = 0
+	data["created_on"] = now
+	data["modified_on"] = now
+	c.store(id, data)
+	writeJSON(w, http.StatusCreated, c.document(data))
+}
+
+func (c *collection) fetch(w http.ResponseWriter, id string) {
+	data, ok := c.resources[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, c.document(data))
+}
+
+func (c *collection) delete(w http.ResponseWriter, r *http.Request, id string) {
+	version, err := strconv.ParseInt(r.URL.Query().Get("version"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	data, ok := c.resources[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if versionOf(data) != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	delete(c.resources, id)
+	for i, stored := range c.ids {
+		if stored == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *collection) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	number, err := pageParam(query.Get("page[number]"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "page[number] in query must be of type integer")
+		return
+	}
+	size, err := pageParam(query.Get("page[size]"), defaultPageSize)
+	if err != nil || size == 0 || size > maxPageSize {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("page[size] in query should be between 1 and %d", maxPageSize))
+		return
+	}
+
+	data := []map[string]any{}
+	for i := number * size; i < len(c.ids) && i < (number+1)*size; i++ {
+		data = append(data, c.resources[c.ids[i]])
+	}
+	last := 0
+	if len(c.ids) > 0 {
+		last = (len(c.ids) - 1) / size
+	}
+	links := map[string]string{
+		"self":  c.pageLink(number, size),
+		"first": c.pageLink(0, size),
+		"last":  c.pageLink(last, size),
+	}
+	if number < last {
+		links["next"] = c.pageLink(number+1, size)
+	}
+	if number > 0 {
+		links["prev"] = c.pageLink(number-1, size)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": data, "links": links})
+}
+
+// validateData validates the envelope fields of the resource and then calls the validate function of the collection.
+func (c *collection) validateData(data map[string]any) []string {
+	if data == nil {
+		return []string{"data in body is required"}
+	}
+
+	var failures []string
+	for _, field := range []string{"id", "organisation_id"} {
+		value, ok := data[field].(string)
+		switch {
+		case !ok || value == "":
+			failures = append(failures, field+" in body is required")
+		case !isUUID(value):
+			failures = append(failures, fmt.Sprintf("%s in body must be of type uuid: %q", field, value))
+		}
+	}
+	if resourceType, _ := data["type"].(string); resourceType != c.resourceType {
+		failures = append(failures, fmt.Sprintf("type in body should be one of [%s]", c.resourceType))
+	}
+	if c.validate != nil {
+		failures = append(failures, c.validate(data)...)
+	}
+	return failures
+}
+
+func (c *collection) document(data map[string]any) map[string]any {
+	return map[string]any{
+		"data":  data,
+		"links": map[string]string{"self": fmt.Sprintf("%s%s/%s", APIVersion, c.path, data["id"])},
+	}
+}
+
+func (c *collection) pageLink(number, size int) string {
+	return fmt.Sprintf("%s%s?page[number]=%d&page[size]=%d", APIVersion, c.path, number, size)
+}
+
+func pageParam(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errors.New("invalid page parameter")
+	}
+	return n, nil
+}
+
+// versionOf returns the version of the resource, which is a float64 when it was decoded from JSON.
+func versionOf(data map[string]any) int64 {
+	switch v := data["version"].(type) {
+	case float64:
+		return int64(v)
+	case int:
+		return int64(v)
+	}
+	return 0
+}
+
+func isUUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil
+}
@@ -0,0 +1,143 @@
+package form3test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+const unitsPath = "/organisation/units"
+
+type collectionTestSuite struct {
+	suite.Suite
+	server *Server
+}
+
+func TestCollectionTestSuite(t *testing.T) {
+	suite.Run(t, new(collectionTestSuite))
+}
+
+func (s *collectionTestSuite) SetupTest() {
+	s.server = NewServer()
+	s.server.HandleCollection(unitsPath, "units", nil)
+}
+
+func (s *collectionTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *collectionTestSuite) send(method, path, body string) (int, map[string]any) {
+	req, err := http.NewRequest(method, s.server.BaseURL()+path, strings.NewReader(body))
+	s.Require().NoError(err)
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	var decoded map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+func (s *collectionTestSuite) create(id string) {
+	status, _ := s.send(http.MethodPost, unitsPath, fmt.Sprintf(`{"data":{"id":%q,"organisation_id":%q,"type":"units"}}`, id, OrganisationID))
+	s.Require().Equal(http.StatusCreated, status)
+}
+
+func (s *collectionTestSuite) TestCreateAndFetch() {
+	id := uuid.NewString()
+	s.create(id)
+
+	status, body := s.send(http.MethodGet, unitsPath+"/"+id, "")
+
+	s.Equal(http.StatusOK, status)
+	data := body["data"].(map[string]any)
+	s.Equal(id, data["id"])
+	s.Equal(float64(0), data["version"])
+	s.NotEmpty(data["created_on"])
+	s.Equal(map[string]any{"self": "/v1/organisation/units/" + id}, body["links"])
+}
+
+func (s *collectionTestSuite) TestCreateValidatesEnvelope() {
+	status, body := s.send(http.MethodPost, unitsPath, `{"data":{"id":"1","type":"accounts"}}`)
+
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal("validation failure list:\n"+
+		"id in body must be of type uuid: \"1\"\n"+
+		"organisation_id in body is required\n"+
+		"type in body should be one of [units]", body["error_message"])
+
+	status, body = s.send(http.MethodPost, unitsPath, `{"data":`)
+	s.Equal(http.StatusBadRequest, status)
+	s.Contains(body["error_message"], "invalid request body")
+}
+
+func (s *collectionTestSuite) TestList() {
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, uuid.NewString())
+		s.create(ids[i])
+	}
+
+	status, body := s.send(http.MethodGet, unitsPath+"?page[number]=1&page[size]=2", "")
+
+	s.Equal(http.StatusOK, status)
+	data := body["data"].([]any)
+	s.Require().Len(data, 1)
+	s.Equal(ids[2], data[0].(map[string]any)["id"])
+	s.Equal(map[string]any{
+		"self":  "/v1/organisation/units?page[number]=1&page[size]=2",
+		"first": "/v1/organisation/units?page[number]=0&page[size]=2",
+		"last":  "/v1/organisation/units?page[number]=1&page[size]=2",
+		"prev":  "/v1/organisation/units?page[number]=0&page[size]=2",
+	}, body["links"])
+
+	status, body = s.send(http.MethodGet, unitsPath+"?page[number]=5", "")
+	s.Equal(http.StatusOK, status)
+	s.Empty(body["data"])
+
+	status, _ = s.send(http.MethodGet, unitsPath+"?page[size]=0", "")
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *collectionTestSuite) TestDelete() {
+	id := uuid.NewString()
+	s.create(id)
+
+	for _, test := range []struct {
+		path     string
+		expected int
+	}{
+		{path: unitsPath + "/" + id, expected: http.StatusBadRequest},
+		{path: unitsPath + "/" + id + "?version=1", expected: http.StatusConflict},
+		{path: unitsPath + "/" + id + "?version=0", expected: http.StatusNoContent},
+		{path: unitsPath + "/" + id + "?version=0", expected: http.StatusNotFound},
+		{path: unitsPath + "/1?version=0", expected: http.StatusBadRequest},
+	} {
+		status, _ := s.send(http.MethodDelete, test.path, "")
+		s.Equal(test.expected, status, test.path)
+	}
+	s.Empty(s.server.Resources(unitsPath))
+}
+
+func (s *collectionTestSuite) TestMethodNotAllowed() {
+	status, _ := s.send(http.MethodPut, unitsPath, "")
+	s.Equal(http.StatusMethodNotAllowed, status)
+
+	status, _ = s.send(http.MethodPatch, unitsPath+"/"+uuid.NewString(), "")
+	s.Equal(http.StatusMethodNotAllowed, status)
+}
+
+func (s *collectionTestSuite) TestPutResource() {
+	id := uuid.NewString()
+
+	s.server.PutResource(unitsPath, json.RawMessage(fmt.Sprintf(`{"id":%q,"version":3}`, id)))
+
+	s.Equal([]json.RawMessage{json.RawMessage(fmt.Sprintf(`{"id":%q,"version":3}`, id))}, s.server.Resources(unitsPath))
+	s.Panics(func() { s.server.PutResource(unitsPath, json.RawMessage(`{}`)) })
+	s.Panics(func() { s.server.PutResource("/organisation/other", json.RawMessage(`{"id":"1"}`)) })
+}
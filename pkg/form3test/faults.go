@@ -0,0 +1,167 @@
+package form3test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault is a failure injected into the responses of the fake, so the retry, timeout and circuit breaker
+// configurations can be tested deterministically. The faults are created with ServerErrors, Latency,
+// DropConnections, MalformedJSON and RateLimit and injected with Server.Inject:
+//
+//	server.Inject(form3test.ServerErrors(2).On(http.MethodPost, form3test.AccountsPath))
+type Fault struct {
+	method, path string
+	// times is the number of the requests the fault is applied to, it's applied to all of them if it's 0.
+	times      int
+	status     int
+	retryAfter time.Duration
+	latency    time.Duration
+	drop       bool
+	malformed  bool
+}
+
+// ServerErrors responds to the next n requests with 500 Internal Server Error.
+func ServerErrors(n int) Fault {
+	return Status(n, http.StatusInternalServerError)
+}
+
+// Status responds to the next n requests with the status code and the error payload of the API.
+func Status(n, status int) Fault {
+	return Fault{times: n, status: status}
+}
+
+// Latency delays the responses of the next n requests by d. The requests are delayed until they are cancelled
+// by the client.
+func Latency(n int, d time.Duration) Fault {
+	return Fault{times: n, latency: d}
+}
+
+// DropConnections closes the connections of the next n requests without responding.
+func DropConnections(n int) Fault {
+	return Fault{times: n, drop: true}
+}
+
+// MalformedJSON cuts the response bodies of the next n requests in half, so they can't be decoded.
+func MalformedJSON(n int) Fault {
+	return Fault{times: n, malformed: true}
+}
+
+// RateLimit responds to the next n requests with 429 Too Many Requests and the Retry-After header if retryAfter
+// is positive.
+func RateLimit(n int, retryAfter time.Duration) Fault {
+	return Fault{times: n, status: http.StatusTooManyRequests, retryAfter: retryAfter}
+}
+
+// Always returns the fault applied to all the requests until the faults are cleared.
+func (f Fault) Always() Fault {
+	f.times = 0
+	return f
+}
+
+// On returns the fault applied only to the requests with the method and the path (without the version, i.e.
+// AccountsPath). The path matches the resources of a collection too. Empty method or path matches all requests.
+func (f Fault) On(method, path string) Fault {
+	f.method, f.path = method, strings.TrimRight(path, "/")
+	return f
+}
+
+func (f Fault) matches(r *http.Request) bool {
+	if f.method != "" && f.method != r.Method {
+		return false
+	}
+	path := strings.TrimPrefix(r.URL.Path, APIVersion)
+	return f.path == "" || path == f.path || strings.HasPrefix(path, f.path+"/")
+}
+
+// Inject adds the faults. The first fault matching a request is applied to it, so the faults are applied in the
+// order they were injected.
+func (s *Server) Inject(faults ...Fault) {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+	s.faults = append(s.faults, faults...)
+}
+
+// ClearFaults removes the faults not applied yet.
+func (s *Server) ClearFaults() {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the number of the requests received by the fake, including the ones failed by the faults.
+func (s *Server) Requests() int {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+	return s.requests
+}
+
+// nextFault counts the request and returns the fault to apply to it, if there is any.
+func (s *Server) nextFault(r *http.Request) (Fault, bool) {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+	s.requests++
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+		switch {
+		case f.times == 1:
+			s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+		case f.times > 1:
+			s.faults[i].times--
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// serveFault applies the fault to the request. It returns false if the request should be served normally.
+func (s *Server) serveFault(w http.ResponseWriter, r *http.Request, f Fault) bool {
+	if f.latency > 0 {
+		select {
+		case <-time.After(f.latency):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	switch {
+	case f.drop:
+		dropConnection(w)
+	case f.status != 0:
+		if f.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.retryAfter.Round(time.Second)/time.Second)))
+		}
+		writeError(w, f.status, fmt.Sprintf("injected %d %s", f.status, http.StatusText(f.status)))
+	case f.malformed:
+		rec := httptest.NewRecorder()
+		s.serve(rec, r)
+		for name, values := range rec.Header() {
+			w.Header()[name] = values
+		}
+		body := rec.Body.Bytes()
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(body[:len(body)/2])
+	default:
+		return false
+	}
+	return true
+}
+
+// dropConnection closes the connection of the request without responding.
+func dropConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	conn.Close()
+}
@@ -0,0 +1,144 @@
+package form3test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/circuitbreaker"
+	"form3interview/pkg/config"
+	"form3interview/pkg/retry"
+)
+
+type faultsTestSuite struct {
+	suite.Suite
+	server *Server
+	id     uuid.UUID
+}
+
+func TestFaultsTestSuite(t *testing.T) {
+	suite.Run(t, new(faultsTestSuite))
+}
+
+func (s *faultsTestSuite) SetupTest() {
+	s.server = NewServer()
+	s.id = uuid.New()
+	s.server.PutAccount(account.AccountData{ID: s.id.String(), OrganisationID: OrganisationID.String()})
+}
+
+func (s *faultsTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *faultsTestSuite) client(opts ...config.Option) *account.Client {
+	opts = append([]config.Option{config.WithRetry(retry.Disabled())}, opts...)
+	client, err := account.NewClient(append(s.server.Options(), opts...)...)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func (s *faultsTestSuite) TestStatusIsRetried() {
+	client := s.client(config.WithRetry(retry.Policy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}))
+	s.server.Inject(Status(2, http.StatusServiceUnavailable))
+
+	_, err := client.Fetch(s.id)
+
+	s.NoError(err)
+	s.Equal(3, s.server.Requests())
+}
+
+func (s *faultsTestSuite) TestServerErrors() {
+	client := s.client()
+	s.server.Inject(ServerErrors(1))
+
+	_, err := client.Fetch(s.id)
+	s.ErrorIs(err, account.ErrServerError)
+
+	_, err = client.Fetch(s.id)
+	s.NoError(err)
+	s.Equal(2, s.server.Requests())
+}
+
+func (s *faultsTestSuite) TestRateLimit() {
+	s.server.Inject(RateLimit(2, 2*time.Second))
+
+	resp, err := http.Get(s.server.BaseURL() + AccountsPath)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("2", resp.Header.Get("Retry-After"))
+
+	_, err = s.client().Fetch(s.id)
+	s.ErrorIs(err, account.ErrRateLimited)
+}
+
+func (s *faultsTestSuite) TestDropConnections() {
+	s.server.Inject(DropConnections(1))
+
+	_, err := s.client().Fetch(s.id)
+
+	s.ErrorIs(err, account.ErrConnection)
+}
+
+func (s *faultsTestSuite) TestMalformedJSON() {
+	s.server.Inject(MalformedJSON(1))
+
+	_, err := s.client().Fetch(s.id)
+
+	s.ErrorIs(err, account.ErrUnexpectedServerResponse)
+}
+
+func (s *faultsTestSuite) TestLatency() {
+	client := s.client(config.WithTimeout(50 * time.Millisecond))
+	s.server.Inject(Latency(1, time.Second))
+
+	_, err := client.Fetch(s.id)
+	s.ErrorIs(err, account.ErrTimeout)
+
+	_, err = client.Fetch(s.id)
+	s.NoError(err)
+}
+
+func (s *faultsTestSuite) TestOn() {
+	client := s.client()
+	s.server.Inject(ServerErrors(1).On(http.MethodDelete, AccountsPath))
+
+	_, err := client.Fetch(s.id)
+	s.NoError(err)
+
+	s.ErrorIs(client.Delete(s.id), account.ErrServerError)
+	s.NoError(client.Delete(s.id))
+}
+
+func (s *faultsTestSuite) TestAlwaysOpensCircuitBreaker() {
+	client := s.client(config.WithCircuitBreaker(circuitbreaker.Settings{FailureThreshold: 2, OpenDuration: time.Minute}))
+	s.server.Inject(ServerErrors(1).Always())
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Fetch(s.id)
+		s.ErrorIs(err, account.ErrServerError)
+	}
+	_, err := client.Fetch(s.id)
+
+	s.ErrorIs(err, circuitbreaker.ErrCircuitOpen)
+	s.Equal(2, s.server.Requests())
+}
+
+func (s *faultsTestSuite) TestClearFaults() {
+	client := s.client()
+	s.server.Inject(ServerErrors(1).Always(), DropConnections(1))
+
+	s.server.ClearFaults()
+
+	_, err := client.Fetch(s.id)
+	s.NoError(err)
+}
@@ -0,0 +1,67 @@
+package form3test
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"form3interview/pkg/account"
+)
+
+// golden are the attributes of the valid accounts per country, they are the source of NewValidAccount.
+//
+//go:embed golden/account_*.json
+var golden embed.FS
+
+// NewValidAccount returns the attributes of a personal account valid in the country (GB, FR, DE, ES, IT, NL, BE
+// or IE) with a consistent bank ID, account number, IBAN and BIC. Every call returns a new copy, so the tests can
+// modify it. It panics if there is no fixture for the country (see Countries).
+func NewValidAccount(country string) account.AccountAttributes {
+	var attributes account.AccountAttributes
+	if err := json.Unmarshal(ValidAccountJSON(country), &attributes); err != nil {
+		panic(fmt.Sprintf("form3test: invalid account fixture of %s: %s", country, err))
+	}
+	return attributes
+}
+
+// ValidAccountJSON returns the golden JSON of the attributes returned by NewValidAccount. It panics if there is
+// no fixture for the country.
+func ValidAccountJSON(country string) json.RawMessage {
+	data, err := golden.ReadFile(goldenAccountFile(country))
+	if err != nil {
+		panic(fmt.Sprintf("form3test: no account fixture for country %s", country))
+	}
+	return data
+}
+
+// Countries returns the countries with valid account fixtures in alphabetical order.
+func Countries() []string {
+	files, _ := fs.Glob(golden, goldenAccountFile("*"))
+	countries := make([]string, 0, len(files))
+	for _, file := range files {
+		country := strings.TrimSuffix(strings.TrimPrefix(file, "golden/account_"), ".json")
+		countries = append(countries, strings.ToUpper(country))
+	}
+	sort.Strings(countries)
+	return countries
+}
+
+// SequentialUUIDs returns a UUID generator for config.WithUUIDGenerator which generates the same IDs in every test
+// run: 00000000-0000-4000-8000-000000000001, 00000000-0000-4000-8000-000000000002 and so on. It's safe for
+// concurrent use, but the order of the IDs depends on the order of the calls then.
+func SequentialUUIDs() func() (uuid.UUID, error) {
+	var n uint64
+	return func() (uuid.UUID, error) {
+		return uuid.Parse(fmt.Sprintf("00000000-0000-4000-8000-%012x", atomic.AddUint64(&n, 1)))
+	}
+}
+
+func goldenAccountFile(country string) string {
+	return "golden/account_" + strings.ToLower(country) + ".json"
+}
@@ -0,0 +1,119 @@
+package form3test
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/config"
+)
+
+type fixturesTestSuite struct {
+	suite.Suite
+}
+
+func TestFixturesTestSuite(t *testing.T) {
+	suite.Run(t, new(fixturesTestSuite))
+}
+
+// validIban checks the mod 97 checksum of the IBAN.
+func validIban(iban string) bool {
+	rearranged := iban[4:] + iban[:4]
+	var digits strings.Builder
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(big.NewInt(int64(r - 'A' + 10)).String())
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func (s *fixturesTestSuite) TestNewValidAccount() {
+	s.Equal([]string{"BE", "DE", "ES", "FR", "GB", "IE", "IT", "NL"}, Countries())
+
+	for _, country := range Countries() {
+		s.Run(country, func() {
+			attributes := NewValidAccount(country)
+
+			s.Equal(country, *attributes.Country)
+			s.True(strings.HasPrefix(attributes.Iban, country), attributes.Iban)
+			s.True(validIban(attributes.Iban), attributes.Iban)
+			s.Contains(attributes.Iban, attributes.AccountNumber)
+			s.Equal(country, attributes.Bic[4:6])
+
+			var data map[string]any
+			s.Require().NoError(json.Unmarshal(ValidAccountJSON(country), &data))
+			s.Empty(validateAccount(map[string]any{"attributes": data}))
+		})
+	}
+}
+
+func (s *fixturesTestSuite) TestNewValidAccountReturnsCopies() {
+	attributes := NewValidAccount("gb")
+	*attributes.AccountClassification = "Business"
+	attributes.Name[0] = "John Doe"
+
+	s.Equal("Personal", *NewValidAccount("GB").AccountClassification)
+	s.Equal([]string{"Jane Doe"}, NewValidAccount("GB").Name)
+}
+
+func (s *fixturesTestSuite) TestGoldenJSONMatchesFixture() {
+	for _, country := range Countries() {
+		data, err := json.Marshal(NewValidAccount(country))
+		s.Require().NoError(err)
+		s.JSONEq(string(ValidAccountJSON(country)), string(data), country)
+	}
+}
+
+func (s *fixturesTestSuite) TestNewValidAccountPanics_WhenCountryIsNotSupported() {
+	s.PanicsWithValue("form3test: no account fixture for country XX", func() { NewValidAccount("XX") })
+	s.Panics(func() { ValidAccountJSON("../golden/account_gb") })
+}
+
+func (s *fixturesTestSuite) TestValidAccountsCanBeCreated() {
+	server := NewServer()
+	defer server.Close()
+	client, err := account.NewClient(server.Options()...)
+	s.Require().NoError(err)
+	defer client.Close()
+
+	for _, country := range Countries() {
+		created, err := client.Create(NewValidAccount(country))
+		s.Require().NoError(err, country)
+		s.Equal(NewValidAccount(country), *created.Attributes)
+	}
+}
+
+func (s *fixturesTestSuite) TestSequentialUUIDs() {
+	generate := SequentialUUIDs()
+
+	first, err := generate()
+	s.Require().NoError(err)
+	second, err := generate()
+	s.Require().NoError(err)
+
+	s.Equal("00000000-0000-4000-8000-000000000001", first.String())
+	s.Equal("00000000-0000-4000-8000-000000000002", second.String())
+	s.Equal(uuid.Version(4), first.Version())
+}
+
+func (s *fixturesTestSuite) TestSequentialUUIDsAreUsedForNewAccounts() {
+	server := NewServer()
+	defer server.Close()
+	client, err := account.NewClient(append(server.Options(), config.WithUUIDGenerator(SequentialUUIDs()))...)
+	s.Require().NoError(err)
+	defer client.Close()
+
+	created, err := client.Create(NewValidAccount("GB"))
+
+	s.Require().NoError(err)
+	s.Equal("00000000-0000-4000-8000-000000000001", created.ID)
+}
@@ -0,0 +1,210 @@
+// Package form3test provides an in-process fake of the Form3 API, so the consumers of the SDK can run their
+// integration tests without Docker or the sandbox. The fake keeps the resources in memory, versions them and
+// responds with the status codes and error payloads of the API:
+//
+//	server := form3test.NewServer()
+//	defer server.Close()
+//	client, err := account.NewClient(server.Options()...)
+//
+// The accounts are served by default, other resource collections can be added with HandleCollection. Failures
+// can be injected into the responses with Inject.
+package form3test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/config"
+	"form3interview/pkg/result"
+)
+
+const (
+	// APIVersion is the version prefix of the paths served by the fake.
+	APIVersion = "/v1"
+	// AccountsPath is the path of the accounts collection.
+	AccountsPath = "/organisation/accounts"
+	// HealthPath is the path of the health endpoint.
+	HealthPath = "/health"
+)
+
+// OrganisationID is the organisation ID set by Options.
+var OrganisationID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Server is the fake Form3 API. It's safe for concurrent use.
+type Server struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	collections map[string]*collection
+
+	faultsMu sync.Mutex
+	faults   []Fault
+	requests int
+}
+
+// NewServer starts a fake Form3 API serving the accounts. It should be closed when the test is done.
+func NewServer() *Server {
+	s := &Server{collections: map[string]*collection{}}
+	s.HandleCollection(AccountsPath, "accounts", validateAccount)
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// BaseURL returns the base URL of the API including the version, i.e. http://127.0.0.1:41234/v1.
+func (s *Server) BaseURL() string {
+	return s.server.URL + APIVersion
+}
+
+// Options returns the config options connecting the clients to the fake.
+func (s *Server) Options() []config.Option {
+	return []config.Option{
+		config.WithBaseUrl(s.BaseURL()),
+		config.WithOrganisationID(OrganisationID),
+	}
+}
+
+// Close shuts down the fake and blocks until all its requests are done.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// HandleCollection serves a resource collection on the path (without the version, i.e. /organisation/accounts)
+// with resources of the type. The resources are validated with validate if it's not nil, it returns the
+// validation failures in the "<field> in body <failure>" format of the API.
+func (s *Server) HandleCollection(path, resourceType string, validate func(data map[string]any) []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections[strings.TrimRight(path, "/")] = newCollection(path, resourceType, validate)
+}
+
+// Accounts returns the stored accounts in the order they were created.
+func (s *Server) Accounts() []account.AccountData {
+	var accounts []account.AccountData
+	for _, data := range s.Resources(AccountsPath) {
+		var acc account.AccountData
+		if err := json.Unmarshal(data, &acc); err == nil {
+			accounts = append(accounts, acc)
+		}
+	}
+	return accounts
+}
+
+// PutAccount stores the account as is, so the tests can start with existing accounts. The version is set to 0
+// if it's not set.
+func (s *Server) PutAccount(acc account.AccountData) {
+	if acc.Type == "" {
+		acc.Type = "accounts"
+	}
+	data, err := json.Marshal(acc)
+	if err != nil {
+		panic(fmt.Sprintf("form3test: invalid account: %s", err))
+	}
+	s.PutResource(AccountsPath, data)
+}
+
+// Resources returns the JSON of the resources of the collection on the path in the order they were created.
+func (s *Server) Resources(path string) []json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.collections[path]
+	if !ok {
+		return nil
+	}
+	return c.all()
+}
+
+// PutResource stores the JSON of the resource in the collection on the path as is. It panics if the collection
+// is not handled or the JSON is not an object with an id.
+func (s *Server) PutResource(path string, data json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.collections[path]
+	if !ok {
+		panic(fmt.Sprintf("form3test: collection %s is not handled", path))
+	}
+	if err := c.put(data); err != nil {
+		panic(fmt.Sprintf("form3test: invalid resource: %s", err))
+	}
+}
+
+// Reset deletes all the stored resources.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.collections {
+		c.reset()
+	}
+}
+
+// ServeHTTP serves the requests of the fake API applying the injected faults.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(result.RequestIDHeader, uuid.NewString())
+	if f, ok := s.nextFault(r); ok && s.serveFault(w, r, f) {
+		return
+	}
+	s.serve(w, r)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, APIVersion) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s is not found", r.URL.Path))
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, APIVersion)
+	if path == HealthPath {
+		serveHealth(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, collectionPath := range s.paths() {
+		c := s.collections[collectionPath]
+		if path == collectionPath {
+			c.serveCollection(w, r)
+			return
+		}
+		if id := strings.TrimPrefix(path, collectionPath+"/"); id != path && !strings.Contains(id, "/") {
+			c.serveResource(w, r, id)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("%s is not found", r.URL.Path))
+}
+
+// paths returns the paths of the collections, the longest first so the nested collections are matched first.
+func (s *Server) paths() []string {
+	paths := make([]string, 0, len(s.collections))
+	for path := range s.collections {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return len(paths[i]) > len(paths[j]) })
+	return paths
+}
+
+func serveHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", r.Method))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "up"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error payload of the API.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error_message": message})
+}
@@ -0,0 +1,120 @@
+package form3test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/config"
+	"form3interview/pkg/health"
+	"form3interview/pkg/result"
+)
+
+type form3TestTestSuite struct {
+	suite.Suite
+	server *Server
+	client *account.Client
+}
+
+func TestForm3TestTestSuite(t *testing.T) {
+	suite.Run(t, new(form3TestTestSuite))
+}
+
+func (s *form3TestTestSuite) SetupTest() {
+	s.server = NewServer()
+	var err error
+	s.client, err = account.NewClient(s.server.Options()...)
+	s.Require().NoError(err)
+}
+
+func (s *form3TestTestSuite) TearDownTest() {
+	s.client.Close()
+	s.server.Close()
+}
+
+func (s *form3TestTestSuite) attributes() account.AccountAttributes {
+	return NewValidAccount("GB")
+}
+
+func (s *form3TestTestSuite) TestCreateFetchAndDeleteAccount() {
+	created, err := s.client.Create(s.attributes())
+	s.Require().NoError(err)
+	s.Equal(OrganisationID.String(), created.OrganisationID)
+	s.Equal(int64(0), *created.Version)
+
+	fetched, err := s.client.Fetch(uuid.MustParse(created.ID))
+	s.Require().NoError(err)
+	s.Equal(created.ID, fetched.ID)
+	s.Equal(s.attributes(), *fetched.Attributes)
+	s.Len(s.server.Accounts(), 1)
+
+	s.ErrorIs(s.client.DeleteVersion(uuid.MustParse(created.ID), 1), account.ErrInvalidAccountVersion)
+	s.NoError(s.client.Delete(uuid.MustParse(created.ID)))
+
+	_, err = s.client.Fetch(uuid.MustParse(created.ID))
+	s.ErrorIs(err, account.ErrAccountNotFound)
+	s.ErrorIs(s.client.DeleteVersion(uuid.MustParse(created.ID), 0), account.ErrAccountNotFound)
+	s.Empty(s.server.Accounts())
+}
+
+func (s *form3TestTestSuite) TestCreateReturnsValidationErrors() {
+	attributes := s.attributes()
+	attributes.Bic = "invalid"
+	attributes.Name = nil
+
+	_, err := s.client.Create(attributes)
+
+	s.ErrorIs(err, account.ErrInvalidRequest)
+	s.Equal([]result.ValidationError{
+		{Field: "name", Rule: result.RuleRequired, Message: "name in body is required"},
+		{Field: "bic", Rule: result.RulePattern, Message: "bic in body should match '^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$'"},
+	}, result.ValidationErrors(err))
+	s.Equal(result.CodeInvalidBIC, result.CodeOf(err))
+	s.Empty(s.server.Accounts())
+}
+
+func (s *form3TestTestSuite) TestCreateReturnsConflict_WhenAccountExists() {
+	id := uuid.New()
+	s.server.PutAccount(account.AccountData{ID: id.String(), OrganisationID: OrganisationID.String()})
+	client, err := account.NewClient(append(s.server.Options(), config.WithUUIDGenerator(func() (uuid.UUID, error) { return id, nil }))...)
+	s.Require().NoError(err)
+	defer client.Close()
+
+	_, err = client.Create(s.attributes())
+
+	s.ErrorIs(err, account.ErrAccountAlreadyExists)
+	s.Equal(result.CodeConflict, result.CodeOf(err))
+}
+
+func (s *form3TestTestSuite) TestReset() {
+	s.server.PutAccount(account.AccountData{ID: uuid.NewString()})
+
+	s.server.Reset()
+
+	s.Empty(s.server.Accounts())
+}
+
+func (s *form3TestTestSuite) TestHealth() {
+	client, err := health.NewClient(s.server.Options()...)
+	s.Require().NoError(err)
+
+	status, err := client.HealthCheck(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal(health.StateUp, status.State)
+}
+
+func (s *form3TestTestSuite) TestServeHTTPReturnsNotFound_WhenPathIsNotHandled() {
+	for _, path := range []string{"/organisation/accounts", "/v1/organisation/units", "/v1/organisation/accounts/1/2"} {
+		resp, err := http.Get(s.server.server.URL + path)
+		s.Require().NoError(err)
+		resp.Body.Close()
+
+		s.Equal(http.StatusNotFound, resp.StatusCode, path)
+		s.NotEmpty(resp.Header.Get(result.RequestIDHeader))
+	}
+}
@@ -0,0 +1,143 @@
+// Package health provides a client to check the availability of the Form3 API.
+// See https://www.api-docs.form3.tech/api/health
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+)
+
+const (
+	healthUrl = "/health"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrUnhealthy the API reported that it's down or returned an error status
+	ErrUnhealthy = errors.New("api is unhealthy")
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+// State of the API.
+type State string
+
+const (
+	StateUp   State = "up"
+	StateDown State = "down"
+)
+
+// Status is the result of a health check.
+type Status struct {
+	State State
+	// StatusCode of the health endpoint. It is 0 when the request failed.
+	StatusCode int
+	// Latency of the health check request.
+	Latency   time.Duration
+	CheckedAt time.Time
+}
+
+// Up tells if the API is available.
+func (s Status) Up() bool {
+	return s.State == StateUp
+}
+
+type (
+	httpClient interface {
+		Do(*http.Request, ...re.RequestEnricher) (*http.Response, error)
+	}
+	healthClient struct {
+		client   httpClient
+		config   conf.ClientConfig
+		watchers *watchers
+	}
+	// healthResponse is a simple container for the health endpoint response.
+	healthResponse struct {
+		Status string `json:"status,omitempty"`
+	}
+)
+
+// Client is the client of the Form3 health endpoint created by NewClient or the form3 facade.
+type Client = healthClient
+
+// NewClient creates a client for checking the health of the Form3 API.
+// The health endpoint doesn't belong to an organisation so only the base url has to be configured.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*healthClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if cfg.BaseUrl == nil || *cfg.BaseUrl == "" {
+		return nil, ErrBaseUrlNotConfigured
+	}
+	if err := resource.CheckMiddleware(cfg); err != nil {
+		return nil, err
+	}
+
+	return &healthClient{
+		client:   resource.NewHttpClient(cfg),
+		config:   cfg,
+		watchers: &watchers{},
+	}, nil
+}
+
+// Close stops the watchers of the client, waits for its in-flight requests, closes its idle connections and
+// fails the further requests with ErrClientClosed.
+func (h healthClient) Close() error {
+	h.watchers.stopAll()
+	return resource.Close(h.client)
+}
+
+// HealthCheck calls the health endpoint of the API.
+// The returned error is nil only when the API is up. It wraps ErrUnhealthy when the API responded but it's not up,
+// otherwise it's the error of the request.
+func (h healthClient) HealthCheck(ctx context.Context) (Status, error) {
+	clk := h.config.ClockOrSystem()
+	status := Status{State: StateDown, CheckedAt: clk.Now()}
+
+	req, err := http.NewRequest(http.MethodGet, h.config.Url(healthUrl), nil)
+	if err != nil {
+		return status, err
+	}
+
+	resp, err := h.client.Do(req, re.RequestEnricher{Ctx: ctx})
+	status.Latency = clk.Now().Sub(status.CheckedAt)
+	if err != nil {
+		return status, err
+	}
+	defer resp.Body.Close()
+
+	status.StatusCode = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		return status, fmt.Errorf("%w: health endpoint returned %d", ErrUnhealthy, resp.StatusCode)
+	}
+
+	var hr healthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&hr); err != nil && !errors.Is(err, io.EOF) {
+		return status, result.Wrap(ErrUnhealthy, err)
+	}
+	// an empty body is accepted as up because the status code already tells it
+	if hr.Status != "" && !strings.EqualFold(hr.Status, string(StateUp)) {
+		return status, fmt.Errorf("%w: status is %s", ErrUnhealthy, hr.Status)
+	}
+
+	status.State = StateUp
+	return status, nil
+}
@@ -0,0 +1,269 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"form3interview/pkg/clock/clocktest"
+	pconfig "form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/retry"
+)
+
+const (
+	Do            = "Do"
+	testBaseUrl   = "testhost"
+	testHealthUrl = testBaseUrl + healthUrl
+)
+
+type healthTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	healthClient   healthClient
+}
+
+func TestHealthTestSuite(t *testing.T) {
+	suite.Run(t, new(healthTestSuite))
+}
+
+func (s *healthTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	baseUrl := testBaseUrl
+	s.healthClient = healthClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{BaseUrl: &baseUrl},
+	}
+}
+
+func (s *healthTestSuite) TestHealthCheck() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedState  State
+		expectedError  error
+	}{
+		{
+			name:           "up",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"status\":\"up\"}",
+			expectedState:  StateUp,
+		},
+		{
+			name:           "up without body",
+			responseStatus: http.StatusOK,
+			expectedState:  StateUp,
+		},
+		{
+			name:           "reported down",
+			responseStatus: http.StatusOK,
+			responseBody:   "{\"status\":\"down\"}",
+			expectedState:  StateDown,
+			expectedError:  ErrUnhealthy,
+		},
+		{
+			name:           "invalid body",
+			responseStatus: http.StatusOK,
+			responseBody:   "up",
+			expectedState:  StateDown,
+			expectedError:  ErrUnhealthy,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedState:  StateDown,
+			expectedError:  ErrUnhealthy,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testHealthUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			status, err := s.healthClient.HealthCheck(context.Background())
+
+			if test.expectedError == nil {
+				s.NoError(err)
+			} else {
+				s.ErrorIs(err, test.expectedError)
+			}
+			s.Equal(test.expectedState, status.State)
+			s.Equal(test.responseStatus, status.StatusCode)
+			s.False(status.CheckedAt.IsZero())
+		})
+	}
+}
+
+func (s *healthTestSuite) TestHealthCheckReturnsHttpClientError() {
+	expectedError := errors.New("connection refused")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testHealthUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	status, err := s.healthClient.HealthCheck(context.Background())
+
+	s.ErrorIs(err, expectedError)
+	s.False(status.Up())
+	s.Zero(status.StatusCode)
+}
+
+func (s *healthTestSuite) TestHealthCheckPassesContext() {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	s.mockHttpClient.
+		On(Do, mock.Anything, mock.MatchedBy(func(en []re.RequestEnricher) bool {
+			return len(en) == 1 && en[0].Ctx == ctx
+		})).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, err := s.healthClient.HealthCheck(ctx)
+
+	s.NoError(err)
+}
+
+func (s *healthTestSuite) TestWatcher() {
+	fake := &fakeHealth{}
+	fake.up.Store(true)
+	s.healthClient.client = fake
+	changes := make(chan Status, 10)
+
+	w := s.healthClient.Watch(context.Background(), WatchOptions{
+		Interval: time.Millisecond,
+		OnChange: func(status Status) { changes <- status },
+	})
+	defer w.Stop()
+
+	s.True((<-changes).Up())
+	s.True(w.Available())
+
+	fake.up.Store(false)
+	s.False((<-changes).Up())
+	s.False(w.Available())
+	s.Equal(http.StatusServiceUnavailable, w.Status().StatusCode)
+}
+
+func (s *healthTestSuite) TestWatcherChecksOnConfiguredClock() {
+	start := time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC)
+	clock := clocktest.NewFake(start)
+	s.healthClient.config.Clock = clock
+	fake := &fakeHealth{}
+	fake.up.Store(true)
+	s.healthClient.client = fake
+	changes := make(chan Status, 10)
+
+	w := s.healthClient.Watch(context.Background(), WatchOptions{
+		Interval: time.Minute,
+		OnChange: func(status Status) { changes <- status },
+	})
+	defer w.Stop()
+
+	s.Equal(start, (<-changes).CheckedAt)
+	fake.up.Store(false)
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	status := <-changes
+	s.False(status.Up())
+	s.Equal(start.Add(time.Minute), status.CheckedAt)
+	s.Zero(status.Latency)
+}
+
+func (s *healthTestSuite) TestWatcherStopsWithContext() {
+	s.healthClient.client = &fakeHealth{}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := s.healthClient.Watch(ctx, WatchOptions{Interval: time.Millisecond})
+	cancel()
+
+	select {
+	case <-w.done:
+	case <-time.After(time.Second):
+		s.Fail("watcher did not stop")
+	}
+	s.False(w.Available())
+}
+
+func (s *healthTestSuite) TestCloseStopsWatchers() {
+	s.healthClient.client = &fakeHealth{}
+	s.healthClient.watchers = &watchers{}
+	w := s.healthClient.Watch(context.Background(), WatchOptions{Interval: time.Millisecond})
+
+	s.Require().NoError(s.healthClient.Close())
+
+	select {
+	case <-w.done:
+	default:
+		s.Fail("watcher did not stop")
+	}
+	s.healthClient.watchers.mu.Lock()
+	defer s.healthClient.watchers.mu.Unlock()
+	s.Empty(s.healthClient.watchers.running)
+}
+
+func (s *healthTestSuite) TestHealthCheckIsNotServedFromStaleCache() {
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"up"}`))
+	}))
+	defer server.Close()
+	client, err := NewClient(
+		pconfig.WithBaseUrl(server.URL),
+		pconfig.WithStaleReads(time.Minute),
+		pconfig.WithRetry(retry.Disabled()),
+	)
+	s.Require().NoError(err)
+	defer client.Close()
+
+	status, err := client.HealthCheck(context.Background())
+	s.Require().NoError(err)
+	s.True(status.Up())
+	down.Store(true)
+	status, err = client.HealthCheck(context.Background())
+
+	s.ErrorIs(err, ErrUnhealthy)
+	s.False(status.Up())
+	s.Equal(http.StatusServiceUnavailable, status.StatusCode)
+}
+
+type ctxKey struct{}
+
+// fakeHealth responds with 200 OK or 503 Service Unavailable depending on the up flag.
+type fakeHealth struct {
+	up atomic.Bool
+}
+
+func (f *fakeHealth) Do(*http.Request, ...re.RequestEnricher) (*http.Response, error) {
+	if f.up.Load() {
+		return &http.Response{StatusCode: http.StatusOK, Body: toResponseBody("")}, nil
+	}
+	return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: toResponseBody("")}, nil
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
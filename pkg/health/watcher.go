@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Watcher checks the health of the API periodically in the background.
+type Watcher struct {
+	client    healthClient
+	interval  time.Duration
+	onChange  func(Status)
+	available atomic.Bool
+	mu        sync.RWMutex
+	last      Status
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// watchers are the running watchers of a client, they are stopped when the client is closed.
+type watchers struct {
+	mu      sync.Mutex
+	running map[*Watcher]struct{}
+}
+
+func (ws *watchers) add(w *Watcher) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.running == nil {
+		ws.running = map[*Watcher]struct{}{}
+	}
+	ws.running[w] = struct{}{}
+}
+
+func (ws *watchers) remove(w *Watcher) {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	delete(ws.running, w)
+}
+
+func (ws *watchers) stopAll() {
+	if ws == nil {
+		return
+	}
+	ws.mu.Lock()
+	running := make([]*Watcher, 0, len(ws.running))
+	for w := range ws.running {
+		running = append(running, w)
+	}
+	ws.mu.Unlock()
+
+	for _, w := range running {
+		w.Stop()
+	}
+}
+
+// WatchOptions configures a Watcher.
+type WatchOptions struct {
+	// Interval between the health checks. The poll interval of the config is used if it's zero.
+	Interval time.Duration
+	// OnChange is called after the first health check and when the availability of the API changes. It is optional.
+	OnChange func(Status)
+}
+
+// Watch starts checking the health of the API until the context is cancelled, the watcher is stopped or
+// the client is closed.
+// The first check is done right away, until it's finished the API is reported as not available.
+func (h healthClient) Watch(ctx context.Context, opts WatchOptions) *Watcher {
+	if opts.Interval <= 0 && h.config.PollInterval != nil {
+		opts.Interval = *h.config.PollInterval
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		client:   h,
+		interval: opts.Interval,
+		onChange: opts.OnChange,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	h.watchers.add(w)
+	go w.run(ctx, h.watchers)
+	return w
+}
+
+// Available tells if the API was up at the last health check.
+func (w *Watcher) Available() bool {
+	return w.available.Load()
+}
+
+// Status returns the result of the last health check.
+func (w *Watcher) Status() Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.last
+}
+
+// Stop stops the health checks and waits for the running one to finish.
+func (w *Watcher) Stop() {
+	w.cancel()
+	<-w.done
+}
+
+func (w *Watcher) run(ctx context.Context, ws *watchers) {
+	defer close(w.done)
+	defer ws.remove(w)
+
+	ticker := w.client.config.ClockOrSystem().NewTicker(w.interval)
+	defer ticker.Stop()
+
+	first := true
+	for {
+		w.check(ctx, first)
+		first = false
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+func (w *Watcher) check(ctx context.Context, first bool) {
+	status, _ := w.client.HealthCheck(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.last = status
+	w.mu.Unlock()
+
+	changed := w.available.Swap(status.Up()) != status.Up()
+	if (changed || first) && w.onChange != nil {
+		w.onChange(status)
+	}
+}
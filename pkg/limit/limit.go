@@ -0,0 +1,144 @@
+// Package limit provides Form3 client to manage payment scheme and participation limits.
+// See https://www.api-docs.form3.tech/api/limits
+package limit
+
+import (
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	limitsUrl  = "/limits"
+	limitsType = "limits"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrLimitNotFound limit not found
+	ErrLimitNotFound = errors.New("limit not found")
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type limitClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 limits created by NewClient or the form3 facade.
+type Client = limitClient
+
+// NewClient creates a client for managing Form3 limits.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*limitClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &limitClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (l limitClient) Close() error {
+	return resource.Close(l.client)
+}
+
+// Create a limit with attributes.
+// See https://www.api-docs.form3.tech/api/limits/create-a-limit
+//
+// The request can be enriched by RequestEnricher
+func (l limitClient) Create(attributes LimitAttributes, en ...re.RequestEnricher) (*LimitData, error) {
+	newID, err := l.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := LimitData{
+		ID:             newID.String(),
+		OrganisationID: l.config.OrganisationID.String(),
+		Type:           limitsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := l.limits().Create(newID, limit, en...)
+	if err != nil {
+		return nil, err
+	}
+	l.config.Log().Debugf("limit %s created", limit.ID)
+	return created, nil
+}
+
+// Fetch a limit by it's ID.
+// See https://www.api-docs.form3.tech/api/limits/fetch-a-limit
+//
+// The request can be enriched by RequestEnricher
+func (l limitClient) Fetch(limitID uuid.UUID, en ...re.RequestEnricher) (*LimitData, error) {
+	return l.limits().Fetch(limitID, en...)
+}
+
+// List limits page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/limits/list-limits
+//
+// The request can be enriched by RequestEnricher
+func (l limitClient) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]LimitData, error) {
+	return l.limits().List(pageNumber, pageSize, en...)
+}
+
+func (l limitClient) bodyToLimitData(body io.Reader) (*LimitData, error) {
+	return resource.Client[LimitData]{Config: l.config}.BodyToData(body)
+}
+
+// limits returns the generic client of the limits collection.
+func (l limitClient) limits() resource.Client[LimitData] {
+	return resource.Client[LimitData]{
+		HTTP:        l.client,
+		Config:      l.config,
+		Url:         limitsUrl,
+		ErrNotFound: ErrLimitNotFound,
+	}
+}
@@ -0,0 +1,206 @@
+package limit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testLimitsUrl      = testBaseUrl + limitsUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type limitTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	limitClient    limitClient
+}
+
+func TestLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(limitTestSuite))
+}
+
+func (s *limitTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.limitClient = limitClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *limitTestSuite) TestCreateReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"amount is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server gateway timeout",
+			responseStatus: http.StatusGatewayTimeout,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testLimitsUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.limitClient.Create(LimitAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *limitTestSuite) TestCreateReturnsHttpClientError() {
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testLimitsUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.limitClient.Create(LimitAttributes{})
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *limitTestSuite) TestCreateLimit() {
+	limitID := uuid.New()
+	s.limitClient.config.UUIDGenerator = func() (uuid.UUID, error) { return limitID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testLimitsUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.limitClient.Create(LimitAttributes{
+		Amount:              "100000.00",
+		Currency:            "GBP",
+		Scheme:              SchemeFPS,
+		SettlementCycleType: SettlementCyclePerScheme,
+		Type:                TypeSending,
+	})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.limitClient.bodyToLimitData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(limitID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(limitsType, requested.Type)
+	s.Equal("100000.00", requested.Attributes.Amount)
+	s.Equal(SchemeFPS, requested.Attributes.Scheme)
+	s.Equal(TypeSending, requested.Attributes.Type)
+}
+
+func (s *limitTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.limitClient.Fetch(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *limitTestSuite) TestFetchReturnsError_WhenLimitNotFound() {
+	limitID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testLimitsUrl, limitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.limitClient.Fetch(limitID)
+
+	s.ErrorIs(actualError, ErrLimitNotFound)
+}
+
+func (s *limitTestSuite) TestFetchReturnsError_WhenResponseCannotBeDecoded() {
+	limitID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testLimitsUrl, limitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody("{\"data\":")}, nil).
+		Once()
+
+	_, actualError := s.limitClient.Fetch(limitID)
+
+	s.ErrorIs(actualError, ErrUnexpectedServerResponse)
+	s.ErrorIs(actualError, io.ErrUnexpectedEOF)
+}
+
+func (s *limitTestSuite) TestFetchLimit() {
+	limitID := uuid.New()
+	body, err := json.Marshal(limitContainer{Data: LimitData{ID: limitID.String()}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testLimitsUrl, limitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.limitClient.Fetch(limitID)
+	s.NoError(err)
+	s.Equal(limitID.String(), actual.ID)
+}
+
+func (s *limitTestSuite) TestListLimits() {
+	firstID, secondID := uuid.New(), uuid.New()
+	body, err := json.Marshal(limitListContainer{Data: []LimitData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testLimitsUrl+"?page[number]=1&page[size]=2")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.limitClient.List(1, 2)
+	s.NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
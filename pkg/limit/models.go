@@ -0,0 +1,59 @@
+package limit
+
+import "form3interview/internal/resource"
+
+// limitContainer is a simple container for the "data" JSON field.
+type limitContainer = resource.DataContainer[LimitData]
+
+// limitListContainer is a simple container for the "data" JSON field of list responses.
+type limitListContainer = resource.ListContainer[LimitData]
+
+// LimitData represents a payment or participation limit of a scheme.
+// See https://www.api-docs.form3.tech/api/limits for
+// more information about fields.
+type LimitData struct {
+	Attributes     *LimitAttributes `json:"attributes,omitempty"`
+	ID             string           `json:"id,omitempty"`
+	OrganisationID string           `json:"organisation_id,omitempty"`
+	Type           string           `json:"type,omitempty"`
+	Version        *int64           `json:"version,omitempty"`
+}
+
+type LimitAttributes struct {
+	Amount              string          `json:"amount,omitempty"`
+	Currency            string          `json:"currency,omitempty"`
+	Gateway             string          `json:"gateway,omitempty"`
+	Scheme              Scheme          `json:"scheme,omitempty"`
+	SettlementCycleType SettlementCycle `json:"settlement_cycle_type,omitempty"`
+	Type                Type            `json:"type,omitempty"`
+}
+
+// Scheme is the payment scheme the limit applies to.
+type Scheme string
+
+// Payment schemes with limits.
+const (
+	SchemeFPS   Scheme = "FPS"
+	SchemeBacs  Scheme = "Bacs"
+	SchemeSEPA  Scheme = "SEPA"
+	SchemeChaps Scheme = "CHAPS"
+)
+
+// SettlementCycle tells how often the limit is reset.
+type SettlementCycle string
+
+// Settlement cycles of a limit.
+const (
+	SettlementCyclePerScheme SettlementCycle = "per_scheme"
+	SettlementCycleDaily     SettlementCycle = "daily"
+)
+
+// Type tells if the limit applies to a single payment or to the participation in the scheme.
+type Type string
+
+// Types of a limit.
+const (
+	TypeSending       Type = "sending"
+	TypeReceiving     Type = "receiving"
+	TypeParticipation Type = "participation"
+)
@@ -0,0 +1,131 @@
+// Package logger provides the logging interface of the Form3 clients, so library consumers control where
+// and whether the clients log. The clients log with the global zerolog logger by default.
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Level is the severity of a log entry.
+type Level int8
+
+// Log levels. LevelDisabled disables the logging when it's used as a minimum level.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelDisabled
+)
+
+var levelNames = map[Level]string{
+	LevelDebug:    "debug",
+	LevelInfo:     "info",
+	LevelWarn:     "warn",
+	LevelError:    "error",
+	LevelDisabled: "disabled",
+}
+
+// Field is a key-value pair of a structured log entry.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Logger receives the log entries of the clients.
+type Logger interface {
+	Log(level Level, msg string, fields ...Field)
+}
+
+// Func is an adapter to use a function as a Logger.
+type Func func(level Level, msg string, fields ...Field)
+
+// Log calls f.
+func (f Func) Log(level Level, msg string, fields ...Field) {
+	f(level, msg, fields...)
+}
+
+// Zerolog returns a Logger which logs with the global zerolog logger.
+func Zerolog() Logger {
+	return Func(func(level Level, msg string, fields ...Field) {
+		event := log.WithLevel(zerologLevels[level])
+		for _, f := range fields {
+			event = event.Str(f.Key, f.Value)
+		}
+		event.Msg(msg)
+	})
+}
+
+var zerologLevels = map[Level]zerolog.Level{
+	LevelDebug:    zerolog.DebugLevel,
+	LevelInfo:     zerolog.InfoLevel,
+	LevelWarn:     zerolog.WarnLevel,
+	LevelError:    zerolog.ErrorLevel,
+	LevelDisabled: zerolog.Disabled,
+}
+
+// Nop returns a Logger which drops all the log entries.
+func Nop() Logger {
+	return Func(func(Level, string, ...Field) {})
+}
+
+// WithMinLevel returns a Logger which drops the log entries below the minimum level.
+func WithMinLevel(l Logger, min Level) Logger {
+	if min <= LevelDebug {
+		return l
+	}
+	return Func(func(level Level, msg string, fields ...Field) {
+		if level >= min {
+			l.Log(level, msg, fields...)
+		}
+	})
+}
+
+// String returns the name of the level.
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", l)
+}
+
+// UnmarshalText parses the name of the level (i.e. warn), so it can be set by an env var.
+func (l *Level) UnmarshalText(text []byte) error {
+	name := strings.ToLower(strings.TrimSpace(string(text)))
+	for level, levelName := range levelNames {
+		if levelName == name {
+			*l = level
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown log level %q", text)
+}
+
+// Printer formats the messages of a Logger.
+type Printer struct {
+	Logger Logger
+}
+
+// Debugf logs a formatted debug message.
+func (p Printer) Debugf(format string, args ...interface{}) {
+	p.Logger.Log(LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Infof logs a formatted info message.
+func (p Printer) Infof(format string, args ...interface{}) {
+	p.Logger.Log(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a formatted warning message.
+func (p Printer) Warnf(format string, args ...interface{}) {
+	p.Logger.Log(LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a formatted error message.
+func (p Printer) Errorf(format string, args ...interface{}) {
+	p.Logger.Log(LevelError, fmt.Sprintf(format, args...))
+}
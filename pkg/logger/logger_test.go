@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type entry struct {
+	level  Level
+	msg    string
+	fields []Field
+}
+
+type loggerTestSuite struct {
+	suite.Suite
+	entries []entry
+	logger  Logger
+}
+
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(loggerTestSuite))
+}
+
+func (s *loggerTestSuite) SetupTest() {
+	s.entries = nil
+	s.logger = Func(func(level Level, msg string, fields ...Field) {
+		s.entries = append(s.entries, entry{level: level, msg: msg, fields: fields})
+	})
+}
+
+func (s *loggerTestSuite) TestPrinter() {
+	p := Printer{Logger: s.logger}
+
+	p.Debugf("account %s created", "id-1")
+	p.Infof("info")
+	p.Warnf("warn")
+	p.Errorf("%s: [%d]", "server error", 500)
+
+	s.Equal([]entry{
+		{level: LevelDebug, msg: "account id-1 created"},
+		{level: LevelInfo, msg: "info"},
+		{level: LevelWarn, msg: "warn"},
+		{level: LevelError, msg: "server error: [500]"},
+	}, s.entries)
+}
+
+func (s *loggerTestSuite) TestWithMinLevel() {
+	for _, test := range []struct {
+		min            Level
+		expectedLevels []Level
+	}{
+		{min: LevelDebug, expectedLevels: []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}},
+		{min: LevelWarn, expectedLevels: []Level{LevelWarn, LevelError}},
+		{min: LevelDisabled},
+	} {
+		s.Run(test.min.String(), func() {
+			s.entries = nil
+			l := WithMinLevel(s.logger, test.min)
+
+			for _, level := range []Level{LevelDebug, LevelInfo, LevelWarn, LevelError} {
+				l.Log(level, "msg")
+			}
+
+			var levels []Level
+			for _, e := range s.entries {
+				levels = append(levels, e.level)
+			}
+			s.Equal(test.expectedLevels, levels)
+		})
+	}
+}
+
+func (s *loggerTestSuite) TestUnmarshalText() {
+	var level Level
+	s.Require().NoError(level.UnmarshalText([]byte("WARN")))
+	s.Equal(LevelWarn, level)
+
+	s.Error(level.UnmarshalText([]byte("verbose")))
+}
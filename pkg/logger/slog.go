@@ -0,0 +1,26 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+var slogLevels = map[Level]slog.Level{
+	LevelDebug: slog.LevelDebug,
+	LevelInfo:  slog.LevelInfo,
+	LevelWarn:  slog.LevelWarn,
+	LevelError: slog.LevelError,
+}
+
+// Slog returns a Logger which logs with the slog logger. The fields are logged as string attributes.
+func Slog(l *slog.Logger) Logger {
+	return Func(func(level Level, msg string, fields ...Field) {
+		attrs := make([]slog.Attr, 0, len(fields))
+		for _, f := range fields {
+			attrs = append(attrs, slog.String(f.Key, f.Value))
+		}
+		l.LogAttrs(context.Background(), slogLevels[level], msg, attrs...)
+	})
+}
@@ -0,0 +1,17 @@
+//go:build go1.21
+
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+)
+
+func (s *loggerTestSuite) TestSlog() {
+	var buf bytes.Buffer
+	l := Slog(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	l.Log(LevelWarn, "API rejected a field", Field{Key: "field", Value: "data.id"})
+
+	s.Contains(buf.String(), `level=WARN msg="API rejected a field" field=data.id`)
+}
@@ -0,0 +1,189 @@
+// Package mandate provides Form3 client to manage SEPA direct debit mandates.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/mandates
+package mandate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	mandatesUrl       = "/transaction/mandates"
+	mandatesType      = "mandates"
+	cancellationsType = "mandate_cancellations"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrMandateNotFound mandate not found
+	ErrMandateNotFound = errors.New("mandate not found")
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type mandateClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 mandates created by NewClient or the form3 facade.
+type Client = mandateClient
+
+// NewClient creates a client for managing Form3 mandates.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*mandateClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &mandateClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (m mandateClient) Close() error {
+	return resource.Close(m.client)
+}
+
+// Create a mandate with attributes.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/mandates/create-a-mandate
+//
+// The request can be enriched by RequestEnricher
+func (m mandateClient) Create(attributes MandateAttributes, en ...re.RequestEnricher) (*MandateData, error) {
+	newID, err := m.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	mandate := MandateData{
+		ID:             newID.String(),
+		OrganisationID: m.config.OrganisationID.String(),
+		Type:           mandatesType,
+		Attributes:     &attributes,
+	}
+
+	created, err := m.mandates().Create(newID, mandate, en...)
+	if err != nil {
+		return nil, err
+	}
+	m.config.Log().Debugf("mandate %s created", mandate.ID)
+	return created, nil
+}
+
+// Fetch a mandate by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/mandates/fetch-a-mandate
+//
+// The request can be enriched by RequestEnricher
+func (m mandateClient) Fetch(mandateID uuid.UUID, en ...re.RequestEnricher) (*MandateData, error) {
+	return m.mandates().Fetch(mandateID, en...)
+}
+
+// List mandates page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/mandates/list-mandates
+//
+// The request can be enriched by RequestEnricher
+func (m mandateClient) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]MandateData, error) {
+	return m.mandates().List(pageNumber, pageSize, en...)
+}
+
+// Cancel a mandate, so no more direct debits can be collected with it.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/mandates/cancel-a-mandate
+//
+// The request can be enriched by RequestEnricher
+func (m mandateClient) Cancel(mandateID uuid.UUID, reason string, en ...re.RequestEnricher) (*CancellationData, error) {
+	if mandateID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := m.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	cancellation := CancellationData{
+		ID:             newID.String(),
+		OrganisationID: m.config.OrganisationID.String(),
+		Type:           cancellationsType,
+		Attributes:     &CancellationAttributes{Reason: reason},
+	}
+
+	created, err := m.cancellations(mandateID).Create(newID, cancellation, en...)
+	if err != nil {
+		return nil, err
+	}
+	m.config.Log().Debugf("mandate %s cancelled", mandateID)
+	return created, nil
+}
+
+// mandates returns the generic client of the mandates collection.
+func (m mandateClient) mandates() resource.Client[MandateData] {
+	return resource.Client[MandateData]{
+		HTTP:        m.client,
+		Config:      m.config,
+		Url:         mandatesUrl,
+		ErrNotFound: ErrMandateNotFound,
+	}
+}
+
+// cancellations returns the generic client of the cancellations of a mandate.
+func (m mandateClient) cancellations(mandateID uuid.UUID) resource.Client[CancellationData] {
+	return resource.Client[CancellationData]{
+		HTTP:        m.client,
+		Config:      m.config,
+		Url:         fmt.Sprintf("%s/%s/cancellations", mandatesUrl, mandateID),
+		ErrNotFound: ErrMandateNotFound,
+	}
+}
+
+func (m mandateClient) bodyToMandateData(body io.Reader) (*MandateData, error) {
+	return resource.Client[MandateData]{Config: m.config}.BodyToData(body)
+}
+
+func (m mandateClient) bodyToCancellationData(body io.Reader) (*CancellationData, error) {
+	return resource.Client[CancellationData]{Config: m.config}.BodyToData(body)
+}
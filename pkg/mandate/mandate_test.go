@@ -0,0 +1,238 @@
+package mandate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testMandatesUrl    = testBaseUrl + mandatesUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type mandateTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	mandateClient  mandateClient
+}
+
+func TestMandateTestSuite(t *testing.T) {
+	suite.Run(t, new(mandateTestSuite))
+}
+
+func (s *mandateTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.mandateClient = mandateClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *mandateTestSuite) TestCreateReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"mandate_reference is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server gateway timeout",
+			responseStatus: http.StatusGatewayTimeout,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testMandatesUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.mandateClient.Create(MandateAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *mandateTestSuite) TestCreateReturnsHttpClientError() {
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testMandatesUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.mandateClient.Create(MandateAttributes{})
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *mandateTestSuite) TestCreateMandate() {
+	mandateID := uuid.New()
+	s.mandateClient.config.UUIDGenerator = func() (uuid.UUID, error) { return mandateID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testMandatesUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.mandateClient.Create(MandateAttributes{MandateReference: "REF-1", SequenceType: "RCUR"})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.mandateClient.bodyToMandateData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(mandateID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(mandatesType, requested.Type)
+	s.Equal("REF-1", requested.Attributes.MandateReference)
+	s.Equal("RCUR", requested.Attributes.SequenceType)
+}
+
+func (s *mandateTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.mandateClient.Fetch(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *mandateTestSuite) TestFetchReturnsError_WhenMandateNotFound() {
+	mandateID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testMandatesUrl, mandateID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.mandateClient.Fetch(mandateID)
+
+	s.ErrorIs(actualError, ErrMandateNotFound)
+}
+
+func (s *mandateTestSuite) TestFetchMandate() {
+	mandateID := uuid.New()
+	body, err := json.Marshal(mandateContainer{Data: MandateData{ID: mandateID.String()}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testMandatesUrl, mandateID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.mandateClient.Fetch(mandateID)
+	s.NoError(err)
+	s.Equal(mandateID.String(), actual.ID)
+}
+
+func (s *mandateTestSuite) TestListMandates() {
+	firstID, secondID := uuid.New(), uuid.New()
+	body, err := json.Marshal(mandateListContainer{Data: []MandateData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testMandatesUrl+"?page[number]=1&page[size]=2")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.mandateClient.List(1, 2)
+	s.NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func (s *mandateTestSuite) TestCancelReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		expectedError  error
+	}{
+		{
+			name:           "mandate not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrMandateNotFound,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			expectedError:  ErrServerError,
+		},
+	} {
+		s.Run(test.name, func() {
+			mandateID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/cancellations", testMandatesUrl, mandateID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody("")}, nil).
+				Once()
+
+			_, actualError := s.mandateClient.Cancel(mandateID, "customer request")
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *mandateTestSuite) TestCancelMandate() {
+	mandateID, cancellationID := uuid.New(), uuid.New()
+	s.mandateClient.config.UUIDGenerator = func() (uuid.UUID, error) { return cancellationID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/cancellations", testMandatesUrl, mandateID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{\"attributes\":{\"status\":\"cancelled\"}}}")}, nil).
+		Once()
+
+	actual, err := s.mandateClient.Cancel(mandateID, "customer request")
+	s.Require().NoError(err)
+	s.Equal("cancelled", actual.Attributes.Status)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.mandateClient.bodyToCancellationData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(cancellationID.String(), requested.ID)
+	s.Equal(cancellationsType, requested.Type)
+	s.Equal("customer request", requested.Attributes.Reason)
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
@@ -0,0 +1,60 @@
+package mandate
+
+import "form3interview/internal/resource"
+
+// mandateContainer is a simple container for the "data" JSON field.
+type mandateContainer = resource.DataContainer[MandateData]
+
+// mandateListContainer is a simple container for the "data" JSON field of list responses.
+type mandateListContainer = resource.ListContainer[MandateData]
+
+// cancellationContainer is a simple container for the "data" JSON field.
+type cancellationContainer = resource.DataContainer[CancellationData]
+
+// MandateData represents a SEPA direct debit mandate.
+// See https://www.api-docs.form3.tech/api/schemes/sepa-direct-debit/mandates for
+// more information about fields.
+type MandateData struct {
+	Attributes     *MandateAttributes `json:"attributes,omitempty"`
+	ID             string             `json:"id,omitempty"`
+	OrganisationID string             `json:"organisation_id,omitempty"`
+	Type           string             `json:"type,omitempty"`
+	Version        *int64             `json:"version,omitempty"`
+}
+
+type MandateAttributes struct {
+	BeneficiaryParty *Party `json:"beneficiary_party,omitempty"`
+	ClearingID       string `json:"clearing_id,omitempty"`
+	DebtorParty      *Party `json:"debtor_party,omitempty"`
+	MandateReference string `json:"mandate_reference,omitempty"`
+	PaymentScheme    string `json:"payment_scheme,omitempty"`
+	SequenceType     string `json:"sequence_type,omitempty"`
+	SignatureDate    string `json:"signature_date,omitempty"`
+	Status           string `json:"status,omitempty"`
+}
+
+// Party represents the debtor or the beneficiary (creditor) of a mandate.
+type Party struct {
+	AccountName       string `json:"account_name,omitempty"`
+	AccountNumber     string `json:"account_number,omitempty"`
+	AccountNumberCode string `json:"account_number_code,omitempty"`
+	Address           string `json:"address,omitempty"`
+	BankID            string `json:"bank_id,omitempty"`
+	BankIDCode        string `json:"bank_id_code,omitempty"`
+	Country           string `json:"country,omitempty"`
+	Name              string `json:"name,omitempty"`
+}
+
+// CancellationData represents the cancellation of a mandate.
+type CancellationData struct {
+	Attributes     *CancellationAttributes `json:"attributes,omitempty"`
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	Type           string                  `json:"type,omitempty"`
+	Version        *int64                  `json:"version,omitempty"`
+}
+
+type CancellationAttributes struct {
+	Reason string `json:"reason,omitempty"`
+	Status string `json:"status,omitempty"`
+}
@@ -0,0 +1,166 @@
+// Package metrics provides the metrics of the Form3 clients: request counts, latencies per operation,
+// in-flight requests, retries and the connection stats of the transport.
+// The clients report into the Metrics interface, so any metrics library can be used. The adapters of
+// Prometheus and OpenTelemetry are the separate form3interview/pkg/metrics/prometheus and
+// form3interview/pkg/metrics/otel modules, so their dependencies are only built by the consumers using them.
+// It is enabled with config.WithMetrics.
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"form3interview/pkg/clock"
+)
+
+// Names of the metrics reported by the clients.
+const (
+	RequestsTotal          = "form3_client_requests_total"
+	RequestDurationSeconds = "form3_client_request_duration_seconds"
+	RequestsInFlight       = "form3_client_requests_in_flight"
+	RetriesTotal           = "form3_client_retries_total"
+	ConnectionsTotal       = "form3_client_connections_total"
+	DialErrorsTotal        = "form3_client_dial_errors_total"
+)
+
+// idSegment matches the UUID path segments which are replaced in the operation label.
+var idSegment = regexp.MustCompile(`/[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+type (
+	// Metrics creates the instruments the clients report into. The label values are passed to the instruments
+	// in the order of the label names. Creating an instrument which already exists should return the existing one,
+	// so the clients sharing the Metrics share their instruments.
+	Metrics interface {
+		Counter(name, help string, labelNames ...string) (Counter, error)
+		Histogram(name, help string, labelNames ...string) (Histogram, error)
+		Gauge(name, help string, labelNames ...string) (Gauge, error)
+	}
+
+	// Counter is a monotonically increasing value.
+	Counter interface {
+		Inc(labelValues ...string)
+	}
+
+	// Histogram is a distribution of observed values.
+	Histogram interface {
+		Observe(value float64, labelValues ...string)
+	}
+
+	// Gauge is a value which can go up and down.
+	Gauge interface {
+		Add(delta float64, labelValues ...string)
+	}
+)
+
+// Recorder records the requests of the clients into the instruments of a Metrics. It's safe for concurrent use
+// and a nil Recorder ignores all records.
+type Recorder struct {
+	requests    Counter
+	duration    Histogram
+	inFlight    Gauge
+	retries     Counter
+	connections Counter
+	dialErrors  Counter
+}
+
+// NewRecorder creates the instruments of the recorder with m.
+func NewRecorder(m Metrics) (*Recorder, error) {
+	r := &Recorder{}
+	var err error
+	if r.requests, err = m.Counter(RequestsTotal, "Number of requests by operation and response status code (error if no response was received).", "operation", "code"); err != nil {
+		return nil, err
+	}
+	if r.duration, err = m.Histogram(RequestDurationSeconds, "Latency of the requests by operation including the retries.", "operation"); err != nil {
+		return nil, err
+	}
+	if r.inFlight, err = m.Gauge(RequestsInFlight, "Number of requests being sent."); err != nil {
+		return nil, err
+	}
+	if r.retries, err = m.Counter(RetriesTotal, "Number of retried requests by operation.", "operation"); err != nil {
+		return nil, err
+	}
+	if r.connections, err = m.Counter(ConnectionsTotal, "Number of connections used by the requests by state (new, reused or idle).", "state"); err != nil {
+		return nil, err
+	}
+	if r.dialErrors, err = m.Counter(DialErrorsTotal, "Number of failed connection attempts."); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Begin counts the request in flight and returns the function recording its result when it's done. The duration
+// is measured on the clock of the request context (see clock.FromContext), so it's the clock of the config on the
+// clients.
+func (r *Recorder) Begin(req *http.Request) func(*http.Response, error) {
+	if r == nil {
+		return func(*http.Response, error) {}
+	}
+
+	r.inFlight.Add(1)
+	clk := clock.FromContext(req.Context())
+	start := clk.Now()
+	return func(resp *http.Response, err error) {
+		r.inFlight.Add(-1)
+		operation := Operation(req)
+		code := "error"
+		if err == nil && resp != nil {
+			code = strconv.Itoa(resp.StatusCode)
+		}
+		r.requests.Inc(operation, code)
+		r.duration.Observe(clk.Now().Sub(start).Seconds(), operation)
+	}
+}
+
+// Retry counts a retry of the request.
+func (r *Recorder) Retry(req *http.Request) {
+	if r == nil {
+		return
+	}
+	r.retries.Inc(Operation(req))
+}
+
+// Trace returns the request with a client trace counting the connections used by it.
+func (r *Recorder) Trace(req *http.Request) *http.Request {
+	if r == nil {
+		return req
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			switch {
+			case info.WasIdle:
+				r.connections.Inc("idle")
+			case info.Reused:
+				r.connections.Inc("reused")
+			default:
+				r.connections.Inc("new")
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err != nil {
+				r.dialErrors.Inc()
+			}
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// Operation returns the operation label of the request: its method and path with the IDs replaced by :id
+// (i.e. GET /v1/organisation/accounts/:id).
+func Operation(req *http.Request) string {
+	return req.Method + " " + idSegment.ReplaceAllString(req.URL.Path, "/:id")
+}
+
+// Resource returns the resource type of the request: the path segment before the first ID or the last segment
+// if the path has no ID (i.e. accounts for /v1/organisation/accounts/:id/events).
+func Resource(req *http.Request) string {
+	path := req.URL.Path
+	if loc := idSegment.FindStringIndex(path); loc != nil {
+		path = path[:loc[0]]
+	}
+	path = strings.TrimSuffix(path, "/")
+	return path[strings.LastIndex(path, "/")+1:]
+}
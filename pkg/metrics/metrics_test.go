@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock"
+	"form3interview/pkg/clock/clocktest"
+)
+
+const testUrl = "http://testhost/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc?version=0"
+
+// fakeMetrics sums the values of the instruments by name and label values.
+type fakeMetrics struct {
+	mu       sync.Mutex
+	values   map[string]float64
+	observed []float64
+	err      error
+}
+
+func (f *fakeMetrics) add(name string, value float64, labelValues []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[strings.Join(append([]string{name}, labelValues...), " ")] += value
+}
+
+func (f *fakeMetrics) value(name string, labelValues ...string) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.values[strings.Join(append([]string{name}, labelValues...), " ")]
+}
+
+func (f *fakeMetrics) Counter(name, _ string, _ ...string) (Counter, error) {
+	return fakeInstrument{metrics: f, name: name}, f.err
+}
+
+func (f *fakeMetrics) Histogram(name, _ string, _ ...string) (Histogram, error) {
+	return fakeInstrument{metrics: f, name: name}, f.err
+}
+
+func (f *fakeMetrics) Gauge(name, _ string, _ ...string) (Gauge, error) {
+	return fakeInstrument{metrics: f, name: name}, f.err
+}
+
+type fakeInstrument struct {
+	metrics *fakeMetrics
+	name    string
+}
+
+func (i fakeInstrument) Inc(labelValues ...string) {
+	i.metrics.add(i.name, 1, labelValues)
+}
+
+func (i fakeInstrument) Observe(value float64, labelValues ...string) {
+	i.metrics.add(i.name, 1, labelValues)
+	i.metrics.mu.Lock()
+	defer i.metrics.mu.Unlock()
+	i.metrics.observed = append(i.metrics.observed, value)
+}
+
+func (i fakeInstrument) Add(delta float64, labelValues ...string) {
+	i.metrics.add(i.name, delta, labelValues)
+}
+
+type metricsTestSuite struct {
+	suite.Suite
+	metrics  *fakeMetrics
+	recorder *Recorder
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(metricsTestSuite))
+}
+
+func (s *metricsTestSuite) SetupTest() {
+	s.metrics = &fakeMetrics{values: map[string]float64{}}
+	recorder, err := NewRecorder(s.metrics)
+	s.Require().NoError(err)
+	s.recorder = recorder
+}
+
+func (s *metricsTestSuite) newRequest(method string) *http.Request {
+	req, err := http.NewRequest(method, testUrl, nil)
+	s.Require().NoError(err)
+	return req
+}
+
+func (s *metricsTestSuite) TestOperation() {
+	s.Equal("DELETE /v1/organisation/accounts/:id", Operation(s.newRequest(http.MethodDelete)))
+}
+
+func (s *metricsTestSuite) TestResource() {
+	for _, test := range []struct {
+		path     string
+		expected string
+	}{
+		{path: "/v1/organisation/accounts", expected: "accounts"},
+		{path: "/v1/organisation/accounts/", expected: "accounts"},
+		{path: "/v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", expected: "accounts"},
+		{path: "/v1/transaction/payments/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc/submissions", expected: "payments"},
+		{path: "/v1/health", expected: "health"},
+	} {
+		s.Run(test.path, func() {
+			s.Equal(test.expected, Resource(&http.Request{URL: &url.URL{Path: test.path}}))
+		})
+	}
+}
+
+func (s *metricsTestSuite) TestBegin() {
+	req := s.newRequest(http.MethodGet)
+	operation := "GET /v1/organisation/accounts/:id"
+
+	done := s.recorder.Begin(req)
+	s.Equal(1.0, s.metrics.value(RequestsInFlight))
+	done(&http.Response{StatusCode: http.StatusOK}, nil)
+	s.recorder.Begin(req)(nil, errors.New("connection reset"))
+
+	s.Zero(s.metrics.value(RequestsInFlight))
+	s.Equal(1.0, s.metrics.value(RequestsTotal, operation, "200"))
+	s.Equal(1.0, s.metrics.value(RequestsTotal, operation, "error"))
+	s.Equal(2.0, s.metrics.value(RequestDurationSeconds, operation))
+}
+
+func (s *metricsTestSuite) TestBeginMeasuresDurationOnClockOfContext() {
+	fake := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	req := s.newRequest(http.MethodGet)
+	req = req.WithContext(clock.NewContext(req.Context(), fake))
+
+	done := s.recorder.Begin(req)
+	fake.Advance(2 * time.Second)
+	done(&http.Response{StatusCode: http.StatusOK}, nil)
+
+	s.Equal([]float64{2}, s.metrics.observed)
+}
+
+func (s *metricsTestSuite) TestRetry() {
+	s.recorder.Retry(s.newRequest(http.MethodGet))
+
+	s.Equal(1.0, s.metrics.value(RetriesTotal, "GET /v1/organisation/accounts/:id"))
+}
+
+func (s *metricsTestSuite) TestTrace() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		s.Require().NoError(err)
+		resp, err := server.Client().Do(s.recorder.Trace(req))
+		s.Require().NoError(err)
+		resp.Body.Close()
+	}
+
+	s.Equal(1.0, s.metrics.value(ConnectionsTotal, "new"))
+	s.Equal(1.0, s.metrics.value(ConnectionsTotal, "idle"))
+}
+
+func (s *metricsTestSuite) TestNewRecorderReturnsError_WhenInstrumentCantBeCreated() {
+	errCreate := errors.New("can't create")
+
+	_, err := NewRecorder(&fakeMetrics{err: errCreate})
+
+	s.ErrorIs(err, errCreate)
+}
+
+func (s *metricsTestSuite) TestNilRecorderIgnoresRecords() {
+	var recorder *Recorder
+	req := s.newRequest(http.MethodGet)
+
+	s.NotPanics(func() {
+		recorder.Begin(req)(nil, nil)
+		recorder.Retry(req)
+		s.Same(req, recorder.Trace(req))
+	})
+}
@@ -0,0 +1,74 @@
+// Package metricstest provides an in-memory metrics.Metrics for testing the metrics reported by the Form3 clients
+// without a metrics library.
+package metricstest
+
+import (
+	"strings"
+	"sync"
+
+	"form3interview/pkg/metrics"
+)
+
+// Fake sums the values reported into its instruments by name and label values. The histograms count the
+// observations. It's safe for concurrent use.
+type Fake struct {
+	// Err is returned when an instrument is created if it's set.
+	Err error
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+var _ metrics.Metrics = (*Fake)(nil)
+
+// Counter creates a counter.
+func (f *Fake) Counter(name, _ string, _ ...string) (metrics.Counter, error) {
+	return instrument{fake: f, name: name}, f.Err
+}
+
+// Histogram creates a histogram counting the observations.
+func (f *Fake) Histogram(name, _ string, _ ...string) (metrics.Histogram, error) {
+	return instrument{fake: f, name: name}, f.Err
+}
+
+// Gauge creates a gauge.
+func (f *Fake) Gauge(name, _ string, _ ...string) (metrics.Gauge, error) {
+	return instrument{fake: f, name: name}, f.Err
+}
+
+// Value returns the value of the instrument with the label values.
+func (f *Fake) Value(name string, labelValues ...string) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.values[key(name, labelValues)]
+}
+
+func (f *Fake) add(name string, value float64, labelValues []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.values == nil {
+		f.values = map[string]float64{}
+	}
+	f.values[key(name, labelValues)] += value
+}
+
+func key(name string, labelValues []string) string {
+	return strings.Join(append([]string{name}, labelValues...), " ")
+}
+
+type instrument struct {
+	fake *Fake
+	name string
+}
+
+func (i instrument) Inc(labelValues ...string) {
+	i.fake.add(i.name, 1, labelValues)
+}
+
+func (i instrument) Observe(_ float64, labelValues ...string) {
+	i.fake.add(i.name, 1, labelValues)
+}
+
+func (i instrument) Add(delta float64, labelValues ...string) {
+	i.fake.add(i.name, delta, labelValues)
+}
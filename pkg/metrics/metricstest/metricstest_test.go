@@ -0,0 +1,63 @@
+package metricstest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type metricsTestTestSuite struct {
+	suite.Suite
+	fake *Fake
+}
+
+func TestMetricsTestTestSuite(t *testing.T) {
+	suite.Run(t, new(metricsTestTestSuite))
+}
+
+func (s *metricsTestTestSuite) SetupTest() {
+	s.fake = &Fake{}
+}
+
+func (s *metricsTestTestSuite) TestCounter() {
+	counter, err := s.fake.Counter("requests_total", "Number of requests.", "code")
+	s.Require().NoError(err)
+
+	counter.Inc("200")
+	counter.Inc("200")
+	counter.Inc("500")
+
+	s.Equal(float64(2), s.fake.Value("requests_total", "200"))
+	s.Equal(float64(1), s.fake.Value("requests_total", "500"))
+	s.Zero(s.fake.Value("requests_total", "404"))
+}
+
+func (s *metricsTestTestSuite) TestHistogramCountsObservations() {
+	histogram, err := s.fake.Histogram("duration_seconds", "Latency.", "operation")
+	s.Require().NoError(err)
+
+	histogram.Observe(0.5, "GET /accounts")
+	histogram.Observe(1.5, "GET /accounts")
+
+	s.Equal(float64(2), s.fake.Value("duration_seconds", "GET /accounts"))
+}
+
+func (s *metricsTestTestSuite) TestGauge() {
+	gauge, err := s.fake.Gauge("in_flight", "Requests in flight.")
+	s.Require().NoError(err)
+
+	gauge.Add(2)
+	gauge.Add(-1)
+
+	s.Equal(float64(1), s.fake.Value("in_flight"))
+}
+
+func (s *metricsTestTestSuite) TestInstrumentsReturnError_WhenErrIsSet() {
+	errCreate := errors.New("create failed")
+	s.fake.Err = errCreate
+
+	_, err := s.fake.Counter("requests_total", "Number of requests.")
+
+	s.ErrorIs(err, errCreate)
+}
@@ -0,0 +1,18 @@
+module form3interview/pkg/metrics/otel
+
+go 1.19
+
+require (
+	form3interview v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.8.3
+	go.opentelemetry.io/otel v1.16.0
+	go.opentelemetry.io/otel/metric v1.16.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace form3interview => ../../../
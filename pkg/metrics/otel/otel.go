@@ -0,0 +1,87 @@
+// Package otel provides the OpenTelemetry adapter of the metrics.Metrics interface. It is a separate module,
+// so the OpenTelemetry dependencies are only built by the consumers using it.
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"form3interview/pkg/metrics"
+)
+
+// Metrics creates the instruments with an OpenTelemetry Meter. The label names are the attribute keys.
+type Metrics struct {
+	meter metric.Meter
+}
+
+// New creates the adapter creating the instruments with meter.
+func New(meter metric.Meter) Metrics {
+	return Metrics{meter: meter}
+}
+
+// Counter creates a Float64Counter.
+func (m Metrics) Counter(name, help string, labelNames ...string) (metrics.Counter, error) {
+	c, err := m.meter.Float64Counter(name, metric.WithDescription(help))
+	if err != nil {
+		return nil, err
+	}
+	return counter{counter: c, labelNames: labelNames}, nil
+}
+
+// Histogram creates a Float64Histogram.
+func (m Metrics) Histogram(name, help string, labelNames ...string) (metrics.Histogram, error) {
+	h, err := m.meter.Float64Histogram(name, metric.WithDescription(help))
+	if err != nil {
+		return nil, err
+	}
+	return histogram{histogram: h, labelNames: labelNames}, nil
+}
+
+// Gauge creates a Float64UpDownCounter.
+func (m Metrics) Gauge(name, help string, labelNames ...string) (metrics.Gauge, error) {
+	g, err := m.meter.Float64UpDownCounter(name, metric.WithDescription(help))
+	if err != nil {
+		return nil, err
+	}
+	return gauge{gauge: g, labelNames: labelNames}, nil
+}
+
+// attributes pairs the label names with their values.
+func attributes(labelNames, labelValues []string) metric.MeasurementOption {
+	attrs := make([]attribute.KeyValue, 0, len(labelNames))
+	for i, name := range labelNames {
+		if i < len(labelValues) {
+			attrs = append(attrs, attribute.String(name, labelValues[i]))
+		}
+	}
+	return metric.WithAttributes(attrs...)
+}
+
+type counter struct {
+	counter    metric.Float64Counter
+	labelNames []string
+}
+
+func (c counter) Inc(labelValues ...string) {
+	c.counter.Add(context.Background(), 1, attributes(c.labelNames, labelValues))
+}
+
+type histogram struct {
+	histogram  metric.Float64Histogram
+	labelNames []string
+}
+
+func (h histogram) Observe(value float64, labelValues ...string) {
+	h.histogram.Record(context.Background(), value, attributes(h.labelNames, labelValues))
+}
+
+type gauge struct {
+	gauge      metric.Float64UpDownCounter
+	labelNames []string
+}
+
+func (g gauge) Add(delta float64, labelValues ...string) {
+	g.gauge.Add(context.Background(), delta, attributes(g.labelNames, labelValues))
+}
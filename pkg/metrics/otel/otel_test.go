@@ -0,0 +1,123 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// measurement is a value recorded by the fake instruments.
+type measurement struct {
+	name       string
+	value      float64
+	attributes attribute.Set
+}
+
+// fakeMeter records the measurements of its instruments.
+type fakeMeter struct {
+	noop.Meter
+	measurements []measurement
+	err          error
+}
+
+func (m *fakeMeter) Float64Counter(name string, _ ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	return fakeCounter{meter: m, name: name}, m.err
+}
+
+func (m *fakeMeter) Float64UpDownCounter(name string, _ ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	return fakeUpDownCounter{meter: m, name: name}, m.err
+}
+
+func (m *fakeMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return fakeHistogram{meter: m, name: name}, m.err
+}
+
+type fakeCounter struct {
+	noop.Float64Counter
+	meter *fakeMeter
+	name  string
+}
+
+func (c fakeCounter) Add(_ context.Context, incr float64, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	c.meter.measurements = append(c.meter.measurements, measurement{name: c.name, value: incr, attributes: attrs})
+}
+
+type fakeUpDownCounter struct {
+	noop.Float64UpDownCounter
+	meter *fakeMeter
+	name  string
+}
+
+func (c fakeUpDownCounter) Add(_ context.Context, incr float64, options ...metric.AddOption) {
+	attrs := metric.NewAddConfig(options).Attributes()
+	c.meter.measurements = append(c.meter.measurements, measurement{name: c.name, value: incr, attributes: attrs})
+}
+
+type fakeHistogram struct {
+	noop.Float64Histogram
+	meter *fakeMeter
+	name  string
+}
+
+func (h fakeHistogram) Record(_ context.Context, value float64, options ...metric.RecordOption) {
+	attrs := metric.NewRecordConfig(options).Attributes()
+	h.meter.measurements = append(h.meter.measurements, measurement{name: h.name, value: value, attributes: attrs})
+}
+
+type otelTestSuite struct {
+	suite.Suite
+	meter   *fakeMeter
+	metrics Metrics
+}
+
+func TestOtelTestSuite(t *testing.T) {
+	suite.Run(t, new(otelTestSuite))
+}
+
+func (s *otelTestSuite) SetupTest() {
+	s.meter = &fakeMeter{}
+	s.metrics = New(s.meter)
+}
+
+func (s *otelTestSuite) TestCounter() {
+	counter, err := s.metrics.Counter("requests_total", "Number of requests.", "operation", "code")
+	s.Require().NoError(err)
+
+	counter.Inc("GET /things", "200")
+
+	expected := attribute.NewSet(attribute.String("operation", "GET /things"), attribute.String("code", "200"))
+	s.Equal([]measurement{{name: "requests_total", value: 1, attributes: expected}}, s.meter.measurements)
+}
+
+func (s *otelTestSuite) TestHistogram() {
+	histogram, err := s.metrics.Histogram("duration_seconds", "Latency.", "operation")
+	s.Require().NoError(err)
+
+	histogram.Observe(0.2, "GET /things")
+
+	expected := attribute.NewSet(attribute.String("operation", "GET /things"))
+	s.Equal([]measurement{{name: "duration_seconds", value: 0.2, attributes: expected}}, s.meter.measurements)
+}
+
+func (s *otelTestSuite) TestGauge() {
+	gauge, err := s.metrics.Gauge("in_flight", "Number of requests being sent.")
+	s.Require().NoError(err)
+
+	gauge.Add(-1)
+
+	s.Equal([]measurement{{name: "in_flight", value: -1, attributes: attribute.NewSet()}}, s.meter.measurements)
+}
+
+func (s *otelTestSuite) TestCounterReturnsError_WhenMeterFails() {
+	s.meter.err = errors.New("invalid name")
+
+	_, err := s.metrics.Counter("requests total", "Number of requests.")
+
+	s.ErrorIs(err, s.meter.err)
+}
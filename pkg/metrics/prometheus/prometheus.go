@@ -0,0 +1,96 @@
+// Package prometheus provides the Prometheus adapter of the metrics.Metrics interface.
+package prometheus
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"form3interview/pkg/config"
+	"form3interview/pkg/metrics"
+)
+
+// Metrics creates the instruments as Prometheus collectors registered into a Registerer.
+type Metrics struct {
+	reg prometheus.Registerer
+}
+
+// New creates the adapter registering the collectors into reg. The collectors already registered by another
+// client are reused, so the clients sharing reg share their metrics.
+func New(reg prometheus.Registerer) Metrics {
+	return Metrics{reg: reg}
+}
+
+// WithRegisterer is the option of the clients registering their metrics into reg (see config.WithMetrics).
+// The clients sharing reg share their metrics.
+func WithRegisterer(reg prometheus.Registerer) config.Option {
+	return config.WithMetrics(New(reg))
+}
+
+// Counter creates a CounterVec.
+func (m Metrics) Counter(name, help string, labelNames ...string) (metrics.Counter, error) {
+	vec, err := register(m.reg, prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames))
+	if err != nil {
+		return nil, err
+	}
+	return counter{vec}, nil
+}
+
+// Histogram creates a HistogramVec with the default buckets.
+func (m Metrics) Histogram(name, help string, labelNames ...string) (metrics.Histogram, error) {
+	opts := prometheus.HistogramOpts{Name: name, Help: help, Buckets: prometheus.DefBuckets}
+	vec, err := register(m.reg, prometheus.NewHistogramVec(opts, labelNames))
+	if err != nil {
+		return nil, err
+	}
+	return histogram{vec}, nil
+}
+
+// Gauge creates a GaugeVec.
+func (m Metrics) Gauge(name, help string, labelNames ...string) (metrics.Gauge, error) {
+	vec, err := register(m.reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames))
+	if err != nil {
+		return nil, err
+	}
+	return gauge{vec}, nil
+}
+
+// register registers the collector or returns the already registered one.
+func register[T prometheus.Collector](reg prometheus.Registerer, collector T) (T, error) {
+	err := reg.Register(collector)
+	if err == nil {
+		return collector, nil
+	}
+
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		if existing, ok := already.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+	return collector, err
+}
+
+type counter struct {
+	vec *prometheus.CounterVec
+}
+
+func (c counter) Inc(labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Inc()
+}
+
+type histogram struct {
+	vec *prometheus.HistogramVec
+}
+
+func (h histogram) Observe(value float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(value)
+}
+
+type gauge struct {
+	vec *prometheus.GaugeVec
+}
+
+func (g gauge) Add(delta float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Add(delta)
+}
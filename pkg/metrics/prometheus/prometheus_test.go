@@ -0,0 +1,101 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+
+	conf "form3interview/internal/config"
+	"form3interview/pkg/config"
+)
+
+type prometheusTestSuite struct {
+	suite.Suite
+	registry *prometheus.Registry
+	metrics  Metrics
+}
+
+func TestPrometheusTestSuite(t *testing.T) {
+	suite.Run(t, new(prometheusTestSuite))
+}
+
+func (s *prometheusTestSuite) SetupTest() {
+	s.registry = prometheus.NewRegistry()
+	s.metrics = New(s.registry)
+}
+
+func (s *prometheusTestSuite) TestWithRegisterer() {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, []config.Option{WithRegisterer(s.registry)})
+
+	s.Equal(New(s.registry), cfg.Metrics)
+}
+
+func (s *prometheusTestSuite) TestCounter() {
+	counter, err := s.metrics.Counter("requests_total", "Number of requests.", "code")
+	s.Require().NoError(err)
+
+	counter.Inc("200")
+
+	expected := `
+# HELP requests_total Number of requests.
+# TYPE requests_total counter
+requests_total{code="200"} 1
+`
+	s.NoError(testutil.GatherAndCompare(s.registry, strings.NewReader(expected)))
+}
+
+func (s *prometheusTestSuite) TestHistogram() {
+	histogram, err := s.metrics.Histogram("duration_seconds", "Latency.", "operation")
+	s.Require().NoError(err)
+
+	histogram.Observe(0.2, "GET /things")
+
+	count, err := testutil.GatherAndCount(s.registry, "duration_seconds")
+	s.Require().NoError(err)
+	s.Equal(1, count)
+}
+
+func (s *prometheusTestSuite) TestGauge() {
+	gauge, err := s.metrics.Gauge("in_flight", "Number of requests being sent.")
+	s.Require().NoError(err)
+
+	gauge.Add(2)
+	gauge.Add(-1)
+
+	expected := `
+# HELP in_flight Number of requests being sent.
+# TYPE in_flight gauge
+in_flight 1
+`
+	s.NoError(testutil.GatherAndCompare(s.registry, strings.NewReader(expected)))
+}
+
+func (s *prometheusTestSuite) TestCounterReusesRegisteredCollector() {
+	first, err := s.metrics.Counter("requests_total", "Number of requests.", "code")
+	s.Require().NoError(err)
+	second, err := New(s.registry).Counter("requests_total", "Number of requests.", "code")
+	s.Require().NoError(err)
+
+	first.Inc("200")
+	second.Inc("200")
+
+	expected := `
+# HELP requests_total Number of requests.
+# TYPE requests_total counter
+requests_total{code="200"} 2
+`
+	s.NoError(testutil.GatherAndCompare(s.registry, strings.NewReader(expected)))
+}
+
+func (s *prometheusTestSuite) TestCounterReturnsError_WhenRegisteredWithOtherType() {
+	_, err := s.metrics.Gauge("requests_total", "Number of requests.")
+	s.Require().NoError(err)
+
+	_, err = s.metrics.Counter("requests_total", "Number of requests.")
+
+	s.Error(err)
+}
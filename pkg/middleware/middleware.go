@@ -0,0 +1,144 @@
+// Package middleware provides the built-in middlewares of the Form3 clients for logging, retries, authentication
+// and metrics. They can be added to the clients with config.WithMiddleware, or they can wrap any
+// requestenricher.Doer, i.e. an *http.Client calling the API directly:
+//
+//	doer := re.Wrap(http.DefaultClient, middleware.Logging(log), middleware.Retry(retry.DefaultPolicy()), middleware.Auth(provider))
+//
+// A RequestEnricher can be used as a middleware with its Middleware method.
+//
+// The middlewares added to the clients run inside the built-in retry of the clients, so the clients are not created
+// with Retry (their NewClient returns ErrNestedRetry) unless the built-in retry is disabled with
+// config.WithRetry(retry.Disabled()).
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"form3interview/pkg/auth"
+	"form3interview/pkg/clock"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/metrics"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/retry"
+)
+
+// ErrNestedRetry the Retry middleware was added to a client which retries the requests itself
+var ErrNestedRetry = errors.New("the retry middleware can't be used while the built-in retry of the client is enabled")
+
+// Logging logs the requests at debug level with their operation, status code and duration, and the failed
+// requests at warn level with their error. The duration is measured on the clock of the request context (see
+// clock.FromContext), so it's the clock of the config on the clients.
+func Logging(log logger.Logger) re.Middleware {
+	return func(next re.Doer) re.Doer {
+		return re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			clk := clock.FromContext(req.Context())
+			start := clk.Now()
+			resp, err := next.Do(req)
+			fields := []logger.Field{
+				{Key: "operation", Value: metrics.Operation(req)},
+				{Key: "duration", Value: clk.Now().Sub(start).String()},
+			}
+			if err != nil {
+				log.Log(logger.LevelWarn, "request failed", append(fields, logger.Field{Key: "error", Value: err.Error()})...)
+				return resp, err
+			}
+			log.Log(logger.LevelDebug, "request", append(fields, logger.Field{Key: "status", Value: strconv.Itoa(resp.StatusCode)})...)
+			return resp, nil
+		})
+	}
+}
+
+// Retry retries the failed requests with the policy (see retry.Policy.Retryable). The delays honor the Retry-After
+// header of the responses and the deadline of the request context. When the budget of the policy is exhausted the
+// last failed attempt is returned as a retry.BudgetExhaustedError.
+// The requests fail with ErrNestedRetry when they are retried by the caller already (see retry.Retrying). The
+// clients retrying the requests themselves reject it when they are created (see Retries).
+func Retry(policy retry.Policy) re.Middleware {
+	return func(next re.Doer) re.Doer {
+		return retryDoer{policy: policy, next: next}
+	}
+}
+
+// Retries tells whether any of the middlewares retries the requests (i.e. Retry), so the clients with their
+// built-in retry enabled can reject them when they are created.
+func Retries(middleware ...re.Middleware) bool {
+	noop := re.DoerFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+	for _, m := range middleware {
+		if _, ok := m(noop).(retryDoer); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDoer is the Doer of the Retry middleware.
+type retryDoer struct {
+	policy retry.Policy
+	next   re.Doer
+}
+
+func (d retryDoer) Do(req *http.Request) (*http.Response, error) {
+	if retry.Retrying(req.Context()) {
+		return nil, ErrNestedRetry
+	}
+	d.policy.Budget.Deposit()
+	for attempt := 1; ; attempt++ {
+		resp, err := d.next.Do(req)
+		if !d.policy.Retryable(req, resp, err, attempt) {
+			return resp, err
+		}
+		delay, ok := d.policy.Delay(attempt, resp)
+		if !ok || retry.PastDeadline(req.Context(), delay) {
+			return resp, err
+		}
+
+		withdrawn := d.policy.Budget.Withdraw()
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if !withdrawn {
+			exhausted := &retry.BudgetExhaustedError{Err: err}
+			if resp != nil {
+				exhausted.StatusCode = resp.StatusCode
+			}
+			return nil, exhausted
+		}
+		if err := retry.Wait(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req, err = retry.Rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Auth sets the bearer token and signs the requests with the credentials of the provider (see auth.Authorize).
+// The requests are not sent if the credentials can't be provided.
+func Auth(provider auth.CredentialsProvider) re.Middleware {
+	return func(next re.Doer) re.Doer {
+		return re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if err := auth.Authorize(req, provider); err != nil {
+				return nil, err
+			}
+			return next.Do(req)
+		})
+	}
+}
+
+// Metrics records the requests, their connections and results with the recorder (see metrics.Recorder).
+func Metrics(recorder *metrics.Recorder) re.Middleware {
+	return func(next re.Doer) re.Doer {
+		return re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req = recorder.Trace(req)
+			done := recorder.Begin(req)
+			resp, err := next.Do(req)
+			done(resp, err)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/auth"
+	"form3interview/pkg/clock"
+	"form3interview/pkg/clock/clocktest"
+	"form3interview/pkg/logger"
+	"form3interview/pkg/metrics"
+	"form3interview/pkg/metrics/metricstest"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/retry"
+)
+
+const testUrl = "http://testhost/v1/organisation/accounts"
+
+type entry struct {
+	level  logger.Level
+	msg    string
+	fields map[string]string
+}
+
+type middlewareTestSuite struct {
+	suite.Suite
+	statusCodes []int
+	errs        []error
+	requests    []*http.Request
+	doer        re.Doer
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(middlewareTestSuite))
+}
+
+func (s *middlewareTestSuite) SetupTest() {
+	s.statusCodes = nil
+	s.errs = nil
+	s.requests = nil
+	s.doer = re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		attempt := len(s.requests)
+		s.requests = append(s.requests, req)
+		if attempt < len(s.errs) && s.errs[attempt] != nil {
+			return nil, s.errs[attempt]
+		}
+		statusCode := http.StatusOK
+		if attempt < len(s.statusCodes) {
+			statusCode = s.statusCodes[attempt]
+		}
+		return &http.Response{StatusCode: statusCode, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+	})
+}
+
+func (s *middlewareTestSuite) newRequest() *http.Request {
+	req, err := http.NewRequest(http.MethodGet, testUrl, nil)
+	s.Require().NoError(err)
+	return req
+}
+
+func (s *middlewareTestSuite) TestLogging() {
+	var entries []entry
+	log := logger.Func(func(level logger.Level, msg string, fields ...logger.Field) {
+		e := entry{level: level, msg: msg, fields: map[string]string{}}
+		for _, f := range fields {
+			e.fields[f.Key] = f.Value
+		}
+		entries = append(entries, e)
+	})
+	errReset := errors.New("connection reset by peer")
+	s.errs = []error{nil, errReset}
+	doer := re.Wrap(s.doer, Logging(log))
+
+	_, err := doer.Do(s.newRequest())
+	s.Require().NoError(err)
+	_, err = doer.Do(s.newRequest())
+	s.Require().ErrorIs(err, errReset)
+
+	s.Require().Len(entries, 2)
+	s.Equal(logger.LevelDebug, entries[0].level)
+	s.Equal("request", entries[0].msg)
+	s.Equal("GET /v1/organisation/accounts", entries[0].fields["operation"])
+	s.Equal("200", entries[0].fields["status"])
+	s.NotEmpty(entries[0].fields["duration"])
+	s.Equal(logger.LevelWarn, entries[1].level)
+	s.Equal("request failed", entries[1].msg)
+	s.Equal(errReset.Error(), entries[1].fields["error"])
+}
+
+func (s *middlewareTestSuite) TestLoggingMeasuresDurationOnTheContextClock() {
+	var durations []string
+	log := logger.Func(func(level logger.Level, msg string, fields ...logger.Field) {
+		for _, f := range fields {
+			if f.Key == "duration" {
+				durations = append(durations, f.Value)
+			}
+		}
+	})
+	fake := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	doer := re.Wrap(re.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		fake.Advance(time.Minute)
+		return s.doer.Do(req)
+	}), Logging(log))
+
+	_, err := doer.Do(s.newRequest().WithContext(clock.NewContext(context.Background(), fake)))
+
+	s.Require().NoError(err)
+	s.Equal([]string{"1m0s"}, durations)
+}
+
+func (s *middlewareTestSuite) TestRetry() {
+	s.statusCodes = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK}
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryableStatusCodes: []int{http.StatusBadGateway}}
+
+	resp, err := re.Wrap(s.doer, Retry(policy)).Do(s.newRequest())
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Len(s.requests, 3)
+}
+
+func (s *middlewareTestSuite) TestRetryReturnsLastAttempt() {
+	s.statusCodes = []int{http.StatusBadGateway, http.StatusBadGateway}
+	policy := retry.Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryableStatusCodes: []int{http.StatusBadGateway}}
+
+	resp, err := re.Wrap(s.doer, Retry(policy)).Do(s.newRequest())
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadGateway, resp.StatusCode)
+	s.Len(s.requests, 2)
+}
+
+func (s *middlewareTestSuite) TestRetryReturnsBudgetExhaustedError() {
+	s.statusCodes = []int{http.StatusBadGateway, http.StatusBadGateway}
+	policy := retry.Policy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusBadGateway},
+		Budget:               retry.NewBudget(0, 0),
+	}
+
+	_, err := re.Wrap(s.doer, Retry(policy)).Do(s.newRequest())
+
+	var exhausted *retry.BudgetExhaustedError
+	s.Require().ErrorAs(err, &exhausted)
+	s.Equal(http.StatusBadGateway, exhausted.StatusCode)
+	s.Len(s.requests, 1)
+}
+
+func (s *middlewareTestSuite) TestRetryStopsWhenContextIsDone() {
+	s.errs = []error{errors.New("connection reset by peer")}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Minute}
+
+	_, err := re.Wrap(s.doer, Retry(policy)).Do(s.newRequest().WithContext(ctx))
+
+	s.Error(err)
+	s.Len(s.requests, 1)
+}
+
+func (s *middlewareTestSuite) TestRetryReturnsError_WhenRequestIsRetriedByTheCaller() {
+	policy := retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	_, err := re.Wrap(s.doer, Retry(policy)).Do(s.newRequest().WithContext(retry.WithRetrying(context.Background())))
+
+	s.ErrorIs(err, ErrNestedRetry)
+	s.Empty(s.requests)
+}
+
+func (s *middlewareTestSuite) TestRetries() {
+	s.True(Retries(Auth(auth.StaticCredentials(auth.Credentials{})), Retry(retry.DefaultPolicy())))
+	s.False(Retries(Auth(auth.StaticCredentials(auth.Credentials{}))))
+	s.False(Retries())
+}
+
+func (s *middlewareTestSuite) TestAuth() {
+	req := s.newRequest()
+
+	_, err := re.Wrap(s.doer, Auth(auth.StaticCredentials(auth.Credentials{Token: "token"}))).Do(req)
+
+	s.Require().NoError(err)
+	s.Require().Len(s.requests, 1)
+	s.Equal("Bearer token", s.requests[0].Header.Get("Authorization"))
+	s.Empty(req.Header.Get("Authorization"))
+}
+
+func (s *middlewareTestSuite) TestAuthReturnsError_WhenCredentialsCantBeProvided() {
+	errVault := errors.New("vault is sealed")
+	provider := auth.CredentialsProviderFunc(func(context.Context) (auth.Credentials, error) {
+		return auth.Credentials{}, errVault
+	})
+
+	_, err := re.Wrap(s.doer, Auth(provider)).Do(s.newRequest())
+
+	s.ErrorIs(err, errVault)
+	s.Empty(s.requests)
+}
+
+func (s *middlewareTestSuite) TestMetrics() {
+	fake := &metricstest.Fake{}
+	recorder, err := metrics.NewRecorder(fake)
+	s.Require().NoError(err)
+
+	_, err = re.Wrap(s.doer, Metrics(recorder)).Do(s.newRequest())
+
+	s.Require().NoError(err)
+	s.Equal(float64(1), fake.Value(metrics.RequestsTotal, "GET /v1/organisation/accounts", "200"))
+}
@@ -0,0 +1,24 @@
+package organisation
+
+import "form3interview/internal/resource"
+
+// unitContainer is a simple container for the "data" JSON field.
+type unitContainer = resource.DataContainer[UnitData]
+
+// unitListContainer is a simple container for the "data" JSON field of list responses.
+type unitListContainer = resource.ListContainer[UnitData]
+
+// UnitData represents an organisation unit. The OrganisationID is the ID of the parent organisation.
+// See https://www.api-docs.form3.tech/api/organisations/units for
+// more information about fields.
+type UnitData struct {
+	Attributes     *UnitAttributes `json:"attributes,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	OrganisationID string          `json:"organisation_id,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	Version        *int64          `json:"version,omitempty"`
+}
+
+type UnitAttributes struct {
+	Name string `json:"name,omitempty"`
+}
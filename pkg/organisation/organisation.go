@@ -0,0 +1,211 @@
+// Package organisation provides Form3 client to manage organisation units.
+// The IDs of the created units can be used as organisation ID for the other clients (i.e. account).
+// See https://www.api-docs.form3.tech/api/organisations/units
+package organisation
+
+import (
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	"form3interview/pkg/conflict"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	unitsUrl  = "/organisation/units"
+	unitsType = "organisations"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrUnitNotFound organisation unit not found
+	ErrUnitNotFound = errors.New("organisation unit not found")
+	// ErrInvalidUnitVersion organisation unit version not found
+	ErrInvalidUnitVersion = errors.New("invalid organisation unit version")
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type organisationClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 organisation units created by NewClient or the form3 facade.
+type Client = organisationClient
+
+// NewClient creates a client for managing Form3 organisation units.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*organisationClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &organisationClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (o organisationClient) Close() error {
+	return resource.Close(o.client)
+}
+
+// Create an organisation unit with attributes under the configured organisation.
+// See https://www.api-docs.form3.tech/api/organisations/units/create-an-organisation-unit
+//
+// The request can be enriched by RequestEnricher
+func (o organisationClient) Create(attributes UnitAttributes, en ...re.RequestEnricher) (*UnitData, error) {
+	newID, err := o.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	unit := UnitData{
+		ID:             newID.String(),
+		OrganisationID: o.config.OrganisationID.String(),
+		Type:           unitsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := o.units().Create(newID, unit, en...)
+	if err != nil {
+		return nil, err
+	}
+	o.config.Log().Debugf("organisation unit %s created", unit.ID)
+	return created, nil
+}
+
+// Fetch an organisation unit by it's ID.
+// See https://www.api-docs.form3.tech/api/organisations/units/fetch-an-organisation-unit
+//
+// The request can be enriched by RequestEnricher
+func (o organisationClient) Fetch(unitID uuid.UUID, en ...re.RequestEnricher) (*UnitData, error) {
+	return o.units().Fetch(unitID, en...)
+}
+
+// List organisation units page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/organisations/units/list-organisation-units
+//
+// The request can be enriched by RequestEnricher
+func (o organisationClient) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]UnitData, error) {
+	return o.units().List(pageNumber, pageSize, en...)
+}
+
+// Delete is a convenience function to delete an organisation unit by it's ID having the latest version.
+// See https://www.api-docs.form3.tech/api/organisations/units/delete-an-organisation-unit
+//
+// Under the hood it fetches the latest organisation unit and delete that with the specific version returned.
+// The request can be enriched by RequestEnricher
+func (o organisationClient) Delete(unitID uuid.UUID, en ...re.RequestEnricher) error {
+	if o.config.ReadOnly {
+		return ErrReadOnly
+	}
+
+	version, _, err := o.latestVersion(unitID, en...)
+	if err != nil {
+		return err
+	}
+	return o.DeleteVersion(unitID, version, en...)
+}
+
+// DeleteVersion deletes an organisation unit by it's ID having a specific version.
+// See https://www.api-docs.form3.tech/api/organisations/units/delete-an-organisation-unit
+//
+// A version conflict is resolved with the conflict policy of the config (see config.WithConflictPolicy).
+// The request can be enriched by RequestEnricher
+func (o organisationClient) DeleteVersion(unitID uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	if unitID == uuid.Nil {
+		return ErrNilUUID
+	}
+
+	return o.config.ConflictPolicy.Resolve(conflict.Write{
+		ID:          unitID,
+		Version:     version,
+		ErrConflict: ErrInvalidUnitVersion,
+		Do: func(version uint) error {
+			return o.deleteVersion(unitID, version, en...)
+		},
+		Latest: func() (uint, any, error) {
+			return o.latestVersion(unitID, en...)
+		},
+	})
+}
+
+// latestVersion fetches the organisation unit and returns its current version.
+func (o organisationClient) latestVersion(unitID uuid.UUID, en ...re.RequestEnricher) (uint, any, error) {
+	unit, err := o.Fetch(unitID, en...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	version := uint(0)
+	if unit.Version != nil {
+		version = uint(*unit.Version)
+	}
+	return version, unit, nil
+}
+
+func (o organisationClient) deleteVersion(unitID uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	if err := o.units().DeleteVersion(unitID, version, en...); err != nil {
+		return err
+	}
+	o.config.Log().Debugf("organisation unit %s deleted", unitID)
+	return nil
+}
+
+func (o organisationClient) bodyToUnitData(body io.Reader) (*UnitData, error) {
+	return resource.Client[UnitData]{Config: o.config}.BodyToData(body)
+}
+
+// units returns the generic client of the organisation units collection.
+func (o organisationClient) units() resource.Client[UnitData] {
+	return resource.Client[UnitData]{
+		HTTP:              o.client,
+		Config:            o.config,
+		Url:               unitsUrl,
+		ErrNotFound:       ErrUnitNotFound,
+		ErrInvalidVersion: ErrInvalidUnitVersion,
+	}
+}
@@ -0,0 +1,224 @@
+package organisation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testUnitsUrl       = testBaseUrl + unitsUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type organisationTestSuite struct {
+	suite.Suite
+	mockHttpClient     *mocks.HttpClientMock
+	organisationClient organisationClient
+}
+
+func TestOrganisationTestSuite(t *testing.T) {
+	suite.Run(t, new(organisationTestSuite))
+}
+
+func (s *organisationTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.organisationClient = organisationClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *organisationTestSuite) TestCreateReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"name is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testUnitsUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.organisationClient.Create(UnitAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *organisationTestSuite) TestCreateReturnsHttpClientError() {
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testUnitsUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.organisationClient.Create(UnitAttributes{})
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *organisationTestSuite) TestCreateUnit() {
+	unitID := uuid.New()
+	s.organisationClient.config.UUIDGenerator = func() (uuid.UUID, error) { return unitID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testUnitsUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.organisationClient.Create(UnitAttributes{Name: "Sub-organisation"})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.organisationClient.bodyToUnitData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(unitID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(unitsType, requested.Type)
+	s.Equal("Sub-organisation", requested.Attributes.Name)
+}
+
+func (s *organisationTestSuite) TestListUnits() {
+	firstID, secondID := uuid.New(), uuid.New()
+	body, err := json.Marshal(unitListContainer{Data: []UnitData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testUnitsUrl+"?page[number]=1&page[size]=2")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.organisationClient.List(1, 2)
+	s.NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func (s *organisationTestSuite) TestListReturnsError() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testUnitsUrl+"?page[number]=0&page[size]=10")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusBadGateway, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.organisationClient.List(0, 10)
+
+	s.ErrorIs(actualError, ErrServerError)
+}
+
+func (s *organisationTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.organisationClient.Fetch(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *organisationTestSuite) TestDeleteVersionedUnitReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		expectedError  error
+	}{
+		{
+			name:           "organisation unit not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrUnitNotFound,
+		},
+		{
+			name:           "invalid organisation unit version",
+			responseStatus: http.StatusConflict,
+			expectedError:  ErrInvalidUnitVersion,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+	} {
+		s.Run(test.name, func() {
+			unitID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, fmt.Sprintf("%s/%s?version=1", testUnitsUrl, unitID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody("")}, nil).
+				Once()
+
+			actualError := s.organisationClient.DeleteVersion(unitID, 1)
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *organisationTestSuite) TestDeleteLatestUnitVersion() {
+	unitID := uuid.New()
+	version := int64(3)
+	body, err := json.Marshal(unitContainer{Data: UnitData{ID: unitID.String(), Version: &version}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testUnitsUrl, unitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, fmt.Sprintf("%s/%s?version=3", testUnitsUrl, unitID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+
+	s.NoError(s.organisationClient.Delete(unitID))
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
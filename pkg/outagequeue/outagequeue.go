@@ -0,0 +1,371 @@
+// Package outagequeue provides a bounded in-memory queue which parks mutating operations while the Form3 API is
+// unavailable (503 Service Unavailable) and flushes them automatically when the API is healthy again.
+// This helps batch jobs to survive short outages instead of failing the whole run.
+package outagequeue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"form3interview/pkg/clock"
+	"form3interview/pkg/config"
+	"form3interview/pkg/health"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/retry"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrQueueFull the operation could not be parked because the queue is full
+	ErrQueueFull = errors.New("outage queue is full")
+	// ErrDropped the parked operation was dropped to make room for a newer one
+	ErrDropped = errors.New("operation dropped from outage queue")
+	// ErrParkTimeout the parked operation was not flushed within MaxWait
+	ErrParkTimeout = errors.New("parked operation timed out")
+	// ErrClosed the queue was closed before the operation was run
+	ErrClosed = errors.New("outage queue is closed")
+)
+
+// OverflowPolicy decides what happens when an operation has to be parked but the queue is full.
+type OverflowPolicy int
+
+const (
+	// RejectNew rejects the new operation with ErrQueueFull.
+	RejectNew OverflowPolicy = iota
+	// DropOldest drops the oldest parked operation with ErrDropped to make room for the new one.
+	DropOldest
+)
+
+// Operation is a mutating client call. It must pass the given RequestEnricher to the client call,
+// because the queue uses it to detect when the API is unavailable. The enricher has the same idempotency key
+// every time the operation is run.
+type Operation func(en re.RequestEnricher) error
+
+// HealthCheck returns nil when the API is healthy.
+type HealthCheck func(ctx context.Context) error
+
+// Config of the queue. Zero values are replaced by the defaults.
+type Config struct {
+	// Capacity is the maximum number of parked operations. Default is 100.
+	Capacity int
+	// Overflow is the policy used when the queue is full. Default is RejectNew.
+	Overflow OverflowPolicy
+	// MaxWait is the maximum time an operation stays parked. Default is 5 minutes.
+	MaxWait time.Duration
+	// ProbeInterval is the time between health checks when the API did not send Retry-After. Default is 5 seconds.
+	ProbeInterval time.Duration
+	// Clock measures MaxWait and the time between the health checks. Default is the system clock.
+	Clock clock.Clock
+	// NewKey generates the idempotency keys of the operations, i.e. the generator set by config.WithUUIDGenerator.
+	// Default is random (v4) UUIDs.
+	NewKey func() (uuid.UUID, error)
+}
+
+const (
+	itemParked int32 = iota
+	itemRunning
+	itemAbandoned
+)
+
+type item struct {
+	ctx      context.Context
+	op       Operation
+	key      string
+	state    int32
+	result   chan error
+	reparked chan struct{}
+}
+
+// Queue runs operations and parks them while the API is unavailable.
+type Queue struct {
+	cfg    Config
+	health HealthCheck
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	parked   []*item
+	outage   bool
+	closed   bool
+	retryAt  time.Time
+	flushing sync.WaitGroup
+}
+
+// New creates a queue which uses health to probe the API during an outage.
+func New(health HealthCheck, cfg Config) *Queue {
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = 100
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = 5 * time.Minute
+	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = 5 * time.Second
+	}
+	cfg.Clock = clock.OrSystem(cfg.Clock)
+	if cfg.NewKey == nil {
+		cfg.NewKey = uuid.NewRandom
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Queue{cfg: cfg, health: health, ctx: ctx, cancel: cancel}
+}
+
+// HealthEndpoint is a HealthCheck calling the health endpoint of the API configured by the options (and env vars)
+// with the health client (see health.NewClient). It returns the error of NewClient if the options are invalid.
+func HealthEndpoint(options ...config.Option) HealthCheck {
+	client, err := health.NewClient(options...)
+	return func(ctx context.Context) error {
+		if err != nil {
+			return err
+		}
+		_, err := client.HealthCheck(ctx)
+		return err
+	}
+}
+
+// Close stops probing the API and flushing the parked operations, and waits until the operation being flushed
+// (if any) completes. The parked operations and the operations run after Close fail with ErrClosed.
+func (q *Queue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	q.cancel()
+	q.flushing.Wait()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, it := range q.parked {
+		if atomic.CompareAndSwapInt32(&it.state, itemParked, itemAbandoned) {
+			it.result <- ErrClosed
+		}
+	}
+	q.parked = nil
+	q.outage = false
+}
+
+// Parked returns the number of parked operations.
+func (q *Queue) Parked() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.parked)
+}
+
+// Do runs the operation and returns it's result.
+// When the API responds with 503 Service Unavailable, or an outage is already in progress, the operation is parked
+// and Do blocks until the operation is flushed, MaxWait elapses or the context is cancelled.
+// An operation which was already started by the flush is always waited for, so it's result is never lost.
+func (q *Queue) Do(ctx context.Context, op Operation) error {
+	id, err := q.cfg.NewKey()
+	if err != nil {
+		return err
+	}
+	key := id.String()
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrClosed
+	}
+	if q.outage {
+		it, err := q.park(ctx, op, key)
+		q.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		return q.wait(ctx, it)
+	}
+	q.mu.Unlock()
+
+	unavailable, retryAfter, err := run(ctx, op, key)
+	if !unavailable {
+		return err
+	}
+
+	q.mu.Lock()
+	it, parkErr := q.park(ctx, op, key)
+	q.startOutage(retryAfter)
+	q.mu.Unlock()
+	if parkErr != nil {
+		return parkErr
+	}
+	return q.wait(ctx, it)
+}
+
+func (q *Queue) park(ctx context.Context, op Operation, key string) (*item, error) {
+	if q.closed {
+		return nil, ErrClosed
+	}
+	if len(q.parked) >= q.cfg.Capacity {
+		if q.cfg.Overflow == RejectNew {
+			return nil, ErrQueueFull
+		}
+
+		oldest := q.parked[0]
+		if atomic.CompareAndSwapInt32(&oldest.state, itemParked, itemAbandoned) {
+			oldest.result <- ErrDropped
+			q.parked = q.parked[1:]
+		} else if len(q.parked) >= q.cfg.Capacity {
+			return nil, ErrQueueFull
+		}
+	}
+
+	it := &item{ctx: ctx, op: op, key: key, result: make(chan error, 1), reparked: make(chan struct{}, 1)}
+	q.parked = append(q.parked, it)
+	return it, nil
+}
+
+func (q *Queue) wait(ctx context.Context, it *item) error {
+	timer := q.cfg.Clock.NewTimer(q.cfg.MaxWait)
+	defer timer.Stop()
+
+	var giveUpErr error
+	select {
+	case err := <-it.result:
+		return err
+	case <-timer.C():
+		giveUpErr = ErrParkTimeout
+	case <-ctx.Done():
+		giveUpErr = ctx.Err()
+	}
+
+	for {
+		if atomic.CompareAndSwapInt32(&it.state, itemParked, itemAbandoned) {
+			return giveUpErr
+		}
+		select {
+		case err := <-it.result:
+			return err
+		case <-it.reparked:
+		}
+	}
+}
+
+func (q *Queue) startOutage(retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = q.cfg.ProbeInterval
+	}
+	if retryAt := q.cfg.Clock.Now().Add(retryAfter); retryAt.After(q.retryAt) {
+		q.retryAt = retryAt
+	}
+
+	if !q.outage && !q.closed {
+		q.outage = true
+		q.flushing.Add(1)
+		go q.flush()
+	}
+}
+
+// flush probes the API and flushes the parked operations when it's healthy. It stops when there are no parked
+// operations left (i.e. all of them timed out) or the queue is closed.
+func (q *Queue) flush() {
+	defer q.flushing.Done()
+	for q.pending() {
+		if !q.waitForRetry() {
+			return
+		}
+		ctx, cancel := context.WithTimeout(q.ctx, q.cfg.ProbeInterval)
+		err := q.health(ctx)
+		cancel()
+		if err != nil {
+			q.mu.Lock()
+			q.retryAt = q.cfg.Clock.Now().Add(q.cfg.ProbeInterval)
+			q.mu.Unlock()
+			continue
+		}
+
+		if q.flushParked() {
+			return
+		}
+	}
+}
+
+// pending removes the abandoned operations and tells if there are parked operations left. The outage is over
+// when there are none.
+func (q *Queue) pending() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	parked := q.parked[:0]
+	for _, it := range q.parked {
+		if atomic.LoadInt32(&it.state) != itemAbandoned {
+			parked = append(parked, it)
+		}
+	}
+	q.parked = parked
+	if len(q.parked) == 0 {
+		q.outage = false
+	}
+	return q.outage
+}
+
+// flushParked runs the parked operations in order and tells if all of them were flushed or the queue was closed.
+func (q *Queue) flushParked() bool {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return true
+		}
+		if len(q.parked) == 0 {
+			q.outage = false
+			q.mu.Unlock()
+			return true
+		}
+		it := q.parked[0]
+		if !atomic.CompareAndSwapInt32(&it.state, itemParked, itemRunning) {
+			q.parked = q.parked[1:]
+			q.mu.Unlock()
+			continue
+		}
+		q.mu.Unlock()
+
+		unavailable, retryAfter, err := run(it.ctx, it.op, it.key)
+		if unavailable {
+			atomic.StoreInt32(&it.state, itemParked)
+			select {
+			case it.reparked <- struct{}{}:
+			default:
+			}
+			q.mu.Lock()
+			q.startOutage(retryAfter)
+			q.mu.Unlock()
+			return false
+		}
+
+		q.mu.Lock()
+		q.parked = q.parked[1:]
+		q.mu.Unlock()
+		it.result <- err
+	}
+}
+
+// waitForRetry waits until the next health check is due. It returns false if the queue was closed meanwhile.
+func (q *Queue) waitForRetry() bool {
+	q.mu.Lock()
+	wait := q.retryAt.Sub(q.cfg.Clock.Now())
+	q.mu.Unlock()
+	if wait > 0 {
+		return clock.Sleep(q.ctx, q.cfg.Clock, wait) == nil
+	}
+	return q.ctx.Err() == nil
+}
+
+// run runs the operation with the idempotency key of the operation, so it's the same when it's flushed.
+func run(ctx context.Context, op Operation, key string) (bool, time.Duration, error) {
+	var unavailable bool
+	var retryAfter time.Duration
+	err := op(re.RequestEnricher{
+		Ctx:            ctx,
+		IdempotencyKey: key,
+		AfterHook: func(resp *http.Response) {
+			unavailable = resp.StatusCode == http.StatusServiceUnavailable
+			retryAfter = retry.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		},
+	})
+	return unavailable, retryAfter, err
+}
@@ -0,0 +1,316 @@
+package outagequeue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/internal/resource"
+	"form3interview/pkg/clock/clocktest"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+var errUnavailable = errors.New("server unavailable")
+
+type outageQueueTestSuite struct {
+	suite.Suite
+	unavailable int32
+	executions  int32
+}
+
+func TestOutageQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(outageQueueTestSuite))
+}
+
+func (s *outageQueueTestSuite) SetupTest() {
+	atomic.StoreInt32(&s.unavailable, 0)
+	atomic.StoreInt32(&s.executions, 0)
+}
+
+func (s *outageQueueTestSuite) TestDoRunsOperation_WhenApiIsAvailable() {
+	q := s.newQueue(Config{})
+
+	s.NoError(q.Do(context.Background(), s.operation))
+
+	s.Equal(int32(1), atomic.LoadInt32(&s.executions))
+	s.Zero(q.Parked())
+}
+
+func (s *outageQueueTestSuite) TestDoReturnsOperationError() {
+	q := s.newQueue(Config{})
+	expectedError := errors.New("invalid request")
+
+	err := q.Do(context.Background(), func(en re.RequestEnricher) error {
+		en.AfterHook(&http.Response{StatusCode: http.StatusBadRequest})
+		return expectedError
+	})
+
+	s.ErrorIs(err, expectedError)
+	s.Zero(q.Parked())
+}
+
+func (s *outageQueueTestSuite) TestDoParksOperationUntilApiRecovers() {
+	q := s.newQueue(Config{})
+	s.setUnavailable(true)
+
+	result := make(chan error)
+	go func() { result <- q.Do(context.Background(), s.operation) }()
+	s.Eventually(func() bool { return q.Parked() == 1 }, time.Second, time.Millisecond)
+
+	s.setUnavailable(false)
+
+	s.NoError(<-result)
+	s.Equal(int32(2), atomic.LoadInt32(&s.executions))
+	s.Zero(q.Parked())
+}
+
+func (s *outageQueueTestSuite) TestDoReusesIdempotencyKey_WhenOperationIsFlushed() {
+	q := s.newQueue(Config{})
+	s.setUnavailable(true)
+	var mu sync.Mutex
+	var keys []string
+
+	result := make(chan error)
+	go func() {
+		result <- q.Do(context.Background(), func(en re.RequestEnricher) error {
+			mu.Lock()
+			keys = append(keys, en.IdempotencyKey)
+			mu.Unlock()
+			return s.operation(en)
+		})
+	}()
+	s.Eventually(func() bool { return q.Parked() == 1 }, time.Second, time.Millisecond)
+
+	s.setUnavailable(false)
+
+	s.NoError(<-result)
+	s.Require().Len(keys, 2)
+	s.NotEmpty(keys[0])
+	s.Equal(keys[0], keys[1])
+}
+
+func (s *outageQueueTestSuite) TestDoParksOperationsDuringOutageInOrder() {
+	q := s.newQueue(Config{})
+	s.setUnavailable(true)
+
+	var mu sync.Mutex
+	var flushed []int
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.NoError(q.Do(context.Background(), func(en re.RequestEnricher) error {
+				if err := s.operation(en); err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				flushed = append(flushed, i)
+				return nil
+			}))
+		}(i)
+		s.Eventually(func() bool { return q.Parked() == i+1 }, time.Second, time.Millisecond)
+	}
+
+	s.Equal(int32(1), atomic.LoadInt32(&s.executions))
+	s.setUnavailable(false)
+	wg.Wait()
+
+	s.Equal([]int{0, 1, 2}, flushed)
+}
+
+func (s *outageQueueTestSuite) TestDoRejectsNewOperation_WhenQueueIsFull() {
+	q := s.newQueue(Config{Capacity: 1, Overflow: RejectNew})
+	s.setUnavailable(true)
+	go func() { _ = q.Do(context.Background(), s.operation) }()
+	s.Eventually(func() bool { return q.Parked() == 1 }, time.Second, time.Millisecond)
+
+	s.ErrorIs(q.Do(context.Background(), s.operation), ErrQueueFull)
+	s.setUnavailable(false)
+}
+
+func (s *outageQueueTestSuite) TestDoDropsOldestOperation_WhenQueueIsFull() {
+	q := s.newQueue(Config{Capacity: 1, Overflow: DropOldest})
+	s.setUnavailable(true)
+	oldest := make(chan error)
+	go func() { oldest <- q.Do(context.Background(), s.operation) }()
+	s.Eventually(func() bool { return q.Parked() == 1 }, time.Second, time.Millisecond)
+
+	newest := make(chan error)
+	go func() { newest <- q.Do(context.Background(), s.operation) }()
+
+	s.ErrorIs(<-oldest, ErrDropped)
+	s.setUnavailable(false)
+	s.NoError(<-newest)
+}
+
+func (s *outageQueueTestSuite) TestDoReturnsError_WhenMaxWaitElapsed() {
+	q := s.newQueue(Config{MaxWait: 20 * time.Millisecond})
+	s.setUnavailable(true)
+
+	s.ErrorIs(q.Do(context.Background(), s.operation), ErrParkTimeout)
+	s.Equal(int32(1), atomic.LoadInt32(&s.executions))
+}
+
+func (s *outageQueueTestSuite) TestDoMeasuresMaxWaitOnTheClock() {
+	clock := clocktest.NewFake(time.Date(2022, 10, 24, 1, 0, 0, 0, time.UTC))
+	q := s.newQueue(Config{MaxWait: time.Minute, Clock: clock})
+	s.setUnavailable(true)
+
+	result := make(chan error)
+	go func() { result <- q.Do(context.Background(), s.operation) }()
+	clock.BlockUntil(2)
+	clock.Advance(time.Minute)
+
+	s.ErrorIs(<-result, ErrParkTimeout)
+}
+
+func (s *outageQueueTestSuite) TestDoUsesGeneratedIdempotencyKey() {
+	expectedKey := uuid.New()
+	q := s.newQueue(Config{NewKey: func() (uuid.UUID, error) { return expectedKey, nil }})
+	var key string
+
+	s.NoError(q.Do(context.Background(), func(en re.RequestEnricher) error {
+		key = en.IdempotencyKey
+		return s.operation(en)
+	}))
+
+	s.Equal(expectedKey.String(), key)
+}
+
+func (s *outageQueueTestSuite) TestDoReturnsError_WhenKeyCantBeGenerated() {
+	expectedError := errors.New("no entropy")
+	q := s.newQueue(Config{NewKey: func() (uuid.UUID, error) { return uuid.Nil, expectedError }})
+
+	s.ErrorIs(q.Do(context.Background(), s.operation), expectedError)
+	s.Zero(atomic.LoadInt32(&s.executions))
+}
+
+func (s *outageQueueTestSuite) TestDoReturnsError_WhenContextIsCancelled() {
+	q := s.newQueue(Config{})
+	s.setUnavailable(true)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	s.ErrorIs(q.Do(ctx, s.operation), context.DeadlineExceeded)
+}
+
+func (s *outageQueueTestSuite) TestOutageEnds_WhenParkedOperationsAreAbandoned() {
+	q := s.newQueue(Config{MaxWait: 20 * time.Millisecond})
+	s.setUnavailable(true)
+
+	s.ErrorIs(q.Do(context.Background(), s.operation), ErrParkTimeout)
+
+	s.Eventually(func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return !q.outage
+	}, time.Second, time.Millisecond)
+	q.flushing.Wait()
+	s.Zero(q.Parked())
+}
+
+func (s *outageQueueTestSuite) TestCloseFailsParkedOperations() {
+	q := s.newQueue(Config{})
+	s.setUnavailable(true)
+	result := make(chan error)
+	go func() { result <- q.Do(context.Background(), s.operation) }()
+	s.Eventually(func() bool { return q.Parked() == 1 }, time.Second, time.Millisecond)
+
+	q.Close()
+
+	s.ErrorIs(<-result, ErrClosed)
+	s.ErrorIs(q.Do(context.Background(), s.operation), ErrClosed)
+	s.Zero(q.Parked())
+	s.Equal(int32(1), atomic.LoadInt32(&s.executions))
+}
+
+func (s *outageQueueTestSuite) TestDoProbesApiAfterRetryAfterOfResponse() {
+	clock := clocktest.NewFake(time.Date(2022, 10, 24, 1, 0, 0, 0, time.UTC))
+	var probes int32
+	q := New(func(context.Context) error {
+		atomic.AddInt32(&probes, 1)
+		return nil
+	}, Config{MaxWait: time.Hour, ProbeInterval: time.Second, Clock: clock})
+	defer q.Close()
+
+	result := make(chan error)
+	unavailable := true
+	go func() {
+		result <- q.Do(context.Background(), func(en re.RequestEnricher) error {
+			if unavailable {
+				unavailable = false
+				en.AfterHook(&http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"120"}}})
+				return errUnavailable
+			}
+			en.AfterHook(&http.Response{StatusCode: http.StatusCreated, Header: http.Header{}})
+			return nil
+		})
+	}()
+	clock.BlockUntil(2)
+	clock.Advance(119 * time.Second)
+	s.Zero(atomic.LoadInt32(&probes))
+
+	clock.Advance(time.Second)
+
+	s.NoError(<-result)
+	s.Equal(int32(1), atomic.LoadInt32(&probes))
+}
+
+func (s *outageQueueTestSuite) TestHealthEndpoint() {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/v1/health", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	health := HealthEndpoint(config.WithBaseUrl(server.URL + "/v1"))
+
+	s.Error(health(context.Background()))
+	status = http.StatusOK
+	s.NoError(health(context.Background()))
+}
+
+func (s *outageQueueTestSuite) TestHealthEndpointReturnsError_WhenBaseUrlNotConfigured() {
+	health := HealthEndpoint()
+
+	s.ErrorIs(health(context.Background()), resource.ErrBaseUrlNotConfigured)
+}
+
+func (s *outageQueueTestSuite) newQueue(cfg Config) *Queue {
+	cfg.ProbeInterval = time.Millisecond
+	return New(func(context.Context) error {
+		if atomic.LoadInt32(&s.unavailable) == 1 {
+			return errUnavailable
+		}
+		return nil
+	}, cfg)
+}
+
+func (s *outageQueueTestSuite) setUnavailable(unavailable bool) {
+	value := int32(0)
+	if unavailable {
+		value = 1
+	}
+	atomic.StoreInt32(&s.unavailable, value)
+}
+
+func (s *outageQueueTestSuite) operation(en re.RequestEnricher) error {
+	atomic.AddInt32(&s.executions, 1)
+	if atomic.LoadInt32(&s.unavailable) == 1 {
+		en.AfterHook(&http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}})
+		return errUnavailable
+	}
+	en.AfterHook(&http.Response{StatusCode: http.StatusCreated, Header: http.Header{}})
+	return nil
+}
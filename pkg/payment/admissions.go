@@ -0,0 +1,65 @@
+package payment
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	"form3interview/internal/resource"
+	re "form3interview/pkg/requestenricher"
+)
+
+// FetchAdmission fetches the admission of an inbound payment by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-admissions/fetch-a-payment-admission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchAdmission(paymentID, admissionID uuid.UUID, en ...re.RequestEnricher) (*AdmissionData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[AdmissionData](p, admissionsUrl(paymentID), ErrAdmissionNotFound).Fetch(admissionID, en...)
+}
+
+// ListAdmissions lists the admissions of an inbound payment page by page. Page numbers start from 0.
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) ListAdmissions(paymentID uuid.UUID, pageNumber, pageSize uint, en ...re.RequestEnricher) ([]AdmissionData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[AdmissionData](p, admissionsUrl(paymentID), ErrPaymentNotFound).List(pageNumber, pageSize, en...)
+}
+
+// FetchReturnAdmission fetches the admission of an inbound payment return by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-returns/fetch-a-return-admission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchReturnAdmission(paymentID, returnID, admissionID uuid.UUID, en ...re.RequestEnricher) (*AdmissionData, error) {
+	if paymentID == uuid.Nil || returnID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[AdmissionData](p, returnAdmissionsUrl(paymentID, returnID), ErrAdmissionNotFound).Fetch(admissionID, en...)
+}
+
+// ListReturnAdmissions lists the admissions of an inbound payment return page by page. Page numbers start from 0.
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) ListReturnAdmissions(paymentID, returnID uuid.UUID, pageNumber, pageSize uint, en ...re.RequestEnricher) ([]AdmissionData, error) {
+	if paymentID == uuid.Nil || returnID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[AdmissionData](p, returnAdmissionsUrl(paymentID, returnID), ErrReturnNotFound).List(pageNumber, pageSize, en...)
+}
+
+func (p paymentClient) bodyToAdmissionData(body io.Reader) (*AdmissionData, error) {
+	return resource.Client[AdmissionData]{Config: p.config}.BodyToData(body)
+}
+
+func admissionsUrl(paymentID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/admissions", paymentsUrl, paymentID)
+}
+
+func returnAdmissionsUrl(paymentID, returnID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/admissions", returnsUrl(paymentID), returnID)
+}
@@ -0,0 +1,101 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *paymentTestSuite) TestFetchAdmissionReturnsError() {
+	_, actualError := s.paymentClient.FetchAdmission(uuid.New(), uuid.Nil)
+	s.ErrorIs(actualError, ErrNilUUID)
+
+	paymentID, admissionID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/admissions/%s", testPaymentsUrl, paymentID, admissionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError = s.paymentClient.FetchAdmission(paymentID, admissionID)
+	s.ErrorIs(actualError, ErrAdmissionNotFound)
+}
+
+func (s *paymentTestSuite) TestFetchAdmission() {
+	paymentID, admissionID := uuid.New(), uuid.New()
+	body, err := json.Marshal(admissionContainer{Data: AdmissionData{
+		ID:         admissionID.String(),
+		Attributes: &AdmissionAttributes{Status: AdmissionStatusConfirmed},
+	}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/admissions/%s", testPaymentsUrl, paymentID, admissionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.paymentClient.FetchAdmission(paymentID, admissionID)
+	s.Require().NoError(err)
+	s.Equal(admissionID.String(), actual.ID)
+	s.Equal(AdmissionStatusConfirmed, actual.Attributes.Status)
+}
+
+func (s *paymentTestSuite) TestListAdmissionsReturnsError_WhenPaymentNotFound() {
+	paymentID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/admissions?page[number]=0&page[size]=10", testPaymentsUrl, paymentID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.paymentClient.ListAdmissions(paymentID, 0, 10)
+
+	s.ErrorIs(actualError, ErrPaymentNotFound)
+}
+
+func (s *paymentTestSuite) TestListAdmissions() {
+	paymentID, firstID, secondID := uuid.New(), uuid.New(), uuid.New()
+	body, err := json.Marshal(admissionListContainer{Data: []AdmissionData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/admissions?page[number]=1&page[size]=2", testPaymentsUrl, paymentID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.paymentClient.ListAdmissions(paymentID, 1, 2)
+	s.Require().NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func (s *paymentTestSuite) TestFetchReturnAdmission() {
+	paymentID, returnID, admissionID := uuid.New(), uuid.New(), uuid.New()
+	body, err := json.Marshal(admissionContainer{Data: AdmissionData{ID: admissionID.String()}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/returns/%s/admissions/%s", testPaymentsUrl, paymentID, returnID, admissionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.paymentClient.FetchReturnAdmission(paymentID, returnID, admissionID)
+	s.Require().NoError(err)
+	s.Equal(admissionID.String(), actual.ID)
+}
+
+func (s *paymentTestSuite) TestListReturnAdmissions() {
+	_, actualError := s.paymentClient.ListReturnAdmissions(uuid.New(), uuid.Nil, 0, 10)
+	s.ErrorIs(actualError, ErrNilUUID)
+
+	paymentID, returnID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/returns/%s/admissions?page[number]=0&page[size]=10", testPaymentsUrl, paymentID, returnID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError = s.paymentClient.ListReturnAdmissions(paymentID, returnID, 0, 10)
+	s.ErrorIs(actualError, ErrReturnNotFound)
+}
@@ -0,0 +1,204 @@
+package payment
+
+import "form3interview/internal/resource"
+
+// submissionContainer is a simple container for the "data" JSON field.
+type submissionContainer = resource.DataContainer[SubmissionData]
+
+// returnContainer is a simple container for the "data" JSON field.
+type returnContainer = resource.DataContainer[ReturnData]
+
+// recallContainer is a simple container for the "data" JSON field.
+type recallContainer = resource.DataContainer[RecallData]
+
+// recallDecisionContainer is a simple container for the "data" JSON field.
+type recallDecisionContainer = resource.DataContainer[RecallDecisionData]
+
+// admissionContainer is a simple container for the "data" JSON field.
+type admissionContainer = resource.DataContainer[AdmissionData]
+
+// admissionListContainer is a simple container for the "data" JSON field of list responses.
+type admissionListContainer = resource.ListContainer[AdmissionData]
+
+// SubmissionData represents a payment, return or recall submission.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-submissions for
+// more information about fields.
+type SubmissionData struct {
+	Attributes     *SubmissionAttributes `json:"attributes,omitempty"`
+	ID             string                `json:"id,omitempty"`
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	Version        *int64                `json:"version,omitempty"`
+}
+
+type SubmissionAttributes struct {
+	Status             string `json:"status,omitempty"`
+	StatusReason       string `json:"status_reason,omitempty"`
+	SchemeStatusCode   string `json:"scheme_status_code,omitempty"`
+	SubmissionDatetime string `json:"submission_datetime,omitempty"`
+}
+
+// Statuses of a payment submission.
+const (
+	SubmissionStatusAccepted          = "accepted"
+	SubmissionStatusValidationPending = "validation_pending"
+	SubmissionStatusValidationPassed  = "validation_passed"
+	SubmissionStatusValidationFailed  = "validation_failed"
+	SubmissionStatusLimitCheckPending = "limit_check_pending"
+	SubmissionStatusLimitCheckPassed  = "limit_check_passed"
+	SubmissionStatusLimitCheckFailed  = "limit_check_failed"
+	SubmissionStatusReleasedToGateway = "released_to_gateway"
+	SubmissionStatusQueuedForDelivery = "queued_for_delivery"
+	SubmissionStatusSubmitted         = "submitted"
+	SubmissionStatusDeliveryConfirmed = "delivery_confirmed"
+	SubmissionStatusDeliveryFailed    = "delivery_failed"
+)
+
+// IsTerminal tells if the submission reached a status which will not change anymore.
+func (s SubmissionData) IsTerminal() bool {
+	if s.Attributes == nil {
+		return false
+	}
+
+	switch s.Attributes.Status {
+	case SubmissionStatusValidationFailed,
+		SubmissionStatusLimitCheckFailed,
+		SubmissionStatusDeliveryConfirmed,
+		SubmissionStatusDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// ReturnData represents the return of a received payment.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-returns for
+// more information about fields.
+type ReturnData struct {
+	Attributes     *ReturnAttributes `json:"attributes,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	OrganisationID string            `json:"organisation_id,omitempty"`
+	Type           string            `json:"type,omitempty"`
+	Version        *int64            `json:"version,omitempty"`
+}
+
+type ReturnAttributes struct {
+	Amount     string     `json:"amount,omitempty"`
+	Currency   string     `json:"currency,omitempty"`
+	ReturnCode ReturnCode `json:"return_code,omitempty"`
+}
+
+// ReturnCode is the reason of a payment return.
+type ReturnCode string
+
+// Reason codes of payment returns.
+const (
+	ReturnCodeIncorrectAccountNumber ReturnCode = "AC01"
+	ReturnCodeClosedAccountNumber    ReturnCode = "AC04"
+	ReturnCodeBlockedAccount         ReturnCode = "AC06"
+	ReturnCodeTransactionForbidden   ReturnCode = "AG01"
+	ReturnCodeInvalidBankOperation   ReturnCode = "AG02"
+	ReturnCodeInvalidCurrency        ReturnCode = "AM03"
+	ReturnCodeInsufficientFunds      ReturnCode = "AM04"
+	ReturnCodeDuplication            ReturnCode = "AM05"
+	ReturnCodeMissingCreditorAddress ReturnCode = "BE04"
+	ReturnCodeEndCustomerDeceased    ReturnCode = "MD07"
+	ReturnCodeNotSpecifiedByCustomer ReturnCode = "MS02"
+	ReturnCodeNotSpecifiedByAgent    ReturnCode = "MS03"
+	ReturnCodeInvalidBankIdentifier  ReturnCode = "RC01"
+	ReturnCodeRegulatoryReason       ReturnCode = "RR04"
+)
+
+// RecallData represents the recall of a sent payment.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls for
+// more information about fields.
+type RecallData struct {
+	Attributes     *RecallAttributes `json:"attributes,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	OrganisationID string            `json:"organisation_id,omitempty"`
+	Type           string            `json:"type,omitempty"`
+	Version        *int64            `json:"version,omitempty"`
+}
+
+type RecallAttributes struct {
+	Reason      RecallReason `json:"reason,omitempty"`
+	Description string       `json:"description,omitempty"`
+}
+
+// RecallReason is the reason of a payment recall.
+type RecallReason string
+
+// Reason codes of payment recalls.
+const (
+	RecallReasonDuplicate              RecallReason = "DUPL"
+	RecallReasonTechnicalProblem       RecallReason = "TECH"
+	RecallReasonFraud                  RecallReason = "FRAD"
+	RecallReasonRequestedByCustomer    RecallReason = "CUST"
+	RecallReasonInvalidCreditorAccount RecallReason = "AC03"
+	RecallReasonWrongAmount            RecallReason = "AM09"
+)
+
+// RecallDecisionData represents the answer given to a received payment recall.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls for
+// more information about fields.
+type RecallDecisionData struct {
+	Attributes     *RecallDecisionAttributes `json:"attributes,omitempty"`
+	ID             string                    `json:"id,omitempty"`
+	OrganisationID string                    `json:"organisation_id,omitempty"`
+	Type           string                    `json:"type,omitempty"`
+	Version        *int64                    `json:"version,omitempty"`
+}
+
+type RecallDecisionAttributes struct {
+	Answer       RecallAnswer       `json:"answer,omitempty"`
+	RejectReason RecallRejectReason `json:"reject_reason,omitempty"`
+}
+
+// RecallAnswer is the decision made about a payment recall.
+type RecallAnswer string
+
+// Answers of a payment recall decision.
+const (
+	RecallAnswerAccepted RecallAnswer = "accepted"
+	RecallAnswerRejected RecallAnswer = "rejected"
+)
+
+// RecallRejectReason is the reason of rejecting a payment recall.
+type RecallRejectReason string
+
+// Reason codes of rejected payment recalls.
+const (
+	RecallRejectReasonClosedAccountNumber   RecallRejectReason = "AC04"
+	RecallRejectReasonInsufficientFunds     RecallRejectReason = "AM04"
+	RecallRejectReasonAlreadyReturned       RecallRejectReason = "ARDT"
+	RecallRejectReasonCustomerDecision      RecallRejectReason = "CUST"
+	RecallRejectReasonLegalDecision         RecallRejectReason = "LEGL"
+	RecallRejectReasonNoAnswerFromCustomer  RecallRejectReason = "NOAS"
+	RecallRejectReasonNoOriginalTransaction RecallRejectReason = "NOOR"
+)
+
+// AdmissionData represents the admission of an inbound payment or return, which tells how it was processed by Form3.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-admissions for
+// more information about fields.
+type AdmissionData struct {
+	Attributes     *AdmissionAttributes `json:"attributes,omitempty"`
+	ID             string               `json:"id,omitempty"`
+	OrganisationID string               `json:"organisation_id,omitempty"`
+	Type           string               `json:"type,omitempty"`
+	Version        *int64               `json:"version,omitempty"`
+}
+
+type AdmissionAttributes struct {
+	AdmissionDatetime string `json:"admission_datetime,omitempty"`
+	SchemeStatusCode  string `json:"scheme_status_code,omitempty"`
+	SettlementCycle   int    `json:"settlement_cycle,omitempty"`
+	SettlementDate    string `json:"settlement_date,omitempty"`
+	SourceGateway     string `json:"source_gateway,omitempty"`
+	Status            string `json:"status,omitempty"`
+	StatusReason      string `json:"status_reason,omitempty"`
+}
+
+// Statuses of an admission.
+const (
+	AdmissionStatusConfirmed = "confirmed"
+	AdmissionStatusFailed    = "failed"
+)
@@ -0,0 +1,237 @@
+// Package payment provides Form3 client to manage payments.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+	"form3interview/pkg/result"
+)
+
+const (
+	paymentsUrl           = "/transaction/payments"
+	submissionsType       = "payment_submissions"
+	returnsType           = "returns"
+	returnSubmissionsType = "return_submissions"
+	recallsType           = "recalls"
+	recallSubmissionsType = "recall_submissions"
+	recallDecisionsType   = "recall_decisions"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrPaymentNotFound payment not found
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrReturnNotFound payment return not found
+	ErrReturnNotFound = errors.New("payment return not found")
+	// ErrRecallNotFound payment recall not found
+	ErrRecallNotFound = errors.New("payment recall not found")
+	// ErrRecallDecisionNotFound payment recall decision not found
+	ErrRecallDecisionNotFound = errors.New("payment recall decision not found")
+	// ErrAdmissionNotFound payment or return admission not found
+	ErrAdmissionNotFound = errors.New("admission not found")
+	// ErrSubmissionNotFound payment, return or recall submission not found
+	ErrSubmissionNotFound = errors.New("submission not found")
+	// ErrSubmissionTimeout payment submission did not reach a terminal status within the poll timeout
+	ErrSubmissionTimeout = errors.New("payment submission timed out")
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type paymentClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 payments created by NewClient or the form3 facade.
+type Client = paymentClient
+
+// NewClient creates a client for managing Form3 payments.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*paymentClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &paymentClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (p paymentClient) Close() error {
+	return resource.Close(p.client)
+}
+
+// CreateSubmission submits a payment for processing.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-submissions/create-a-payment-submission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) CreateSubmission(paymentID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := p.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	submission := SubmissionData{
+		ID:             newID.String(),
+		OrganisationID: p.config.OrganisationID.String(),
+		Type:           submissionsType,
+	}
+
+	created, err := collection[SubmissionData](p, submissionsUrl(paymentID), ErrPaymentNotFound).Create(newID, submission, en...)
+	if err != nil {
+		return nil, err
+	}
+	p.config.Log().Debugf("payment %s submission %s created", paymentID, submission.ID)
+	return created, nil
+}
+
+// FetchSubmission fetches a payment submission by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-submissions/fetch-a-payment-submission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchSubmission(paymentID, submissionID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[SubmissionData](p, submissionsUrl(paymentID), ErrSubmissionNotFound).Fetch(submissionID, en...)
+}
+
+// SubmitAndWait is a convenience function to submit a payment and wait until the submission reaches a terminal status.
+//
+// Under the hood it creates a submission and polls it in every PollInterval until it completes or the PollTimeout elapses
+// (ErrSubmissionTimeout). Both are measured on the clock of the config, the request in flight is cancelled when the
+// PollTimeout elapses.
+// The given context is used for all the requests, so cancelling it stops the polling with the error of the context.
+// The requests can be enriched by RequestEnricher
+func (p paymentClient) SubmitAndWait(ctx context.Context, paymentID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	clk := p.config.ClockOrSystem()
+	pollCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	timeout := clk.NewTimer(*p.config.PollTimeout)
+	defer timeout.Stop()
+	var timedOut atomic.Bool
+	go func() {
+		select {
+		case <-timeout.C():
+			timedOut.Store(true)
+			cancel()
+		case <-pollCtx.Done():
+		}
+	}()
+	en = withContext(pollCtx, en)
+
+	// the poll timeout may elapse while a request is in flight
+	failed := func(err error) (*SubmissionData, error) {
+		if timedOut.Load() {
+			return nil, result.Wrap(ErrSubmissionTimeout, err)
+		}
+		return nil, err
+	}
+
+	submission, err := p.CreateSubmission(paymentID, en...)
+	if err != nil {
+		return failed(err)
+	}
+
+	submissionID, err := uuid.Parse(submission.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := clk.NewTicker(*p.config.PollInterval)
+	defer ticker.Stop()
+	for !submission.IsTerminal() {
+		select {
+		case <-pollCtx.Done():
+			if timedOut.Load() {
+				return nil, ErrSubmissionTimeout
+			}
+			return nil, ctx.Err()
+		case <-ticker.C():
+		}
+
+		if submission, err = p.FetchSubmission(paymentID, submissionID, en...); err != nil {
+			return failed(err)
+		}
+	}
+
+	p.config.Log().Debugf("payment %s submission %s completed with status %s", paymentID, submissionID, submission.Attributes.Status)
+	return submission, nil
+}
+
+func submissionsUrl(paymentID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/submissions", paymentsUrl, paymentID)
+}
+
+func withContext(ctx context.Context, en []re.RequestEnricher) []re.RequestEnricher {
+	enricher := re.Chain(en...)
+	enricher.Ctx = ctx
+	return []re.RequestEnricher{enricher}
+}
+
+func (p paymentClient) bodyToSubmissionData(body io.Reader) (*SubmissionData, error) {
+	return resource.Client[SubmissionData]{Config: p.config}.BodyToData(body)
+}
+
+// collection returns the generic client of a sub-collection of the payments. The not found responses are mapped to
+// errNotFound, so the missing parent and the missing resource can be told apart.
+func collection[T any](p paymentClient, path string, errNotFound error) resource.Client[T] {
+	return resource.Client[T]{
+		HTTP:        p.client,
+		Config:      p.config,
+		Url:         path,
+		ErrNotFound: errNotFound,
+	}
+}
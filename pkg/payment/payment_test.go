@@ -0,0 +1,402 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"form3interview/pkg/clock/clocktest"
+	"form3interview/pkg/requestenricher"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testPaymentsUrl    = testBaseUrl + paymentsUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type paymentTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	paymentClient  paymentClient
+}
+
+func TestPaymentTestSuite(t *testing.T) {
+	suite.Run(t, new(paymentTestSuite))
+}
+
+func (s *paymentTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	pollInterval := time.Millisecond
+	pollTimeout := time.Second
+	s.paymentClient = paymentClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+			PollInterval:   &pollInterval,
+			PollTimeout:    &pollTimeout,
+		},
+	}
+}
+
+func (s *paymentTestSuite) TestCreateSubmissionReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.paymentClient.CreateSubmission(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *paymentTestSuite) TestCreateSubmissionReturnsError() {
+	for _, test := range []struct {
+		name           string
+		paymentID      uuid.UUID
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "payment not found",
+			paymentID:      uuid.New(),
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrPaymentNotFound,
+		},
+		{
+			name:           "invalid request",
+			paymentID:      uuid.New(),
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"payment already submitted\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server error",
+			paymentID:      uuid.New(),
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"error_message\": \"backend error\"}",
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "rate limited",
+			paymentID:      uuid.New(),
+			responseStatus: http.StatusTooManyRequests,
+			expectedError:  ErrRateLimited,
+		},
+		{
+			name:           "server unavailable",
+			paymentID:      uuid.New(),
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			paymentID:      uuid.New(),
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(postSubmissionRequestMatcher(test.paymentID)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.paymentClient.CreateSubmission(test.paymentID)
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *paymentTestSuite) TestCreateSubmissionReturnsHttpClientError() {
+	paymentID := uuid.New()
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.paymentClient.CreateSubmission(paymentID)
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *paymentTestSuite) TestCreateSubmission() {
+	paymentID := uuid.New()
+	submissionID := uuid.New()
+	s.paymentClient.config.UUIDGenerator = func() (uuid.UUID, error) { return submissionID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+
+	actual, err := s.paymentClient.CreateSubmission(paymentID)
+	s.Require().NoError(err)
+	s.Equal(SubmissionStatusAccepted, actual.Attributes.Status)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.paymentClient.bodyToSubmissionData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(submissionID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(submissionsType, requested.Type)
+}
+
+func (s *paymentTestSuite) TestFetchSubmissionReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.paymentClient.FetchSubmission(uuid.New(), uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *paymentTestSuite) TestFetchSubmissionReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "submission not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrSubmissionNotFound,
+		},
+		{
+			name:           "server bad gateway",
+			responseStatus: http.StatusBadGateway,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server gateway timeout",
+			responseStatus: http.StatusGatewayTimeout,
+			expectedError:  ErrServerError,
+		},
+	} {
+		s.Run(test.name, func() {
+			paymentID, submissionID := uuid.New(), uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(getSubmissionRequestMatcher(paymentID, submissionID)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.paymentClient.FetchSubmission(paymentID, submissionID)
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *paymentTestSuite) TestFetchSubmission() {
+	paymentID, submissionID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getSubmissionRequestMatcher(paymentID, submissionID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: submissionBody(s, submissionID, SubmissionStatusDeliveryConfirmed)}, nil).
+		Once()
+
+	actual, err := s.paymentClient.FetchSubmission(paymentID, submissionID)
+	s.Require().NoError(err)
+	s.Equal(submissionID.String(), actual.ID)
+	s.True(actual.IsTerminal())
+}
+
+func (s *paymentTestSuite) TestSubmitAndWait() {
+	paymentID, submissionID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getSubmissionRequestMatcher(paymentID, submissionID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: submissionBody(s, submissionID, SubmissionStatusQueuedForDelivery)}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getSubmissionRequestMatcher(paymentID, submissionID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: submissionBody(s, submissionID, SubmissionStatusDeliveryConfirmed)}, nil).
+		Once()
+
+	actual, err := s.paymentClient.SubmitAndWait(context.Background(), paymentID)
+	s.Require().NoError(err)
+	s.Equal(SubmissionStatusDeliveryConfirmed, actual.Attributes.Status)
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func (s *paymentTestSuite) TestSubmitAndWaitReturnsContextError_WhenCallerContextEnds() {
+	pollInterval := time.Hour
+	s.paymentClient.config.PollInterval = &pollInterval
+	paymentID, submissionID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := s.paymentClient.SubmitAndWait(ctx, paymentID)
+
+	s.Equal(context.DeadlineExceeded, err)
+}
+
+func (s *paymentTestSuite) TestSubmitAndWaitReturnsError_WhenPollTimeoutElapsedWhileFetching() {
+	clock := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	pollInterval := time.Minute
+	pollTimeout := time.Hour
+	s.paymentClient.config.PollInterval = &pollInterval
+	s.paymentClient.config.PollTimeout = &pollTimeout
+	s.paymentClient.config.Clock = clock
+	paymentID, submissionID := uuid.New(), uuid.New()
+	fetchError := errors.New("fetch cancelled")
+	fetching := make(chan struct{})
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getSubmissionRequestMatcher(paymentID, submissionID)), mock.Anything).
+		Run(func(args mock.Arguments) {
+			close(fetching)
+			<-args.Get(1).([]requestenricher.RequestEnricher)[0].Ctx.Done()
+		}).
+		Return(nil, fetchError).
+		Once()
+
+	done := make(chan error)
+	go func() {
+		_, err := s.paymentClient.SubmitAndWait(context.Background(), paymentID)
+		done <- err
+	}()
+	clock.BlockUntil(2)
+	clock.Advance(pollInterval)
+	<-fetching
+	clock.Advance(pollTimeout - pollInterval)
+
+	err := <-done
+	s.ErrorIs(err, ErrSubmissionTimeout)
+	s.ErrorIs(err, fetchError)
+}
+
+func (s *paymentTestSuite) TestSubmitAndWaitPollsOnConfiguredClock() {
+	clock := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	pollInterval := time.Minute
+	pollTimeout := time.Hour
+	s.paymentClient.config.PollInterval = &pollInterval
+	s.paymentClient.config.PollTimeout = &pollTimeout
+	s.paymentClient.config.Clock = clock
+	paymentID, submissionID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(getSubmissionRequestMatcher(paymentID, submissionID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: submissionBody(s, submissionID, SubmissionStatusDeliveryConfirmed)}, nil).
+		Once()
+
+	done := make(chan error)
+	go func() {
+		_, err := s.paymentClient.SubmitAndWait(context.Background(), paymentID)
+		done <- err
+	}()
+	clock.BlockUntil(2)
+	s.mockHttpClient.AssertNumberOfCalls(s.T(), Do, 1)
+	clock.Advance(pollInterval)
+
+	s.NoError(<-done)
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func (s *paymentTestSuite) TestSubmitAndWaitReturnsError_WhenPollTimeoutElapsedOnConfiguredClock() {
+	clock := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	pollInterval := time.Hour
+	s.paymentClient.config.PollInterval = &pollInterval
+	s.paymentClient.config.Clock = clock
+	paymentID, submissionID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+
+	done := make(chan error)
+	go func() {
+		_, err := s.paymentClient.SubmitAndWait(context.Background(), paymentID)
+		done <- err
+	}()
+	clock.BlockUntil(2)
+	clock.Advance(*s.paymentClient.config.PollTimeout)
+
+	s.ErrorIs(<-done, ErrSubmissionTimeout)
+}
+
+func (s *paymentTestSuite) TestSubmitAndWaitUsesCallerContext() {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	paymentID := uuid.New()
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(postSubmissionRequestMatcher(paymentID)), mock.Anything).
+		Run(func(args mock.Arguments) {
+			en := args.Get(1).([]requestenricher.RequestEnricher)
+			s.Require().Len(en, 1)
+			s.Equal("value", en[0].Ctx.Value(ctxKey{}))
+			_, hasDeadline := en[0].Ctx.Deadline()
+			s.False(hasDeadline, "the poll timeout is measured on the clock of the config")
+			s.NotNil(en[0].BeforeHook)
+		}).
+		Return(nil, expectedError).
+		Once()
+
+	_, err := s.paymentClient.SubmitAndWait(ctx, paymentID, requestenricher.RequestEnricher{BeforeHook: func() {}})
+
+	s.ErrorIs(err, expectedError)
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func submissionBody(s *paymentTestSuite, submissionID uuid.UUID, status string) io.ReadCloser {
+	body, err := json.Marshal(submissionContainer{Data: SubmissionData{
+		ID:         submissionID.String(),
+		Attributes: &SubmissionAttributes{Status: status},
+	}})
+	s.Require().NoError(err)
+	return toResponseBody(string(body))
+}
+
+func postSubmissionRequestMatcher(expectedPaymentID uuid.UUID) func(input *http.Request) bool {
+	expectedUrl := fmt.Sprintf("%s/%s/submissions", testPaymentsUrl, expectedPaymentID)
+	return func(input *http.Request) bool {
+		return input.Method == http.MethodPost &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func getSubmissionRequestMatcher(expectedPaymentID, expectedSubmissionID uuid.UUID) func(input *http.Request) bool {
+	expectedUrl := fmt.Sprintf("%s/%s/submissions/%s", testPaymentsUrl, expectedPaymentID, expectedSubmissionID)
+	return func(input *http.Request) bool {
+		return input.Method == http.MethodGet &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
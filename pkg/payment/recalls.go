@@ -0,0 +1,174 @@
+package payment
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	"form3interview/internal/resource"
+	re "form3interview/pkg/requestenricher"
+)
+
+// RequestRecall asks the beneficiary's bank to send back a payment.
+// It creates a recall with the given reason and submits it for processing in one step.
+// When the recall was created but the submission failed the recall is returned together with the error,
+// so the submission can be retried with CreateRecallSubmission.
+//
+// The requests can be enriched by RequestEnricher
+func (p paymentClient) RequestRecall(paymentID uuid.UUID, reason RecallReason, en ...re.RequestEnricher) (*RecallData, *SubmissionData, error) {
+	recall, err := p.CreateRecall(paymentID, RecallAttributes{Reason: reason}, en...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recallID, err := uuid.Parse(recall.ID)
+	if err != nil {
+		return recall, nil, fmt.Errorf("%w: invalid recall id %q", ErrUnexpectedServerResponse, recall.ID)
+	}
+
+	submission, err := p.CreateRecallSubmission(paymentID, recallID, en...)
+	if err != nil {
+		return recall, nil, err
+	}
+	return recall, submission, nil
+}
+
+// CreateRecall creates a recall for a sent payment.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls/create-a-payment-recall
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) CreateRecall(paymentID uuid.UUID, attributes RecallAttributes, en ...re.RequestEnricher) (*RecallData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := p.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	recall := RecallData{
+		ID:             newID.String(),
+		OrganisationID: p.config.OrganisationID.String(),
+		Type:           recallsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := collection[RecallData](p, recallsUrl(paymentID), ErrPaymentNotFound).Create(newID, recall, en...)
+	if err != nil {
+		return nil, err
+	}
+	p.config.Log().Debugf("payment %s recall %s created", paymentID, recall.ID)
+	return created, nil
+}
+
+// FetchRecall fetches a payment recall by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls/fetch-a-payment-recall
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchRecall(paymentID, recallID uuid.UUID, en ...re.RequestEnricher) (*RecallData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[RecallData](p, recallsUrl(paymentID), ErrRecallNotFound).Fetch(recallID, en...)
+}
+
+// CreateRecallSubmission submits a payment recall for processing.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls/create-a-recall-submission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) CreateRecallSubmission(paymentID, recallID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if paymentID == uuid.Nil || recallID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := p.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	submission := SubmissionData{
+		ID:             newID.String(),
+		OrganisationID: p.config.OrganisationID.String(),
+		Type:           recallSubmissionsType,
+	}
+
+	created, err := collection[SubmissionData](p, recallSubmissionsUrl(paymentID, recallID), ErrRecallNotFound).Create(newID, submission, en...)
+	if err != nil {
+		return nil, err
+	}
+	p.config.Log().Debugf("payment %s recall %s submission %s created", paymentID, recallID, submission.ID)
+	return created, nil
+}
+
+// FetchRecallSubmission fetches a payment recall submission by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls/fetch-a-recall-submission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchRecallSubmission(paymentID, recallID, submissionID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if paymentID == uuid.Nil || recallID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[SubmissionData](p, recallSubmissionsUrl(paymentID, recallID), ErrSubmissionNotFound).Fetch(submissionID, en...)
+}
+
+// CreateRecallDecision answers a received payment recall.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls/create-a-recall-decision
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) CreateRecallDecision(paymentID, recallID uuid.UUID, attributes RecallDecisionAttributes, en ...re.RequestEnricher) (*RecallDecisionData, error) {
+	if paymentID == uuid.Nil || recallID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := p.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	decision := RecallDecisionData{
+		ID:             newID.String(),
+		OrganisationID: p.config.OrganisationID.String(),
+		Type:           recallDecisionsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := collection[RecallDecisionData](p, recallDecisionsUrl(paymentID, recallID), ErrRecallNotFound).Create(newID, decision, en...)
+	if err != nil {
+		return nil, err
+	}
+	p.config.Log().Debugf("payment %s recall %s decision %s created", paymentID, recallID, decision.ID)
+	return created, nil
+}
+
+// FetchRecallDecision fetches a payment recall decision by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-recalls/fetch-a-recall-decision
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchRecallDecision(paymentID, recallID, decisionID uuid.UUID, en ...re.RequestEnricher) (*RecallDecisionData, error) {
+	if paymentID == uuid.Nil || recallID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[RecallDecisionData](p, recallDecisionsUrl(paymentID, recallID), ErrRecallDecisionNotFound).Fetch(decisionID, en...)
+}
+
+func (p paymentClient) bodyToRecallData(body io.Reader) (*RecallData, error) {
+	return resource.Client[RecallData]{Config: p.config}.BodyToData(body)
+}
+
+func (p paymentClient) bodyToRecallDecisionData(body io.Reader) (*RecallDecisionData, error) {
+	return resource.Client[RecallDecisionData]{Config: p.config}.BodyToData(body)
+}
+
+func recallsUrl(paymentID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/recalls", paymentsUrl, paymentID)
+}
+
+func recallSubmissionsUrl(paymentID, recallID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/submissions", recallsUrl(paymentID), recallID)
+}
+
+func recallDecisionsUrl(paymentID, recallID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/decisions", recallsUrl(paymentID), recallID)
+}
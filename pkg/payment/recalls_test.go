@@ -0,0 +1,199 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *paymentTestSuite) TestCreateRecallReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "payment not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrPaymentNotFound,
+		},
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"reason is invalid\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			expectedError:  ErrServerError,
+		},
+	} {
+		s.Run(test.name, func() {
+			paymentID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/recalls", testPaymentsUrl, paymentID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.paymentClient.CreateRecall(paymentID, RecallAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *paymentTestSuite) TestCreateRecall() {
+	paymentID, recallID := uuid.New(), uuid.New()
+	s.paymentClient.config.UUIDGenerator = func() (uuid.UUID, error) { return recallID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/recalls", testPaymentsUrl, paymentID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.paymentClient.CreateRecall(paymentID, RecallAttributes{Reason: RecallReasonDuplicate, Description: "sent twice"})
+	s.Require().NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.paymentClient.bodyToRecallData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(recallID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(recallsType, requested.Type)
+	s.Equal(RecallReasonDuplicate, requested.Attributes.Reason)
+	s.Equal("sent twice", requested.Attributes.Description)
+}
+
+func (s *paymentTestSuite) TestFetchRecallReturnsError() {
+	_, actualError := s.paymentClient.FetchRecall(uuid.Nil, uuid.New())
+	s.ErrorIs(actualError, ErrNilUUID)
+
+	paymentID, recallID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/recalls/%s", testPaymentsUrl, paymentID, recallID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError = s.paymentClient.FetchRecall(paymentID, recallID)
+	s.ErrorIs(actualError, ErrRecallNotFound)
+}
+
+func (s *paymentTestSuite) TestFetchRecall() {
+	paymentID, recallID := uuid.New(), uuid.New()
+	body, err := json.Marshal(recallContainer{Data: RecallData{
+		ID:         recallID.String(),
+		Attributes: &RecallAttributes{Reason: RecallReasonFraud},
+	}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/recalls/%s", testPaymentsUrl, paymentID, recallID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.paymentClient.FetchRecall(paymentID, recallID)
+	s.Require().NoError(err)
+	s.Equal(recallID.String(), actual.ID)
+	s.Equal(RecallReasonFraud, actual.Attributes.Reason)
+}
+
+func (s *paymentTestSuite) TestFetchRecallSubmission() {
+	paymentID, recallID, submissionID := uuid.New(), uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/recalls/%s/submissions/%s", testPaymentsUrl, paymentID, recallID, submissionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: submissionBody(s, submissionID, SubmissionStatusDeliveryConfirmed)}, nil).
+		Once()
+
+	actual, err := s.paymentClient.FetchRecallSubmission(paymentID, recallID, submissionID)
+	s.Require().NoError(err)
+	s.Equal(submissionID.String(), actual.ID)
+}
+
+func (s *paymentTestSuite) TestCreateRecallDecision() {
+	paymentID, recallID, decisionID := uuid.New(), uuid.New(), uuid.New()
+	s.paymentClient.config.UUIDGenerator = func() (uuid.UUID, error) { return decisionID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/recalls/%s/decisions", testPaymentsUrl, paymentID, recallID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.paymentClient.CreateRecallDecision(paymentID, recallID, RecallDecisionAttributes{
+		Answer:       RecallAnswerRejected,
+		RejectReason: RecallRejectReasonAlreadyReturned,
+	})
+	s.Require().NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.paymentClient.bodyToRecallDecisionData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(decisionID.String(), requested.ID)
+	s.Equal(recallDecisionsType, requested.Type)
+	s.Equal(RecallAnswerRejected, requested.Attributes.Answer)
+	s.Equal(RecallRejectReasonAlreadyReturned, requested.Attributes.RejectReason)
+}
+
+func (s *paymentTestSuite) TestFetchRecallDecisionReturnsError_WhenDecisionNotFound() {
+	paymentID, recallID, decisionID := uuid.New(), uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/recalls/%s/decisions/%s", testPaymentsUrl, paymentID, recallID, decisionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.paymentClient.FetchRecallDecision(paymentID, recallID, decisionID)
+
+	s.ErrorIs(actualError, ErrRecallDecisionNotFound)
+}
+
+func (s *paymentTestSuite) TestRequestRecall() {
+	paymentID, recallID, submissionID := uuid.New(), uuid.New(), uuid.New()
+	ids := []uuid.UUID{recallID, submissionID}
+	s.paymentClient.config.UUIDGenerator = func() (uuid.UUID, error) {
+		id := ids[0]
+		ids = ids[1:]
+		return id, nil
+	}
+
+	recallBody, err := json.Marshal(recallContainer{Data: RecallData{ID: recallID.String()}})
+	s.Require().NoError(err)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/recalls", testPaymentsUrl, paymentID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody(string(recallBody))}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/recalls/%s/submissions", testPaymentsUrl, paymentID, recallID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+
+	recall, submission, err := s.paymentClient.RequestRecall(paymentID, RecallReasonRequestedByCustomer)
+
+	s.Require().NoError(err)
+	s.Equal(recallID.String(), recall.ID)
+	s.Equal(submissionID.String(), submission.ID)
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func (s *paymentTestSuite) TestRequestRecallReturnsRecall_WhenSubmissionFailed() {
+	paymentID, recallID := uuid.New(), uuid.New()
+	recallBody, err := json.Marshal(recallContainer{Data: RecallData{ID: recallID.String()}})
+	s.Require().NoError(err)
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/recalls", testPaymentsUrl, paymentID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody(string(recallBody))}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/recalls/%s/submissions", testPaymentsUrl, paymentID, recallID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusServiceUnavailable, Body: toResponseBody("")}, nil).
+		Once()
+
+	recall, submission, err := s.paymentClient.RequestRecall(paymentID, RecallReasonTechnicalProblem)
+
+	s.ErrorIs(err, ErrServerUnavailable)
+	s.Equal(recallID.String(), recall.ID)
+	s.Nil(submission)
+}
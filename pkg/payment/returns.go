@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	"form3interview/internal/resource"
+	re "form3interview/pkg/requestenricher"
+)
+
+// CreateReturn returns a received payment to the sender.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-returns/create-a-payment-return
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) CreateReturn(paymentID uuid.UUID, attributes ReturnAttributes, en ...re.RequestEnricher) (*ReturnData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := p.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := ReturnData{
+		ID:             newID.String(),
+		OrganisationID: p.config.OrganisationID.String(),
+		Type:           returnsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := collection[ReturnData](p, returnsUrl(paymentID), ErrPaymentNotFound).Create(newID, ret, en...)
+	if err != nil {
+		return nil, err
+	}
+	p.config.Log().Debugf("payment %s return %s created", paymentID, ret.ID)
+	return created, nil
+}
+
+// FetchReturn fetches a payment return by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-returns/fetch-a-payment-return
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchReturn(paymentID, returnID uuid.UUID, en ...re.RequestEnricher) (*ReturnData, error) {
+	if paymentID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[ReturnData](p, returnsUrl(paymentID), ErrReturnNotFound).Fetch(returnID, en...)
+}
+
+// CreateReturnSubmission submits a payment return for processing.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-returns/create-a-return-submission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) CreateReturnSubmission(paymentID, returnID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if paymentID == uuid.Nil || returnID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := p.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	submission := SubmissionData{
+		ID:             newID.String(),
+		OrganisationID: p.config.OrganisationID.String(),
+		Type:           returnSubmissionsType,
+	}
+
+	created, err := collection[SubmissionData](p, returnSubmissionsUrl(paymentID, returnID), ErrReturnNotFound).Create(newID, submission, en...)
+	if err != nil {
+		return nil, err
+	}
+	p.config.Log().Debugf("payment %s return %s submission %s created", paymentID, returnID, submission.ID)
+	return created, nil
+}
+
+// FetchReturnSubmission fetches a payment return submission by it's ID.
+// See https://www.api-docs.form3.tech/api/schemes/fps-direct/payments/payment-returns/fetch-a-return-submission
+//
+// The request can be enriched by RequestEnricher
+func (p paymentClient) FetchReturnSubmission(paymentID, returnID, submissionID uuid.UUID, en ...re.RequestEnricher) (*SubmissionData, error) {
+	if paymentID == uuid.Nil || returnID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+	return collection[SubmissionData](p, returnSubmissionsUrl(paymentID, returnID), ErrSubmissionNotFound).Fetch(submissionID, en...)
+}
+
+func (p paymentClient) bodyToReturnData(body io.Reader) (*ReturnData, error) {
+	return resource.Client[ReturnData]{Config: p.config}.BodyToData(body)
+}
+
+func returnsUrl(paymentID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/returns", paymentsUrl, paymentID)
+}
+
+func returnSubmissionsUrl(paymentID, returnID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/submissions", returnsUrl(paymentID), returnID)
+}
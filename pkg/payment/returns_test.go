@@ -0,0 +1,146 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *paymentTestSuite) TestCreateReturnReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "payment not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrPaymentNotFound,
+		},
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"return_code is invalid\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+	} {
+		s.Run(test.name, func() {
+			paymentID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/returns", testPaymentsUrl, paymentID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.paymentClient.CreateReturn(paymentID, ReturnAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *paymentTestSuite) TestCreateReturn() {
+	paymentID, returnID := uuid.New(), uuid.New()
+	s.paymentClient.config.UUIDGenerator = func() (uuid.UUID, error) { return returnID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/returns", testPaymentsUrl, paymentID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.paymentClient.CreateReturn(paymentID, ReturnAttributes{ReturnCode: ReturnCodeClosedAccountNumber})
+	s.Require().NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.paymentClient.bodyToReturnData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(returnID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(returnsType, requested.Type)
+	s.Equal(ReturnCodeClosedAccountNumber, requested.Attributes.ReturnCode)
+}
+
+func (s *paymentTestSuite) TestFetchReturnReturnsError() {
+	_, actualError := s.paymentClient.FetchReturn(uuid.New(), uuid.Nil)
+	s.ErrorIs(actualError, ErrNilUUID)
+
+	paymentID, returnID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/returns/%s", testPaymentsUrl, paymentID, returnID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError = s.paymentClient.FetchReturn(paymentID, returnID)
+	s.ErrorIs(actualError, ErrReturnNotFound)
+}
+
+func (s *paymentTestSuite) TestFetchReturn() {
+	paymentID, returnID := uuid.New(), uuid.New()
+	body, err := json.Marshal(returnContainer{Data: ReturnData{
+		ID:         returnID.String(),
+		Attributes: &ReturnAttributes{ReturnCode: ReturnCodeInsufficientFunds},
+	}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/returns/%s", testPaymentsUrl, paymentID, returnID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.paymentClient.FetchReturn(paymentID, returnID)
+	s.Require().NoError(err)
+	s.Equal(returnID.String(), actual.ID)
+	s.Equal(ReturnCodeInsufficientFunds, actual.Attributes.ReturnCode)
+}
+
+func (s *paymentTestSuite) TestCreateReturnSubmission() {
+	paymentID, returnID, submissionID := uuid.New(), uuid.New(), uuid.New()
+	s.paymentClient.config.UUIDGenerator = func() (uuid.UUID, error) { return submissionID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/returns/%s/submissions", testPaymentsUrl, paymentID, returnID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: submissionBody(s, submissionID, SubmissionStatusAccepted)}, nil).
+		Once()
+
+	actual, err := s.paymentClient.CreateReturnSubmission(paymentID, returnID)
+	s.Require().NoError(err)
+	s.Equal(SubmissionStatusAccepted, actual.Attributes.Status)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.paymentClient.bodyToSubmissionData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(submissionID.String(), requested.ID)
+	s.Equal(returnSubmissionsType, requested.Type)
+}
+
+func (s *paymentTestSuite) TestCreateReturnSubmissionReturnsError_WhenReturnNotFound() {
+	paymentID, returnID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/returns/%s/submissions", testPaymentsUrl, paymentID, returnID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.paymentClient.CreateReturnSubmission(paymentID, returnID)
+
+	s.ErrorIs(actualError, ErrReturnNotFound)
+}
+
+func (s *paymentTestSuite) TestFetchReturnSubmission() {
+	paymentID, returnID, submissionID := uuid.New(), uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/returns/%s/submissions/%s", testPaymentsUrl, paymentID, returnID, submissionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: submissionBody(s, submissionID, SubmissionStatusDeliveryConfirmed)}, nil).
+		Once()
+
+	actual, err := s.paymentClient.FetchReturnSubmission(paymentID, returnID, submissionID)
+	s.Require().NoError(err)
+	s.Equal(submissionID.String(), actual.ID)
+}
@@ -0,0 +1,95 @@
+// Package ratelimit provides the client-side rate limiter of the Form3 clients. The requests wait for a token
+// of a token bucket before they are sent, so batch jobs stay under the rate limits of the API instead of
+// being rejected with 429 Too Many Requests.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"form3interview/pkg/clock"
+)
+
+// Limiter is a token bucket refilled with rps tokens per second up to burst tokens. It's safe for concurrent use.
+type Limiter struct {
+	rps   float64
+	burst int
+	clock clock.Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// New creates a limiter allowing rps requests per second on average and burst requests at once.
+// The burst is at least 1. The bucket is full when it's created. The requests are not limited if rps is not positive.
+func New(rps float64, burst int) *Limiter {
+	return NewWithClock(rps, burst, clock.System)
+}
+
+// NewWithClock creates a limiter (see New) which refills the bucket and waits for the tokens on the clock.
+func NewWithClock(rps float64, burst int, clk clock.Clock) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{rps: rps, burst: burst, clock: clk, tokens: float64(burst), last: clk.Now()}
+}
+
+// Rps returns the number of requests allowed per second.
+func (l *Limiter) Rps() float64 {
+	return l.rps
+}
+
+// Burst returns the number of requests allowed at once.
+func (l *Limiter) Burst() int {
+	return l.burst
+}
+
+// Wait blocks until a token is available or the context is done. The token is given back if the context is
+// done before the wait is over.
+func (l *Limiter) Wait(ctx context.Context) error {
+	delay := l.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := l.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token and returns how long to wait until it's available.
+func (l *Limiter) reserve() time.Duration {
+	if l.rps <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	t := l.clock.Now()
+	l.tokens += t.Sub(l.last).Seconds() * l.rps
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = t
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rps * float64(time.Second))
+}
+
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
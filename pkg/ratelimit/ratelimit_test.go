@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock/clocktest"
+)
+
+type rateLimitTestSuite struct {
+	suite.Suite
+	clock *clocktest.Fake
+}
+
+func TestRateLimitTestSuite(t *testing.T) {
+	suite.Run(t, new(rateLimitTestSuite))
+}
+
+func (s *rateLimitTestSuite) SetupTest() {
+	s.clock = clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+}
+
+func (s *rateLimitTestSuite) TestReserve() {
+	limiter := NewWithClock(10, 2, s.clock)
+
+	s.Zero(limiter.reserve())
+	s.Zero(limiter.reserve())
+	s.Equal(100*time.Millisecond, limiter.reserve())
+	s.Equal(200*time.Millisecond, limiter.reserve())
+
+	s.clock.Advance(time.Second)
+	s.Zero(limiter.reserve())
+	s.Zero(limiter.reserve())
+}
+
+func (s *rateLimitTestSuite) TestReserveRefillsUpToBurst() {
+	limiter := NewWithClock(10, 1, s.clock)
+	s.Zero(limiter.reserve())
+
+	s.clock.Advance(time.Hour)
+	s.Zero(limiter.reserve())
+	s.Equal(100*time.Millisecond, limiter.reserve())
+}
+
+func (s *rateLimitTestSuite) TestReserveDoesNotLimit_WhenRpsIsNotPositive() {
+	limiter := NewWithClock(0, 0, s.clock)
+
+	for i := 0; i < 10; i++ {
+		s.Zero(limiter.reserve())
+	}
+	s.Equal(1, limiter.Burst())
+}
+
+func (s *rateLimitTestSuite) TestWait() {
+	limiter := NewWithClock(100, 1, s.clock)
+	s.Require().NoError(limiter.Wait(context.Background()))
+
+	waited := make(chan error)
+	go func() { waited <- limiter.Wait(context.Background()) }()
+	s.clock.BlockUntil(1)
+	s.clock.Advance(9 * time.Millisecond)
+	s.Equal(1, s.clock.Waiters())
+	s.clock.Advance(time.Millisecond)
+
+	s.NoError(<-waited)
+}
+
+func (s *rateLimitTestSuite) TestNewUsesSystemClock() {
+	limiter := New(100, 1)
+
+	start := time.Now()
+	s.Require().NoError(limiter.Wait(context.Background()))
+	s.Require().NoError(limiter.Wait(context.Background()))
+
+	s.GreaterOrEqual(time.Since(start), 5*time.Millisecond)
+}
+
+func (s *rateLimitTestSuite) TestWaitGivesBackToken_WhenContextIsDone() {
+	limiter := NewWithClock(1, 1, s.clock)
+	s.Zero(limiter.reserve())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s.ErrorIs(limiter.Wait(ctx), context.Canceled)
+	s.Equal(time.Second, limiter.reserve())
+}
@@ -0,0 +1,30 @@
+package report
+
+import "form3interview/internal/resource"
+
+// reportContainer is a simple container for the "data" JSON field.
+type reportContainer = resource.DataContainer[ReportData]
+
+// reportListContainer is a simple container for the "data" JSON field of list responses.
+type reportListContainer = resource.ListContainer[ReportData]
+
+// ReportData represents a report generated by Form3.
+// The contents of the report file can be downloaded with Download.
+// See https://www.api-docs.form3.tech/api/reports for
+// more information about fields.
+type ReportData struct {
+	Attributes     *ReportAttributes `json:"attributes,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	OrganisationID string            `json:"organisation_id,omitempty"`
+	Type           string            `json:"type,omitempty"`
+	Version        *int64            `json:"version,omitempty"`
+}
+
+type ReportAttributes struct {
+	ContentType string `json:"content_type,omitempty"`
+	CreatedOn   string `json:"created_on,omitempty"`
+	Description string `json:"description,omitempty"`
+	FileName    string `json:"file_name,omitempty"`
+	ReportType  string `json:"report_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
@@ -0,0 +1,145 @@
+// Package report provides Form3 client to list reports and download their files.
+// See https://www.api-docs.form3.tech/api/reports
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	reportsUrl = "/reports"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrNilWriter nil writer is not allowed
+	ErrNilWriter = errors.New("nil writer not allowed")
+	// ErrReportNotFound report not found
+	ErrReportNotFound = errors.New("report not found")
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type reportClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 reports created by NewClient or the form3 facade.
+type Client = reportClient
+
+// NewClient creates a client for listing and downloading Form3 reports.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+//
+// Note that the configured timeout includes reading the response body,
+// so it must be long enough to download the largest expected report.
+func NewClient(options ...config.Option) (*reportClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &reportClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (r reportClient) Close() error {
+	return resource.Close(r.client)
+}
+
+// Fetch a report by it's ID.
+// See https://www.api-docs.form3.tech/api/reports/fetch-a-report
+//
+// The request can be enriched by RequestEnricher
+func (r reportClient) Fetch(reportID uuid.UUID, en ...re.RequestEnricher) (*ReportData, error) {
+	return r.reports().Fetch(reportID, en...)
+}
+
+// List the reports of the configured organisation page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/reports/list-reports
+//
+// The request can be enriched by RequestEnricher
+func (r reportClient) List(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]ReportData, error) {
+	query := fmt.Sprintf("filter[organisation_id]=%s&page[number]=%d&page[size]=%d", r.config.OrganisationID, pageNumber, pageSize)
+	return r.reports().Find(query, en...)
+}
+
+// Download streams the file contents of a report to w and returns the number of bytes written.
+// The file is copied as it arrives, so large reports are not loaded into memory.
+// If the download breaks, the bytes already written are returned together with the error.
+// See https://www.api-docs.form3.tech/api/reports/download-a-report
+//
+// The request can be enriched by RequestEnricher
+func (r reportClient) Download(reportID uuid.UUID, w io.Writer, en ...re.RequestEnricher) (int64, error) {
+	if reportID == uuid.Nil {
+		return 0, ErrNilUUID
+	}
+	if w == nil {
+		return 0, ErrNilWriter
+	}
+
+	written, err := r.reports().Download(reportID, "/file", w, en...)
+	if err != nil {
+		return written, err
+	}
+	r.config.Log().Debugf("report %s downloaded (%d bytes)", reportID, written)
+	return written, nil
+}
+
+func (r reportClient) bodyToReportData(body io.Reader) (*ReportData, error) {
+	return resource.Client[ReportData]{Config: r.config}.BodyToData(body)
+}
+
+// reports returns the generic client of the reports collection.
+func (r reportClient) reports() resource.Client[ReportData] {
+	return resource.Client[ReportData]{
+		HTTP:        r.client,
+		Config:      r.config,
+		Url:         reportsUrl,
+		ErrNotFound: ErrReportNotFound,
+	}
+}
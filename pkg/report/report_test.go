@@ -0,0 +1,197 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testReportsUrl     = testBaseUrl + reportsUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type reportTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	reportClient   reportClient
+}
+
+func TestReportTestSuite(t *testing.T) {
+	suite.Run(t, new(reportTestSuite))
+}
+
+func (s *reportTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.reportClient = reportClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *reportTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.reportClient.Fetch(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *reportTestSuite) TestFetchReport() {
+	reportID := uuid.New()
+	body, err := json.Marshal(reportContainer{Data: ReportData{ID: reportID.String()}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testReportsUrl, reportID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.reportClient.Fetch(reportID)
+	s.NoError(err)
+	s.Equal(reportID.String(), actual.ID)
+}
+
+func (s *reportTestSuite) TestListReports() {
+	firstID, secondID := uuid.New(), uuid.New()
+	body, err := json.Marshal(reportListContainer{Data: []ReportData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	expectedUrl := testReportsUrl + "?filter[organisation_id]=" + testOrganisationID + "&page[number]=1&page[size]=2"
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, expectedUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.reportClient.List(1, 2)
+	s.NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func (s *reportTestSuite) TestDownloadReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "report not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrReportNotFound,
+		},
+		{
+			name:           "server internal error",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "{\"error_message\":\"oops\"}",
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			reportID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/file", testReportsUrl, reportID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			var buf bytes.Buffer
+			written, actualError := s.reportClient.Download(reportID, &buf)
+
+			s.ErrorIs(actualError, test.expectedError)
+			s.Zero(written)
+			s.Zero(buf.Len())
+		})
+	}
+}
+
+func (s *reportTestSuite) TestDownloadReturnsError_WhenInvalidArgumentsGiven() {
+	_, actualError := s.reportClient.Download(uuid.Nil, io.Discard)
+	s.ErrorIs(actualError, ErrNilUUID)
+
+	_, actualError = s.reportClient.Download(uuid.New(), nil)
+	s.ErrorIs(actualError, ErrNilWriter)
+
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *reportTestSuite) TestDownloadReport() {
+	reportID := uuid.New()
+	content := "line1\nline2\n"
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/file", testReportsUrl, reportID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(content)}, nil).
+		Once()
+
+	var buf bytes.Buffer
+	written, err := s.reportClient.Download(reportID, &buf)
+
+	s.NoError(err)
+	s.Equal(int64(len(content)), written)
+	s.Equal(content, buf.String())
+}
+
+func (s *reportTestSuite) TestDownloadReturnsWrittenBytes_WhenDownloadBreaks() {
+	reportID := uuid.New()
+	expectedError := errors.New("connection reset")
+	body := io.NopCloser(io.MultiReader(bytes.NewBufferString("partial"), failingReader{err: expectedError}))
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/file", testReportsUrl, reportID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: body}, nil).
+		Once()
+
+	var buf bytes.Buffer
+	written, err := s.reportClient.Download(reportID, &buf)
+
+	s.ErrorIs(err, expectedError)
+	s.Equal(int64(len("partial")), written)
+	s.Equal("partial", buf.String())
+}
+
+type failingReader struct {
+	err error
+}
+
+func (f failingReader) Read([]byte) (int, error) {
+	return 0, f.err
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
@@ -0,0 +1,141 @@
+package requestenricher
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Doer sends the requests, i.e. an *http.Client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc is a function used as a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls the function.
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Doer, i.e. to modify the requests or to inspect the responses of every attempt of the
+// client calls (see config.WithMiddleware). The middleware package provides the built-in ones.
+type Middleware func(next Doer) Doer
+
+// Wrap returns the Doer wrapped by the middlewares. The first middleware is the outermost, so it gets the
+// request first and the response last.
+func Wrap(doer Doer, middleware ...Middleware) Doer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		doer = middleware[i](doer)
+	}
+	return doer
+}
+
+// Middleware returns the enricher as a Middleware, so it can be used with any Doer. It's the CallMiddleware of the
+// enricher wrapping its AttemptMiddleware with the first DefaultBodyLimit bytes of the response body passed to the
+// AfterHook. The clients run the same two parts around their retries (see CallMiddleware).
+func (en RequestEnricher) Middleware() Middleware {
+	call, attempt := en.CallMiddleware(), en.AttemptMiddleware(DefaultBodyLimit)
+	return func(next Doer) Doer {
+		return call(attempt(next))
+	}
+}
+
+// CallMiddleware returns the part of the enricher which runs once per call, so it wraps the retries of the
+// request. The request is sent with the Ctx, the Timeout, the IdempotencyKey (if it's mutating), the Header and
+// the Query of the enricher, on a copy which BeforeRequest can modify before it's sent. OnError is called with
+// the error of the call. The panics of the hooks are returned as a *HookPanicError.
+func (en RequestEnricher) CallMiddleware() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (resp *http.Response, err error) {
+			if en.OnError != nil {
+				defer func() {
+					if err != nil {
+						err = CallOnError(en.OnError, req, err)
+					}
+				}()
+			}
+
+			ctx := req.Context()
+			if en.Ctx != nil {
+				ctx = en.Ctx
+			}
+			var cancel context.CancelFunc
+			if en.Timeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, en.Timeout)
+			}
+			resp, err = en.call(next, req.Clone(ctx))
+			if cancel != nil {
+				if err != nil {
+					cancel()
+				} else {
+					resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+func (en RequestEnricher) call(next Doer, req *http.Request) (*http.Response, error) {
+	if en.IdempotencyKey != "" && IsMutating(req.Method) && req.Header.Get(IdempotencyKeyHeader) == "" {
+		req.Header.Set(IdempotencyKeyHeader, en.IdempotencyKey)
+	}
+	en.SetOn(req)
+	if en.BeforeRequest != nil {
+		if err := RecoverHook("BeforeRequest", func() error { return en.BeforeRequest(req) }); err != nil {
+			return nil, err
+		}
+	}
+	return next.Do(req)
+}
+
+// AttemptMiddleware returns the part of the enricher which runs around every attempt of the request. The request
+// is traced if Trace is set, BeforeHook runs before it's sent and AfterHook after it with the first bodyLimit
+// bytes of the response body (see PeekResponse). The panics of the hooks are returned as a *HookPanicError and the
+// response is closed.
+func (en RequestEnricher) AttemptMiddleware(bodyLimit int) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if en.Trace {
+				req = Trace(req)
+			}
+			if en.BeforeHook != nil {
+				if err := RecoverHook("BeforeHook", func() error { en.BeforeHook(); return nil }); err != nil {
+					return nil, err
+				}
+			}
+
+			resp, err := next.Do(req)
+			if en.AfterHook != nil && resp != nil {
+				if err := RecoverHook("AfterHook", func() error { en.AfterHook(PeekResponse(resp, bodyLimit)); return nil }); err != nil {
+					resp.Body.Close()
+					return nil, err
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+// IsMutating tells whether the requests of the method modify the resources (POST, PUT, PATCH and DELETE).
+func IsMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// cancelOnClose cancels the context of the request with a timeout when the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,208 @@
+package requestenricher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type middlewareTestSuite struct {
+	suite.Suite
+	requests []*http.Request
+	doer     Doer
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(middlewareTestSuite))
+}
+
+func (s *middlewareTestSuite) SetupTest() {
+	s.requests = nil
+	s.doer = DoerFunc(func(req *http.Request) (*http.Response, error) {
+		s.requests = append(s.requests, req)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+	})
+}
+
+func (s *middlewareTestSuite) newRequest(method string) *http.Request {
+	req, err := http.NewRequest(method, "http://testhost/v1/organisation/accounts", nil)
+	s.Require().NoError(err)
+	return req
+}
+
+func (s *middlewareTestSuite) TestWrap() {
+	var calls []string
+	named := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, "before "+name)
+				resp, err := next.Do(req)
+				calls = append(calls, "after "+name)
+				return resp, err
+			})
+		}
+	}
+
+	_, err := Wrap(s.doer, named("logging"), named("auth")).Do(s.newRequest(http.MethodGet))
+
+	s.Require().NoError(err)
+	s.Equal([]string{"before logging", "before auth", "after auth", "after logging"}, calls)
+	s.Len(s.requests, 1)
+}
+
+func (s *middlewareTestSuite) TestEnricherMiddleware() {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "caller")
+	var calls []string
+	var hookBody string
+	en := RequestEnricher{
+		Ctx:            ctx,
+		Timeout:        time.Minute,
+		IdempotencyKey: "key-1",
+		BeforeRequest: func(req *http.Request) error {
+			req.Header.Set("X-Trace-Id", "trace-1")
+			calls = append(calls, "before request")
+			return nil
+		},
+		BeforeHook: func() { calls = append(calls, "before hook") },
+		AfterHook: func(resp *http.Response) {
+			body, err := io.ReadAll(resp.Body)
+			s.Require().NoError(err)
+			hookBody = string(body)
+			calls = append(calls, "after hook")
+		},
+		OnError: func(*http.Request, error) { calls = append(calls, "on error") },
+	}
+	req := s.newRequest(http.MethodPost)
+
+	resp, err := Wrap(s.doer, en.Middleware()).Do(req)
+
+	s.Require().NoError(err)
+	s.Equal([]string{"before request", "before hook", "after hook"}, calls)
+	s.Equal("{}", hookBody)
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Equal("{}", string(body))
+	s.Require().Len(s.requests, 1)
+	sent := s.requests[0]
+	s.Equal("caller", sent.Context().Value(key{}))
+	_, hasDeadline := sent.Context().Deadline()
+	s.True(hasDeadline)
+	s.Equal("key-1", sent.Header.Get(IdempotencyKeyHeader))
+	s.Equal("trace-1", sent.Header.Get("X-Trace-Id"))
+	s.Empty(req.Header.Get("X-Trace-Id"))
+
+	resp.Body.Close()
+	s.ErrorIs(sent.Context().Err(), context.Canceled)
+}
+
+func (s *middlewareTestSuite) TestEnricherMiddlewareAbortsRequest() {
+	expectedErr := errors.New("not allowed")
+	var reported error
+	en := RequestEnricher{
+		Timeout:       time.Minute,
+		BeforeRequest: func(*http.Request) error { return expectedErr },
+		OnError:       func(_ *http.Request, err error) { reported = err },
+	}
+
+	_, err := Wrap(s.doer, en.Middleware()).Do(s.newRequest(http.MethodGet))
+
+	s.ErrorIs(err, expectedErr)
+	s.ErrorIs(reported, expectedErr)
+	s.Empty(s.requests)
+}
+
+func (s *middlewareTestSuite) TestEnricherMiddlewareRecoversPanickingHooks() {
+	body := &closeTrackingBody{Reader: strings.NewReader("{}")}
+	doer := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: body, Request: req}, nil
+	})
+	var reported error
+	en := RequestEnricher{
+		AfterHook: func(*http.Response) { panic("boom") },
+		OnError:   func(_ *http.Request, err error) { reported = err },
+	}
+
+	resp, err := Wrap(doer, en.Middleware()).Do(s.newRequest(http.MethodGet))
+
+	s.Nil(resp)
+	s.ErrorIs(err, ErrHookPanicked)
+	s.ErrorIs(reported, ErrHookPanicked)
+	s.True(body.closed)
+}
+
+func (s *middlewareTestSuite) TestEnricherMiddlewareSetsHeaderAndQuery() {
+	req := s.newRequest(http.MethodGet)
+
+	_, err := Wrap(s.doer, Chain(WithHeader("X-Feature-Flag", "sepa"), WithQueryParam("filter[country]", "GB")).Middleware()).Do(req)
+
+	s.Require().NoError(err)
+	s.Require().Len(s.requests, 1)
+	s.Equal("sepa", s.requests[0].Header.Get("X-Feature-Flag"))
+	s.Equal("GB", s.requests[0].URL.Query().Get("filter[country]"))
+	s.Empty(req.Header.Get("X-Feature-Flag"))
+	s.Empty(req.URL.RawQuery)
+}
+
+func (s *middlewareTestSuite) TestEnricherMiddlewareTracesRequests() {
+	_, err := Wrap(s.doer, WithTrace().Middleware()).Do(s.newRequest(http.MethodGet))
+	s.Require().NoError(err)
+
+	s.Require().Len(s.requests, 1)
+	_, traced := TimingOf(&http.Response{Request: s.requests[0]})
+	s.True(traced)
+}
+
+func (s *middlewareTestSuite) TestEnricherMiddlewareSetsIdempotencyKeyOfMutatingRequests() {
+	_, err := Wrap(s.doer, WithIdempotencyKey("key-1").Middleware()).Do(s.newRequest(http.MethodGet))
+	s.Require().NoError(err)
+
+	s.Require().Len(s.requests, 1)
+	s.Empty(s.requests[0].Header.Get(IdempotencyKeyHeader))
+}
+
+func (s *middlewareTestSuite) TestCallMiddlewareRunsOnceAroundRetries() {
+	var calls []string
+	var hookBody string
+	en := RequestEnricher{
+		BeforeRequest: func(*http.Request) error { calls = append(calls, "before request"); return nil },
+		BeforeHook:    func() { calls = append(calls, "before hook") },
+		AfterHook: func(resp *http.Response) {
+			body, err := io.ReadAll(resp.Body)
+			s.Require().NoError(err)
+			hookBody = string(body)
+			calls = append(calls, "after hook")
+		},
+	}
+	retrying := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if _, err := next.Do(req); err != nil {
+				return nil, err
+			}
+			return next.Do(req)
+		})
+	}
+
+	_, err := Wrap(s.doer, en.CallMiddleware(), retrying, en.AttemptMiddleware(1)).Do(s.newRequest(http.MethodGet))
+
+	s.Require().NoError(err)
+	s.Equal([]string{"before request", "before hook", "after hook", "before hook", "after hook"}, calls)
+	s.Equal("{", hookBody)
+	s.Len(s.requests, 2)
+}
+
+type closeTrackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeTrackingBody) Close() error {
+	b.closed = true
+	return nil
+}
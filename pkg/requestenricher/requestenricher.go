@@ -2,18 +2,303 @@
 package requestenricher
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"runtime/debug"
+	"time"
 )
 
+const (
+	// IdempotencyKeyHeader is the request header carrying the idempotency key of the mutating requests.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// DefaultBodyLimit is the number of the response body bytes passed to the AfterHook by default.
+	DefaultBodyLimit = 64 * 1024
+)
+
+// ErrHookPanicked is returned when a hook of the RequestEnricher panicked (see HookPanicError).
+var ErrHookPanicked = errors.New("request enricher hook panicked")
+
+// HookPanicError is returned instead of crashing when a hook of the RequestEnricher panics. The response of the
+// request is closed and the error is passed to the OnError hook.
+type HookPanicError struct {
+	// Hook is the name of the hook, i.e. AfterHook.
+	Hook string
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+	// Err is the error of the call if the OnError hook panicked.
+	Err error
+}
+
+func (e *HookPanicError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", ErrHookPanicked, e.Hook, e.Value)
+}
+
+// Is reports whether the target is ErrHookPanicked.
+func (e *HookPanicError) Is(target error) bool {
+	return target == ErrHookPanicked
+}
+
+// Unwrap returns the error of the call if the OnError hook panicked.
+func (e *HookPanicError) Unwrap() error {
+	return e.Err
+}
+
+// RecoverHook calls the hook and returns its panic as a *HookPanicError, so a panicking hook fails only its
+// request.
+func RecoverHook(hook string, fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &HookPanicError{Hook: hook, Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
+
+// CallOnError calls the OnError hook with the error of the request. It returns the error, or a *HookPanicError
+// wrapping it if the hook panicked.
+func CallOnError(onError func(*http.Request, error), req *http.Request, err error) error {
+	if hookErr := RecoverHook("OnError", func() error { onError(req, err); return nil }); hookErr != nil {
+		hookErr.(*HookPanicError).Err = err
+		return hookErr
+	}
+	return err
+}
+
 // RequestEnricher is passed to every client request and it helps the caller to have more control over the requests.
 // This could be helpful on using custom context or instrumenting the client calls i.e. for measuring request time.
+// Several enrichers can be passed to a call, i.e. for tracing, timing and auth hooks, they are combined by Chain.
 type RequestEnricher struct {
 	// Ctx is used to pass the callers context which may have a timeout for instance.
 	Ctx context.Context
+	// Timeout limits the time of the request including reading the response body.
+	// It overrides the client's global timeout only if it's shorter. It's not used when it's zero.
+	Timeout time.Duration
+	// IdempotencyKey is sent in the Idempotency-Key header of the mutating (POST, PUT, PATCH and DELETE) requests,
+	// so a request retried after a network failure is not applied twice. A random key is generated if it's empty.
+	IdempotencyKey string
+	// Header is set on the request, replacing the values of the same headers (i.e. a feature flag header).
+	Header http.Header
+	// Query is set on the url of the request, replacing the values of the same parameters.
+	Query url.Values
+	// BeforeRequest is called with the outgoing request before it's authorized and signed, so it can modify its
+	// header and query. It's called once per call, not on every retry. The call is aborted with the returned error.
+	BeforeRequest func(*http.Request) error
 	// BeforeHook is a function which runs before the client request.
 	BeforeHook func()
 	// AfterHook is a function which runs after the client request.
-	// The http response is passed without the body so the caller can inspect headers and other details.
+	// The http response is passed with a copy of the first DefaultBodyLimit bytes of its body (see PeekResponse and
+	// config.WithAfterHookBodyLimit), so the caller can inspect the headers and the body without consuming it.
 	AfterHook func(*http.Response)
+	// OnError is called once per call with the error of the call, i.e. when the request can't be sent or the
+	// client maps an unsuccessful response to an error. It helps to collect the failure metrics in one place.
+	OnError func(*http.Request, error)
+	// Trace enables collecting the time breakdown (DNS, connect, TLS, TTFB) of the requests. It can be read
+	// with TimingOf in the AfterHook, and it's recorded in the result.CallMeta of the *WithMeta methods.
+	Trace bool
+}
+
+// Chain combines the enrichers into one, so they can be used on the same call:
+//   - the BeforeRequest functions and the BeforeHooks run in the order of the enrichers and the AfterHooks in
+//     reverse order, so the first enricher wraps the others like a middleware
+//   - the BeforeRequest functions stop at the first error
+//   - the OnError functions run in the order of the enrichers
+//   - the Ctx and the IdempotencyKey of the last enricher setting them are used
+//   - the shortest Timeout is used
+//   - the Header and the Query are merged, the last enricher setting a header or a parameter wins
+//   - the requests are traced if any of the enrichers asks for it
+func Chain(en ...RequestEnricher) RequestEnricher {
+	if len(en) == 1 {
+		return en[0]
+	}
+
+	var chain RequestEnricher
+	var beforeRequests []func(*http.Request) error
+	var beforeHooks []func()
+	var afterHooks []func(*http.Response)
+	var onErrors []func(*http.Request, error)
+	for _, e := range en {
+		if e.Ctx != nil {
+			chain.Ctx = e.Ctx
+		}
+		if e.Timeout > 0 && (chain.Timeout == 0 || e.Timeout < chain.Timeout) {
+			chain.Timeout = e.Timeout
+		}
+		if e.IdempotencyKey != "" {
+			chain.IdempotencyKey = e.IdempotencyKey
+		}
+		for name, values := range e.Header {
+			if chain.Header == nil {
+				chain.Header = http.Header{}
+			}
+			chain.Header[name] = values
+		}
+		for name, values := range e.Query {
+			if chain.Query == nil {
+				chain.Query = url.Values{}
+			}
+			chain.Query[name] = values
+		}
+		if e.BeforeRequest != nil {
+			beforeRequests = append(beforeRequests, e.BeforeRequest)
+		}
+		if e.BeforeHook != nil {
+			beforeHooks = append(beforeHooks, e.BeforeHook)
+		}
+		if e.AfterHook != nil {
+			afterHooks = append(afterHooks, e.AfterHook)
+		}
+		if e.OnError != nil {
+			onErrors = append(onErrors, e.OnError)
+		}
+		chain.Trace = chain.Trace || e.Trace
+	}
+
+	if len(beforeRequests) > 0 {
+		chain.BeforeRequest = func(req *http.Request) error {
+			for _, beforeRequest := range beforeRequests {
+				if err := beforeRequest(req); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	if len(beforeHooks) > 0 {
+		chain.BeforeHook = func() {
+			for _, hook := range beforeHooks {
+				hook()
+			}
+		}
+	}
+	if len(afterHooks) > 0 {
+		chain.AfterHook = func(resp *http.Response) {
+			for i := len(afterHooks) - 1; i >= 0; i-- {
+				afterHooks[i](resp)
+			}
+		}
+	}
+	if len(onErrors) > 0 {
+		chain.OnError = func(req *http.Request, err error) {
+			for _, onError := range onErrors {
+				onError(req, err)
+			}
+		}
+	}
+	return chain
+}
+
+// WithHeader returns a RequestEnricher which sets the header of the request, i.e. to pass a feature flag header
+// on a single call.
+func WithHeader(name, value string) RequestEnricher {
+	header := http.Header{}
+	header.Set(name, value)
+	return RequestEnricher{Header: header}
+}
+
+// WithQueryParam returns a RequestEnricher which sets the query parameter of the request.
+func WithQueryParam(name, value string) RequestEnricher {
+	return RequestEnricher{Query: url.Values{name: []string{value}}}
+}
+
+// SetOn sets the Header and the Query of the enricher on the request. The url of the request is replaced,
+// so it's not shared with the original request.
+func (en RequestEnricher) SetOn(req *http.Request) {
+	if len(en.Header) > 0 && req.Header == nil {
+		req.Header = http.Header{}
+	}
+	for name, values := range en.Header {
+		req.Header[name] = values
+	}
+	if len(en.Query) > 0 {
+		query := req.URL.Query()
+		for name, values := range en.Query {
+			query[name] = values
+		}
+		u := *req.URL
+		u.RawQuery = query.Encode()
+		req.URL = &u
+	}
+}
+
+// WithBeforeRequest returns a RequestEnricher which calls fn with the outgoing request (see RequestEnricher.BeforeRequest),
+// i.e. to add a tracing header.
+func WithBeforeRequest(fn func(*http.Request) error) RequestEnricher {
+	return RequestEnricher{BeforeRequest: fn}
+}
+
+// WithOnError returns a RequestEnricher which calls fn with the errors of the calls (see RequestEnricher.OnError).
+func WithOnError(fn func(*http.Request, error)) RequestEnricher {
+	return RequestEnricher{OnError: fn}
+}
+
+// WithTimeout returns a RequestEnricher which limits the time of the request, i.e. to give a fast account fetch
+// a shorter budget than the client's global timeout.
+func WithTimeout(timeout time.Duration) RequestEnricher {
+	return RequestEnricher{Timeout: timeout}
+}
+
+// WithTrace returns a RequestEnricher which collects the time breakdown of the requests (see RequestEnricher.Trace).
+func WithTrace() RequestEnricher {
+	return RequestEnricher{Trace: true}
+}
+
+// WithIdempotencyKey returns a RequestEnricher which sends the key in the Idempotency-Key header, i.e. to reuse
+// the key of a request which failed with a network error when it's sent again.
+func WithIdempotencyKey(key string) RequestEnricher {
+	return RequestEnricher{IdempotencyKey: key}
+}
+
+// PeekResponse returns a copy of the response with the first limit bytes of its body, and replaces the body of
+// resp with one replaying them before the rest, so it can still be read whole. The copy has an empty body if the
+// limit is not positive.
+func PeekResponse(resp *http.Response, limit int) *http.Response {
+	var buf []byte
+	if limit > 0 {
+		var err error
+		buf, err = io.ReadAll(io.LimitReader(resp.Body, int64(limit)))
+		var rest io.Reader = resp.Body
+		if err != nil {
+			rest = errReader{err: err}
+		}
+		resp.Body = replayBody{Reader: io.MultiReader(bytes.NewReader(buf), rest), Closer: resp.Body}
+	}
+
+	return &http.Response{
+		Status:           resp.Status,
+		StatusCode:       resp.StatusCode,
+		Proto:            resp.Proto,
+		ProtoMajor:       resp.ProtoMajor,
+		ProtoMinor:       resp.ProtoMinor,
+		Header:           resp.Header,
+		Body:             io.NopCloser(bytes.NewReader(buf)),
+		ContentLength:    resp.ContentLength,
+		TransferEncoding: resp.TransferEncoding,
+		Close:            resp.Close,
+		Uncompressed:     resp.Uncompressed,
+		Trailer:          resp.Trailer,
+		Request:          resp.Request,
+		TLS:              resp.TLS,
+	}
+}
+
+// replayBody reads the buffered part of the response body before the rest of it.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader returns the error of reading the buffered part of the response body after it.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
 }
@@ -0,0 +1,194 @@
+package requestenricher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type requestEnricherTestSuite struct {
+	suite.Suite
+}
+
+func TestRequestEnricherTestSuite(t *testing.T) {
+	suite.Run(t, new(requestEnricherTestSuite))
+}
+
+func (s *requestEnricherTestSuite) TestChainRunsHooksInOrder() {
+	var calls []string
+	hooks := func(name string) RequestEnricher {
+		return RequestEnricher{
+			BeforeHook: func() { calls = append(calls, "before "+name) },
+			AfterHook:  func(*http.Response) { calls = append(calls, "after "+name) },
+		}
+	}
+
+	chain := Chain(hooks("tracing"), WithTrace(), hooks("timing"))
+	chain.BeforeHook()
+	chain.AfterHook(&http.Response{})
+
+	s.Equal([]string{"before tracing", "before timing", "after timing", "after tracing"}, calls)
+	s.True(chain.Trace)
+}
+
+func (s *requestEnricherTestSuite) TestChainStopsBeforeRequests_WhenOneFails() {
+	var calls []string
+	expectedErr := errors.New("not allowed")
+	before := func(name string, err error) RequestEnricher {
+		return WithBeforeRequest(func(*http.Request) error {
+			calls = append(calls, name)
+			return err
+		})
+	}
+
+	err := Chain(before("first", nil), before("second", expectedErr), before("third", nil)).BeforeRequest(&http.Request{})
+
+	s.ErrorIs(err, expectedErr)
+	s.Equal([]string{"first", "second"}, calls)
+}
+
+func (s *requestEnricherTestSuite) TestChainCallsOnErrors() {
+	expectedErr := errors.New("not found")
+	var calls []string
+	onError := func(name string) RequestEnricher {
+		return WithOnError(func(_ *http.Request, err error) {
+			s.Equal(expectedErr, err)
+			calls = append(calls, name)
+		})
+	}
+
+	Chain(onError("metrics"), WithTrace(), onError("alerting")).OnError(&http.Request{}, expectedErr)
+
+	s.Equal([]string{"metrics", "alerting"}, calls)
+}
+
+func (s *requestEnricherTestSuite) TestChainMergesSettings() {
+	type key struct{}
+	first := context.WithValue(context.Background(), key{}, "first")
+	last := context.WithValue(context.Background(), key{}, "last")
+
+	chain := Chain(
+		RequestEnricher{Ctx: first, Timeout: time.Second, IdempotencyKey: "key-1"},
+		WithTimeout(2*time.Second),
+		RequestEnricher{Ctx: last, IdempotencyKey: "key-2"},
+		WithTimeout(500*time.Millisecond),
+	)
+
+	s.Same(last, chain.Ctx)
+	s.Equal(500*time.Millisecond, chain.Timeout)
+	s.Equal("key-2", chain.IdempotencyKey)
+	s.False(chain.Trace)
+	s.Nil(chain.BeforeHook)
+	s.Nil(chain.AfterHook)
+}
+
+func (s *requestEnricherTestSuite) TestChainMergesHeaderAndQuery() {
+	chain := Chain(
+		WithHeader("x-feature-flag", "new-scheme"),
+		WithQueryParam("filter[bank_id]", "400300"),
+		WithHeader("X-Feature-Flag", "sepa"),
+		WithHeader("X-Trace-Id", "trace-1"),
+		WithQueryParam("filter[country]", "GB"),
+	)
+
+	s.Equal(http.Header{"X-Feature-Flag": []string{"sepa"}, "X-Trace-Id": []string{"trace-1"}}, chain.Header)
+	s.Equal(url.Values{"filter[bank_id]": []string{"400300"}, "filter[country]": []string{"GB"}}, chain.Query)
+}
+
+func (s *requestEnricherTestSuite) TestSetOn() {
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/v1/organisation/accounts?page[size]=10&filter[country]=FR", nil)
+	s.Require().NoError(err)
+	req.Header.Set("X-Feature-Flag", "old")
+	original := req.URL
+
+	Chain(WithHeader("X-Feature-Flag", "sepa"), WithQueryParam("filter[country]", "GB")).SetOn(req)
+
+	s.Equal("sepa", req.Header.Get("X-Feature-Flag"))
+	s.Equal(url.Values{"page[size]": []string{"10"}, "filter[country]": []string{"GB"}}, req.URL.Query())
+	s.Equal("page[size]=10&filter[country]=FR", original.RawQuery)
+}
+
+func (s *requestEnricherTestSuite) TestChainOfOneOrNone() {
+	s.Equal(RequestEnricher{}, Chain())
+	s.Equal(WithIdempotencyKey("key"), Chain(WithIdempotencyKey("key")))
+}
+
+func (s *requestEnricherTestSuite) TestPeekResponse() {
+	body := `{"data":{"id":"1"}}`
+	testCases := []struct {
+		name     string
+		limit    int
+		expected string
+	}{
+		{name: "whole body", limit: DefaultBodyLimit, expected: body},
+		{name: "truncated body", limit: 9, expected: `{"data":{`},
+		{name: "disabled", limit: 0, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Request-Id": []string{"1"}}, Body: io.NopCloser(strings.NewReader(body))}
+
+			peeked := PeekResponse(resp, tc.limit)
+
+			s.Equal(http.StatusOK, peeked.StatusCode)
+			s.Equal("1", peeked.Header.Get("X-Request-Id"))
+			peekedBody, err := io.ReadAll(peeked.Body)
+			s.Require().NoError(err)
+			s.Equal(tc.expected, string(peekedBody))
+			actual, err := io.ReadAll(resp.Body)
+			s.Require().NoError(err)
+			s.Equal(body, string(actual))
+		})
+	}
+}
+
+func (s *requestEnricherTestSuite) TestPeekResponseKeepsReadError() {
+	errRead := errors.New("unexpected EOF")
+	resp := &http.Response{Body: io.NopCloser(io.MultiReader(strings.NewReader("{"), iotest.ErrReader(errRead)))}
+
+	peeked := PeekResponse(resp, DefaultBodyLimit)
+
+	peekedBody, err := io.ReadAll(peeked.Body)
+	s.Require().NoError(err)
+	s.Equal("{", string(peekedBody))
+	actual, err := io.ReadAll(resp.Body)
+	s.ErrorIs(err, errRead)
+	s.Equal("{", string(actual))
+}
+
+func (s *requestEnricherTestSuite) TestRecoverHook() {
+	s.NoError(RecoverHook("BeforeHook", func() error { return nil }))
+
+	err := RecoverHook("BeforeHook", func() error { panic("boom") })
+
+	s.ErrorIs(err, ErrHookPanicked)
+	var panicErr *HookPanicError
+	s.Require().ErrorAs(err, &panicErr)
+	s.Equal("BeforeHook", panicErr.Hook)
+	s.Equal("boom", panicErr.Value)
+	s.NotEmpty(panicErr.Stack)
+	s.EqualError(err, "request enricher hook panicked: BeforeHook: boom")
+}
+
+func (s *requestEnricherTestSuite) TestCallOnError() {
+	errNotFound := errors.New("not found")
+	req, err := http.NewRequest(http.MethodGet, "http://testhost", nil)
+	s.Require().NoError(err)
+	var reported error
+
+	s.Equal(errNotFound, CallOnError(func(_ *http.Request, err error) { reported = err }, req, errNotFound))
+	s.Equal(errNotFound, reported)
+
+	err = CallOnError(func(*http.Request, error) { panic("boom") }, req, errNotFound)
+	s.ErrorIs(err, ErrHookPanicked)
+	s.ErrorIs(err, errNotFound)
+}
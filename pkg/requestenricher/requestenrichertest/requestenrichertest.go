@@ -0,0 +1,140 @@
+// Package requestenrichertest provides request enrichers for testing the instrumentation built on the
+// RequestEnricher hooks: a Recorder capturing the hook calls with their requests, responses and errors, and
+// enrichers simulating slow hooks.
+package requestenrichertest
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	re "form3interview/pkg/requestenricher"
+)
+
+// The names of the hooks recorded in Call.Hook.
+const (
+	BeforeRequest = "BeforeRequest"
+	BeforeHook    = "BeforeHook"
+	AfterHook     = "AfterHook"
+	OnError       = "OnError"
+)
+
+// Call is a recorded call of a hook.
+type Call struct {
+	// Hook is the name of the called hook, i.e. AfterHook.
+	Hook string
+	// Request is a clone of the request passed to BeforeRequest or OnError.
+	Request *http.Request
+	// Response is the response passed to AfterHook.
+	Response *http.Response
+	// Body is the body of the response passed to AfterHook.
+	Body []byte
+	// Err is the error passed to OnError.
+	Err error
+}
+
+// Recorder records the calls of the hooks of its enricher. It's safe for concurrent use, so one Recorder can be
+// passed to parallel calls.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewRecorder creates a Recorder without calls.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Enricher returns the enricher recording its BeforeRequest, BeforeHook, AfterHook and OnError calls.
+func (r *Recorder) Enricher() re.RequestEnricher {
+	return re.RequestEnricher{
+		BeforeRequest: func(req *http.Request) error {
+			r.record(Call{Hook: BeforeRequest, Request: req.Clone(req.Context())})
+			return nil
+		},
+		BeforeHook: func() {
+			r.record(Call{Hook: BeforeHook})
+		},
+		AfterHook: func(resp *http.Response) {
+			body, _ := io.ReadAll(resp.Body)
+			r.record(Call{Hook: AfterHook, Response: resp, Body: body})
+		},
+		OnError: func(req *http.Request, err error) {
+			r.record(Call{Hook: OnError, Request: req.Clone(req.Context()), Err: err})
+		},
+	}
+}
+
+func (r *Recorder) record(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+// Calls returns the recorded calls in the order of the hook calls.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Hooks returns the names of the called hooks in the order of the calls.
+func (r *Recorder) Hooks() []string {
+	var hooks []string
+	for _, call := range r.Calls() {
+		hooks = append(hooks, call.Hook)
+	}
+	return hooks
+}
+
+// Requests returns the requests passed to BeforeRequest.
+func (r *Recorder) Requests() []*http.Request {
+	var requests []*http.Request
+	for _, call := range r.Calls() {
+		if call.Hook == BeforeRequest {
+			requests = append(requests, call.Request)
+		}
+	}
+	return requests
+}
+
+// Responses returns the calls of AfterHook with the responses and their bodies.
+func (r *Recorder) Responses() []Call {
+	var responses []Call
+	for _, call := range r.Calls() {
+		if call.Hook == AfterHook {
+			responses = append(responses, call)
+		}
+	}
+	return responses
+}
+
+// Errors returns the errors passed to OnError.
+func (r *Recorder) Errors() []error {
+	var errs []error
+	for _, call := range r.Calls() {
+		if call.Hook == OnError {
+			errs = append(errs, call.Err)
+		}
+	}
+	return errs
+}
+
+// Reset removes the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// SlowBeforeHook returns an enricher with a BeforeHook sleeping for the delay, i.e. to test the slow request
+// reporting.
+func SlowBeforeHook(delay time.Duration) re.RequestEnricher {
+	return re.RequestEnricher{BeforeHook: func() { time.Sleep(delay) }}
+}
+
+// SlowAfterHook returns an enricher with an AfterHook sleeping for the delay.
+func SlowAfterHook(delay time.Duration) re.RequestEnricher {
+	return re.RequestEnricher{AfterHook: func(*http.Response) { time.Sleep(delay) }}
+}
@@ -0,0 +1,74 @@
+package requestenrichertest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	re "form3interview/pkg/requestenricher"
+)
+
+type requestEnricherTestTestSuite struct {
+	suite.Suite
+}
+
+func TestRequestEnricherTestTestSuite(t *testing.T) {
+	suite.Run(t, new(requestEnricherTestTestSuite))
+}
+
+func (s *requestEnricherTestTestSuite) do(doer re.DoerFunc, enricher ...re.RequestEnricher) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, "http://testhost/v1/organisation/accounts", nil)
+	s.Require().NoError(err)
+	return re.Wrap(doer, re.Chain(enricher...).Middleware()).Do(req)
+}
+
+func (s *requestEnricherTestTestSuite) TestRecorder() {
+	recorder := NewRecorder()
+	doer := func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"data":{}}`)), Request: req}, nil
+	}
+
+	resp, err := s.do(doer, re.WithHeader("X-Feature-Flag", "sepa"), recorder.Enricher())
+	s.Require().NoError(err)
+	resp.Body.Close()
+
+	s.Equal([]string{BeforeRequest, BeforeHook, AfterHook}, recorder.Hooks())
+	s.Require().Len(recorder.Requests(), 1)
+	s.Equal("sepa", recorder.Requests()[0].Header.Get("X-Feature-Flag"))
+	s.Require().Len(recorder.Responses(), 1)
+	s.Equal(http.StatusOK, recorder.Responses()[0].Response.StatusCode)
+	s.Equal(`{"data":{}}`, string(recorder.Responses()[0].Body))
+	s.Empty(recorder.Errors())
+
+	recorder.Reset()
+	s.Empty(recorder.Calls())
+}
+
+func (s *requestEnricherTestTestSuite) TestRecorderRecordsErrors() {
+	errReset := errors.New("connection reset by peer")
+	recorder := NewRecorder()
+
+	_, err := s.do(func(*http.Request) (*http.Response, error) { return nil, errReset }, recorder.Enricher())
+
+	s.ErrorIs(err, errReset)
+	s.Equal([]string{BeforeRequest, BeforeHook, OnError}, recorder.Hooks())
+	s.Equal([]error{errReset}, recorder.Errors())
+	s.Equal("/v1/organisation/accounts", recorder.Calls()[2].Request.URL.Path)
+}
+
+func (s *requestEnricherTestTestSuite) TestSlowHooks() {
+	doer := func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	}
+
+	start := time.Now()
+	_, err := s.do(doer, SlowBeforeHook(20*time.Millisecond), SlowAfterHook(30*time.Millisecond))
+
+	s.Require().NoError(err)
+	s.GreaterOrEqual(time.Since(start), 50*time.Millisecond)
+}
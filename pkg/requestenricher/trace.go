@@ -0,0 +1,91 @@
+package requestenricher
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"form3interview/pkg/result"
+)
+
+type tracerKey struct{}
+
+// tracer collects the timing of a request from the httptrace hooks. The hooks may be called from other
+// goroutines, i.e. the connect hooks of the dials racing for the same connection.
+type tracer struct {
+	mu                                             sync.Mutex
+	start, dnsStart, connectStart, tlsStart, wrote time.Time
+	timing                                         result.Timing
+}
+
+// Trace returns the request with a client trace collecting its timing, which can be read with TimingOf from
+// its response.
+func Trace(req *http.Request) *http.Request {
+	t := &tracer{}
+	ctx := httptrace.WithClientTrace(req.Context(), t.clientTrace())
+	return req.WithContext(context.WithValue(ctx, tracerKey{}, t))
+}
+
+// TimingOf returns the timing of the request of the response if it was traced.
+func TimingOf(resp *http.Response) (result.Timing, bool) {
+	if resp == nil || resp.Request == nil {
+		return result.Timing{}, false
+	}
+	t, ok := resp.Request.Context().Value(tracerKey{}).(*tracer)
+	if !ok {
+		return result.Timing{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.timing, true
+}
+
+func (t *tracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.set(func() { t.start = time.Now() })
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.set(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.set(func() { t.timing.DNS = time.Now().Sub(t.dnsStart) })
+		},
+		ConnectStart: func(_, _ string) {
+			t.set(func() { t.connectStart = time.Now() })
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.set(func() { t.timing.Connect = time.Now().Sub(t.connectStart) })
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.set(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.set(func() { t.timing.TLS = time.Now().Sub(t.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.set(func() { t.timing.ReusedConn = info.Reused })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.set(func() { t.wrote = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			t.set(func() {
+				t.timing.TTFB = time.Now().Sub(t.wrote)
+				t.timing.Total = time.Now().Sub(t.start)
+			})
+		},
+	}
+}
+
+func (t *tracer) set(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn()
+}
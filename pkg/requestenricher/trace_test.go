@@ -0,0 +1,72 @@
+package requestenricher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type traceTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func TestTraceTestSuite(t *testing.T) {
+	suite.Run(t, new(traceTestSuite))
+}
+
+func (s *traceTestSuite) SetupTest() {
+	s.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+	}))
+}
+
+func (s *traceTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *traceTestSuite) send() *http.Response {
+	req, err := http.NewRequest(http.MethodGet, s.server.URL, nil)
+	s.Require().NoError(err)
+	resp, err := s.server.Client().Do(Trace(req))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	return resp
+}
+
+func (s *traceTestSuite) TestTimingOf() {
+	timing, ok := TimingOf(s.send())
+
+	s.Require().True(ok)
+	s.Positive(timing.Connect)
+	s.Positive(timing.TLS)
+	s.GreaterOrEqual(timing.TTFB, 10*time.Millisecond)
+	s.GreaterOrEqual(timing.Total, timing.Connect+timing.TLS+timing.TTFB)
+	s.False(timing.ReusedConn)
+}
+
+func (s *traceTestSuite) TestTimingOfReusedConnection() {
+	s.send()
+
+	timing, ok := TimingOf(s.send())
+
+	s.Require().True(ok)
+	s.True(timing.ReusedConn)
+	s.Zero(timing.Connect)
+	s.Zero(timing.TLS)
+	s.Positive(timing.TTFB)
+}
+
+func (s *traceTestSuite) TestTimingOfReturnsFalse_WhenRequestIsNotTraced() {
+	req, err := http.NewRequest(http.MethodGet, s.server.URL, nil)
+	s.Require().NoError(err)
+
+	_, ok := TimingOf(&http.Response{Request: req})
+
+	s.False(ok)
+	_, ok = TimingOf(nil)
+	s.False(ok)
+}
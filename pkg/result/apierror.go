@@ -0,0 +1,117 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultErrorBodyLimit is the number of the body bytes captured by NewAPIError.
+const DefaultErrorBodyLimit = 64 * 1024
+
+// APIError is returned for the unsuccessful responses of the API with the diagnostics sent by the server. It
+// matches the error the client mapped the response to (i.e. account.ErrInvalidRequest) with errors.Is, so the
+// callers can branch on the category and log the details:
+//
+//	var apiErr *result.APIError
+//	if errors.As(err, &apiErr) {
+//		log.Printf("request %s failed: %s", apiErr.RequestID, apiErr.ErrorMessage)
+//	}
+type APIError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// ErrorCode is the error_code field of the response body, if there was any.
+	ErrorCode string
+	// ErrorMessage is the error_message field of the response body, if there was any.
+	ErrorMessage string
+	// RequestID is the value of the X-Request-Id header of the response, if there was any.
+	RequestID string
+	// RawBody is the body of the response, cut at the limit of NewAPIErrorWithLimit.
+	RawBody []byte
+	// Truncated tells that the body was longer than the limit and RawBody holds only its beginning.
+	Truncated bool
+	// Validation holds the fields which failed the validation of a 400 Bad Request response.
+	Validation []ValidationError
+	// Err is the error the response is mapped to.
+	Err error
+}
+
+// errorBody is the error response body of the API.
+type errorBody struct {
+	ErrorCode    string `json:"error_code,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// NewAPIError reads the body of the unsuccessful response, and returns an *APIError matching err. When reading
+// the body fails, err is returned wrapping the read error. The body doesn't have to be JSON, it's kept in RawBody
+// anyway. At most DefaultErrorBodyLimit bytes of the body are read.
+func NewAPIError(resp *http.Response, err error) error {
+	return NewAPIErrorWithLimit(resp, err, DefaultErrorBodyLimit)
+}
+
+// NewAPIErrorWithLimit is NewAPIError reading at most limit bytes of the body, the rest is left unread. The
+// body is not limited if the limit is not positive.
+func NewAPIErrorWithLimit(resp *http.Response, err error, limit int) error {
+	body, truncated, readErr := readBody(resp.Body, limit)
+	if readErr != nil {
+		return Wrap(fmt.Errorf("%w: [%d] reading the body", err, resp.StatusCode), readErr)
+	}
+
+	var eb errorBody
+	_ = json.Unmarshal(body, &eb)
+	apiErr := &APIError{
+		StatusCode:   resp.StatusCode,
+		ErrorCode:    eb.ErrorCode,
+		ErrorMessage: eb.ErrorMessage,
+		RequestID:    resp.Header.Get(RequestIDHeader),
+		RawBody:      body,
+		Truncated:    truncated,
+		Err:          err,
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		apiErr.Validation = ParseValidationErrors(eb.ErrorMessage)
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s: [%d]", e.Err, e.StatusCode)
+	if e.ErrorCode != "" {
+		msg += " " + e.ErrorCode
+	}
+	if e.ErrorMessage != "" {
+		msg += " " + e.ErrorMessage
+	} else if len(e.RawBody) > 0 {
+		msg += " " + string(e.RawBody)
+	}
+	if e.Truncated {
+		msg += " [truncated]"
+	}
+	if e.RequestID != "" {
+		msg += " (request " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the error the response is mapped to.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// readBody reads at most limit bytes of the body and tells if there were more.
+func readBody(body io.Reader, limit int) ([]byte, bool, error) {
+	if limit <= 0 {
+		b, err := io.ReadAll(body)
+		return b, false, err
+	}
+
+	b, err := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(b) > limit {
+		return b[:limit], true, nil
+	}
+	return b, false, nil
+}
@@ -0,0 +1,99 @@
+package result
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/suite"
+)
+
+var errInvalidRequest = errors.New("invalid request")
+
+type apiErrorTestSuite struct {
+	suite.Suite
+}
+
+func TestAPIErrorTestSuite(t *testing.T) {
+	suite.Run(t, new(apiErrorTestSuite))
+}
+
+func newResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{RequestIDHeader: []string{"request-1"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func (s *apiErrorTestSuite) TestNewAPIError() {
+	body := `{"error_code":"4d7f0f4c","error_message":"base_currency is required"}`
+
+	err := NewAPIError(newResponse(http.StatusBadRequest, body), errInvalidRequest)
+
+	s.ErrorIs(err, errInvalidRequest)
+	var apiErr *APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal(&APIError{
+		StatusCode:   http.StatusBadRequest,
+		ErrorCode:    "4d7f0f4c",
+		ErrorMessage: "base_currency is required",
+		RequestID:    "request-1",
+		RawBody:      []byte(body),
+		Err:          errInvalidRequest,
+	}, apiErr)
+	s.EqualError(err, "invalid request: [400] 4d7f0f4c base_currency is required (request request-1)")
+}
+
+func (s *apiErrorTestSuite) TestNewAPIErrorKeepsBodyWhichIsNotJSON() {
+	err := NewAPIError(newResponse(http.StatusTeapot, "oops"), errInvalidRequest)
+
+	var apiErr *APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Empty(apiErr.ErrorMessage)
+	s.Equal([]byte("oops"), apiErr.RawBody)
+	s.EqualError(err, "invalid request: [418] oops (request request-1)")
+}
+
+func (s *apiErrorTestSuite) TestNewAPIErrorWithLimitTruncatesBody() {
+	body := strings.NewReader("<html>" + strings.Repeat("x", 100) + "</html>")
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(body)}
+
+	err := NewAPIErrorWithLimit(resp, errInvalidRequest, 10)
+
+	var apiErr *APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal([]byte("<html>xxxx"), apiErr.RawBody)
+	s.True(apiErr.Truncated)
+	s.EqualError(err, "invalid request: [502] <html>xxxx [truncated]")
+	s.Equal(102, body.Len())
+}
+
+func (s *apiErrorTestSuite) TestNewAPIErrorWithLimitKeepsBodyOfLimitSize() {
+	err := NewAPIErrorWithLimit(newResponse(http.StatusTeapot, "oops"), errInvalidRequest, 4)
+
+	var apiErr *APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal([]byte("oops"), apiErr.RawBody)
+	s.False(apiErr.Truncated)
+
+	err = NewAPIErrorWithLimit(newResponse(http.StatusTeapot, "oops"), errInvalidRequest, 0)
+
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal([]byte("oops"), apiErr.RawBody)
+	s.False(apiErr.Truncated)
+}
+
+func (s *apiErrorTestSuite) TestNewAPIErrorWrapsReadError() {
+	errRead := errors.New("unexpected EOF")
+	resp := &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(iotest.ErrReader(errRead))}
+
+	err := NewAPIError(resp, errInvalidRequest)
+
+	s.ErrorIs(err, errInvalidRequest)
+	s.ErrorIs(err, errRead)
+	s.EqualError(err, "invalid request: [400] reading the body: unexpected EOF")
+}
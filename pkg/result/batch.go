@@ -0,0 +1,103 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BatchResult is the outcome of a bulk operation which continues after the failed items, so the callers can
+// handle the partial failures the same way for all bulk helpers. The zero value is an empty result.
+//
+//	var batch result.BatchResult[*account.AccountData]
+//	for i, attributes := range accounts {
+//		created, err := client.Create(attributes)
+//		if err != nil {
+//			batch.Fail(i, "", err)
+//			continue
+//		}
+//		batch.Succeed(created)
+//	}
+//	if err := batch.Err(); err != nil {
+//		log.Printf("%d accounts were created: %s", len(batch.Succeeded), err)
+//	}
+type BatchResult[T any] struct {
+	// Succeeded are the results of the successful items in the order of the input.
+	Succeeded []T
+	// Failed are the errors of the failed items in the order of the input.
+	Failed []BatchItemError
+}
+
+// BatchItemError is the error of an item of a bulk operation.
+type BatchItemError struct {
+	// Index is the index of the item in the input of the operation.
+	Index int
+	// Key identifies the item (i.e. its ID), it can be empty.
+	Key string
+	// Err is the error the item failed with.
+	Err error
+}
+
+func (e BatchItemError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("item %d: %s", e.Index, e.Err)
+	}
+	return fmt.Sprintf("item %d (%s): %s", e.Index, e.Key, e.Err)
+}
+
+// Unwrap returns the error of the item.
+func (e BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// Succeed adds the result of a successful item.
+func (r *BatchResult[T]) Succeed(value T) {
+	r.Succeeded = append(r.Succeeded, value)
+}
+
+// Fail adds the error of a failed item.
+func (r *BatchResult[T]) Fail(index int, key string, err error) {
+	r.Failed = append(r.Failed, BatchItemError{Index: index, Key: key, Err: err})
+}
+
+// Total returns the number of items processed.
+func (r *BatchResult[T]) Total() int {
+	return len(r.Succeeded) + len(r.Failed)
+}
+
+// Err returns nil if all the items succeeded, otherwise the result itself as an error.
+func (r *BatchResult[T]) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	return r
+}
+
+// Error aggregates the errors of the failed items.
+func (r *BatchResult[T]) Error() string {
+	errs := make([]string, len(r.Failed))
+	for i, failed := range r.Failed {
+		errs[i] = failed.Error()
+	}
+	return fmt.Sprintf("%d of %d items failed: %s", len(r.Failed), r.Total(), strings.Join(errs, "; "))
+}
+
+// Is reports whether the error of any failed item matches the target.
+func (r *BatchResult[T]) Is(target error) bool {
+	for _, failed := range r.Failed {
+		if errors.Is(failed, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the failed items matching the target.
+func (r *BatchResult[T]) As(target any) bool {
+	for _, failed := range r.Failed {
+		if errors.As(failed, target) {
+			return true
+		}
+	}
+	return false
+}
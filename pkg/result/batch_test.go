@@ -0,0 +1,48 @@
+package result
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+var errNotFound = errors.New("not found")
+
+type batchTestSuite struct {
+	suite.Suite
+}
+
+func TestBatchTestSuite(t *testing.T) {
+	suite.Run(t, new(batchTestSuite))
+}
+
+func (s *batchTestSuite) TestErrIsNil_WhenAllItemsSucceeded() {
+	var batch BatchResult[string]
+	s.NoError(batch.Err())
+
+	batch.Succeed("a")
+	batch.Succeed("b")
+
+	s.NoError(batch.Err())
+	s.Equal([]string{"a", "b"}, batch.Succeeded)
+	s.Equal(2, batch.Total())
+}
+
+func (s *batchTestSuite) TestPartialFailure() {
+	var batch BatchResult[string]
+	batch.Succeed("a")
+	batch.Fail(1, "b", errNotFound)
+	batch.Fail(2, "", errInvalidRequest)
+
+	err := batch.Err()
+
+	s.Require().Error(err)
+	s.EqualError(err, "2 of 3 items failed: item 1 (b): not found; item 2: invalid request")
+	s.ErrorIs(err, errNotFound)
+	s.ErrorIs(err, errInvalidRequest)
+	var itemErr BatchItemError
+	s.Require().ErrorAs(err, &itemErr)
+	s.Equal(BatchItemError{Index: 1, Key: "b", Err: errNotFound}, itemErr)
+	s.Equal([]string{"a"}, batch.Succeeded)
+}
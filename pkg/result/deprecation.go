@@ -0,0 +1,109 @@
+package result
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DeprecationHeader is the response header telling that the endpoint is deprecated (RFC 9745). Its value is
+	// the date of the deprecation as @<unix seconds>, an HTTP date or true.
+	DeprecationHeader = "Deprecation"
+	// SunsetHeader is the response header carrying the HTTP date when the endpoint stops working (RFC 8594).
+	SunsetHeader = "Sunset"
+	// deprecatedWarningCode is the code of the Warning header values sent for the deprecated endpoints.
+	deprecatedWarningCode = "299"
+	// dateLayout formats the deprecation dates.
+	dateLayout = "2006-01-02"
+)
+
+// deprecationLink matches the Link header values pointing to the documentation of the deprecation or the sunset.
+var deprecationLink = regexp.MustCompile(`<([^>]*)>\s*;[^,]*rel="?(?:deprecation|sunset)"?`)
+
+// Deprecation describes the deprecation of an endpoint announced by the headers of its responses.
+type Deprecation struct {
+	// Date is when the endpoint was or will be deprecated. It's zero if the API didn't tell it.
+	Date time.Time
+	// Sunset is when the endpoint stops working. It's zero if the API didn't tell it.
+	Sunset time.Time
+	// Warnings are the texts of the 299 Warning headers (i.e. "Deprecated API").
+	Warnings []string
+	// Link is the URL of the documentation of the deprecation, if there is any.
+	Link string
+}
+
+// DeprecationOf returns the deprecation announced by the Deprecation, Sunset and 299 Warning headers of the
+// response. It returns false if the response has none of them.
+func DeprecationOf(resp *http.Response) (Deprecation, bool) {
+	if resp == nil {
+		return Deprecation{}, false
+	}
+
+	var d Deprecation
+	deprecation := resp.Header.Get(DeprecationHeader)
+	sunset := resp.Header.Get(SunsetHeader)
+	d.Date = parseDeprecationDate(deprecation)
+	if t, err := http.ParseTime(sunset); err == nil {
+		d.Sunset = t
+	}
+	for _, warning := range resp.Header.Values(WarningHeader) {
+		if text, ok := deprecationWarning(warning); ok {
+			d.Warnings = append(d.Warnings, text)
+		}
+	}
+	if deprecation == "" && sunset == "" && len(d.Warnings) == 0 {
+		return Deprecation{}, false
+	}
+
+	for _, link := range resp.Header.Values("Link") {
+		if m := deprecationLink.FindStringSubmatch(link); m != nil {
+			d.Link = m[1]
+			break
+		}
+	}
+	return d, true
+}
+
+// String describes the deprecation in one line for the logs.
+func (d Deprecation) String() string {
+	parts := []string{"deprecated"}
+	if !d.Date.IsZero() {
+		parts = append(parts, "since "+d.Date.UTC().Format(dateLayout))
+	}
+	if !d.Sunset.IsZero() {
+		parts = append(parts, "sunset on "+d.Sunset.UTC().Format(dateLayout))
+	}
+	parts = append(parts, d.Warnings...)
+	if d.Link != "" {
+		parts = append(parts, "see "+d.Link)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func parseDeprecationDate(value string) time.Time {
+	if strings.HasPrefix(value, "@") {
+		if unix, err := strconv.ParseInt(value[1:], 10, 64); err == nil {
+			return time.Unix(unix, 0).UTC()
+		}
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// deprecationWarning returns the text of a 299 warning value (i.e. 299 - "Deprecated API").
+func deprecationWarning(value string) (string, bool) {
+	fields := strings.SplitN(strings.TrimSpace(value), " ", 3)
+	if len(fields) < 3 || fields[0] != deprecatedWarningCode {
+		return "", false
+	}
+	text := fields[2]
+	if end := strings.LastIndex(text, `"`); strings.HasPrefix(text, `"`) && end > 0 {
+		text = text[1:end]
+	}
+	return text, true
+}
@@ -0,0 +1,84 @@
+package result
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type deprecationTestSuite struct {
+	suite.Suite
+}
+
+func TestDeprecationTestSuite(t *testing.T) {
+	suite.Run(t, new(deprecationTestSuite))
+}
+
+func (s *deprecationTestSuite) TestDeprecationOf() {
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name     string
+		header   http.Header
+		expected Deprecation
+	}{
+		{
+			name:     "unix deprecation date",
+			header:   http.Header{DeprecationHeader: []string{"@1767225600"}},
+			expected: Deprecation{Date: date},
+		},
+		{
+			name:     "http deprecation date",
+			header:   http.Header{DeprecationHeader: []string{"Thu, 01 Jan 2026 00:00:00 GMT"}},
+			expected: Deprecation{Date: date},
+		},
+		{
+			name:     "deprecated without date",
+			header:   http.Header{DeprecationHeader: []string{"true"}},
+			expected: Deprecation{},
+		},
+		{
+			name: "sunset with link",
+			header: http.Header{
+				SunsetHeader: []string{"Wed, 01 Jul 2026 00:00:00 GMT"},
+				"Link":       []string{`<https://api.form3.tech/v1/organisation>; rel="next"`, `<https://docs.form3.tech/deprecations>; rel="sunset"; type="text/html"`},
+			},
+			expected: Deprecation{Sunset: sunset, Link: "https://docs.form3.tech/deprecations"},
+		},
+		{
+			name:     "warnings",
+			header:   http.Header{WarningHeader: []string{StaleWarning, `299 - "Deprecated API"`, `299 api.form3.tech "Use /v2/limits"`}},
+			expected: Deprecation{Warnings: []string{"Deprecated API", "Use /v2/limits"}},
+		},
+	} {
+		s.Run(test.name, func() {
+			deprecation, ok := DeprecationOf(&http.Response{Header: test.header})
+
+			s.True(ok)
+			s.Equal(test.expected, deprecation)
+		})
+	}
+}
+
+func (s *deprecationTestSuite) TestDeprecationOfReturnsFalse_WhenNotDeprecated() {
+	_, ok := DeprecationOf(&http.Response{Header: http.Header{WarningHeader: []string{StaleWarning}}})
+	s.False(ok)
+
+	_, ok = DeprecationOf(nil)
+	s.False(ok)
+}
+
+func (s *deprecationTestSuite) TestString() {
+	deprecation := Deprecation{
+		Date:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:   time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		Warnings: []string{"Deprecated API"},
+		Link:     "https://docs.form3.tech/deprecations",
+	}
+
+	s.Equal("deprecated, since 2026-01-01, sunset on 2026-07-01, Deprecated API, see https://docs.form3.tech/deprecations", deprecation.String())
+	s.Equal("deprecated", Deprecation{}.String())
+}
@@ -0,0 +1,112 @@
+package result
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrorCode is the machine-readable category of an unsuccessful response, so the callers can handle the errors
+// programmatically at a finer granularity than the status codes.
+type ErrorCode string
+
+// The error codes the SDK understands.
+const (
+	// CodeUnknown is returned when the error is not mapped from a response or its code is not known.
+	CodeUnknown ErrorCode = ""
+
+	// The codes of the fields failing the validation of a 400 Bad Request response.
+	CodeInvalidBIC           ErrorCode = "invalid_bic"
+	CodeInvalidIBAN          ErrorCode = "invalid_iban"
+	CodeInvalidBankID        ErrorCode = "invalid_bank_id"
+	CodeInvalidBankIDCode    ErrorCode = "invalid_bank_id_code"
+	CodeInvalidAccountNumber ErrorCode = "invalid_account_number"
+	CodeInvalidCountry       ErrorCode = "invalid_country"
+	CodeInvalidCurrency      ErrorCode = "invalid_currency"
+	// CodeValidationFailed is returned for the 400 Bad Request responses without a more specific code.
+	CodeValidationFailed ErrorCode = "validation_failed"
+
+	// The codes of the 409 Conflict responses.
+	CodeDuplicateIBAN          ErrorCode = "duplicate_iban"
+	CodeDuplicateAccountNumber ErrorCode = "duplicate_account_number"
+	CodeDuplicateResource      ErrorCode = "duplicate_resource"
+	CodeVersionConflict        ErrorCode = "version_conflict"
+	// CodeConflict is returned for the 409 Conflict responses without a more specific code.
+	CodeConflict ErrorCode = "conflict"
+
+	// The codes of the other status codes.
+	CodeUnauthorized       ErrorCode = "unauthorized"
+	CodeForbidden          ErrorCode = "forbidden"
+	CodeNotFound           ErrorCode = "not_found"
+	CodeRateLimited        ErrorCode = "rate_limited"
+	CodeServerError        ErrorCode = "server_error"
+	CodeServiceUnavailable ErrorCode = "service_unavailable"
+)
+
+// knownCodes are the codes the API may send in the error_code field.
+var knownCodes = map[ErrorCode]bool{
+	CodeInvalidBIC:             true,
+	CodeInvalidIBAN:            true,
+	CodeInvalidBankID:          true,
+	CodeInvalidBankIDCode:      true,
+	CodeInvalidAccountNumber:   true,
+	CodeInvalidCountry:         true,
+	CodeInvalidCurrency:        true,
+	CodeValidationFailed:       true,
+	CodeDuplicateIBAN:          true,
+	CodeDuplicateAccountNumber: true,
+	CodeDuplicateResource:      true,
+	CodeVersionConflict:        true,
+	CodeConflict:               true,
+}
+
+// fieldCodes maps the last part of the names of the fields failing the validation to the codes.
+var fieldCodes = map[string]ErrorCode{
+	"bic":            CodeInvalidBIC,
+	"iban":           CodeInvalidIBAN,
+	"bank_id":        CodeInvalidBankID,
+	"bank_id_code":   CodeInvalidBankIDCode,
+	"account_number": CodeInvalidAccountNumber,
+	"country":        CodeInvalidCountry,
+	"base_currency":  CodeInvalidCurrency,
+	"currency":       CodeInvalidCurrency,
+}
+
+// statusCodes maps the status codes to the codes of the responses without a more specific code.
+var statusCodes = map[int]ErrorCode{
+	http.StatusBadRequest:          CodeValidationFailed,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusConflict:            CodeConflict,
+	http.StatusTooManyRequests:     CodeRateLimited,
+	http.StatusInternalServerError: CodeServerError,
+	http.StatusBadGateway:          CodeServerError,
+	http.StatusServiceUnavailable:  CodeServiceUnavailable,
+	http.StatusGatewayTimeout:      CodeServerError,
+}
+
+// Code maps the response to an error code. The error_code of the body is used if the SDK knows it, otherwise
+// the code of the first field failing the validation, and then the code of the status.
+func (e *APIError) Code() ErrorCode {
+	if code := ErrorCode(strings.ToLower(e.ErrorCode)); knownCodes[code] {
+		return code
+	}
+	for _, v := range e.Validation {
+		field := v.Field[strings.LastIndex(v.Field, ".")+1:]
+		if code, ok := fieldCodes[field]; ok {
+			return code
+		}
+	}
+	return statusCodes[e.StatusCode]
+}
+
+// CodeOf returns the error code of the response the error was mapped from, or CodeUnknown if it wasn't mapped
+// from a response.
+func CodeOf(err error) ErrorCode {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return CodeUnknown
+	}
+	return apiErr.Code()
+}
@@ -0,0 +1,62 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type errorCodeTestSuite struct {
+	suite.Suite
+}
+
+func TestErrorCodeTestSuite(t *testing.T) {
+	suite.Run(t, new(errorCodeTestSuite))
+}
+
+func (s *errorCodeTestSuite) TestCodeOf() {
+	for _, test := range []struct {
+		name     string
+		status   int
+		body     string
+		expected ErrorCode
+	}{
+		{
+			name:     "known error code",
+			status:   http.StatusConflict,
+			body:     `{"error_code":"DUPLICATE_IBAN","error_message":"iban already exists"}`,
+			expected: CodeDuplicateIBAN,
+		},
+		{
+			name:     "validation failure",
+			status:   http.StatusBadRequest,
+			body:     `{"error_code":"4d7f0f4c","error_message":"validation failure list:\nname in body is required\ndata.attributes.bic in body should match '^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$'"}`,
+			expected: CodeInvalidBIC,
+		},
+		{
+			name:     "validation failure of unknown field",
+			status:   http.StatusBadRequest,
+			body:     `{"error_message":"validation failure list:\nname in body is required"}`,
+			expected: CodeValidationFailed,
+		},
+		{name: "conflict", status: http.StatusConflict, body: `{"error_message":"invalid version"}`, expected: CodeConflict},
+		{name: "not found", status: http.StatusNotFound, expected: CodeNotFound},
+		{name: "rate limited", status: http.StatusTooManyRequests, expected: CodeRateLimited},
+		{name: "server error", status: http.StatusBadGateway, body: "<html>bad gateway</html>", expected: CodeServerError},
+		{name: "unknown status", status: http.StatusTeapot, expected: CodeUnknown},
+	} {
+		s.Run(test.name, func() {
+			err := fmt.Errorf("fetch failed: %w", NewAPIError(newResponse(test.status, test.body), errInvalidRequest))
+
+			s.Equal(test.expected, CodeOf(err))
+		})
+	}
+}
+
+func (s *errorCodeTestSuite) TestCodeOfReturnsUnknown_WhenErrorIsNotMappedFromResponse() {
+	s.Equal(CodeUnknown, CodeOf(errors.New("connection refused")))
+	s.Equal(CodeUnknown, CodeOf(nil))
+}
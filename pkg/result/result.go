@@ -0,0 +1,63 @@
+// Package result provides the typed envelope returned by the *WithMeta client methods.
+package result
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// RequestIDHeader is the response header carrying the ID the server assigned to the request.
+	RequestIDHeader = "X-Request-Id"
+	// WarningHeader is the response header carrying the StaleWarning of the responses served from the stale cache.
+	WarningHeader = "Warning"
+	// StaleWarning marks the cached responses served while the API is failing (see config.WithStaleReads).
+	StaleWarning = `110 - "Response is Stale"`
+)
+
+// CallMeta holds the details of the http calls made by a client operation.
+// When an operation needs more than one request (i.e. Delete fetches the latest version first)
+// the fields describe the last response, except Requests and Duration which cover all of them.
+type CallMeta struct {
+	// StatusCode is the status code of the last response.
+	StatusCode int
+	// Header holds the headers of the last response.
+	Header http.Header
+	// RequestID is the value of the X-Request-Id header of the last response, if there was any.
+	RequestID string
+	// IdempotencyKey is the Idempotency-Key header of the last request, if it was a mutating one. It can be
+	// used to reconcile the resource when the outcome of the request is unknown.
+	IdempotencyKey string
+	// Duration is the time spent waiting for the responses.
+	Duration time.Duration
+	// Requests is the number of http requests made by the operation.
+	Requests int
+	// Stale tells that the last response was served from the stale cache because the API was failing.
+	Stale bool
+	// Timing is the time breakdown of the last request. It's only set when the request is traced
+	// (see requestenricher.WithTrace).
+	Timing Timing
+}
+
+// Timing is the time breakdown of a traced request, so it can be told if the latency is on the network or
+// on the server side. The phases not done by the request (i.e. DNS and connect on a reused connection) are zero.
+type Timing struct {
+	// DNS is the time of the DNS lookup.
+	DNS time.Duration
+	// Connect is the time of opening the TCP connection.
+	Connect time.Duration
+	// TLS is the time of the TLS handshake.
+	TLS time.Duration
+	// TTFB is the time from writing the request until the first byte of the response (the server side latency).
+	TTFB time.Duration
+	// Total is the time from getting a connection until the first byte of the response.
+	Total time.Duration
+	// ReusedConn tells that the request was sent on a reused connection.
+	ReusedConn bool
+}
+
+// Result wraps the value returned by a client operation together with the metadata of the call.
+type Result[T any] struct {
+	Value T
+	Meta  CallMeta
+}
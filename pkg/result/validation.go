@@ -0,0 +1,88 @@
+package result
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// The rules of the ValidationError.
+const (
+	RuleRequired   = "required"
+	RuleNotAllowed = "not_allowed"
+	RulePattern    = "pattern"
+	RuleType       = "type"
+	RuleEnum       = "enum"
+	RuleMinLength  = "min_length"
+	RuleMaxLength  = "max_length"
+	RuleMinimum    = "minimum"
+	RuleMaximum    = "maximum"
+	// RuleInvalid is set when the message doesn't tell the rule.
+	RuleInvalid = "invalid"
+)
+
+// validationLine matches the "<field> in body <failure>" lines of the validation error messages.
+var validationLine = regexp.MustCompile(`^(\S+) in (?:body|query|path) (.+)$`)
+
+// rules maps the failure descriptions of the validation error messages to the rules.
+var rules = []struct {
+	prefix, suffix, rule string
+}{
+	{prefix: "is required", rule: RuleRequired},
+	{prefix: "is not allowed", rule: RuleNotAllowed},
+	{prefix: "should match", rule: RulePattern},
+	{prefix: "must be of type", rule: RuleType},
+	{prefix: "should be one of", rule: RuleEnum},
+	{prefix: "should be at least", suffix: "chars long", rule: RuleMinLength},
+	{prefix: "should be at most", suffix: "chars long", rule: RuleMaxLength},
+	{prefix: "should be greater than", rule: RuleMinimum},
+	{prefix: "should be less than", rule: RuleMaximum},
+}
+
+// ValidationError is a field which failed the validation of the API, parsed from the error message of a 400 Bad
+// Request response.
+type ValidationError struct {
+	// Field is the field as it's named in the message, i.e. data.attributes.country or country.
+	Field string
+	// Rule is the failed validation rule, i.e. RuleRequired.
+	Rule string
+	// Message is the line of the error message describing the failure.
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ParseValidationErrors returns the failed fields of the validation error message of the API, i.e.
+// "validation failure list:\ncountry in body is required". The lines not referencing a field are skipped.
+func ParseValidationErrors(errorMessage string) []ValidationError {
+	var errs []ValidationError
+	for _, line := range strings.Split(errorMessage, "\n") {
+		line = strings.TrimSpace(line)
+		match := validationLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		errs = append(errs, ValidationError{Field: match[1], Rule: ruleOf(match[2]), Message: line})
+	}
+	return errs
+}
+
+func ruleOf(failure string) string {
+	for _, r := range rules {
+		if strings.HasPrefix(failure, r.prefix) && strings.HasSuffix(failure, r.suffix) {
+			return r.rule
+		}
+	}
+	return RuleInvalid
+}
+
+// ValidationErrors returns the failed fields of the 400 Bad Request response the error was mapped from.
+func ValidationErrors(err error) []ValidationError {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	return apiErr.Validation
+}
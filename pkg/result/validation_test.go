@@ -0,0 +1,63 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type validationTestSuite struct {
+	suite.Suite
+}
+
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(validationTestSuite))
+}
+
+func (s *validationTestSuite) TestParseValidationErrors() {
+	for _, test := range []struct {
+		line         string
+		expectedRule string
+	}{
+		{line: "country in body is required", expectedRule: RuleRequired},
+		{line: "organisation_id in body is not allowed", expectedRule: RuleNotAllowed},
+		{line: "data.attributes.country in body should match '^[A-Z]{2}$'", expectedRule: RulePattern},
+		{line: "id in body must be of type uuid", expectedRule: RuleType},
+		{line: "type in body should be one of [accounts]", expectedRule: RuleEnum},
+		{line: "bank_id in body should be at least 6 chars long", expectedRule: RuleMinLength},
+		{line: "bic in body should be at most 11 chars long", expectedRule: RuleMaxLength},
+		{line: "version in body should be greater than or equal to 0", expectedRule: RuleMinimum},
+		{line: "page[size] in query should be less than or equal to 100", expectedRule: RuleMaximum},
+		{line: "iban in body is invalid", expectedRule: RuleInvalid},
+	} {
+		s.Run(test.line, func() {
+			errs := ParseValidationErrors(test.line)
+
+			s.Require().Len(errs, 1)
+			s.Equal(test.expectedRule, errs[0].Rule)
+			s.Equal(test.line, errs[0].Message)
+		})
+	}
+}
+
+func (s *validationTestSuite) TestParseValidationErrorList() {
+	errs := ParseValidationErrors("validation failure list:\nvalidation failure list:\ncountry in body is required\n" +
+		"data.attributes.bic in body should match '^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$'")
+
+	s.Equal([]ValidationError{
+		{Field: "country", Rule: RuleRequired, Message: "country in body is required"},
+		{Field: "data.attributes.bic", Rule: RulePattern, Message: "data.attributes.bic in body should match '^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$'"},
+	}, errs)
+	s.Empty(ParseValidationErrors("base_currency is required"))
+}
+
+func (s *validationTestSuite) TestValidationErrors() {
+	err := NewAPIError(newResponse(http.StatusBadRequest, `{"error_message":"validation failure list:\ncountry in body is required"}`), errInvalidRequest)
+
+	s.Equal([]ValidationError{{Field: "country", Rule: RuleRequired, Message: "country in body is required"}}, ValidationErrors(fmt.Errorf("create: %w", err)))
+	s.Nil(ValidationErrors(errors.New("connection reset by peer")))
+	s.Nil(ValidationErrors(NewAPIError(newResponse(http.StatusConflict, `{"error_message":"country in body is required"}`), errInvalidRequest)))
+}
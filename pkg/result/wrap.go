@@ -0,0 +1,37 @@
+package result
+
+import "errors"
+
+// WrappedError is an error of a category (i.e. ErrUnexpectedServerResponse) caused by another error (i.e. the
+// JSON decoding error), so both the category and the cause match with errors.Is and errors.As.
+type WrappedError struct {
+	Kind  error
+	Cause error
+}
+
+// Wrap returns the error of the kind caused by cause. It returns kind if cause is nil.
+func Wrap(kind, cause error) error {
+	if cause == nil {
+		return kind
+	}
+	return &WrappedError{Kind: kind, Cause: cause}
+}
+
+func (e *WrappedError) Error() string {
+	return e.Kind.Error() + ": " + e.Cause.Error()
+}
+
+// Is reports whether the target matches the kind of the error.
+func (e *WrappedError) Is(target error) bool {
+	return errors.Is(e.Kind, target)
+}
+
+// As finds the first error in the chain of the kind matching the target.
+func (e *WrappedError) As(target any) bool {
+	return errors.As(e.Kind, target)
+}
+
+// Unwrap returns the cause of the error.
+func (e *WrappedError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,38 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type wrapTestSuite struct {
+	suite.Suite
+}
+
+func TestWrapTestSuite(t *testing.T) {
+	suite.Run(t, new(wrapTestSuite))
+}
+
+func (s *wrapTestSuite) TestWrap() {
+	errCategory := errors.New("category")
+	kind := fmt.Errorf("specific %w", errCategory)
+	cause := &APIError{StatusCode: 500, Err: io.ErrUnexpectedEOF}
+
+	err := Wrap(kind, cause)
+
+	s.EqualError(err, "specific category: "+cause.Error())
+	s.ErrorIs(err, kind)
+	s.ErrorIs(err, errCategory)
+	s.ErrorIs(err, io.ErrUnexpectedEOF)
+	var apiErr *APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal(cause, apiErr)
+}
+
+func (s *wrapTestSuite) TestWrapReturnsKind_WhenCauseIsNil() {
+	s.Equal(io.EOF, Wrap(io.EOF, nil))
+}
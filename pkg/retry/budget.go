@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBudgetExhausted the failed request was not retried because the retry budget is exhausted
+var ErrBudgetExhausted = errors.New("retry budget exhausted")
+
+// BudgetExhaustedError is returned instead of the last failed attempt when it could not be retried because
+// the retry budget is exhausted. It matches ErrBudgetExhausted with errors.Is.
+type BudgetExhaustedError struct {
+	// StatusCode is the status code of the last response, it's 0 if the last attempt failed with an error.
+	StatusCode int
+	// Err is the error of the last attempt, it's nil if the last attempt returned a response.
+	Err error
+}
+
+func (e *BudgetExhaustedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s", ErrBudgetExhausted, e.Err)
+	}
+	return fmt.Sprintf("%s: last response status %d", ErrBudgetExhausted, e.StatusCode)
+}
+
+// Is reports whether the target is ErrBudgetExhausted.
+func (e *BudgetExhaustedError) Is(target error) bool {
+	return target == ErrBudgetExhausted
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *BudgetExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// Budget limits the retries to a fraction of the requests, so a flood of failures can't multiply the number
+// of requests sent to the API. Every request deposits ratio tokens and every retry withdraws one, the budget
+// holds maxRetries tokens at most and it's full when it's created. Share the same budget in the policies of
+// all the clients to make it global. It's safe for concurrent use.
+type Budget struct {
+	ratio float64
+	max   float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// NewBudget creates a budget allowing ratio retries per request on average and maxRetries retries at once.
+func NewBudget(ratio float64, maxRetries int) *Budget {
+	return &Budget{ratio: ratio, max: float64(maxRetries), tokens: float64(maxRetries)}
+}
+
+// Deposit adds the tokens of a new request to the budget. It does nothing on a nil budget.
+func (b *Budget) Deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+// Withdraw takes a token for a retry and reports whether there was one. It's always true for a nil budget.
+func (b *Budget) Withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
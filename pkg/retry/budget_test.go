@@ -0,0 +1,59 @@
+package retry
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type budgetTestSuite struct {
+	suite.Suite
+}
+
+func TestBudgetTestSuite(t *testing.T) {
+	suite.Run(t, new(budgetTestSuite))
+}
+
+func (s *budgetTestSuite) TestWithdraw() {
+	budget := NewBudget(0.5, 2)
+
+	s.True(budget.Withdraw())
+	s.True(budget.Withdraw())
+	s.False(budget.Withdraw())
+
+	budget.Deposit()
+	s.False(budget.Withdraw())
+	budget.Deposit()
+	s.True(budget.Withdraw())
+}
+
+func (s *budgetTestSuite) TestDepositIsCappedAtMaxRetries() {
+	budget := NewBudget(1, 1)
+
+	for i := 0; i < 10; i++ {
+		budget.Deposit()
+	}
+
+	s.True(budget.Withdraw())
+	s.False(budget.Withdraw())
+}
+
+func (s *budgetTestSuite) TestNilBudgetIsUnlimited() {
+	var budget *Budget
+
+	budget.Deposit()
+	for i := 0; i < 10; i++ {
+		s.True(budget.Withdraw())
+	}
+}
+
+func (s *budgetTestSuite) TestBudgetExhaustedError() {
+	lastErr := errors.New("connection reset by peer")
+
+	s.ErrorIs(&BudgetExhaustedError{Err: lastErr}, ErrBudgetExhausted)
+	s.ErrorIs(&BudgetExhaustedError{Err: lastErr}, lastErr)
+	s.EqualError(&BudgetExhaustedError{Err: lastErr}, "retry budget exhausted: connection reset by peer")
+	s.EqualError(&BudgetExhaustedError{StatusCode: http.StatusServiceUnavailable}, "retry budget exhausted: last response status 503")
+}
@@ -0,0 +1,205 @@
+// Package retry provides the retry policy of the Form3 clients. The failed requests are retried with
+// exponential backoff and jitter, so transient errors (i.e. 502 Bad Gateway during a deployment) don't
+// have to be handled by every caller.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"form3interview/pkg/clock"
+	re "form3interview/pkg/requestenricher"
+)
+
+// Policy decides which requests are retried and how long to wait between the attempts.
+type Policy struct {
+	// MaxAttempts is the maximum number of attempts including the first one. Retries are disabled if it's
+	// less than 2.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, it's doubled for every further retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between the attempts.
+	MaxDelay time.Duration
+	// Jitter randomizes the delays by the given fraction (0-1), so the clients don't retry in lockstep.
+	Jitter float64
+	// RetryableStatusCodes are the status codes of the responses which are retried. Network errors are
+	// always retried.
+	RetryableStatusCodes []int
+	// MaxRetryAfter caps the wait requested by the Retry-After header of the responses. A response asking
+	// for a longer wait is returned without retrying. The Retry-After waits are not capped if it's zero.
+	MaxRetryAfter time.Duration
+	// Budget limits the number of retries, they are not limited if it's nil. When it's exhausted the last failed
+	// attempt is returned as a BudgetExhaustedError.
+	Budget *Budget
+	// RetryMutations enables retrying the mutating (POST, PUT, PATCH and DELETE) requests which have an
+	// Idempotency-Key header. Only the GET, HEAD and OPTIONS requests are retried otherwise.
+	RetryMutations bool
+}
+
+// DefaultPolicy returns the policy used by the clients by default: 3 attempts with 100ms base delay and
+// 2s max delay retrying 429 Too Many Requests, 502 Bad Gateway, 503 Service Unavailable and 504 Gateway Timeout.
+// The Retry-After header is honored up to 30s.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:   3,
+		BaseDelay:     100 * time.Millisecond,
+		MaxDelay:      2 * time.Second,
+		Jitter:        0.2,
+		MaxRetryAfter: 30 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// Disabled returns a policy which never retries.
+func Disabled() Policy {
+	return Policy{MaxAttempts: 1}
+}
+
+// Enabled returns true if the policy allows retries.
+func (p Policy) Enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// RetryableStatus returns true if the responses with the status code are retried.
+func (p Policy) RetryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryableMethod returns true if the requests with the method can be retried. The mutating requests are
+// retryable only if RetryMutations is enabled and they have an idempotency key.
+func (p Policy) RetryableMethod(method string, hasIdempotencyKey bool) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return p.RetryMutations && hasIdempotencyKey
+	}
+	return false
+}
+
+// Retryable returns true if the failed attempt (starting from 1) of the request can be retried: the policy allows
+// another attempt, the context of the request is not done, its method is retryable (see RetryableMethod), its body
+// can be sent again and the attempt failed with an error or a retryable status code.
+func (p Policy) Retryable(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if attempt >= p.MaxAttempts || req.Context().Err() != nil {
+		return false
+	}
+	if !p.RetryableMethod(req.Method, req.Header.Get(re.IdempotencyKeyHeader) != "") {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	return err != nil || p.RetryableStatus(resp.StatusCode)
+}
+
+// Backoff returns the delay before the given retry (starting from 1).
+func (p Policy) Backoff(retry int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Delay returns the delay before the given retry (starting from 1) of the response, which is the longer of
+// the backoff and the wait requested by the Retry-After header. It returns false if the requested wait is
+// longer than MaxRetryAfter.
+func (p Policy) Delay(retry int, resp *http.Response) (time.Duration, bool) {
+	delay := p.Backoff(retry)
+	if resp == nil {
+		return delay, true
+	}
+
+	retryAfter := RetryAfter(resp)
+	if p.MaxRetryAfter > 0 && retryAfter > p.MaxRetryAfter {
+		return 0, false
+	}
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay, true
+}
+
+// Rewind returns a copy of the request with a new body, so it can be sent again.
+func Rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// PastDeadline reports whether the deadline of the context is over before the delay.
+func PastDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < delay
+}
+
+// Wait waits for the delay on the clock of the context (see clock.FromContext) or until the context is done, in
+// which case it returns the error of the context.
+func Wait(ctx context.Context, delay time.Duration) error {
+	return clock.Sleep(ctx, clock.FromContext(ctx), delay)
+}
+
+type retryingKey struct{}
+
+// WithRetrying returns a copy of the context telling that the requests are retried by the caller. The clients
+// set it when their built-in retry is enabled, so the Retry middleware doesn't nest the retries.
+func WithRetrying(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryingKey{}, true)
+}
+
+// Retrying tells if the requests of the context are retried by the caller (see WithRetrying).
+func Retrying(ctx context.Context) bool {
+	retrying, _ := ctx.Value(retryingKey{}).(bool)
+	return retrying
+}
+
+// RetryAfter returns the wait requested by the Retry-After header of the response, or by the RateLimit-Reset
+// header of a 429 Too Many Requests response. It returns 0 if the response has none of them.
+func RetryAfter(resp *http.Response) time.Duration {
+	if retryAfter := ParseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > 0 {
+		return retryAfter
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ParseRetryAfter(resp.Header.Get("RateLimit-Reset"))
+	}
+	return 0
+}
+
+// ParseRetryAfter parses the delay seconds or the HTTP date of a Retry-After header value. It returns 0 if the
+// value is empty, invalid or in the past.
+func ParseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && time.Until(t) > 0 {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,194 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock"
+	"form3interview/pkg/clock/clocktest"
+)
+
+type retryTestSuite struct {
+	suite.Suite
+}
+
+func TestRetryTestSuite(t *testing.T) {
+	suite.Run(t, new(retryTestSuite))
+}
+
+func (s *retryTestSuite) TestBackoff() {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	s.Equal(100*time.Millisecond, policy.Backoff(1))
+	s.Equal(200*time.Millisecond, policy.Backoff(2))
+	s.Equal(400*time.Millisecond, policy.Backoff(3))
+	s.Equal(time.Second, policy.Backoff(10))
+}
+
+func (s *retryTestSuite) TestBackoffWithJitter() {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		delay := policy.Backoff(2)
+		s.GreaterOrEqual(delay, 100*time.Millisecond)
+		s.LessOrEqual(delay, 300*time.Millisecond)
+	}
+}
+
+func (s *retryTestSuite) TestRetryableStatus() {
+	policy := DefaultPolicy()
+
+	s.True(policy.RetryableStatus(http.StatusTooManyRequests))
+	s.True(policy.RetryableStatus(http.StatusBadGateway))
+	s.True(policy.RetryableStatus(http.StatusServiceUnavailable))
+	s.True(policy.RetryableStatus(http.StatusGatewayTimeout))
+	s.False(policy.RetryableStatus(http.StatusInternalServerError))
+	s.False(policy.RetryableStatus(http.StatusNotFound))
+}
+
+func (s *retryTestSuite) TestRetryableMethod() {
+	policy := DefaultPolicy()
+
+	s.True(policy.RetryableMethod(http.MethodGet, false))
+	s.False(policy.RetryableMethod(http.MethodPost, true))
+
+	policy.RetryMutations = true
+	s.True(policy.RetryableMethod(http.MethodPost, true))
+	s.True(policy.RetryableMethod(http.MethodDelete, true))
+	s.False(policy.RetryableMethod(http.MethodPost, false))
+}
+
+func (s *retryTestSuite) TestRetryable() {
+	policy := Policy{MaxAttempts: 3, RetryableStatusCodes: []int{http.StatusBadGateway}}
+	get, err := http.NewRequest(http.MethodGet, "http://testhost/v1/organisation/accounts", nil)
+	s.Require().NoError(err)
+	post, err := http.NewRequest(http.MethodPost, "http://testhost/v1/organisation/accounts", strings.NewReader("{}"))
+	s.Require().NoError(err)
+	post.Header.Set("Idempotency-Key", "key-1")
+	unrewindable := post.Clone(context.Background())
+	unrewindable.GetBody = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	badGateway := &http.Response{StatusCode: http.StatusBadGateway}
+
+	s.True(policy.Retryable(get, badGateway, nil, 1))
+	s.True(policy.Retryable(get, nil, errors.New("connection reset"), 2))
+	s.False(policy.Retryable(get, badGateway, nil, 3))
+	s.False(policy.Retryable(get, &http.Response{StatusCode: http.StatusNotFound}, nil, 1))
+	s.False(policy.Retryable(get.WithContext(ctx), badGateway, nil, 1))
+	s.False(policy.Retryable(post, badGateway, nil, 1))
+	policy.RetryMutations = true
+	s.True(policy.Retryable(post, badGateway, nil, 1))
+	s.False(policy.Retryable(unrewindable, badGateway, nil, 1))
+}
+
+func (s *retryTestSuite) TestRewind() {
+	req, err := http.NewRequest(http.MethodPost, "http://testhost/v1/organisation/accounts", strings.NewReader("{}"))
+	s.Require().NoError(err)
+	_, err = io.ReadAll(req.Body)
+	s.Require().NoError(err)
+
+	rewound, err := Rewind(req)
+
+	s.Require().NoError(err)
+	s.NotSame(req, rewound)
+	body, err := io.ReadAll(rewound.Body)
+	s.Require().NoError(err)
+	s.Equal("{}", string(body))
+}
+
+func (s *retryTestSuite) TestWait() {
+	s.NoError(Wait(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.ErrorIs(Wait(ctx, time.Minute), context.Canceled)
+}
+
+func (s *retryTestSuite) TestWaitOnTheContextClock() {
+	fake := clocktest.NewFake(time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC))
+	done := make(chan error)
+	go func() { done <- Wait(clock.NewContext(context.Background(), fake), time.Minute) }()
+	fake.BlockUntil(1)
+	fake.Advance(time.Minute)
+
+	s.NoError(<-done)
+}
+
+func (s *retryTestSuite) TestRetrying() {
+	s.False(Retrying(context.Background()))
+	s.True(Retrying(WithRetrying(context.Background())))
+}
+
+func (s *retryTestSuite) TestPastDeadline() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s.False(PastDeadline(ctx, time.Millisecond))
+	s.True(PastDeadline(ctx, time.Minute))
+	s.False(PastDeadline(context.Background(), time.Hour))
+}
+
+func (s *retryTestSuite) TestDelay() {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, MaxRetryAfter: 10 * time.Second}
+	testCases := []struct {
+		name          string
+		retryAfter    string
+		expectedDelay time.Duration
+		expectedRetry bool
+	}{
+		{name: "backoff without retry after", expectedDelay: 100 * time.Millisecond, expectedRetry: true},
+		{name: "backoff longer than retry after", retryAfter: "0", expectedDelay: 100 * time.Millisecond, expectedRetry: true},
+		{name: "retry after longer than backoff", retryAfter: "3", expectedDelay: 3 * time.Second, expectedRetry: true},
+		{name: "retry after over the limit", retryAfter: "60", expectedRetry: false},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+			resp.Header.Set("Retry-After", tc.retryAfter)
+
+			delay, ok := policy.Delay(1, resp)
+
+			s.Equal(tc.expectedRetry, ok)
+			s.Equal(tc.expectedDelay, delay)
+		})
+	}
+}
+
+func (s *retryTestSuite) TestRetryAfter() {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	s.Zero(RetryAfter(resp))
+
+	resp.Header.Set("RateLimit-Reset", "7")
+	s.Equal(7*time.Second, RetryAfter(resp))
+
+	resp.Header.Set("Retry-After", "2")
+	s.Equal(2*time.Second, RetryAfter(resp))
+
+	resp = &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Ratelimit-Reset": []string{"7"}}}
+	s.Zero(RetryAfter(resp))
+}
+
+func (s *retryTestSuite) TestParseRetryAfter() {
+	s.Equal(120*time.Second, ParseRetryAfter("120"))
+	s.Zero(ParseRetryAfter(""))
+	s.Zero(ParseRetryAfter("soon"))
+	s.Zero(ParseRetryAfter("-1"))
+	s.Zero(ParseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)))
+	inFuture := ParseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	s.InDelta(time.Hour, inFuture, float64(2*time.Second))
+}
+
+func (s *retryTestSuite) TestEnabled() {
+	s.True(DefaultPolicy().Enabled())
+	s.False(Disabled().Enabled())
+	s.False(Policy{}.Enabled())
+}
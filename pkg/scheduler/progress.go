@@ -0,0 +1,85 @@
+package scheduler
+
+import "sync"
+
+// Progress tracks the live state of an executed plan.
+type Progress struct {
+	mu        sync.RWMutex
+	total     int
+	running   int
+	completed int
+	errs      map[string]error
+	done      chan struct{}
+}
+
+// Snapshot is a point in time view of the progress.
+type Snapshot struct {
+	Total     int
+	Pending   int
+	Running   int
+	Succeeded int
+	Failed    int
+}
+
+func newProgress(total int) *Progress {
+	return &Progress{
+		total: total,
+		errs:  map[string]error{},
+		done:  make(chan struct{}),
+	}
+}
+
+// Done is closed when all the operations are finished or skipped.
+func (p *Progress) Done() <-chan struct{} {
+	return p.done
+}
+
+// Snapshot returns the current state of the progress.
+func (p *Progress) Snapshot() Snapshot {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return Snapshot{
+		Total:     p.total,
+		Pending:   p.total - p.completed - p.running,
+		Running:   p.running,
+		Succeeded: p.completed - len(p.errs),
+		Failed:    len(p.errs),
+	}
+}
+
+// Errors returns the errors of the failed or skipped operations by their name.
+func (p *Progress) Errors() map[string]error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	errs := make(map[string]error, len(p.errs))
+	for name, err := range p.errs {
+		errs[name] = err
+	}
+	return errs
+}
+
+func (p *Progress) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running++
+}
+
+func (p *Progress) finish(name string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running--
+	p.complete(name, err)
+}
+
+func (p *Progress) skip(name string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.complete(name, err)
+}
+
+func (p *Progress) complete(name string, err error) {
+	if err != nil {
+		p.errs[name] = err
+	}
+	p.completed++
+}
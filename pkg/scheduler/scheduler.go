@@ -0,0 +1,154 @@
+// Package scheduler provides a quota-aware scheduler for running batches of client operations.
+// Operations are ordered earliest-deadline-first and their starts are spread over time so the configured
+// rate limit is never exceeded, which helps long running batch jobs to finish on time without being throttled.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"form3interview/pkg/clock"
+)
+
+var (
+	// ErrInvalidRate rate must be positive
+	ErrInvalidRate = errors.New("rate must be positive")
+	// ErrDeadlineUnreachable some operations can't be started before their deadline within the rate limit
+	ErrDeadlineUnreachable = errors.New("deadline unreachable within rate limit")
+)
+
+// Operation is a single unit of work of a batch.
+type Operation struct {
+	// Name identifies the operation in the plan and the progress.
+	Name string
+	// Deadline is the time until the operation must complete. The batch deadline is used when it is zero.
+	Deadline time.Time
+	// Run executes the operation. The context is cancelled when the deadline of the operation passes.
+	Run func(ctx context.Context) error
+}
+
+// PlannedOperation is an operation with the time it is scheduled to start.
+type PlannedOperation struct {
+	Operation
+	StartAt time.Time
+	// Late tells that the operation is scheduled to start after it's deadline.
+	Late bool
+}
+
+// Plan is the schedule of a batch in the order of execution.
+type Plan struct {
+	Operations []PlannedOperation
+	StartsAt   time.Time
+	FinishesAt time.Time
+}
+
+// Scheduler spreads operations over time within a rate limit.
+type Scheduler struct {
+	interval time.Duration
+	burst    int
+	clock    clock.Clock
+}
+
+// New creates a scheduler which starts at most ratePerSecond operations per second.
+// The first burst operations are started without waiting.
+func New(ratePerSecond float64, burst int) (*Scheduler, error) {
+	return NewWithClock(ratePerSecond, burst, clock.System)
+}
+
+// NewWithClock creates a scheduler (see New) which plans and waits for the starts on the clock.
+func NewWithClock(ratePerSecond float64, burst int, clk clock.Clock) (*Scheduler, error) {
+	if ratePerSecond <= 0 {
+		return nil, ErrInvalidRate
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Scheduler{
+		interval: time.Duration(float64(time.Second) / ratePerSecond),
+		burst:    burst,
+		clock:    clk,
+	}, nil
+}
+
+// Plan orders the operations earliest-deadline-first and assigns a start time to each of them.
+// Operations without deadline get the batch deadline.
+// The plan is always returned, but ErrDeadlineUnreachable is returned as well when any of the operations is late.
+func (s *Scheduler) Plan(ops []Operation, deadline time.Time) (Plan, error) {
+	planned := make([]PlannedOperation, len(ops))
+	for i, op := range ops {
+		if op.Deadline.IsZero() {
+			op.Deadline = deadline
+		}
+		planned[i] = PlannedOperation{Operation: op}
+	}
+	sort.SliceStable(planned, func(i, j int) bool {
+		return planned[i].Deadline.Before(planned[j].Deadline)
+	})
+
+	start := s.clock.Now()
+	plan := Plan{Operations: planned, StartsAt: start, FinishesAt: start}
+	late := 0
+	for i := range planned {
+		slot := 0
+		if i >= s.burst {
+			slot = i - s.burst + 1
+		}
+		planned[i].StartAt = start.Add(time.Duration(slot) * s.interval)
+		planned[i].Late = planned[i].StartAt.After(planned[i].Deadline)
+		if planned[i].Late {
+			late++
+		}
+		plan.FinishesAt = planned[i].StartAt
+	}
+
+	if late > 0 {
+		return plan, fmt.Errorf("%w: %d of %d operations are late", ErrDeadlineUnreachable, late, len(planned))
+	}
+	return plan, nil
+}
+
+// Execute runs the planned operations in the background at their start time and returns the live progress.
+// Operations which were not started yet are skipped when the context is cancelled.
+func (s *Scheduler) Execute(ctx context.Context, plan Plan) *Progress {
+	progress := newProgress(len(plan.Operations))
+	go func() {
+		defer close(progress.done)
+
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		for _, op := range plan.Operations {
+			if !s.waitUntil(ctx, op.StartAt) {
+				progress.skip(op.Name, ctx.Err())
+				continue
+			}
+
+			wg.Add(1)
+			progress.start()
+			go func(op PlannedOperation) {
+				defer wg.Done()
+				opCtx, cancel := context.WithDeadline(ctx, op.Deadline)
+				defer cancel()
+				progress.finish(op.Name, op.Run(opCtx))
+			}(op)
+		}
+	}()
+	return progress
+}
+
+func (s *Scheduler) waitUntil(ctx context.Context, t time.Time) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	wait := t.Sub(s.clock.Now())
+	if wait <= 0 {
+		return true
+	}
+
+	return clock.Sleep(ctx, s.clock, wait) == nil
+}
@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/clock/clocktest"
+)
+
+type schedulerTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(schedulerTestSuite))
+}
+
+func (s *schedulerTestSuite) SetupTest() {
+	s.now = time.Date(2022, 10, 24, 1, 0, 0, 0, time.UTC)
+}
+
+func (s *schedulerTestSuite) TestNewReturnsError_WhenRateIsNotPositive() {
+	_, err := New(0, 1)
+
+	s.ErrorIs(err, ErrInvalidRate)
+}
+
+func (s *schedulerTestSuite) TestPlanOrdersEarliestDeadlineFirst() {
+	scheduler := s.newScheduler(10, 2)
+	deadline := s.now.Add(time.Hour)
+	ops := []Operation{
+		{Name: "batch deadline"},
+		{Name: "late deadline", Deadline: s.now.Add(30 * time.Minute)},
+		{Name: "early deadline", Deadline: s.now.Add(time.Minute)},
+		{Name: "another batch deadline"},
+	}
+
+	plan, err := scheduler.Plan(ops, deadline)
+	s.Require().NoError(err)
+
+	s.Equal([]string{"early deadline", "late deadline", "batch deadline", "another batch deadline"}, names(plan))
+	s.Equal([]time.Time{
+		s.now,
+		s.now,
+		s.now.Add(100 * time.Millisecond),
+		s.now.Add(200 * time.Millisecond),
+	}, startTimes(plan))
+	s.Equal(deadline, plan.Operations[2].Deadline)
+	s.Equal(s.now, plan.StartsAt)
+	s.Equal(s.now.Add(200*time.Millisecond), plan.FinishesAt)
+}
+
+func (s *schedulerTestSuite) TestPlanReturnsError_WhenDeadlineIsUnreachable() {
+	scheduler := s.newScheduler(1, 1)
+	ops := []Operation{{Name: "first"}, {Name: "second"}, {Name: "third"}}
+
+	plan, err := scheduler.Plan(ops, s.now.Add(1500*time.Millisecond))
+
+	s.ErrorIs(err, ErrDeadlineUnreachable)
+	s.Len(plan.Operations, 3)
+	s.False(plan.Operations[1].Late)
+	s.True(plan.Operations[2].Late)
+}
+
+func (s *schedulerTestSuite) TestExecuteRunsOperationsAndReportsProgress() {
+	scheduler, err := New(1000, 1)
+	s.Require().NoError(err)
+	expectedError := errors.New("operation error")
+	var mu sync.Mutex
+	var executed []string
+	run := func(name string, err error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			executed = append(executed, name)
+			return err
+		}
+	}
+	ops := []Operation{
+		{Name: "first", Run: run("first", nil)},
+		{Name: "second", Run: run("second", expectedError)},
+		{Name: "third", Run: run("third", nil)},
+	}
+	plan, err := scheduler.Plan(ops, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+
+	progress := scheduler.Execute(context.Background(), plan)
+	<-progress.Done()
+
+	s.ElementsMatch([]string{"first", "second", "third"}, executed)
+	s.Equal(Snapshot{Total: 3, Succeeded: 2, Failed: 1}, progress.Snapshot())
+	s.Equal(map[string]error{"second": expectedError}, progress.Errors())
+}
+
+func (s *schedulerTestSuite) TestExecuteSkipsOperations_WhenContextIsCancelled() {
+	scheduler, err := New(1, 1)
+	s.Require().NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	ops := []Operation{
+		{Name: "first", Run: func(context.Context) error { cancel(); return nil }},
+		{Name: "second", Run: func(context.Context) error { return nil }},
+	}
+	plan, err := scheduler.Plan(ops, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+
+	progress := scheduler.Execute(ctx, plan)
+	<-progress.Done()
+
+	s.Equal(Snapshot{Total: 2, Succeeded: 1, Failed: 1}, progress.Snapshot())
+	s.ErrorIs(progress.Errors()["second"], context.Canceled)
+}
+
+func (s *schedulerTestSuite) TestExecutePassesOperationDeadline() {
+	scheduler, err := New(10, 1)
+	s.Require().NoError(err)
+	deadline := time.Now().Add(time.Minute)
+	var actualDeadline time.Time
+	ops := []Operation{{Name: "op", Run: func(ctx context.Context) error {
+		actualDeadline, _ = ctx.Deadline()
+		return nil
+	}}}
+	plan, err := scheduler.Plan(ops, deadline)
+	s.Require().NoError(err)
+
+	<-scheduler.Execute(context.Background(), plan).Done()
+
+	s.Equal(deadline, actualDeadline)
+}
+
+func (s *schedulerTestSuite) TestExecuteWaitsForStartOnTheClock() {
+	clock := clocktest.NewFake(s.now)
+	scheduler, err := NewWithClock(1, 1, clock)
+	s.Require().NoError(err)
+	ops := []Operation{
+		{Name: "first", Run: func(context.Context) error { return nil }},
+		{Name: "second", Run: func(context.Context) error { return nil }},
+	}
+	plan, err := scheduler.Plan(ops, time.Now().Add(time.Minute))
+	s.Require().NoError(err)
+
+	progress := scheduler.Execute(context.Background(), plan)
+	clock.BlockUntil(1)
+	s.Equal(1, progress.Snapshot().Pending)
+	clock.Advance(time.Second)
+	<-progress.Done()
+
+	s.Equal(Snapshot{Total: 2, Succeeded: 2}, progress.Snapshot())
+}
+
+func (s *schedulerTestSuite) newScheduler(rate float64, burst int) *Scheduler {
+	scheduler, err := NewWithClock(rate, burst, clocktest.NewFake(s.now))
+	s.Require().NoError(err)
+	return scheduler
+}
+
+func names(plan Plan) []string {
+	var names []string
+	for _, op := range plan.Operations {
+		names = append(names, op.Name)
+	}
+	return names
+}
+
+func startTimes(plan Plan) []time.Time {
+	var times []time.Time
+	for _, op := range plan.Operations {
+		times = append(times, op.StartAt)
+	}
+	return times
+}
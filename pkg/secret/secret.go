@@ -0,0 +1,83 @@
+// Package secret provides secrets read from mounted files (i.e. Kubernetes secrets), so the credentials
+// never have to pass through env vars. The files are re-read when they change, so rotated secrets are
+// picked up without restarting the process.
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCheckInterval is how often the secret file is checked for changes.
+const DefaultCheckInterval = time.Second
+
+// ErrEmptySecret the secret file is empty
+var ErrEmptySecret = errors.New("secret file is empty")
+
+// File is a secret read from a file. The file is checked for changes (by its modification time and size)
+// at most once per CheckInterval when the value is requested and it's re-read when it changes.
+type File struct {
+	path          string
+	checkInterval time.Duration
+	now           func() time.Time
+
+	mu        sync.Mutex
+	value     []byte
+	modTime   time.Time
+	size      int64
+	checkedAt time.Time
+}
+
+// NewFile creates a secret of the file. The file is read on the first Value call.
+func NewFile(path string) *File {
+	return &File{path: path, checkInterval: DefaultCheckInterval, now: time.Now}
+}
+
+// Path returns the path of the secret file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Value returns the content of the file without the leading and trailing white spaces.
+// If the file can't be re-read after a change, the previous value is returned until the next check.
+func (f *File) Value() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	if f.value != nil && now.Sub(f.checkedAt) < f.checkInterval {
+		return f.value, nil
+	}
+	f.checkedAt = now
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return f.cachedOr(err)
+	}
+	if f.value != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.value, nil
+	}
+
+	content, err := os.ReadFile(f.path)
+	if err != nil {
+		return f.cachedOr(err)
+	}
+	value := bytes.TrimSpace(content)
+	if len(value) == 0 {
+		return f.cachedOr(fmt.Errorf("%w: %s", ErrEmptySecret, f.path))
+	}
+
+	f.value, f.modTime, f.size = value, info.ModTime(), info.Size()
+	return f.value, nil
+}
+
+func (f *File) cachedOr(err error) ([]byte, error) {
+	if f.value != nil {
+		return f.value, nil
+	}
+	return nil, err
+}
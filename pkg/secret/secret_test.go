@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type secretTestSuite struct {
+	suite.Suite
+	path string
+	now  time.Time
+	file *File
+}
+
+func TestSecretTestSuite(t *testing.T) {
+	suite.Run(t, new(secretTestSuite))
+}
+
+func (s *secretTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "api-key")
+	s.now = time.Now()
+	s.file = NewFile(s.path)
+	s.file.now = func() time.Time { return s.now }
+}
+
+func (s *secretTestSuite) TestValue() {
+	s.write("secret-1\n", time.Now())
+
+	value, err := s.file.Value()
+
+	s.Require().NoError(err)
+	s.Equal("secret-1", string(value))
+	s.Equal(s.path, s.file.Path())
+}
+
+func (s *secretTestSuite) TestValueIsReReadWhenFileChanges() {
+	modTime := time.Now().Add(-time.Hour)
+	s.write("secret-1", modTime)
+	_, err := s.file.Value()
+	s.Require().NoError(err)
+
+	s.write("secret-2", modTime.Add(time.Minute))
+	value, err := s.file.Value()
+	s.Require().NoError(err)
+	s.Equal("secret-1", string(value), "file is not checked within the check interval")
+
+	s.now = s.now.Add(DefaultCheckInterval)
+	value, err = s.file.Value()
+	s.Require().NoError(err)
+	s.Equal("secret-2", string(value))
+}
+
+func (s *secretTestSuite) TestValueKeepsPreviousValue_WhenFileCannotBeReRead() {
+	s.write("secret-1", time.Now())
+	_, err := s.file.Value()
+	s.Require().NoError(err)
+
+	s.Require().NoError(os.Remove(s.path))
+	s.now = s.now.Add(DefaultCheckInterval)
+	value, err := s.file.Value()
+
+	s.Require().NoError(err)
+	s.Equal("secret-1", string(value))
+}
+
+func (s *secretTestSuite) TestValueReturnsError() {
+	_, err := s.file.Value()
+	s.ErrorIs(err, os.ErrNotExist)
+
+	s.write(" \n", time.Now())
+	s.now = s.now.Add(DefaultCheckInterval)
+	_, err = s.file.Value()
+	s.ErrorIs(err, ErrEmptySecret)
+}
+
+func (s *secretTestSuite) write(content string, modTime time.Time) {
+	s.Require().NoError(os.WriteFile(s.path, []byte(content), 0o600))
+	s.Require().NoError(os.Chtimes(s.path, modTime, modTime))
+}
@@ -0,0 +1,85 @@
+package security
+
+import "form3interview/internal/resource"
+
+// userContainer is a simple container for the "data" JSON field.
+type userContainer = resource.DataContainer[UserData]
+
+// userListContainer is a simple container for the "data" JSON field of list responses.
+type userListContainer = resource.ListContainer[UserData]
+
+// roleContainer is a simple container for the "data" JSON field.
+type roleContainer = resource.DataContainer[RoleData]
+
+// roleListContainer is a simple container for the "data" JSON field of list responses.
+type roleListContainer = resource.ListContainer[RoleData]
+
+// aceContainer is a simple container for the "data" JSON field.
+type aceContainer = resource.DataContainer[ACEData]
+
+// aceListContainer is a simple container for the "data" JSON field of list responses.
+type aceListContainer = resource.ListContainer[ACEData]
+
+// UserData represents an API user.
+// See https://www.api-docs.form3.tech/api/security/users for
+// more information about fields.
+type UserData struct {
+	Attributes     *UserAttributes `json:"attributes,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	OrganisationID string          `json:"organisation_id,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	Version        *int64          `json:"version,omitempty"`
+}
+
+type UserAttributes struct {
+	Email    string   `json:"email,omitempty"`
+	RoleIDs  []string `json:"role_ids,omitempty"`
+	Username string   `json:"username,omitempty"`
+}
+
+// RoleData represents a role which can be assigned to users.
+// See https://www.api-docs.form3.tech/api/security/roles for
+// more information about fields.
+type RoleData struct {
+	Attributes     *RoleAttributes `json:"attributes,omitempty"`
+	ID             string          `json:"id,omitempty"`
+	OrganisationID string          `json:"organisation_id,omitempty"`
+	Type           string          `json:"type,omitempty"`
+	Version        *int64          `json:"version,omitempty"`
+}
+
+type RoleAttributes struct {
+	Name         string `json:"name,omitempty"`
+	ParentRoleID string `json:"parent_role_id,omitempty"`
+}
+
+// ACEData represents an access control entry which allows an action on a record type for a role.
+// See https://www.api-docs.form3.tech/api/security/roles/access-control-entries for
+// more information about fields.
+type ACEData struct {
+	Attributes     *ACEAttributes `json:"attributes,omitempty"`
+	ID             string         `json:"id,omitempty"`
+	OrganisationID string         `json:"organisation_id,omitempty"`
+	Type           string         `json:"type,omitempty"`
+	Version        *int64         `json:"version,omitempty"`
+}
+
+type ACEAttributes struct {
+	Action     Action `json:"action,omitempty"`
+	RecordType string `json:"record_type,omitempty"`
+	RoleID     string `json:"role_id,omitempty"`
+}
+
+// Action is the operation allowed by an access control entry.
+type Action string
+
+// Actions of an access control entry.
+const (
+	ActionCreate        Action = "CREATE"
+	ActionRead          Action = "READ"
+	ActionEdit          Action = "EDIT"
+	ActionDelete        Action = "DELETE"
+	ActionCreateApprove Action = "CREATE_APPROVE"
+	ActionEditApprove   Action = "EDIT_APPROVE"
+	ActionDeleteApprove Action = "DELETE_APPROVE"
+)
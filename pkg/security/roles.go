@@ -0,0 +1,159 @@
+package security
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	"form3interview/internal/resource"
+	re "form3interview/pkg/requestenricher"
+)
+
+// CreateRole creates a role with attributes.
+// See https://www.api-docs.form3.tech/api/security/roles/create-a-role
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) CreateRole(attributes RoleAttributes, en ...re.RequestEnricher) (*RoleData, error) {
+	newID, err := s.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	role := RoleData{
+		ID:             newID.String(),
+		OrganisationID: s.config.OrganisationID.String(),
+		Type:           rolesType,
+		Attributes:     &attributes,
+	}
+
+	created, err := s.roles().Create(newID, role, en...)
+	if err != nil {
+		return nil, err
+	}
+	s.config.Log().Debugf("role %s created", role.ID)
+	return created, nil
+}
+
+// FetchRole fetches a role by it's ID.
+// See https://www.api-docs.form3.tech/api/security/roles/fetch-a-role
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) FetchRole(roleID uuid.UUID, en ...re.RequestEnricher) (*RoleData, error) {
+	return s.roles().Fetch(roleID, en...)
+}
+
+// ListRoles lists the roles page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/security/roles/list-roles
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) ListRoles(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]RoleData, error) {
+	return s.roles().List(pageNumber, pageSize, en...)
+}
+
+// DeleteRole deletes a role by it's ID having a specific version.
+// See https://www.api-docs.form3.tech/api/security/roles/delete-a-role
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) DeleteRole(roleID uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	if err := s.roles().DeleteVersion(roleID, version, en...); err != nil {
+		return err
+	}
+	s.config.Log().Debugf("role %s deleted", roleID)
+	return nil
+}
+
+// CreateACE adds an access control entry to a role allowing the action on the record type.
+// See https://www.api-docs.form3.tech/api/security/roles/access-control-entries/create-an-ace
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) CreateACE(roleID uuid.UUID, action Action, recordType string, en ...re.RequestEnricher) (*ACEData, error) {
+	if roleID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	newID, err := s.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	ace := ACEData{
+		ID:             newID.String(),
+		OrganisationID: s.config.OrganisationID.String(),
+		Type:           acesType,
+		Attributes: &ACEAttributes{
+			Action:     action,
+			RecordType: recordType,
+			RoleID:     roleID.String(),
+		},
+	}
+
+	created, err := s.aces(roleID, ErrRoleNotFound).Create(newID, ace, en...)
+	if err != nil {
+		return nil, err
+	}
+	s.config.Log().Debugf("role %s ace %s created", roleID, ace.ID)
+	return created, nil
+}
+
+// ListACEs lists the access control entries of a role.
+// See https://www.api-docs.form3.tech/api/security/roles/access-control-entries/list-aces
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) ListACEs(roleID uuid.UUID, en ...re.RequestEnricher) ([]ACEData, error) {
+	if roleID == uuid.Nil {
+		return nil, ErrNilUUID
+	}
+
+	return s.aces(roleID, ErrRoleNotFound).Find("", en...)
+}
+
+// DeleteACE removes an access control entry from a role.
+// See https://www.api-docs.form3.tech/api/security/roles/access-control-entries/delete-an-ace
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) DeleteACE(roleID, aceID uuid.UUID, en ...re.RequestEnricher) error {
+	if roleID == uuid.Nil || aceID == uuid.Nil {
+		return ErrNilUUID
+	}
+
+	if err := s.aces(roleID, ErrACENotFound).Delete(aceID, en...); err != nil {
+		return err
+	}
+	s.config.Log().Debugf("role %s ace %s deleted", roleID, aceID)
+	return nil
+}
+
+func (s securityClient) bodyToRoleData(body io.Reader) (*RoleData, error) {
+	return resource.Client[RoleData]{Config: s.config}.BodyToData(body)
+}
+
+func (s securityClient) bodyToACEData(body io.Reader) (*ACEData, error) {
+	return resource.Client[ACEData]{Config: s.config}.BodyToData(body)
+}
+
+func acesUrl(roleID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/aces", rolesUrl, roleID)
+}
+
+// roles returns the generic client of the roles collection.
+func (s securityClient) roles() resource.Client[RoleData] {
+	return resource.Client[RoleData]{
+		HTTP:              s.client,
+		Config:            s.config,
+		Url:               rolesUrl,
+		ErrNotFound:       ErrRoleNotFound,
+		ErrInvalidVersion: ErrInvalidVersion,
+	}
+}
+
+// aces returns the generic client of the access control entries of a role. The not found responses are mapped to
+// errNotFound, so the missing role and access control entry can be told apart.
+func (s securityClient) aces(roleID uuid.UUID, errNotFound error) resource.Client[ACEData] {
+	return resource.Client[ACEData]{
+		HTTP:        s.client,
+		Config:      s.config,
+		Url:         acesUrl(roleID),
+		ErrNotFound: errNotFound,
+	}
+}
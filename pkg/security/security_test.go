@@ -0,0 +1,327 @@
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                 = "Do"
+	testBaseUrl        = "testhost"
+	testUsersUrl       = testBaseUrl + usersUrl
+	testRolesUrl       = testBaseUrl + rolesUrl
+	testOrganisationID = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type securityTestSuite struct {
+	suite.Suite
+	mockHttpClient *mocks.HttpClientMock
+	securityClient securityClient
+}
+
+func TestSecurityTestSuite(t *testing.T) {
+	suite.Run(t, new(securityTestSuite))
+}
+
+func (s *securityTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.securityClient = securityClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *securityTestSuite) TestNewClientReturnsError_WhenNotConfigured() {
+	_, err := NewClient()
+	s.ErrorIs(err, ErrBaseUrlNotConfigured)
+}
+
+func (s *securityTestSuite) TestCreateUserReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"username is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testUsersUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.securityClient.CreateUser(UserAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *securityTestSuite) TestCreateUserReturnsHttpClientError() {
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testUsersUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.securityClient.CreateUser(UserAttributes{})
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *securityTestSuite) TestCreateUser() {
+	userID, roleID := uuid.New(), uuid.New()
+	s.securityClient.config.UUIDGenerator = func() (uuid.UUID, error) { return userID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testUsersUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.securityClient.CreateUser(UserAttributes{
+		Username: "admin",
+		Email:    "admin@example.com",
+		RoleIDs:  []string{roleID.String()},
+	})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.securityClient.bodyToUserData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(userID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(usersType, requested.Type)
+	s.Equal("admin", requested.Attributes.Username)
+	s.Equal([]string{roleID.String()}, requested.Attributes.RoleIDs)
+}
+
+func (s *securityTestSuite) TestFetchUserReturnsError() {
+	_, actualError := s.securityClient.FetchUser(uuid.Nil)
+	s.ErrorIs(actualError, ErrNilUUID)
+
+	userID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testUsersUrl, userID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError = s.securityClient.FetchUser(userID)
+	s.ErrorIs(actualError, ErrUserNotFound)
+}
+
+func (s *securityTestSuite) TestListUsers() {
+	firstID, secondID := uuid.New(), uuid.New()
+	body, err := json.Marshal(userListContainer{Data: []UserData{{ID: firstID.String()}, {ID: secondID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testUsersUrl+"?page[number]=1&page[size]=2")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.securityClient.ListUsers(1, 2)
+	s.NoError(err)
+	s.Require().Len(actual, 2)
+	s.Equal(firstID.String(), actual[0].ID)
+	s.Equal(secondID.String(), actual[1].ID)
+}
+
+func (s *securityTestSuite) TestDeleteUserReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		expectedError  error
+	}{
+		{
+			name:           "user not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrUserNotFound,
+		},
+		{
+			name:           "invalid version",
+			responseStatus: http.StatusConflict,
+			expectedError:  ErrInvalidVersion,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+	} {
+		s.Run(test.name, func() {
+			userID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, fmt.Sprintf("%s/%s?version=1", testUsersUrl, userID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody("")}, nil).
+				Once()
+
+			actualError := s.securityClient.DeleteUser(userID, 1)
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *securityTestSuite) TestCreateRole() {
+	roleID := uuid.New()
+	s.securityClient.config.UUIDGenerator = func() (uuid.UUID, error) { return roleID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testRolesUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.securityClient.CreateRole(RoleAttributes{Name: "auditors"})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.securityClient.bodyToRoleData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(roleID.String(), requested.ID)
+	s.Equal(rolesType, requested.Type)
+	s.Equal("auditors", requested.Attributes.Name)
+}
+
+func (s *securityTestSuite) TestFetchRoleReturnsError_WhenRoleNotFound() {
+	roleID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testRolesUrl, roleID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.securityClient.FetchRole(roleID)
+
+	s.ErrorIs(actualError, ErrRoleNotFound)
+}
+
+func (s *securityTestSuite) TestListRoles() {
+	roleID := uuid.New()
+	body, err := json.Marshal(roleListContainer{Data: []RoleData{{ID: roleID.String()}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testRolesUrl+"?page[number]=0&page[size]=10")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.securityClient.ListRoles(0, 10)
+	s.NoError(err)
+	s.Require().Len(actual, 1)
+	s.Equal(roleID.String(), actual[0].ID)
+}
+
+func (s *securityTestSuite) TestDeleteRole() {
+	roleID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, fmt.Sprintf("%s/%s?version=2", testRolesUrl, roleID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+
+	s.NoError(s.securityClient.DeleteRole(roleID, 2))
+}
+
+func (s *securityTestSuite) TestCreateACE() {
+	roleID, aceID := uuid.New(), uuid.New()
+	s.securityClient.config.UUIDGenerator = func() (uuid.UUID, error) { return aceID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/aces", testRolesUrl, roleID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.securityClient.CreateACE(roleID, ActionRead, "accounts")
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.securityClient.bodyToACEData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(aceID.String(), requested.ID)
+	s.Equal(acesType, requested.Type)
+	s.Equal(ActionRead, requested.Attributes.Action)
+	s.Equal("accounts", requested.Attributes.RecordType)
+	s.Equal(roleID.String(), requested.Attributes.RoleID)
+}
+
+func (s *securityTestSuite) TestCreateACEReturnsError_WhenRoleNotFound() {
+	roleID := uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, fmt.Sprintf("%s/%s/aces", testRolesUrl, roleID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.securityClient.CreateACE(roleID, ActionCreate, "payments")
+
+	s.ErrorIs(actualError, ErrRoleNotFound)
+}
+
+func (s *securityTestSuite) TestListACEs() {
+	roleID, aceID := uuid.New(), uuid.New()
+	body, err := json.Marshal(aceListContainer{Data: []ACEData{{ID: aceID.String(), Attributes: &ACEAttributes{Action: ActionEdit}}}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s/aces", testRolesUrl, roleID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+
+	actual, err := s.securityClient.ListACEs(roleID)
+	s.NoError(err)
+	s.Require().Len(actual, 1)
+	s.Equal(ActionEdit, actual[0].Attributes.Action)
+}
+
+func (s *securityTestSuite) TestDeleteACEReturnsError() {
+	s.ErrorIs(s.securityClient.DeleteACE(uuid.New(), uuid.Nil), ErrNilUUID)
+
+	roleID, aceID := uuid.New(), uuid.New()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, fmt.Sprintf("%s/%s/aces/%s", testRolesUrl, roleID, aceID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNotFound, Body: toResponseBody("")}, nil).
+		Once()
+
+	s.ErrorIs(s.securityClient.DeleteACE(roleID, aceID), ErrACENotFound)
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
@@ -0,0 +1,78 @@
+package security
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+
+	"form3interview/internal/resource"
+	re "form3interview/pkg/requestenricher"
+)
+
+// CreateUser creates an API user with attributes.
+// See https://www.api-docs.form3.tech/api/security/users/create-a-user
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) CreateUser(attributes UserAttributes, en ...re.RequestEnricher) (*UserData, error) {
+	newID, err := s.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	user := UserData{
+		ID:             newID.String(),
+		OrganisationID: s.config.OrganisationID.String(),
+		Type:           usersType,
+		Attributes:     &attributes,
+	}
+
+	created, err := s.users().Create(newID, user, en...)
+	if err != nil {
+		return nil, err
+	}
+	s.config.Log().Debugf("user %s created", user.ID)
+	return created, nil
+}
+
+// FetchUser fetches an API user by it's ID.
+// See https://www.api-docs.form3.tech/api/security/users/fetch-a-user
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) FetchUser(userID uuid.UUID, en ...re.RequestEnricher) (*UserData, error) {
+	return s.users().Fetch(userID, en...)
+}
+
+// ListUsers lists the API users page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/security/users/list-users
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) ListUsers(pageNumber, pageSize uint, en ...re.RequestEnricher) ([]UserData, error) {
+	return s.users().List(pageNumber, pageSize, en...)
+}
+
+// DeleteUser deletes an API user by it's ID having a specific version.
+// See https://www.api-docs.form3.tech/api/security/users/delete-a-user
+//
+// The request can be enriched by RequestEnricher
+func (s securityClient) DeleteUser(userID uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	if err := s.users().DeleteVersion(userID, version, en...); err != nil {
+		return err
+	}
+	s.config.Log().Debugf("user %s deleted", userID)
+	return nil
+}
+
+func (s securityClient) bodyToUserData(body io.Reader) (*UserData, error) {
+	return resource.Client[UserData]{Config: s.config}.BodyToData(body)
+}
+
+// users returns the generic client of the users collection.
+func (s securityClient) users() resource.Client[UserData] {
+	return resource.Client[UserData]{
+		HTTP:              s.client,
+		Config:            s.config,
+		Url:               usersUrl,
+		ErrNotFound:       ErrUserNotFound,
+		ErrInvalidVersion: ErrInvalidVersion,
+	}
+}
@@ -0,0 +1,120 @@
+// Package shim provides a registry of decoding shims which normalize responses of different Form3 API versions.
+// When Form3 renames or reshapes a field in a new API version, a shim registered for the affected version and
+// resource type rewrites the raw response so it can be decoded into the same Go model.
+package shim
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Func rewrites the raw "data" object of a resource in place. The numbers of the object are json.Number values,
+// so they keep their precision (i.e. int64 versions) when the object is decoded into the model.
+type Func func(data map[string]interface{})
+
+type (
+	key struct {
+		version      string
+		resourceType string
+	}
+	registry struct {
+		mu    sync.RWMutex
+		shims map[key][]Func
+	}
+)
+
+var defaultRegistry = newRegistry()
+
+// Register adds a shim which is applied on every resource of resourceType returned by the given API version.
+// Shims registered for the same version and resource type are applied in registration order.
+func Register(version, resourceType string, fn Func) {
+	defaultRegistry.register(version, resourceType, fn)
+}
+
+// Decode is used internally by the API clients to decode a response body into v after applying the registered shims.
+func Decode(version string, body io.Reader, v interface{}) error {
+	return defaultRegistry.decode(version, body, v)
+}
+
+// RenameAttribute is a shim which moves the oldName attribute to newName, unless newName is already set.
+func RenameAttribute(oldName, newName string) Func {
+	return func(data map[string]interface{}) {
+		attributes, ok := data["attributes"].(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		value, ok := attributes[oldName]
+		if !ok {
+			return
+		}
+		delete(attributes, oldName)
+		if _, ok := attributes[newName]; !ok {
+			attributes[newName] = value
+		}
+	}
+}
+
+func newRegistry() *registry {
+	return &registry{shims: map[key][]Func{}}
+}
+
+func (r *registry) register(version, resourceType string, fn Func) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := key{version: version, resourceType: resourceType}
+	r.shims[k] = append(r.shims[k], fn)
+}
+
+func (r *registry) hasVersion(version string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for k := range r.shims {
+		if k.version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *registry) apply(version string, data map[string]interface{}) {
+	resourceType, _ := data["type"].(string)
+	r.mu.RLock()
+	shims := r.shims[key{version: version, resourceType: resourceType}]
+	r.mu.RUnlock()
+
+	for _, fn := range shims {
+		fn(data)
+	}
+}
+
+func (r *registry) decode(version string, body io.Reader, v interface{}) error {
+	if !r.hasVersion(version) {
+		return json.NewDecoder(body).Decode(v)
+	}
+
+	var container map[string]interface{}
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&container); err != nil {
+		return err
+	}
+
+	switch data := container["data"].(type) {
+	case map[string]interface{}:
+		r.apply(version, data)
+	case []interface{}:
+		for _, item := range data {
+			if itemData, ok := item.(map[string]interface{}); ok {
+				r.apply(version, itemData)
+			}
+		}
+	}
+
+	normalized, err := json.Marshal(container)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(normalized, v)
+}
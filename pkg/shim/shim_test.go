@@ -0,0 +1,108 @@
+package shim
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testResourceType = "accounts"
+
+type (
+	testAttributes struct {
+		Name         []string `json:"name"`
+		BaseCurrency string   `json:"base_currency"`
+	}
+	testData struct {
+		Type       string         `json:"type"`
+		Version    int64          `json:"version"`
+		Attributes testAttributes `json:"attributes"`
+	}
+	testContainer struct {
+		Data testData `json:"data"`
+	}
+	testListContainer struct {
+		Data []testData `json:"data"`
+	}
+)
+
+type shimTestSuite struct {
+	suite.Suite
+	registry *registry
+}
+
+func TestShimTestSuite(t *testing.T) {
+	suite.Run(t, new(shimTestSuite))
+}
+
+func (s *shimTestSuite) SetupTest() {
+	s.registry = newRegistry()
+}
+
+func (s *shimTestSuite) TestDecodeWithoutShims() {
+	body := `{"data":{"type":"accounts","attributes":{"name":["test"],"base_currency":"EUR"}}}`
+
+	var actual testContainer
+	s.Require().NoError(s.registry.decode("v1", strings.NewReader(body), &actual))
+
+	s.Equal([]string{"test"}, actual.Data.Attributes.Name)
+	s.Equal("EUR", actual.Data.Attributes.BaseCurrency)
+}
+
+func (s *shimTestSuite) TestDecodeAppliesShimsOfVersionAndResourceType() {
+	s.registry.register("v2", testResourceType, RenameAttribute("currency", "base_currency"))
+	s.registry.register("v2", testResourceType, func(data map[string]interface{}) {
+		attributes := data["attributes"].(map[string]interface{})
+		attributes["name"] = []interface{}{attributes["name"]}
+	})
+	s.registry.register("v2", "payments", RenameAttribute("base_currency", "currency"))
+	s.registry.register("v3", testResourceType, RenameAttribute("base_currency", "currency"))
+	body := `{"data":{"type":"accounts","attributes":{"name":"test","currency":"EUR"}}}`
+
+	var actual testContainer
+	s.Require().NoError(s.registry.decode("v2", strings.NewReader(body), &actual))
+
+	s.Equal([]string{"test"}, actual.Data.Attributes.Name)
+	s.Equal("EUR", actual.Data.Attributes.BaseCurrency)
+}
+
+func (s *shimTestSuite) TestDecodeAppliesShimsOnEveryListItem() {
+	s.registry.register("v2", testResourceType, RenameAttribute("currency", "base_currency"))
+	body := `{"data":[{"type":"accounts","attributes":{"currency":"EUR"}},{"type":"accounts","attributes":{"currency":"GBP"}}]}`
+
+	var actual testListContainer
+	s.Require().NoError(s.registry.decode("v2", strings.NewReader(body), &actual))
+
+	s.Require().Len(actual.Data, 2)
+	s.Equal("EUR", actual.Data[0].Attributes.BaseCurrency)
+	s.Equal("GBP", actual.Data[1].Attributes.BaseCurrency)
+}
+
+func (s *shimTestSuite) TestDecodeKeepsPrecisionOfNumbers() {
+	s.registry.register("v2", testResourceType, RenameAttribute("currency", "base_currency"))
+	body := `{"data":{"type":"accounts","version":9007199254740993,"attributes":{"currency":"EUR"}}}`
+
+	var actual testContainer
+	s.Require().NoError(s.registry.decode("v2", strings.NewReader(body), &actual))
+
+	s.Equal(int64(9007199254740993), actual.Data.Version)
+	s.Equal("EUR", actual.Data.Attributes.BaseCurrency)
+}
+
+func (s *shimTestSuite) TestRenameAttributeKeepsNewValue() {
+	data := map[string]interface{}{
+		"attributes": map[string]interface{}{"currency": "GBP", "base_currency": "EUR"},
+	}
+
+	RenameAttribute("currency", "base_currency")(data)
+
+	s.Equal(map[string]interface{}{"base_currency": "EUR"}, data["attributes"])
+}
+
+func (s *shimTestSuite) TestDecodeReturnsError_WhenBodyIsInvalid() {
+	s.registry.register("v2", testResourceType, RenameAttribute("currency", "base_currency"))
+
+	var actual testContainer
+	s.Error(s.registry.decode("v2", strings.NewReader("oops"), &actual))
+}
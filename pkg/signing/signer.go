@@ -0,0 +1,56 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+)
+
+// Signer computes the request signatures, so the signing can be delegated to a KMS (i.e. AWS KMS or GCP KMS)
+// or an HSM and the private key never has to be loaded into the memory of the process.
+type Signer interface {
+	// KeyID returns the ID of the key registered in Form3.
+	KeyID() string
+	// Algorithm returns the name of the signature algorithm (rsa-sha256, ecdsa-sha256 or ed25519).
+	Algorithm() string
+	// Sign returns the signature of the signing string. The data is hashed with SHA-256 by the rsa-sha256 and
+	// ecdsa-sha256 algorithms, while ed25519 signs it as is.
+	Sign(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// keySigner is a Signer of a private key held in memory.
+type keySigner struct {
+	keyID     string
+	algorithm string
+	key       crypto.Signer
+}
+
+// NewKeySigner returns the in-memory Signer of the private key (RSA, ECDSA or Ed25519).
+func NewKeySigner(keyID string, key crypto.Signer) (Signer, error) {
+	if keyID == "" {
+		return nil, ErrMissingKeyID
+	}
+	algorithm, err := Algorithm(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return keySigner{keyID: keyID, algorithm: algorithm, key: key}, nil
+}
+
+func (s keySigner) KeyID() string {
+	return s.keyID
+}
+
+func (s keySigner) Algorithm() string {
+	return s.algorithm
+}
+
+func (s keySigner) Sign(_ context.Context, data []byte) ([]byte, error) {
+	if _, ok := s.key.Public().(ed25519.PublicKey); ok {
+		return s.key.Sign(rand.Reader, data, crypto.Hash(0))
+	}
+	sum := sha256.Sum256(data)
+	return s.key.Sign(rand.Reader, sum[:], crypto.SHA256)
+}
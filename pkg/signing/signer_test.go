@@ -0,0 +1,98 @@
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ctxKey struct{}
+
+// kmsSigner is a Signer which keeps the key away from the caller like a KMS does.
+type kmsSigner struct {
+	Signer
+	ctx context.Context
+	err error
+}
+
+func (s *kmsSigner) Sign(ctx context.Context, data []byte) ([]byte, error) {
+	s.ctx = ctx
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.Signer.Sign(ctx, data)
+}
+
+type signerTestSuite struct {
+	suite.Suite
+	key *ecdsa.PrivateKey
+}
+
+func TestSignerTestSuite(t *testing.T) {
+	suite.Run(t, new(signerTestSuite))
+}
+
+func (s *signerTestSuite) SetupSuite() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	s.key = key
+}
+
+func (s *signerTestSuite) TestNewKeySigner() {
+	signer, err := NewKeySigner(testKeyID, s.key)
+
+	s.Require().NoError(err)
+	s.Equal(testKeyID, signer.KeyID())
+	s.Equal("ecdsa-sha256", signer.Algorithm())
+}
+
+func (s *signerTestSuite) TestNewKeySignerReturnsError() {
+	_, err := NewKeySigner("", s.key)
+	s.ErrorIs(err, ErrMissingKeyID)
+
+	_, err = NewKeySigner(testKeyID, unsupportedSigner{})
+	s.ErrorIs(err, ErrUnsupportedKey)
+}
+
+func (s *signerTestSuite) TestSignRequestWith() {
+	keySigner, err := NewKeySigner(testKeyID, s.key)
+	s.Require().NoError(err)
+	signer := &kmsSigner{Signer: keySigner}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.form3.tech/v1/organisation/accounts", strings.NewReader(`{}`))
+	s.Require().NoError(err)
+
+	s.Require().NoError(SignRequestWith(req, signer))
+
+	s.Equal("request", signer.ctx.Value(ctxKey{}))
+	match := signatureRegexp.FindStringSubmatch(req.Header.Get(SignatureHeader))
+	s.Require().Len(match, 5)
+	s.Equal(testKeyID, match[1])
+	s.Equal("ecdsa-sha256", match[2])
+	signature, err := base64.StdEncoding.DecodeString(match[4])
+	s.Require().NoError(err)
+	hash := sha256.Sum256([]byte(SigningString(req, strings.Split(match[3], " "))))
+	s.True(ecdsa.VerifyASN1(&s.key.PublicKey, hash[:], signature))
+}
+
+func (s *signerTestSuite) TestSignRequestWithReturnsSignerError() {
+	keySigner, err := NewKeySigner(testKeyID, s.key)
+	s.Require().NoError(err)
+	errKMS := errors.New("kms unavailable")
+	req, err := http.NewRequest(http.MethodGet, "https://api.form3.tech/v1/organisation/accounts", nil)
+	s.Require().NoError(err)
+
+	err = SignRequestWith(req, &kmsSigner{Signer: keySigner, err: errKMS})
+
+	s.ErrorIs(err, errKMS)
+	s.Empty(req.Header.Get(SignatureHeader))
+}
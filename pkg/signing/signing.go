@@ -0,0 +1,187 @@
+// Package signing signs the requests with Form3 HTTP Signatures (draft-cavage-http-signatures), which are required
+// by the Form3 production API. The Digest header is the SHA-256 hash of the request body and the Signature header
+// is computed from the request target and the signed headers with the private key of the client.
+//
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/authentication
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"form3interview/pkg/result"
+)
+
+// Header names of the signatures.
+const (
+	SignatureHeader = "Signature"
+	DigestHeader    = "Digest"
+	DateHeader      = "Date"
+)
+
+var (
+	// ErrMissingKeyID key ID is required to sign the requests
+	ErrMissingKeyID = errors.New("signing key ID is required")
+	// ErrUnsupportedKey the private key is not an RSA, ECDSA or Ed25519 key
+	ErrUnsupportedKey = errors.New("unsupported signing key")
+	// ErrInvalidPrivateKey the PEM data contains no private key
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+)
+
+// now is used to set the Date header of the requests.
+var now = time.Now
+
+// Digest returns the Digest header value of the body.
+func Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// SignRequest sets the Date (if missing), Digest and Signature headers of the request with the private key.
+// See SignRequestWith.
+func SignRequest(req *http.Request, keyID string, key crypto.Signer) error {
+	signer, err := NewKeySigner(keyID, key)
+	if err != nil {
+		return err
+	}
+	return SignRequestWith(req, signer)
+}
+
+// SignRequestWith sets the Date (if missing), Digest and Signature headers of the request with the signer.
+// The body of the request is read for the digest and it's restored, so the request can still be sent.
+// The signed headers are (request-target), host and date, accept if it's set and digest, content-type and
+// content-length when the request has a body.
+func SignRequestWith(req *http.Request, signer Signer) error {
+	if signer.KeyID() == "" {
+		return ErrMissingKeyID
+	}
+
+	if req.Header.Get(DateHeader) == "" {
+		req.Header.Set(DateHeader, now().UTC().Format(http.TimeFormat))
+	}
+	headers := []string{"(request-target)", "host", "date"}
+	if req.Header.Get("Accept") != "" {
+		headers = append(headers, "accept")
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set(DigestHeader, Digest(body))
+		headers = append(headers, "digest")
+		if req.Header.Get("Content-Type") != "" {
+			headers = append(headers, "content-type")
+		}
+		headers = append(headers, "content-length")
+	}
+
+	signature, err := signer.Sign(req.Context(), []byte(SigningString(req, headers)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(SignatureHeader, fmt.Sprintf(`keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		signer.KeyID(), signer.Algorithm(), strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// SigningString returns the string which is signed for the headers of the request.
+func SigningString(req *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(req.Method), req.URL.RequestURI()))
+		case "host":
+			host := req.Host
+			if host == "" {
+				host = req.URL.Host
+			}
+			lines = append(lines, "host: "+host)
+		case "content-length":
+			lines = append(lines, fmt.Sprintf("content-length: %d", req.ContentLength))
+		default:
+			lines = append(lines, h+": "+req.Header.Get(h))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Algorithm returns the signature algorithm name of the public key.
+func Algorithm(publicKey crypto.PublicKey) (string, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return "rsa-sha256", nil
+	case *ecdsa.PublicKey:
+		return "ecdsa-sha256", nil
+	case ed25519.PublicKey:
+		return "ed25519", nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnsupportedKey, publicKey)
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #1, PKCS #8 or SEC 1 (EC) private key.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, result.Wrap(ErrInvalidPrivateKey, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+	}
+	return signer, nil
+}
+
+// readBody returns the body of the request (nil if it has none) and restores it, so it can be sent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	content, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(content))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+	req.ContentLength = int64(len(content))
+	return content, nil
+}
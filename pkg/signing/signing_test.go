@@ -0,0 +1,166 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testKeyID = "75a8ba12-fff2-4a52-ad8a-e8b34c5ccec8"
+
+var signatureRegexp = regexp.MustCompile(`^keyId="([^"]*)",algorithm="([^"]*)",headers="([^"]*)",signature="([^"]*)"$`)
+
+type signingTestSuite struct {
+	suite.Suite
+	key *rsa.PrivateKey
+}
+
+func TestSigningTestSuite(t *testing.T) {
+	suite.Run(t, new(signingTestSuite))
+}
+
+func (s *signingTestSuite) SetupSuite() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.key = key
+	now = func() time.Time { return time.Date(2022, 3, 14, 10, 0, 0, 0, time.UTC) }
+}
+
+func (s *signingTestSuite) TearDownSuite() {
+	now = time.Now
+}
+
+func (s *signingTestSuite) TestSignRequest() {
+	body := `{"data":{"id":"1"}}`
+	req, err := http.NewRequest(http.MethodPost, "https://api.form3.tech/v1/organisation/accounts?x=1", strings.NewReader(body))
+	s.Require().NoError(err)
+	req.Header.Set("Accept", "application/vnd.api+json")
+	req.Header.Set("Content-Type", "application/vnd.api+json")
+
+	s.Require().NoError(SignRequest(req, testKeyID, s.key))
+
+	s.Equal("Mon, 14 Mar 2022 10:00:00 GMT", req.Header.Get(DateHeader))
+	s.Equal(Digest([]byte(body)), req.Header.Get(DigestHeader))
+	keyID, algorithm, headers, signature := s.parseSignature(req)
+	s.Equal(testKeyID, keyID)
+	s.Equal("rsa-sha256", algorithm)
+	s.Equal([]string{"(request-target)", "host", "date", "accept", "digest", "content-type", "content-length"}, headers)
+	s.Contains(SigningString(req, headers), "(request-target): post /v1/organisation/accounts?x=1\nhost: api.form3.tech\n")
+	hash := sha256.Sum256([]byte(SigningString(req, headers)))
+	s.NoError(rsa.VerifyPKCS1v15(&s.key.PublicKey, crypto.SHA256, hash[:], signature))
+
+	sent, err := io.ReadAll(req.Body)
+	s.Require().NoError(err)
+	s.Equal(body, string(sent))
+}
+
+func (s *signingTestSuite) TestSignRequestWithoutBody() {
+	req, err := http.NewRequest(http.MethodGet, "https://api.form3.tech/v1/organisation/accounts", nil)
+	s.Require().NoError(err)
+	req.Header.Set(DateHeader, "Tue, 15 Mar 2022 10:00:00 GMT")
+
+	s.Require().NoError(SignRequest(req, testKeyID, s.key))
+
+	s.Equal("Tue, 15 Mar 2022 10:00:00 GMT", req.Header.Get(DateHeader))
+	s.Empty(req.Header.Get(DigestHeader))
+	_, _, headers, _ := s.parseSignature(req)
+	s.Equal([]string{"(request-target)", "host", "date"}, headers)
+}
+
+func (s *signingTestSuite) TestSignRequestWithECDSAAndEd25519Keys() {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+
+	req := s.newRequest()
+	s.Require().NoError(SignRequest(req, testKeyID, ecKey))
+	_, algorithm, headers, signature := s.parseSignature(req)
+	hash := sha256.Sum256([]byte(SigningString(req, headers)))
+	s.Equal("ecdsa-sha256", algorithm)
+	s.True(ecdsa.VerifyASN1(&ecKey.PublicKey, hash[:], signature))
+
+	req = s.newRequest()
+	s.Require().NoError(SignRequest(req, testKeyID, edKey))
+	_, algorithm, headers, signature = s.parseSignature(req)
+	s.Equal("ed25519", algorithm)
+	s.True(ed25519.Verify(edPub, []byte(SigningString(req, headers)), signature))
+}
+
+func (s *signingTestSuite) TestSignRequestReturnsError() {
+	s.ErrorIs(SignRequest(s.newRequest(), "", s.key), ErrMissingKeyID)
+	s.ErrorIs(SignRequest(s.newRequest(), testKeyID, unsupportedSigner{}), ErrUnsupportedKey)
+}
+
+func (s *signingTestSuite) TestParsePrivateKey() {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	ecDer, err := x509.MarshalECPrivateKey(ecKey)
+	s.Require().NoError(err)
+	pkcs8Der, err := x509.MarshalPKCS8PrivateKey(s.key)
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name     string
+		pemBlock *pem.Block
+	}{
+		{"PKCS1", &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(s.key)}},
+		{"PKCS8", &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Der}},
+		{"EC", &pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDer}},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			key, err := ParsePrivateKey(pem.EncodeToMemory(tc.pemBlock))
+
+			s.Require().NoError(err)
+			s.NotNil(key)
+		})
+	}
+}
+
+func (s *signingTestSuite) TestParsePrivateKeyReturnsError_WhenKeyIsInvalid() {
+	_, err := ParsePrivateKey([]byte("not a key"))
+	s.ErrorIs(err, ErrInvalidPrivateKey)
+
+	_, err = ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("invalid")}))
+	s.ErrorIs(err, ErrInvalidPrivateKey)
+	var asn1Err asn1.StructuralError
+	s.ErrorAs(err, &asn1Err)
+}
+
+func (s *signingTestSuite) newRequest() *http.Request {
+	req, err := http.NewRequest(http.MethodPatch, "https://api.form3.tech/v1/organisation/accounts/1", strings.NewReader(`{}`))
+	s.Require().NoError(err)
+	return req
+}
+
+func (s *signingTestSuite) parseSignature(req *http.Request) (string, string, []string, []byte) {
+	match := signatureRegexp.FindStringSubmatch(req.Header.Get(SignatureHeader))
+	s.Require().Len(match, 5)
+	signature, err := base64.StdEncoding.DecodeString(match[4])
+	s.Require().NoError(err)
+	return match[1], match[2], strings.Split(match[3], " "), signature
+}
+
+type unsupportedSigner struct{}
+
+func (unsupportedSigner) Public() crypto.PublicKey { return "key" }
+
+func (unsupportedSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) { return nil, nil }
@@ -0,0 +1,100 @@
+package signing
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrDigestMismatch the Digest header doesn't match the body of the response
+	ErrDigestMismatch = errors.New("response digest mismatch")
+	// ErrSignatureMismatch the Signature header of the response is invalid
+	ErrSignatureMismatch = errors.New("response signature mismatch")
+)
+
+// VerifyResponse verifies the Digest header of the response against the body and the Signature header with
+// the public key, if the headers are present. The signature is not verified if the public key is nil.
+// The body of the response is read and it's restored, so it can still be decoded.
+func VerifyResponse(resp *http.Response, publicKey crypto.PublicKey) error {
+	digest := resp.Header.Get(DigestHeader)
+	signature := resp.Header.Get(SignatureHeader)
+	if digest == "" && (signature == "" || publicKey == nil) {
+		return nil
+	}
+
+	if digest != "" {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if digest != Digest(body) {
+			return ErrDigestMismatch
+		}
+	}
+
+	if signature != "" && publicKey != nil {
+		return verifySignature(resp.Header, signature, publicKey)
+	}
+	return nil
+}
+
+// verifySignature verifies the signature of the signed headers of the response.
+func verifySignature(header http.Header, signature string, publicKey crypto.PublicKey) error {
+	params := parseSignature(signature)
+	sig, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("%w: invalid signature", ErrSignatureMismatch)
+	}
+
+	signed := strings.Fields(params["headers"])
+	if len(signed) == 0 {
+		signed = []string{"date"}
+	}
+	lines := make([]string, 0, len(signed))
+	for _, h := range signed {
+		lines = append(lines, h+": "+header.Get(h))
+	}
+	data := []byte(strings.Join(lines, "\n"))
+
+	if !verify(publicKey, data, sig) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func verify(publicKey crypto.PublicKey, data, sig []byte) bool {
+	sum := sha256.Sum256(data)
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig) == nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, sum[:], sig)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, sig)
+	}
+	return false
+}
+
+// parseSignature parses the key="value" parameters of the Signature header.
+func parseSignature(signature string) map[string]string {
+	params := map[string]string{}
+	for _, param := range strings.Split(signature, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok {
+			params[key] = strings.Trim(value, `"`)
+		}
+	}
+	return params
+}
@@ -0,0 +1,115 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+const testResponseBody = `{"data":{"id":"1"}}`
+
+type verifyTestSuite struct {
+	suite.Suite
+	key *rsa.PrivateKey
+}
+
+func TestVerifyTestSuite(t *testing.T) {
+	suite.Run(t, new(verifyTestSuite))
+}
+
+func (s *verifyTestSuite) SetupSuite() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.key = key
+}
+
+func (s *verifyTestSuite) TestVerifyResponse() {
+	resp := s.newResponse(testResponseBody)
+	s.signResponse(resp, s.key)
+
+	s.Require().NoError(VerifyResponse(resp, &s.key.PublicKey))
+
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Equal(testResponseBody, string(body))
+}
+
+func (s *verifyTestSuite) TestVerifyResponseWithoutHeaders() {
+	s.NoError(VerifyResponse(s.newResponse(testResponseBody), &s.key.PublicKey))
+}
+
+func (s *verifyTestSuite) TestVerifyResponseWithEd25519Key() {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	resp := s.newResponse(testResponseBody)
+	s.signResponse(resp, privateKey)
+
+	s.NoError(VerifyResponse(resp, publicKey))
+}
+
+func (s *verifyTestSuite) TestVerifyResponseReturnsDigestMismatch() {
+	resp := s.newResponse(testResponseBody)
+	resp.Header.Set(DigestHeader, Digest([]byte(`{"data":{"id":"2"}}`)))
+
+	s.ErrorIs(VerifyResponse(resp, nil), ErrDigestMismatch)
+}
+
+func (s *verifyTestSuite) TestVerifyResponseReturnsSignatureMismatch() {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+
+	s.Run("signed with another key", func() {
+		resp := s.newResponse(testResponseBody)
+		s.signResponse(resp, otherKey)
+
+		s.ErrorIs(VerifyResponse(resp, &s.key.PublicKey), ErrSignatureMismatch)
+	})
+
+	s.Run("invalid signature", func() {
+		resp := s.newResponse(testResponseBody)
+		resp.Header.Set(SignatureHeader, `keyId="server",algorithm="rsa-sha256",headers="date",signature="!"`)
+
+		s.ErrorIs(VerifyResponse(resp, &s.key.PublicKey), ErrSignatureMismatch)
+	})
+
+	s.Run("not verified without public key", func() {
+		resp := s.newResponse(testResponseBody)
+		s.signResponse(resp, otherKey)
+
+		s.NoError(VerifyResponse(resp, nil))
+	})
+}
+
+func (s *verifyTestSuite) newResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{DateHeader: []string{"Mon, 14 Mar 2022 10:00:00 GMT"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func (s *verifyTestSuite) signResponse(resp *http.Response, key crypto.Signer) {
+	resp.Header.Set(DigestHeader, Digest([]byte(testResponseBody)))
+	data := "date: " + resp.Header.Get(DateHeader) + "\ndigest: " + resp.Header.Get(DigestHeader)
+	var signature []byte
+	var err error
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		signature, err = key.Sign(rand.Reader, []byte(data), crypto.Hash(0))
+	} else {
+		sum := sha256.Sum256([]byte(data))
+		signature, err = key.Sign(rand.Reader, sum[:], crypto.SHA256)
+	}
+	s.Require().NoError(err)
+	resp.Header.Set(SignatureHeader, fmt.Sprintf(`keyId="server",algorithm="rsa-sha256",headers="date digest",signature="%s"`,
+		base64.StdEncoding.EncodeToString(signature)))
+}
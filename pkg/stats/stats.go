@@ -0,0 +1,29 @@
+// Package stats provides the counters collected by the Form3 clients about their own usage.
+package stats
+
+// Names of the features counted in Stats.Features.
+const (
+	// FeatureEnricher counts the requests sent with a RequestEnricher (including the ones of the *WithMeta methods).
+	FeatureEnricher = "enricher"
+	// FeatureChainedEnrichers counts the requests where more than one RequestEnricher was given (see requestenricher.Chain).
+	FeatureChainedEnrichers = "chained_enrichers"
+	// FeatureWithMeta counts the calls of the *WithMeta methods.
+	FeatureWithMeta = "with_meta"
+	// FeatureBatch counts the batch operations (i.e. TouchAccounts or CreateBatch).
+	FeatureBatch = "batch"
+	// FeatureRetry counts the retried requests of the clients supporting retries.
+	FeatureRetry = "retry"
+	// FeatureCacheHit counts the responses served from cache by the clients supporting caching.
+	FeatureCacheHit = "cache_hit"
+	// FeatureSlowRequest counts the requests taking longer than the slow request threshold (see config.WithSlowRequestThreshold).
+	FeatureSlowRequest = "slow_request"
+)
+
+// Stats is a snapshot of the counters collected by a client since it was created.
+type Stats struct {
+	// SchemaWarnings counts the schema evolution warnings by the JSON path of the rejected field.
+	// It is only collected in strict mode (see config.WithStrictMode).
+	SchemaWarnings map[string]uint64
+	// Features counts how many times the SDK features were used by their names (see the Feature* constants).
+	Features map[string]uint64
+}
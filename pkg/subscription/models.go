@@ -0,0 +1,42 @@
+package subscription
+
+import "form3interview/internal/resource"
+
+// subscriptionContainer is a simple container for the "data" JSON field.
+type subscriptionContainer = resource.DataContainer[SubscriptionData]
+
+// subscriptionListContainer is a simple container for the "data" JSON field of list responses.
+type subscriptionListContainer = resource.ListContainer[SubscriptionData]
+
+// Callback transports of a subscription.
+const (
+	// CallbackTransportHttp delivers the events by calling the CallbackURI.
+	CallbackTransportHttp = "http"
+	// CallbackTransportQueue delivers the events to the queue given by the CallbackURI.
+	CallbackTransportQueue = "queue"
+)
+
+// SubscriptionData represents a notification subscription.
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/subscriptions for
+// more information about fields.
+type SubscriptionData struct {
+	Attributes     *SubscriptionAttributes `json:"attributes,omitempty"`
+	ID             string                  `json:"id,omitempty"`
+	OrganisationID string                  `json:"organisation_id,omitempty"`
+	Type           string                  `json:"type,omitempty"`
+	Version        *int64                  `json:"version,omitempty"`
+}
+
+type SubscriptionAttributes struct {
+	CallbackTransport string `json:"callback_transport,omitempty"`
+	CallbackURI       string `json:"callback_uri,omitempty"`
+	Deactivated       *bool  `json:"deactivated,omitempty"`
+	EventType         string `json:"event_type,omitempty"`
+	RecordType        string `json:"record_type,omitempty"`
+}
+
+// ListFilter narrows down the listed subscriptions. Empty fields are not used for filtering.
+type ListFilter struct {
+	EventType  string
+	RecordType string
+}
@@ -0,0 +1,220 @@
+// Package subscription provides Form3 client to manage event notification subscriptions.
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/subscriptions
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/google/uuid"
+
+	conf "form3interview/internal/config"
+	"form3interview/internal/resource"
+	"form3interview/pkg/config"
+	"form3interview/pkg/conflict"
+	re "form3interview/pkg/requestenricher"
+)
+
+const (
+	subscriptionsUrl  = "/notification/subscriptions"
+	subscriptionsType = "subscriptions"
+)
+
+var (
+	// ErrBaseUrlNotConfigured base url is not configured
+	ErrBaseUrlNotConfigured = resource.ErrBaseUrlNotConfigured
+	// ErrOrganisationIDNotConfigured organisation ID is not configured
+	ErrOrganisationIDNotConfigured = resource.ErrOrganisationIDNotConfigured
+	// ErrNilUUID nil UUID is not allowed
+	ErrNilUUID = resource.ErrNilUUID
+	// ErrSubscriptionNotFound subscription not found
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	// ErrInvalidSubscriptionVersion subscription version not found
+	ErrInvalidSubscriptionVersion = errors.New("invalid subscription version")
+	// ErrResourceAlreadyExists matches the already exists errors of all clients
+	ErrResourceAlreadyExists = resource.ErrResourceAlreadyExists
+	// ErrServerError server side error occured.
+	// This includes these server errors:
+	// 		500 Internal Server Error
+	// 		502 Bad Gateway
+	// 		504 Gateway Timeout
+	ErrServerError = resource.ErrServerError
+	// ErrServerUnavailable server is unavailable
+	ErrServerUnavailable = resource.ErrServerUnavailable
+	// ErrUnexpectedServerResponse server response not handled by the client
+	ErrUnexpectedServerResponse = resource.ErrUnexpectedServerResponse
+	// ErrReadOnly mutating call of a client configured with config.WithReadOnly
+	ErrReadOnly = resource.ErrReadOnly
+	// ErrInvalidRequest server returned with 400 Bad Request
+	ErrInvalidRequest = resource.ErrInvalidRequest
+	// ErrRateLimited server returned with 429 Too Many Requests
+	ErrRateLimited = resource.ErrRateLimited
+	// ErrUnauthorized server returned with 401 Unauthorized
+	ErrUnauthorized = resource.ErrUnauthorized
+	// ErrForbidden server returned with 403 Forbidden
+	ErrForbidden = resource.ErrForbidden
+	// ErrClientClosed the request was sent with a closed client
+	ErrClientClosed = resource.ErrClientClosed
+	// ErrTimeout the request timed out
+	ErrTimeout = resource.ErrTimeout
+	// ErrConnection the connection to the API failed or it was broken
+	ErrConnection = resource.ErrConnection
+)
+
+type subscriptionClient struct {
+	client resource.HttpClient
+	config conf.ClientConfig
+}
+
+// Client is the client of the Form3 notification subscriptions created by NewClient or the form3 facade.
+type Client = subscriptionClient
+
+// NewClient creates a client for managing Form3 subscriptions.
+// The client can be configured by passing config.Options with the helpers from the form3interview/pkg/config package.
+func NewClient(options ...config.Option) (*subscriptionClient, error) {
+	cfg := conf.NewConfig()
+	config.ApplyOptions(&cfg, options)
+
+	if err := resource.CheckConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return &subscriptionClient{
+		client: resource.NewHttpClient(cfg),
+		config: cfg,
+	}, nil
+}
+
+// Close waits for the in-flight requests of the client, closes its idle connections and fails the further
+// requests with ErrClientClosed.
+func (s subscriptionClient) Close() error {
+	return resource.Close(s.client)
+}
+
+// Create a subscription with attributes.
+// The CallbackTransport decides if the events are delivered to a callback url or to a queue given by the CallbackURI.
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/subscriptions/create-a-subscription
+//
+// The request can be enriched by RequestEnricher
+func (s subscriptionClient) Create(attributes SubscriptionAttributes, en ...re.RequestEnricher) (*SubscriptionData, error) {
+	newID, err := s.config.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := SubscriptionData{
+		ID:             newID.String(),
+		OrganisationID: s.config.OrganisationID.String(),
+		Type:           subscriptionsType,
+		Attributes:     &attributes,
+	}
+
+	created, err := s.subscriptions().Create(newID, subscription, en...)
+	if err != nil {
+		return nil, err
+	}
+	s.config.Log().Debugf("subscription %s created", subscription.ID)
+	return created, nil
+}
+
+// Fetch a subscription by it's ID.
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/subscriptions/fetch-a-subscription
+//
+// The request can be enriched by RequestEnricher
+func (s subscriptionClient) Fetch(subscriptionID uuid.UUID, en ...re.RequestEnricher) (*SubscriptionData, error) {
+	return s.subscriptions().Fetch(subscriptionID, en...)
+}
+
+// List subscriptions matching the filter page by page. Page numbers start from 0.
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/subscriptions/list-subscriptions
+//
+// The request can be enriched by RequestEnricher
+func (s subscriptionClient) List(filter ListFilter, pageNumber, pageSize uint, en ...re.RequestEnricher) ([]SubscriptionData, error) {
+	query := fmt.Sprintf("page[number]=%d&page[size]=%d", pageNumber, pageSize)
+	if filter.RecordType != "" {
+		query += "&filter[record_type]=" + url.QueryEscape(filter.RecordType)
+	}
+	if filter.EventType != "" {
+		query += "&filter[event_type]=" + url.QueryEscape(filter.EventType)
+	}
+	return s.subscriptions().Find(query, en...)
+}
+
+// Delete is a convenience function to delete a subscription by it's ID having the latest version.
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/subscriptions/delete-a-subscription
+//
+// Under the hood it fetches the latest subscription and delete that with the specific version returned.
+// The request can be enriched by RequestEnricher
+func (s subscriptionClient) Delete(subscriptionID uuid.UUID, en ...re.RequestEnricher) error {
+	if s.config.ReadOnly {
+		return ErrReadOnly
+	}
+
+	version, _, err := s.latestVersion(subscriptionID, en...)
+	if err != nil {
+		return err
+	}
+	return s.DeleteVersion(subscriptionID, version, en...)
+}
+
+// DeleteVersion deletes a subscription by it's ID having a specific version.
+// See https://www.api-docs.form3.tech/api/tutorials/getting-started/subscriptions/delete-a-subscription
+//
+// A version conflict is resolved with the conflict policy of the config (see config.WithConflictPolicy).
+// The request can be enriched by RequestEnricher
+func (s subscriptionClient) DeleteVersion(subscriptionID uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	if subscriptionID == uuid.Nil {
+		return ErrNilUUID
+	}
+
+	return s.config.ConflictPolicy.Resolve(conflict.Write{
+		ID:          subscriptionID,
+		Version:     version,
+		ErrConflict: ErrInvalidSubscriptionVersion,
+		Do: func(version uint) error {
+			return s.deleteVersion(subscriptionID, version, en...)
+		},
+		Latest: func() (uint, any, error) {
+			return s.latestVersion(subscriptionID, en...)
+		},
+	})
+}
+
+// latestVersion fetches the subscription and returns its current version.
+func (s subscriptionClient) latestVersion(subscriptionID uuid.UUID, en ...re.RequestEnricher) (uint, any, error) {
+	subscription, err := s.Fetch(subscriptionID, en...)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	version := uint(0)
+	if subscription.Version != nil {
+		version = uint(*subscription.Version)
+	}
+	return version, subscription, nil
+}
+
+func (s subscriptionClient) deleteVersion(subscriptionID uuid.UUID, version uint, en ...re.RequestEnricher) error {
+	if err := s.subscriptions().DeleteVersion(subscriptionID, version, en...); err != nil {
+		return err
+	}
+	s.config.Log().Debugf("subscription %s deleted", subscriptionID)
+	return nil
+}
+
+func (s subscriptionClient) bodyToSubscriptionData(body io.Reader) (*SubscriptionData, error) {
+	return resource.Client[SubscriptionData]{Config: s.config}.BodyToData(body)
+}
+
+// subscriptions returns the generic client of the subscriptions collection.
+func (s subscriptionClient) subscriptions() resource.Client[SubscriptionData] {
+	return resource.Client[SubscriptionData]{
+		HTTP:              s.client,
+		Config:            s.config,
+		Url:               subscriptionsUrl,
+		ErrNotFound:       ErrSubscriptionNotFound,
+		ErrInvalidVersion: ErrInvalidSubscriptionVersion,
+	}
+}
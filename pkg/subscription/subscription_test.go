@@ -0,0 +1,247 @@
+package subscription
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3interview/internal/config"
+	"form3interview/internal/mocks"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	Do                   = "Do"
+	testBaseUrl          = "testhost"
+	testSubscriptionsUrl = testBaseUrl + subscriptionsUrl
+	testOrganisationID   = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+)
+
+type subscriptionTestSuite struct {
+	suite.Suite
+	mockHttpClient     *mocks.HttpClientMock
+	subscriptionClient subscriptionClient
+}
+
+func TestSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, new(subscriptionTestSuite))
+}
+
+func (s *subscriptionTestSuite) SetupTest() {
+	s.mockHttpClient = &mocks.HttpClientMock{}
+	orgID := uuid.MustParse(testOrganisationID)
+	baseUrl := testBaseUrl
+	s.subscriptionClient = subscriptionClient{
+		client: s.mockHttpClient,
+		config: config.ClientConfig{
+			BaseUrl:        &baseUrl,
+			OrganisationID: &orgID,
+		},
+	}
+}
+
+func (s *subscriptionTestSuite) TestCreateReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		expectedError  error
+	}{
+		{
+			name:           "invalid request",
+			responseStatus: http.StatusBadRequest,
+			responseBody:   "{\"error_message\":\"callback_uri is required\"}",
+			expectedError:  ErrInvalidRequest,
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			expectedError:  ErrServerError,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+		{
+			name:           "unexpected server response",
+			responseStatus: http.StatusTeapot,
+			responseBody:   "oops",
+			expectedError:  ErrUnexpectedServerResponse,
+		},
+	} {
+		s.Run(test.name, func() {
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testSubscriptionsUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody(test.responseBody)}, nil).
+				Once()
+
+			_, actualError := s.subscriptionClient.Create(SubscriptionAttributes{})
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *subscriptionTestSuite) TestCreateReturnsHttpClientError() {
+	expectedError := errors.New("http client error")
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testSubscriptionsUrl)), mock.Anything).
+		Return(nil, expectedError).
+		Once()
+
+	_, actualError := s.subscriptionClient.Create(SubscriptionAttributes{})
+
+	s.ErrorIs(actualError, expectedError)
+}
+
+func (s *subscriptionTestSuite) TestCreateSubscription() {
+	subscriptionID := uuid.New()
+	s.subscriptionClient.config.UUIDGenerator = func() (uuid.UUID, error) { return subscriptionID, nil }
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodPost, testSubscriptionsUrl)), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusCreated, Body: toResponseBody("{\"data\":{}}")}, nil).
+		Once()
+
+	_, err := s.subscriptionClient.Create(SubscriptionAttributes{
+		CallbackTransport: CallbackTransportQueue,
+		CallbackURI:       "https://sqs.eu-west-1.amazonaws.com/123456789012/events",
+		RecordType:        "accounts",
+		EventType:         "created",
+	})
+	s.NoError(err)
+
+	request := s.mockHttpClient.Calls[0].Arguments[0].(*http.Request)
+	requested, err := s.subscriptionClient.bodyToSubscriptionData(request.Body)
+	s.Require().NoError(err)
+	s.Equal(subscriptionID.String(), requested.ID)
+	s.Equal(testOrganisationID, requested.OrganisationID)
+	s.Equal(subscriptionsType, requested.Type)
+	s.Equal(CallbackTransportQueue, requested.Attributes.CallbackTransport)
+	s.Equal("accounts", requested.Attributes.RecordType)
+	s.Equal("created", requested.Attributes.EventType)
+}
+
+func (s *subscriptionTestSuite) TestListSubscriptions() {
+	for _, test := range []struct {
+		name        string
+		filter      ListFilter
+		expectedUrl string
+	}{
+		{
+			name:        "without filter",
+			expectedUrl: testSubscriptionsUrl + "?page[number]=0&page[size]=10",
+		},
+		{
+			name:        "with filter",
+			filter:      ListFilter{RecordType: "accounts", EventType: "created"},
+			expectedUrl: testSubscriptionsUrl + "?page[number]=0&page[size]=10&filter[record_type]=accounts&filter[event_type]=created",
+		},
+	} {
+		s.Run(test.name, func() {
+			subscriptionID := uuid.New()
+			body, err := json.Marshal(subscriptionListContainer{Data: []SubscriptionData{{ID: subscriptionID.String()}}})
+			s.Require().NoError(err)
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, test.expectedUrl)), mock.Anything).
+				Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+				Once()
+
+			actual, err := s.subscriptionClient.List(test.filter, 0, 10)
+			s.NoError(err)
+			s.Require().Len(actual, 1)
+			s.Equal(subscriptionID.String(), actual[0].ID)
+		})
+	}
+}
+
+func (s *subscriptionTestSuite) TestListReturnsError() {
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, testSubscriptionsUrl+"?page[number]=0&page[size]=10")), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusBadGateway, Body: toResponseBody("")}, nil).
+		Once()
+
+	_, actualError := s.subscriptionClient.List(ListFilter{}, 0, 10)
+
+	s.ErrorIs(actualError, ErrServerError)
+}
+
+func (s *subscriptionTestSuite) TestFetchReturnsError_WhenNilUuidGiven() {
+	_, actualError := s.subscriptionClient.Fetch(uuid.Nil)
+
+	s.ErrorIs(actualError, ErrNilUUID)
+	s.mockHttpClient.AssertNotCalled(s.T(), Do)
+}
+
+func (s *subscriptionTestSuite) TestDeleteVersionedSubscriptionReturnsError() {
+	for _, test := range []struct {
+		name           string
+		responseStatus int
+		expectedError  error
+	}{
+		{
+			name:           "subscription not found",
+			responseStatus: http.StatusNotFound,
+			expectedError:  ErrSubscriptionNotFound,
+		},
+		{
+			name:           "invalid subscription version",
+			responseStatus: http.StatusConflict,
+			expectedError:  ErrInvalidSubscriptionVersion,
+		},
+		{
+			name:           "server unavailable",
+			responseStatus: http.StatusServiceUnavailable,
+			expectedError:  ErrServerUnavailable,
+		},
+	} {
+		s.Run(test.name, func() {
+			subscriptionID := uuid.New()
+			s.mockHttpClient.
+				On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, fmt.Sprintf("%s/%s?version=1", testSubscriptionsUrl, subscriptionID))), mock.Anything).
+				Return(&http.Response{StatusCode: test.responseStatus, Body: toResponseBody("")}, nil).
+				Once()
+
+			actualError := s.subscriptionClient.DeleteVersion(subscriptionID, 1)
+
+			s.ErrorIs(actualError, test.expectedError)
+		})
+	}
+}
+
+func (s *subscriptionTestSuite) TestDeleteLatestSubscriptionVersion() {
+	subscriptionID := uuid.New()
+	version := int64(3)
+	body, err := json.Marshal(subscriptionContainer{Data: SubscriptionData{ID: subscriptionID.String(), Version: &version}})
+	s.Require().NoError(err)
+
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodGet, fmt.Sprintf("%s/%s", testSubscriptionsUrl, subscriptionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusOK, Body: toResponseBody(string(body))}, nil).
+		Once()
+	s.mockHttpClient.
+		On(Do, mock.MatchedBy(requestMatcher(http.MethodDelete, fmt.Sprintf("%s/%s?version=3", testSubscriptionsUrl, subscriptionID))), mock.Anything).
+		Return(&http.Response{StatusCode: http.StatusNoContent, Body: toResponseBody("")}, nil).
+		Once()
+
+	s.NoError(s.subscriptionClient.Delete(subscriptionID))
+	s.mockHttpClient.AssertExpectations(s.T())
+}
+
+func requestMatcher(expectedMethod, expectedUrl string) func(input *http.Request) bool {
+	return func(input *http.Request) bool {
+		return input.Method == expectedMethod &&
+			input.URL.String() == expectedUrl
+	}
+}
+
+func toResponseBody(body string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(body))
+}
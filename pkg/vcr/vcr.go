@@ -0,0 +1,319 @@
+// Package vcr provides the record/replay transport of the Form3 clients. In record mode the interactions with the
+// API (i.e. the sandbox) are captured to a cassette file with the credentials redacted, in replay mode they are
+// served from the cassette, so the tests run without network access:
+//
+//	recorder, err := vcr.New("testdata/create_account.json", vcr.Settings{Mode: vcr.ModeAuto})
+//	defer recorder.Save()
+//	client, err := account.NewClient(config.WrapTransport(recorder.Wrap))
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"form3interview/pkg/debuglog"
+	"form3interview/pkg/result"
+)
+
+var (
+	// ErrCassetteNotFound the cassette to replay doesn't exist
+	ErrCassetteNotFound = errors.New("cassette not found")
+	// ErrInvalidCassette the cassette can't be decoded
+	ErrInvalidCassette = errors.New("invalid cassette")
+	// ErrInteractionNotFound the cassette has no interaction left matching the request
+	ErrInteractionNotFound = errors.New("interaction not found in cassette")
+)
+
+// Mode is the mode of the recorder.
+type Mode int
+
+const (
+	// ModeReplay serves the requests from the cassette, it fails if the cassette doesn't exist.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests to the API and records them, the cassette is overwritten when it's saved.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records it otherwise, so a cassette is re-recorded by
+	// deleting it.
+	ModeAuto
+)
+
+// DefaultRedactedFields are the JSON fields of the bodies redacted by default, they carry credentials.
+var DefaultRedactedFields = []string{"client_secret", "access_token", "refresh_token", "password"}
+
+// Settings are the settings of the recorder.
+type Settings struct {
+	Mode Mode
+	// Match returns true if the recorded interaction is the response to the request. The requests are matched
+	// by their method and URL by default. Every interaction is replayed once in the recorded order.
+	Match func(r *http.Request, body []byte, interaction Interaction) bool
+	// RedactFields are the JSON fields of the bodies redacted at any depth in addition to DefaultRedactedFields.
+	// The headers carrying credentials or signatures are always redacted.
+	RedactFields []string
+	// Sanitize is called with every recorded interaction after the redaction, i.e. to mask personal data.
+	Sanitize func(interaction *Interaction)
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder records or replays the interactions of a cassette. It's safe for concurrent use.
+type Recorder struct {
+	path     string
+	mode     Mode
+	settings Settings
+	redacted map[string]bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// New creates a recorder of the cassette on the path. The cassette is loaded if it's replayed, it returns
+// ErrCassetteNotFound if the cassette doesn't exist in replay mode.
+func New(path string, settings Settings) (*Recorder, error) {
+	r := &Recorder{path: path, mode: settings.Mode, settings: settings, redacted: map[string]bool{}}
+	for _, field := range append(append([]string{}, DefaultRedactedFields...), settings.RedactFields...) {
+		r.redacted[strings.ToLower(field)] = true
+	}
+	if r.settings.Match == nil {
+		r.settings.Match = MatchMethodAndURL
+	}
+
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if r.mode == ModeAuto {
+			r.mode = ModeRecord
+			return r, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrCassetteNotFound, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, result.Wrap(fmt.Errorf("%w: %s", ErrInvalidCassette, path), err)
+	}
+	r.mode = ModeReplay
+	r.interactions = c.Interactions
+	r.replayed = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// MatchMethodAndURL matches the requests by their method and URL.
+func MatchMethodAndURL(r *http.Request, _ []byte, interaction Interaction) bool {
+	return r.Method == interaction.Request.Method && r.URL.String() == interaction.Request.URL
+}
+
+// Recording returns true if the recorder records the interactions, false if it replays them.
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+// Interactions returns the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Wrap returns the transport recording the requests sent through next or replaying them, it can be passed
+// to config.WrapTransport.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return transport{recorder: r, next: next}
+}
+
+// Save writes the recorded interactions to the cassette, creating its directory if needed. It does nothing
+// in replay mode.
+func (r *Recorder) Save() error {
+	if !r.Recording() {
+		return nil
+	}
+	r.mu.Lock()
+	c := cassette{Interactions: append([]Interaction{}, r.interactions...)}
+	r.mu.Unlock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+type transport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if !t.recorder.Recording() {
+		return t.recorder.replay(req, body)
+	}
+
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.recorder.record(Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Header: req.Header.Clone(), Body: string(body)},
+		Response: Response{StatusCode: resp.StatusCode, Header: resp.Header.Clone(), Body: string(respBody)},
+	})
+	return resp, nil
+}
+
+// readBody reads and closes the body of the request, the RoundTripper is responsible for closing it.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func (r *Recorder) record(interaction Interaction) {
+	r.sanitize(&interaction)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, interaction)
+}
+
+// sanitize redacts the sensitive headers and body fields of the interaction and applies the Sanitize setting.
+func (r *Recorder) sanitize(interaction *Interaction) {
+	redactHeader(interaction.Request.Header)
+	redactHeader(interaction.Response.Header)
+	interaction.Request.Body = r.redactBody(interaction.Request.Body)
+	interaction.Response.Body = r.redactBody(interaction.Response.Body)
+	if r.settings.Sanitize != nil {
+		r.settings.Sanitize(interaction)
+	}
+}
+
+func redactHeader(header http.Header) {
+	for name := range header {
+		if debuglog.SensitiveHeader(name) {
+			header[name] = []string{debuglog.Redacted}
+		}
+	}
+}
+
+// redactBody redacts the fields of a JSON body, other bodies are kept as they are.
+func (r *Recorder) redactBody(body string) string {
+	var v interface{}
+	if body == "" || json.Unmarshal([]byte(body), &v) != nil {
+		return body
+	}
+	if !r.redact(v) {
+		return body
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(redacted)
+}
+
+// redact redacts the fields of the decoded JSON at any depth, it returns true if any field was redacted.
+func (r *Recorder) redact(v interface{}) bool {
+	var redacted bool
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if r.redacted[strings.ToLower(key)] {
+				v[key] = debuglog.Redacted
+				redacted = true
+			} else if r.redact(value) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if r.redact(value) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
+
+// replay returns the response of the first interaction not replayed yet matching the request.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !r.settings.Match(req, body, interaction) {
+			continue
+		}
+		r.replayed[i] = true
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, req.URL)
+}
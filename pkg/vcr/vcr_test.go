@@ -0,0 +1,144 @@
+package vcr
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"form3interview/pkg/account"
+	"form3interview/pkg/config"
+	"form3interview/pkg/debuglog"
+	"form3interview/pkg/form3test"
+)
+
+type vcrTestSuite struct {
+	suite.Suite
+	path string
+}
+
+func TestVcrTestSuite(t *testing.T) {
+	suite.Run(t, new(vcrTestSuite))
+}
+
+func (s *vcrTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "cassettes", "account.json")
+}
+
+func (s *vcrTestSuite) client(recorder *Recorder, opts ...config.Option) *account.Client {
+	client, err := account.NewClient(append(opts, config.WrapTransport(recorder.Wrap))...)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func (s *vcrTestSuite) TestRecordAndReplay() {
+	server := form3test.NewServer()
+	id := uuid.New()
+	server.PutAccount(account.AccountData{ID: id.String(), OrganisationID: form3test.OrganisationID.String()})
+	recorder, err := New(s.path, Settings{Mode: ModeAuto})
+	s.Require().NoError(err)
+	s.True(recorder.Recording())
+
+	recorded, err := s.client(recorder, server.Options()...).Fetch(id)
+	s.Require().NoError(err)
+	_, err = s.client(recorder, server.Options()...).Fetch(uuid.New())
+	s.Require().ErrorIs(err, account.ErrAccountNotFound)
+	s.Require().NoError(recorder.Save())
+	baseUrl := server.BaseURL()
+	server.Close()
+
+	replayer, err := New(s.path, Settings{Mode: ModeAuto})
+	s.Require().NoError(err)
+	s.False(replayer.Recording())
+	s.Len(replayer.Interactions(), 2)
+	client := s.client(replayer, config.WithBaseUrl(baseUrl), config.WithOrganisationID(form3test.OrganisationID))
+
+	replayed, err := client.Fetch(id)
+	s.Require().NoError(err)
+	s.Equal(recorded, replayed)
+
+	_, err = client.Fetch(id)
+	s.ErrorIs(err, ErrInteractionNotFound)
+}
+
+func (s *vcrTestSuite) TestRecordRedactsCredentials() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Api-Key", "secret")
+		_, _ = io.WriteString(w, `{"access_token":"token","expires_in":3600}`)
+	}))
+	defer server.Close()
+	recorder, err := New(s.path, Settings{
+		Mode:         ModeRecord,
+		RedactFields: []string{"name"},
+		Sanitize: func(interaction *Interaction) {
+			interaction.Request.URL = strings.Replace(interaction.Request.URL, server.URL, "https://api.test", 1)
+		},
+	})
+	s.Require().NoError(err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/oauth2/token", strings.NewReader(`{"client_secret":"secret","data":{"name":["Jane"]}}`))
+	s.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := (&http.Client{Transport: recorder.Wrap(nil)}).Do(req)
+	s.Require().NoError(err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	s.Equal(`{"access_token":"token","expires_in":3600}`, string(body))
+	s.Require().Len(recorder.Interactions(), 1)
+	interaction := recorder.Interactions()[0]
+	s.Equal("https://api.test/oauth2/token", interaction.Request.URL)
+	s.Equal(debuglog.Redacted, interaction.Request.Header.Get("Authorization"))
+	s.JSONEq(`{"client_secret":"REDACTED","data":{"name":"REDACTED"}}`, interaction.Request.Body)
+	s.Equal(debuglog.Redacted, interaction.Response.Header.Get("X-Api-Key"))
+	s.JSONEq(`{"access_token":"REDACTED","expires_in":3600}`, interaction.Response.Body)
+}
+
+func (s *vcrTestSuite) TestReplayWithMatcher() {
+	s.Require().NoError(os.MkdirAll(filepath.Dir(s.path), 0o755))
+	data, err := json.Marshal(cassette{Interactions: []Interaction{
+		{Request: Request{Method: http.MethodPost, URL: "https://api.test/a", Body: "1"}, Response: Response{StatusCode: http.StatusCreated, Body: "first"}},
+		{Request: Request{Method: http.MethodPost, URL: "https://api.test/a", Body: "2"}, Response: Response{StatusCode: http.StatusConflict, Body: "second"}},
+	}})
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(s.path, data, 0o644))
+	recorder, err := New(s.path, Settings{Match: func(r *http.Request, body []byte, interaction Interaction) bool {
+		return MatchMethodAndURL(r, body, interaction) && string(body) == interaction.Request.Body
+	}})
+	s.Require().NoError(err)
+	client := &http.Client{Transport: recorder.Wrap(nil)}
+
+	resp, err := client.Post("https://api.test/a", "text/plain", strings.NewReader("2"))
+	s.Require().NoError(err)
+	body, _ := io.ReadAll(resp.Body)
+
+	s.Equal(http.StatusConflict, resp.StatusCode)
+	s.Equal("second", string(body))
+	s.NoError(recorder.Save())
+}
+
+func (s *vcrTestSuite) TestNew() {
+	_, err := New(s.path, Settings{Mode: ModeReplay})
+	s.ErrorIs(err, ErrCassetteNotFound)
+
+	s.Require().NoError(os.MkdirAll(filepath.Dir(s.path), 0o755))
+	s.Require().NoError(os.WriteFile(s.path, []byte("{"), 0o644))
+	_, err = New(s.path, Settings{Mode: ModeAuto})
+	s.ErrorIs(err, ErrInvalidCassette)
+
+	recorder, err := New(s.path, Settings{Mode: ModeRecord})
+	s.Require().NoError(err)
+	s.True(recorder.Recording())
+	s.Require().NoError(recorder.Save())
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err)
+	s.JSONEq(`{"interactions":[]}`, string(data))
+}
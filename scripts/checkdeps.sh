@@ -0,0 +1,33 @@
+#!/bin/sh
+# Checks the dependency surface of the core module promised in the README: the client packages (everything in pkg/
+# except the mocks) only build the allowed modules, and the test-only and optional dependencies are not in the
+# module graph of the core module at all.
+set -eu
+
+allowed="github.com/caarlos0/env/v6 github.com/google/uuid github.com/mattn/go-colorable github.com/mattn/go-isatty github.com/rs/zerolog golang.org/x/sys gopkg.in/yaml.v3"
+forbidden="github.com/prometheus/client_golang go.opentelemetry.io/otel gorm.io/gorm github.com/testcontainers/testcontainers-go"
+
+status=0
+
+packages=$(go list ./pkg/... | grep -v '/mock$')
+modules=$(go list -deps -f '{{with .Module}}{{if not .Main}}{{.Path}}{{end}}{{end}}' $packages | sort -u)
+for m in $modules; do
+	case " $allowed " in
+	*" $m "*) ;;
+	*)
+		echo "the client packages depend on $m, which is not in the allowed dependencies:"
+		go mod why -m "$m"
+		status=1
+		;;
+	esac
+done
+
+for m in $forbidden; do
+	if ! go mod why -m "$m" | grep -q "does not need module"; then
+		echo "the core module needs $m, which must stay out of it:"
+		go mod why -m "$m"
+		status=1
+	fi
+done
+
+exit $status
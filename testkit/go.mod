@@ -0,0 +1,23 @@
+module form3interview/testkit
+
+go 1.19
+
+require (
+	form3interview v0.0.0-00010101000000-000000000000
+	github.com/google/uuid v1.3.0
+	github.com/rs/zerolog v1.28.0
+	github.com/stretchr/testify v1.8.3
+)
+
+require (
+	github.com/caarlos0/env/v6 v6.10.1 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/kr/text v0.1.0 // indirect
+	github.com/mattn/go-colorable v0.1.12 // indirect
+	github.com/mattn/go-isatty v0.0.14 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace form3interview => ../
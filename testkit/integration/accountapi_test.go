@@ -0,0 +1,128 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"form3interview/internal/testharness"
+	"form3interview/pkg/account"
+	"form3interview/pkg/form3test"
+	"form3interview/pkg/requestenricher"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	intTestOrganisationID = form3test.OrganisationID
+	intTestAttributes     = form3test.NewValidAccount("FR")
+)
+
+type accountApiTestSuite struct {
+	suite.Suite
+	harness       *testharness.Harness
+	accountClient account.AccountsService
+	createdIDs    []string
+}
+
+func TestAccountApiTestSuite(t *testing.T) {
+	suite.Run(t, new(accountApiTestSuite))
+}
+
+func (s *accountApiTestSuite) SetupSuite() {
+	settings, err := testharness.SettingsFromEnv()
+	s.Require().NoError(err)
+	s.harness, err = testharness.Start(context.Background(), settings)
+	s.Require().NoError(err)
+
+	s.accountClient, err = account.NewClient(s.harness.Options()...)
+	s.Require().NoError(err)
+}
+
+func (s *accountApiTestSuite) TearDownSuite() {
+	if s.accountClient != nil {
+		s.NoError(s.accountClient.Close())
+	}
+	if s.harness != nil {
+		s.NoError(s.harness.Close())
+	}
+}
+
+func (s *accountApiTestSuite) TearDownTest() {
+	for _, id := range s.createdIDs {
+		err := s.accountClient.Delete(uuid.MustParse(id))
+		if !errors.Is(err, account.ErrAccountNotFound) {
+			s.Require().NoError(err)
+		}
+	}
+	s.createdIDs = nil
+}
+
+func (s *accountApiTestSuite) create(en ...requestenricher.RequestEnricher) *account.AccountData {
+	data, err := s.accountClient.Create(intTestAttributes, en...)
+	s.Require().NoError(err)
+	s.createdIDs = append(s.createdIDs, data.ID)
+	return data
+}
+
+func (s *accountApiTestSuite) Test1_CreateAccount() {
+	actualData := s.create()
+	s.assertAccountData(actualData)
+}
+
+func (s *accountApiTestSuite) Test2_FetchAccount() {
+	created := s.create()
+
+	actualData, err := s.accountClient.Fetch(uuid.MustParse(created.ID))
+	s.NoError(err)
+	s.assertAccountData(actualData)
+	s.Equal(created.ID, actualData.ID)
+}
+
+func (s *accountApiTestSuite) Test3_DeleteAccount() {
+	created := s.create()
+	accountID := uuid.MustParse(created.ID)
+
+	s.NoError(s.accountClient.Delete(accountID))
+	_, err := s.accountClient.Fetch(accountID)
+	s.ErrorIs(err, account.ErrAccountNotFound)
+}
+
+func (s *accountApiTestSuite) Test4_EnrichedRequest() {
+	var start time.Time
+	beforeHookCalled := false
+	afterHookCalled := false
+	en := requestenricher.RequestEnricher{
+		BeforeHook: func() {
+			beforeHookCalled = true
+			start = time.Now()
+		},
+		AfterHook: func(r *http.Response) {
+			afterHookCalled = true
+			log.Info().Msgf("Request took %s and returned with status %d", time.Since(start), r.StatusCode)
+		},
+	}
+
+	actualData := s.create(en)
+	s.True(beforeHookCalled)
+	s.True(afterHookCalled)
+	s.assertAccountData(actualData)
+}
+
+func (s *accountApiTestSuite) assertAccountData(data *account.AccountData) {
+	s.NotNil(data)
+
+	_, err := uuid.Parse(data.ID)
+	s.NoError(err)
+	s.Equal(intTestOrganisationID.String(), data.OrganisationID)
+	s.Equal("accounts", data.Type)
+	s.Equal(int64(0), *data.Version)
+
+	s.Equal(intTestAttributes, *data.Attributes)
+}